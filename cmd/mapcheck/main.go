@@ -0,0 +1,130 @@
+// Command mapcheck validates map JSON files and reports every problem found.
+// PNG and BMP pictures are imported first, and with -write saved as map JSON
+// next to the picture.
+//
+//	mapcheck [-json] [-strict] map.json...
+//	mapcheck -cell 8 -write europe.png
+//
+// It exits non-zero if any map has errors, or warnings with -strict.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"lords-of-conquest/pkg/maps"
+)
+
+// result is the -json output for one file.
+type result struct {
+	File        string           `json:"file"`
+	Error       string           `json:"error,omitempty"`
+	Diagnostics maps.Diagnostics `json:"diagnostics"`
+}
+
+func main() {
+	asJSON := flag.Bool("json", false, "Print diagnostics as JSON")
+	strict := flag.Bool("strict", false, "Treat warnings as errors")
+	cell := flag.Int("cell", 1, "Pixels per map cell when importing images")
+	minCells := flag.Int("min-cells", 1, "Smallest image region kept as a territory")
+	write := flag.Bool("write", false, "Save imported images as <name>.json")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: mapcheck [options] map.json|map.png|map.bmp...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	failed := false
+	var results []result
+	for _, file := range flag.Args() {
+		r := check(file, maps.ImageOptions{CellSize: *cell, MinCells: *minCells}, *write)
+		if r.Error != "" || r.Diagnostics.HasErrors() || (*strict && len(r.Diagnostics) > 0) {
+			failed = true
+		}
+
+		if *asJSON {
+			results = append(results, r)
+			continue
+		}
+		switch {
+		case r.Error != "":
+			fmt.Printf("%s: %s\n", file, r.Error)
+		case len(r.Diagnostics) == 0:
+			fmt.Printf("%s: ok\n", file)
+		default:
+			for _, d := range r.Diagnostics {
+				fmt.Printf("%s: %s\n", file, d)
+			}
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// check reads and validates one map file.
+func check(file string, opts maps.ImageOptions, write bool) result {
+	r := result{File: file, Diagnostics: maps.Diagnostics{}}
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".png", ".bmp":
+		return checkImage(file, opts, write)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	var raw maps.RawMap
+	if err := json.Unmarshal(data, &raw); err != nil {
+		r.Error = fmt.Sprintf("failed to parse map JSON: %v", err)
+		return r
+	}
+
+	r.Diagnostics = maps.Validate(&raw)
+	return r
+}
+
+// checkImage imports and validates a map picture, saving it as JSON if asked.
+// The JSON is written even if the map has errors, so they can be fixed in it.
+func checkImage(file string, opts maps.ImageOptions, write bool) result {
+	r := result{File: file, Diagnostics: maps.Diagnostics{}}
+
+	raw, err := maps.ImportImageFile(file, opts)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	r.Diagnostics = maps.Validate(raw)
+
+	if write {
+		data, err := json.MarshalIndent(raw, "", "  ")
+		if err != nil {
+			r.Error = err.Error()
+			return r
+		}
+		out := strings.TrimSuffix(file, filepath.Ext(file)) + ".json"
+		if err := os.WriteFile(out, append(data, '\n'), 0644); err != nil {
+			r.Error = err.Error()
+		}
+	}
+	return r
+}
@@ -0,0 +1,125 @@
+// Command mapgen generates maps offline for curating a map library. It writes
+// the map as RawMap JSON, renders a PNG preview, and prints the map's debug
+// dump, adjacency matrix, balance and any validation diagnostics.
+//
+//	mapgen -seed 42 -territories 60 -out maps/
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"lords-of-conquest/pkg/maps"
+)
+
+func main() {
+	defaults := maps.DefaultOptions()
+	width := flag.Int("width", defaults.Width, "Map width in cells (20-60)")
+	territories := flag.Int("territories", defaults.Territories, "Target territory count (24-120)")
+	islands := flag.Int("islands", defaults.Islands, "Island spread (1 = one landmass, 5 = many islands)")
+	resources := flag.Int("resources", defaults.Resources, "Resource coverage percentage (10-100)")
+	waterBorder := flag.Bool("water-border", defaults.WaterBorder, "Surround the map with water")
+	topology := flag.String("topology", "square", "Grid topology: square or hex")
+	terrain := flag.Bool("terrain", false, "Give territories terrain (plains, forest, mountains, marsh)")
+	template := flag.String("template", "", "Land layout: continents, archipelago, pangaea, ring or mirror (default scatters land by -islands)")
+	symmetry := flag.String("symmetry", "", "Copy one part to every seat: mirror, rotate (2 seats) or quad (4 seats)")
+	seats := flag.Int("seats", 4, "Players to balance and report fairness for")
+	fairness := flag.Int("fairness", 0, "Minimum fairness percentage (0 = don't check)")
+	seed := flag.Int64("seed", 0, "Random seed (0 = random)")
+	name := flag.String("name", "", "Map name (default \"Generated Map\")")
+	id := flag.String("id", "", "Map ID (default gen_<seed>)")
+	outDir := flag.String("out", ".", "Directory to write <id>.json and <id>.png to")
+	scale := flag.Int("scale", 24, "PNG pixels per map cell")
+	noPNG := flag.Bool("no-png", false, "Don't render a PNG preview")
+	quiet := flag.Bool("q", false, "Don't print the debug dump and adjacency matrix")
+	flag.Parse()
+
+	if !maps.Topology(*topology).Known() {
+		log.Fatalf("Unknown topology %q", *topology)
+	}
+	if !maps.Template(*template).Known() {
+		log.Fatalf("Unknown template %q", *template)
+	}
+	if sym := maps.Symmetry(*symmetry); !sym.Known() || !sym.Supports(maps.Topology(*topology)) {
+		log.Fatalf("Symmetry %q is not possible on %s maps", *symmetry, *topology)
+	}
+
+	if *seed == 0 {
+		// Pick the seed here so it can be printed and reused
+		*seed = time.Now().UnixNano()
+	}
+
+	gen := maps.NewGenerator(maps.GeneratorOptions{
+		Width:       *width,
+		Territories: *territories,
+		WaterBorder: *waterBorder,
+		Islands:     *islands,
+		Resources:   *resources,
+		Seats:       *seats,
+		Fairness:    *fairness,
+		Seed:        *seed,
+		Topology:    maps.Topology(*topology),
+		Terrain:     *terrain,
+		Template:    maps.Template(*template),
+		Symmetry:    maps.Symmetry(*symmetry),
+	})
+	m, _ := gen.Generate()
+	if *id != "" {
+		m.ID = *id
+	}
+	if *name != "" {
+		m.Name = *name
+	}
+
+	if !*quiet {
+		fmt.Print(m.Debug())
+		fmt.Println()
+		fmt.Print(m.PrintAdjacencyMatrix())
+		fmt.Println()
+	}
+
+	raw := m.Raw()
+	for _, d := range maps.Validate(raw) {
+		fmt.Println(d)
+	}
+	report := maps.Analyze(m, *seats)
+	fmt.Printf("Seed %d: %d territories, %d land masses, %d chokepoints, fairness %.0f%% for %d seats\n",
+		*seed, len(m.Territories), len(report.Regions), len(report.Chokepoints), report.Fairness*100, *seats)
+
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		log.Fatalf("Failed to create output directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to encode map: %v", err)
+	}
+	jsonPath := filepath.Join(*outDir, m.ID+".json")
+	if err := os.WriteFile(jsonPath, append(data, '\n'), 0644); err != nil {
+		log.Fatalf("Failed to write map: %v", err)
+	}
+	fmt.Printf("Wrote %s\n", jsonPath)
+
+	if *noPNG {
+		return
+	}
+	pngPath := filepath.Join(*outDir, m.ID+".png")
+	f, err := os.Create(pngPath)
+	if err != nil {
+		log.Fatalf("Failed to create preview: %v", err)
+	}
+	if err := png.Encode(f, render(m, *scale)); err != nil {
+		f.Close()
+		log.Fatalf("Failed to render preview: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatalf("Failed to write preview: %v", err)
+	}
+	fmt.Printf("Wrote %s\n", pngPath)
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"lords-of-conquest/internal/game"
+	"lords-of-conquest/pkg/maps"
+)
+
+var (
+	colorWater  = color.RGBA{30, 60, 120, 255}
+	colorBorder = color.RGBA{20, 25, 20, 255}
+	colorText   = color.RGBA{255, 255, 255, 255}
+	colorShadow = color.RGBA{0, 0, 0, 200}
+)
+
+// resourceIcons are the colour and letter drawn for each resource.
+var resourceIcons = map[game.ResourceType]struct {
+	color  color.RGBA
+	letter string
+}{
+	game.ResourceCoal:      {color.RGBA{40, 40, 40, 255}, "C"},
+	game.ResourceGold:      {color.RGBA{230, 190, 40, 255}, "G"},
+	game.ResourceIron:      {color.RGBA{130, 140, 160, 255}, "I"},
+	game.ResourceTimber:    {color.RGBA{110, 70, 30, 255}, "T"},
+	game.ResourceGrassland: {color.RGBA{150, 210, 80, 255}, "H"},
+}
+
+// render draws a preview of the map: land shaded per territory, borders
+// between territories and along coasts, and each territory's name and
+// resource at its centre. Hex maps are drawn as offset rows of square cells,
+// which touch the same six neighbours a hex does.
+func render(m *maps.Map, scale int) *image.RGBA {
+	if scale < 4 {
+		scale = 4
+	}
+	hex := m.Topology == maps.TopologyHex
+	width := m.Width * scale
+	if hex {
+		width += scale / 2
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, m.Height*scale))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorWater}, image.Point{}, draw.Src)
+
+	border := 1
+	if scale >= 16 {
+		border = 2
+	}
+
+	// The cells above or below x,y that touch its left and right halves
+	vertical := func(x, y, dy int) (int, int) {
+		if !hex {
+			return m.TerritoryAt(x, y+dy), m.TerritoryAt(x, y+dy)
+		}
+		if y%2 == 0 {
+			return m.TerritoryAt(x-1, y+dy), m.TerritoryAt(x, y+dy)
+		}
+		return m.TerritoryAt(x, y+dy), m.TerritoryAt(x+1, y+dy)
+	}
+
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			tid := m.Grid[y][x]
+			if tid == 0 {
+				continue
+			}
+			cell := cellRect(m, x, y, scale)
+			mid := cell.Min.X + scale/2
+			draw.Draw(img, cell, &image.Uniform{landColor(tid)}, image.Point{}, draw.Src)
+
+			// Edges facing another territory or water
+			if left, right := vertical(x, y, -1); left != tid || right != tid {
+				if left != tid {
+					fill(img, image.Rect(cell.Min.X, cell.Min.Y, mid, cell.Min.Y+border), colorBorder)
+				}
+				if right != tid {
+					fill(img, image.Rect(mid, cell.Min.Y, cell.Max.X, cell.Min.Y+border), colorBorder)
+				}
+			}
+			if left, right := vertical(x, y, 1); left != tid || right != tid {
+				if left != tid {
+					fill(img, image.Rect(cell.Min.X, cell.Max.Y-border, mid, cell.Max.Y), colorBorder)
+				}
+				if right != tid {
+					fill(img, image.Rect(mid, cell.Max.Y-border, cell.Max.X, cell.Max.Y), colorBorder)
+				}
+			}
+			if m.TerritoryAt(x-1, y) != tid {
+				fill(img, image.Rect(cell.Min.X, cell.Min.Y, cell.Min.X+border, cell.Max.Y), colorBorder)
+			}
+			if m.TerritoryAt(x+1, y) != tid {
+				fill(img, image.Rect(cell.Max.X-border, cell.Min.Y, cell.Max.X, cell.Max.Y), colorBorder)
+			}
+		}
+	}
+
+	face := basicfont.Face7x13
+	for _, t := range m.Territories {
+		cx, cy := labelCell(t)
+		center := cellRect(m, cx, cy, scale).Min.Add(image.Pt(scale/2, scale/2))
+		px, py := center.X, center.Y
+
+		if icon, ok := resourceIcons[t.Resource]; ok {
+			r := scale/2 + 2
+			disc(img, px, py-r, r, icon.color)
+			text(img, face, px, py-r+4, icon.letter, colorText)
+		}
+		text(img, face, px, py+12, t.Name, colorText)
+	}
+
+	return img
+}
+
+// cellRect returns the pixels covered by cell x,y.
+func cellRect(m *maps.Map, x, y, scale int) image.Rectangle {
+	cx, cy := m.Topology.CellCenter(x, y)
+	left, top := int(cx*float64(scale))-scale/2, int(cy*float64(scale))-scale/2
+	return image.Rect(left, top, left+scale, top+scale)
+}
+
+// landColor gives each territory a slightly different shade of green.
+func landColor(tid int) color.RGBA {
+	base := uint8(80 + (tid*17)%40)
+	return color.RGBA{base, base + 40, base - 10, 255}
+}
+
+// labelCell returns the territory cell closest to its centre, so the label
+// sits on the territory even if it is oddly shaped.
+func labelCell(t *maps.Territory) (int, int) {
+	sx, sy := 0, 0
+	for _, c := range t.Cells {
+		sx += c[0]
+		sy += c[1]
+	}
+	n := len(t.Cells)
+	best, bestDist := t.Cells[0], -1
+	for _, c := range t.Cells {
+		dx, dy := c[0]*n-sx, c[1]*n-sy
+		if d := dx*dx + dy*dy; bestDist < 0 || d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best[0], best[1]
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+// disc draws a filled circle with a dark outline.
+func disc(img *image.RGBA, cx, cy, r int, c color.RGBA) {
+	for y := -r; y <= r; y++ {
+		for x := -r; x <= r; x++ {
+			d := x*x + y*y
+			switch {
+			case d <= (r-1)*(r-1):
+				img.Set(cx+x, cy+y, c)
+			case d <= r*r:
+				img.Set(cx+x, cy+y, colorBorder)
+			}
+		}
+	}
+}
+
+// text draws s centred on x with its baseline at y, over a drop shadow.
+func text(img *image.RGBA, face font.Face, x, y int, s string, c color.Color) {
+	width := font.MeasureString(face, s).Round()
+	for _, pass := range []struct {
+		offset int
+		color  color.Color
+	}{{1, colorShadow}, {0, c}} {
+		d := &font.Drawer{
+			Dst:  img,
+			Src:  image.NewUniform(pass.color),
+			Face: face,
+			Dot:  fixed.P(x-width/2+pass.offset, y+pass.offset),
+		}
+		d.DrawString(s)
+	}
+}
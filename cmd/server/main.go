@@ -3,9 +3,11 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -15,6 +17,11 @@ import (
 func main() {
 	port := flag.String("port", "30000", "Server port")
 	dbPath := flag.String("db", "data/lords.db", "Database path")
+	databaseURL := flag.String("database-url", "", "PostgreSQL connection URL (overrides -db)")
+	nodeID := flag.String("node", "", "Node ID when running several instances")
+	peers := flag.String("peers", "", "Other instances as node=url pairs, comma separated")
+	busSecret := flag.String("bus-secret", "", "Shared secret for traffic between instances (required with -peers)")
+	busPort := flag.String("bus-port", "30001", "Internal port other instances reach the bus on")
 	flag.Parse()
 
 	// Use PORT env var if set (required for Render.com and similar platforms)
@@ -31,9 +38,35 @@ func main() {
 		log.Printf("Using DB_PATH from environment: %s", actualDBPath)
 	}
 
+	// Use DATABASE_URL env var if set, for deployments backed by PostgreSQL
+	actualDatabaseURL := envOr("DATABASE_URL", *databaseURL)
+
 	cfg := server.Config{
-		Addr:   ":" + actualPort,
-		DBPath: actualDBPath,
+		Addr:        ":" + actualPort,
+		DBPath:      actualDBPath,
+		DatabaseURL: actualDatabaseURL,
+	}
+
+	// Cluster settings, also from NODE_ID, BUS_PEERS, BUS_SECRET and BUS_PORT.
+	// Peer URLs point at the other instances' bus ports.
+	actualNodeID := envOr("NODE_ID", *nodeID)
+	actualPeers := envOr("BUS_PEERS", *peers)
+	if actualPeers != "" && actualNodeID == "" {
+		log.Fatalf("Peers need a node ID (-node or NODE_ID)")
+	}
+	if actualNodeID != "" && actualPeers != "" {
+		peerURLs, err := parsePeers(actualPeers)
+		if err != nil {
+			log.Fatalf("Invalid peers: %v", err)
+		}
+		bus, err := server.NewHTTPBus(peerURLs, envOr("BUS_SECRET", *busSecret))
+		if err != nil {
+			log.Fatalf("Refusing to cluster: %v (set -bus-secret or BUS_SECRET)", err)
+		}
+		cfg.NodeID = actualNodeID
+		cfg.Bus = bus
+		cfg.BusAddr = ":" + envOr("BUS_PORT", *busPort)
+		log.Printf("Clustering as node %s with %d peers", actualNodeID, len(peerURLs))
 	}
 
 	srv, err := server.New(cfg)
@@ -52,7 +85,11 @@ func main() {
 	}()
 
 	log.Printf("Lords of Conquest Server running on %s", cfg.Addr)
-	log.Printf("Database: %s", cfg.DBPath)
+	if cfg.DatabaseURL != "" {
+		log.Printf("Database: PostgreSQL")
+	} else {
+		log.Printf("Database: %s", cfg.DBPath)
+	}
 
 	<-done
 	log.Println("Shutting down server...")
@@ -66,3 +103,28 @@ func main() {
 
 	log.Println("Server stopped")
 }
+
+// envOr returns the environment variable if set, otherwise fallback.
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// parsePeers parses "node-a=http://host-a:30000,node-b=http://host-b:30000".
+func parsePeers(s string) (map[string]string, error) {
+	peers := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		node, url, ok := strings.Cut(pair, "=")
+		if !ok || node == "" || url == "" {
+			return nil, fmt.Errorf("expected node=url, got %q", pair)
+		}
+		peers[node] = strings.TrimRight(url, "/")
+	}
+	return peers, nil
+}
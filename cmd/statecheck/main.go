@@ -0,0 +1,105 @@
+// Command statecheck loads every saved game from a server database and checks
+// it against the current engine: that it upgrades to the current state schema
+// and passes GameState.Validate. Run it before deploying a build that changes
+// the game state format.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"lords-of-conquest/internal/database"
+	"lords-of-conquest/internal/game"
+)
+
+func main() {
+	dbPath := flag.String("db", "data/lords.db", "Database path")
+	databaseURL := flag.String("database-url", "", "PostgreSQL connection URL (overrides -db)")
+	upgrade := flag.Bool("upgrade", false, "Write upgraded states back to the database")
+	flag.Parse()
+
+	var db *database.DB
+	var err error
+	if url := envOr("DATABASE_URL", *databaseURL); url != "" {
+		db, err = database.NewPostgres(url)
+	} else {
+		db, err = database.New(envOr("DB_PATH", *dbPath))
+	}
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	ids, err := db.GetGameStateIDs()
+	if err != nil {
+		log.Fatalf("Failed to list games: %v", err)
+	}
+
+	failed, upgraded := 0, 0
+	for _, id := range ids {
+		stateJSON, err := db.GetGameState(id)
+		if err != nil {
+			fmt.Printf("%s: failed to read: %v\n", id, err)
+			failed++
+			continue
+		}
+
+		from, err := game.SavedStateVersion([]byte(stateJSON))
+		if err != nil {
+			fmt.Printf("%s: failed to load: %v\n", id, err)
+			failed++
+			continue
+		}
+		state, err := game.UnmarshalState([]byte(stateJSON))
+		if err != nil {
+			fmt.Printf("%s: failed to load: %v\n", id, err)
+			failed++
+			continue
+		}
+
+		if errs := state.Validate(); len(errs) > 0 {
+			fmt.Printf("%s: %d problems\n", id, len(errs))
+			for _, e := range errs {
+				fmt.Printf("  %v\n", e)
+			}
+			failed++
+			continue
+		}
+
+		if from == game.StateVersion {
+			fmt.Printf("%s: ok\n", id)
+			continue
+		}
+		if !*upgrade {
+			fmt.Printf("%s: ok, needs upgrade from version %d\n", id, from)
+			continue
+		}
+
+		data, err := game.MarshalState(state)
+		if err == nil {
+			err = db.SaveGameState(id, string(data), state.CurrentPlayerID, state.Round, state.Phase.String())
+		}
+		if err != nil {
+			fmt.Printf("%s: failed to save upgrade: %v\n", id, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s: upgraded from version %d\n", id, from)
+		upgraded++
+	}
+
+	fmt.Printf("%d games checked, %d upgraded, %d with problems\n", len(ids), upgraded, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// envOr returns the environment variable if set, otherwise fallback.
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -47,6 +47,8 @@ type Game struct {
 	inGame        bool
 	currentGameID string
 	lobbyState    *protocol.LobbyStatePayload
+	mapLibrary    []protocol.MapListItem
+	mapPreview    *protocol.MapPreviewPayload
 
 	// Music control UI
 	showMusicControl  bool
@@ -413,7 +415,57 @@ func (g *Game) UpdateGameSettings(key, value string) error {
 
 // UpdateMap sends a new map to the server (host only).
 func (g *Game) UpdateMap(m *maps.Map) error {
-	// Convert to protocol MapData
+	payload := protocol.UpdateMapPayload{
+		MapData: toMapData(m),
+	}
+	return g.network.SendPayload(protocol.TypeUpdateMap, payload)
+}
+
+// ListMaps requests a page of the map library, sorted by "rating", "plays"
+// or "newest".
+func (g *Game) ListMaps(sort string) error {
+	payload := protocol.ListMapsPayload{
+		Sort: sort,
+	}
+	return g.network.SendPayload(protocol.TypeListMaps, payload)
+}
+
+// GetMap requests a library map for previewing.
+func (g *Game) GetMap(mapID string) error {
+	payload := protocol.GetMapPayload{
+		MapID: mapID,
+	}
+	return g.network.SendPayload(protocol.TypeGetMap, payload)
+}
+
+// PublishMap adds a map to the server's map library.
+func (g *Game) PublishMap(m *maps.Map, name string) error {
+	payload := protocol.PublishMapPayload{
+		Name:    name,
+		MapData: toMapData(m),
+	}
+	return g.network.SendPayload(protocol.TypePublishMap, payload)
+}
+
+// RateMap rates a library map from 1 to 5.
+func (g *Game) RateMap(mapID string, rating int) error {
+	payload := protocol.RateMapPayload{
+		MapID:  mapID,
+		Rating: rating,
+	}
+	return g.network.SendPayload(protocol.TypeRateMap, payload)
+}
+
+// SelectMap uses a library map for the current game (host only).
+func (g *Game) SelectMap(mapID string) error {
+	payload := protocol.SelectMapPayload{
+		MapID: mapID,
+	}
+	return g.network.SendPayload(protocol.TypeSelectMap, payload)
+}
+
+// toMapData converts a map to the form it is sent over the network in.
+func toMapData(m *maps.Map) *protocol.MapData {
 	mapData := &protocol.MapData{
 		ID:          m.ID,
 		Name:        m.Name,
@@ -421,18 +473,21 @@ func (g *Game) UpdateMap(m *maps.Map) error {
 		Height:      m.Height,
 		Grid:        m.Grid,
 		Territories: make(map[string]protocol.TerritoryInfo),
+		Topology:    string(m.Topology),
+		Symmetry:    string(m.Symmetry),
+		Starts:      m.Starts,
 	}
 	for id, t := range m.Territories {
 		mapData.Territories[fmt.Sprintf("%d", id)] = protocol.TerritoryInfo{
 			Name:     t.Name,
 			Resource: t.Resource.String(),
+			Terrain:  string(t.Terrain),
 		}
 	}
-
-	payload := protocol.UpdateMapPayload{
-		MapData: mapData,
+	for _, sea := range m.Seas() {
+		mapData.Seas = append(mapData.Seas, protocol.SeaInfo{Name: sea.Name, Cell: sea.Cell})
 	}
-	return g.network.SendPayload(protocol.TypeUpdateMap, payload)
+	return mapData
 }
 
 // LeaveGame leaves the current game.
@@ -464,8 +519,22 @@ func (g *Game) MoveStockpile(destinationID string) error {
 	return g.network.SendPayload(protocol.TypeMoveStockpile, payload)
 }
 
-// MoveUnit moves a unit (horse, boat) during shipment phase.
-func (g *Game) MoveUnit(unitType, fromID, toID, waterBodyID string, carryHorse, carryWeapon bool) error {
+// ShipResources moves resources between the player's stockpile and depots
+// during shipment.
+func (g *Game) ShipResources(fromID, toID string, coal, gold, iron, timber int) error {
+	payload := protocol.ShipResourcesPayload{
+		From:   fromID,
+		To:     toID,
+		Coal:   coal,
+		Gold:   gold,
+		Iron:   iron,
+		Timber: timber,
+	}
+	return g.network.SendPayload(protocol.TypeShipResources, payload)
+}
+
+// MoveUnit moves a unit (horse, boat, siege engine, transport) during shipment phase.
+func (g *Game) MoveUnit(unitType, fromID, toID, waterBodyID string, carryHorse, carryWeapon bool, carrySiege int) error {
 	payload := protocol.MoveUnitPayload{
 		UnitType:    unitType,
 		From:        fromID,
@@ -473,6 +542,7 @@ func (g *Game) MoveUnit(unitType, fromID, toID, waterBodyID string, carryHorse,
 		WaterBodyID: waterBodyID,
 		CarryHorse:  carryHorse,
 		CarryWeapon: carryWeapon,
+		CarrySiege:  carrySiege,
 	}
 	return g.network.SendPayload(protocol.TypeMoveUnit, payload)
 }
@@ -523,10 +593,11 @@ func (g *Game) Build(buildType, territoryID string, useGold bool) error {
 	return g.network.SendPayload(protocol.TypeBuild, payload)
 }
 
-// BuildBoatInWater builds a boat in a specific water body.
-func (g *Game) BuildBoatInWater(territoryID, waterBodyID string, useGold bool) error {
+// BuildBoatInWater builds a boat, or with buildType "transport" a transport,
+// in a specific water body.
+func (g *Game) BuildBoatInWater(buildType, territoryID, waterBodyID string, useGold bool) error {
 	payload := protocol.BuildPayload{
-		Type:        "boat",
+		Type:        buildType,
 		Territory:   territoryID,
 		WaterBodyID: waterBodyID,
 		UseGold:     useGold,
@@ -655,6 +726,51 @@ func (g *Game) BuyCard(cardType, resource string) error {
 	return g.network.SendPayload(protocol.TypeBuyCard, payload)
 }
 
+// Raid strikes at an enemy stockpile or depot during conquest.
+func (g *Game) Raid(targetID string) error {
+	payload := protocol.RaidPayload{
+		TargetTerritory: targetID,
+	}
+	return g.network.SendPayload(protocol.TypeRaid, payload)
+}
+
+// NavalAttack attacks the boats a territory keeps in a water body during conquest.
+func (g *Game) NavalAttack(targetID, waterBodyID string) error {
+	payload := protocol.NavalAttackPayload{
+		TargetTerritory: targetID,
+		WaterBodyID:     waterBodyID,
+	}
+	return g.network.SendPayload(protocol.TypeNavalAttack, payload)
+}
+
+// MarketTrade sells resources to the bank for another resource during the Trade phase.
+func (g *Game) MarketTrade(sell string, amount int, buy string) error {
+	payload := protocol.MarketTradePayload{
+		Sell:   sell,
+		Amount: amount,
+		Buy:    buy,
+	}
+	return g.network.SendPayload(protocol.TypeMarketTrade, payload)
+}
+
+// DiscardCard discards a combat card for resources during Development.
+func (g *Game) DiscardCard(cardID, resource string) error {
+	payload := protocol.DiscardCardPayload{
+		CardID:   cardID,
+		Resource: resource,
+	}
+	return g.network.SendPayload(protocol.TypeDiscardCard, payload)
+}
+
+// CombineCards trades two common combat cards for an uncommon one during
+// Development.
+func (g *Game) CombineCards(firstID, secondID string) error {
+	payload := protocol.CombineCardsPayload{
+		CardIDs: []string{firstID, secondID},
+	}
+	return g.network.SendPayload(protocol.TypeCombineCards, payload)
+}
+
 // SelectDefenseCards sends the defender's card selection for card combat.
 func (g *Game) SelectDefenseCards(cardIDs []string) error {
 	payload := protocol.SelectCardsPayload{
@@ -698,6 +814,23 @@ func (g *Game) Surrender(targetPlayerID string) error {
 	return g.network.SendPayload(protocol.TypeSurrender, payload)
 }
 
+// OfferVassalage offers to become another player's vassal.
+func (g *Game) OfferVassalage(targetPlayerID string) error {
+	payload := protocol.OfferVassalagePayload{
+		TargetPlayerID: targetPlayerID,
+	}
+	return g.network.SendPayload(protocol.TypeOfferVassalage, payload)
+}
+
+// AnswerVassalage accepts or declines another player's offer to become our vassal.
+func (g *Game) AnswerVassalage(vassalID string, accept bool) error {
+	payload := protocol.AnswerVassalagePayload{
+		VassalID: vassalID,
+		Accept:   accept,
+	}
+	return g.network.SendPayload(protocol.TypeAnswerVassalage, payload)
+}
+
 // AllianceVote sends the player's vote for an alliance request.
 func (g *Game) AllianceVote(battleID, side string) error {
 	payload := protocol.AllianceVotePayload{
@@ -788,6 +921,38 @@ func (g *Game) handleMessage(msg *protocol.Message) {
 			lobby.SetGameList(payload.Games)
 		}
 
+	case protocol.TypeMapList:
+		var payload protocol.MapListPayload
+		if err := msg.ParsePayload(&payload); err != nil {
+			return
+		}
+		g.mapLibrary = payload.Maps
+
+	case protocol.TypeMapPreview:
+		var payload protocol.MapPreviewPayload
+		if err := msg.ParsePayload(&payload); err != nil {
+			return
+		}
+		g.mapPreview = &payload
+
+	case protocol.TypeMapPublished:
+		var payload protocol.MapPublishedPayload
+		if err := msg.ParsePayload(&payload); err != nil {
+			return
+		}
+		log.Printf("Published map %q (%s)", payload.Map.Name, payload.Map.ID)
+
+	case protocol.TypeMapRated:
+		var payload protocol.MapRatedPayload
+		if err := msg.ParsePayload(&payload); err != nil {
+			return
+		}
+		for i := range g.mapLibrary {
+			if g.mapLibrary[i].ID == payload.Map.ID {
+				g.mapLibrary[i] = payload.Map
+			}
+		}
+
 	case protocol.TypeYourGames:
 		var payload protocol.YourGamesPayload
 		if err := msg.ParsePayload(&payload); err != nil {
@@ -870,6 +1035,10 @@ func (g *Game) handleMessage(msg *protocol.Message) {
 				CapturedIron:          payload.CapturedIron,
 				CapturedTimber:        payload.CapturedTimber,
 				CapturedFromTerritory: payload.CapturedFromTerritory,
+				FreedVassal:           payload.FreedVassal,
+				Raid:                  payload.Raid,
+				Naval:                 payload.Naval,
+				BoatCaptured:          payload.Naval && len(payload.UnitsCaptured) > 0,
 			}
 			g.gameplayScene.ShowCombatResult(result)
 
@@ -916,6 +1085,7 @@ func (g *Game) handleMessage(msg *protocol.Message) {
 			DefenseStrength:      payload.DefenseStrength,
 			AttackerAllyStrength: payload.AttackerAllyStrength,
 			DefenderAllyStrength: payload.DefenderAllyStrength,
+			WinChance:            payload.WinChance,
 			CanAttack:            payload.CanAttack,
 			Reinforcements:       reinforcements,
 		}
@@ -984,6 +1154,15 @@ func (g *Game) handleMessage(msg *protocol.Message) {
 		g.gameplayScene.ShowPhaseSkipped(payload.EventID, payload.Phase, payload.Reason)
 		log.Printf("Phase skipped: %s - %s (event: %s)", payload.Phase, payload.Reason, payload.EventID)
 
+	case protocol.TypeWorldEvent:
+		var payload protocol.WorldEventPayload
+		if err := msg.ParsePayload(&payload); err != nil {
+			log.Printf("Failed to parse world event: %v", err)
+			return
+		}
+		g.gameplayScene.ShowWorldEvent(&payload)
+		log.Printf("World event: %s - %s (event: %s)", payload.Name, payload.Description, payload.EventID)
+
 	case protocol.TypeProductionResults:
 		var payload protocol.ProductionResultsPayload
 		if err := msg.ParsePayload(&payload); err != nil {
@@ -1014,6 +1193,24 @@ func (g *Game) handleMessage(msg *protocol.Message) {
 			payload.SurrenderedPlayerName, payload.TargetPlayerName, payload.TerritoriesGained)
 		// The game state update will handle showing the changes
 
+	case protocol.TypeVassalageOffer:
+		var payload protocol.VassalageOfferPayload
+		if err := msg.ParsePayload(&payload); err != nil {
+			log.Printf("Failed to parse vassalage offer: %v", err)
+			return
+		}
+		g.gameplayScene.ShowVassalageOffer(&payload)
+		log.Printf("Vassalage offer from %s", payload.VassalName)
+
+	case protocol.TypeVassalageResult:
+		var payload protocol.VassalageResultPayload
+		if err := msg.ParsePayload(&payload); err != nil {
+			log.Printf("Failed to parse vassalage result: %v", err)
+			return
+		}
+		g.gameplayScene.ShowVassalageResult(&payload)
+		log.Printf("Vassalage: %s %s by %s", payload.VassalName, payload.Result, payload.OverlordName)
+
 	case protocol.TypeTerritoryDrawing:
 		var payload protocol.DrawTerritoryPayload
 		if err := msg.ParsePayload(&payload); err != nil {
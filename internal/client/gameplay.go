@@ -72,16 +72,25 @@ type GameplayScene struct {
 	selectedTerritory string // For multi-step actions like moving stockpile
 
 	// Development phase - select what to build first, then click territory
-	selectedBuildType string // "city", "weapon", or "boat" (empty = none selected)
+	selectedBuildType string // "city", "weapon", "boat", "fortress", "siege", "transport", "mine", "lumber_mill" or "stable" (empty = none selected)
 	buildUseGold      bool   // Toggle for using gold instead of resources
 	devCityBtn        *Button
 	devWeaponBtn      *Button
 	devBoatBtn        *Button
+	devFortressBtn    *Button
+	devSiegeBtn       *Button
+	devTransportBtn   *Button
+	devMineBtn        *Button
+	devLumberMillBtn  *Button
+	devStableBtn      *Button
+	devDepotBtn       *Button
 	devUseGoldBtn     *Button
 
 	// Card combat - Development phase card purchasing
 	devBuyAttackCardBtn  *Button
 	devBuyDefenseCardBtn *Button
+	devDiscardCardBtn    *Button
+	devCombineCardsBtn   *Button
 	cardBuyResource      string // "coal", "gold", "iron", "timber" - selected resource for card purchase
 	showCardDrawn        bool   // Show the drawn card popup
 	drawnCardName        string
@@ -100,7 +109,7 @@ type GameplayScene struct {
 	lastHoveredIsAtk  bool    // Last hovered card type
 
 	// Card combat - Card selection via card hand (bottom bar context mode)
-	cardSelectionMode string          // "", "attack", "defense" - when non-empty, card hand is in selection mode
+	cardSelectionMode string          // "", "attack", "defense", "discard", "combine" - when non-empty, card hand is in selection mode
 	selectedCardIDs   map[string]bool // Card IDs currently selected (toggled by clicking)
 
 	// Card combat - Attack card selection (during conquest)
@@ -135,6 +144,31 @@ type GameplayScene struct {
 	// Combat mode setting
 	combatMode string // "classic" or "cards"
 
+	// Terrain and straits rules settings
+	terrainRules bool
+	straitsRules bool
+
+	// Extended unit roster setting (fortresses, siege engines, transports)
+	extendedUnits bool
+
+	// Economy rules setting (upkeep, storage caps, city specializations)
+	economyRules bool
+
+	// Market setting and the bank's current prices (what it pays per unit)
+	marketRules  bool
+	marketPrices map[string]int
+
+	// Depots setting (extra stockpiles and raids)
+	depotRules bool
+
+	// Naval combat setting (sea battles and blockades)
+	navalRules bool
+
+	// This round's world events: doubled production, and the water body
+	// whose boats can't sail
+	harvest    bool
+	stormWater string
+
 	// Water body selection for boats (when territory touches multiple water bodies)
 	buildMenuTerritory string // Territory where we're building (for water body selection)
 
@@ -178,6 +212,8 @@ type GameplayScene struct {
 	attackNoReinfBtn      *Button
 	attackWithReinfBtn    *Button
 	cancelAttackBtn       *Button
+	raidBtn               *Button
+	seaBattleBtn          *Button
 	loadHorseCheckbox     bool // For boats: load horse?
 	loadWeaponCheckbox    bool // For boats: load weapon?
 
@@ -206,6 +242,8 @@ type GameplayScene struct {
 	confirmSurrenderBtn  *Button
 	cancelSurrenderBtn   *Button
 	surrenderPlayerBtns  []*Button // Buttons for surrendering to specific players
+	surrenderAsVassal    bool      // Confirming an offer of vassalage rather than a full surrender
+	vassalPlayerBtns     []*Button // Buttons for offering to serve specific players
 
 	// Alliance request popup (when asked to join a battle)
 	showAllyRequest      bool
@@ -233,17 +271,27 @@ type GameplayScene struct {
 	returnToLobbyBtn  *Button
 
 	// Shipment phase UI
-	shipmentMode          string // "", "stockpile", "horse", "boat"
+	shipmentMode          string // "", "stockpile", "horse", "boat", "siege", "transport", "resources"
 	shipmentFromTerritory string // Source territory for unit movement
 	shipmentWaterBodyID   string // For boats: which water body
 	shipmentCarryHorse    bool   // For boats: carry horse?
 	shipmentCarryWeapon   bool   // For boats/horses: carry weapon?
+	shipmentCarrySiege    bool   // For transports: load siege engines?
 	moveStockpileBtn      *Button
 	moveHorseBtn          *Button
 	moveBoatBtn           *Button
+	moveSiegeBtn          *Button
+	moveTransportBtn      *Button
+	moveResourcesBtn      *Button
 	cancelShipmentBtn     *Button
 	shipmentConfirmBtn    *Button
 
+	// Shipping resources between depots (depots setting)
+	showShipResources bool
+	shipAmounts       [4]int // Coal, gold, iron and timber, in marketResourceNames order
+	shipResourcesBtn  *Button
+	shipCancelBtn     *Button
+
 	// Edit territory dialog
 	showEditTerritory      bool
 	editTerritoryID        string
@@ -299,6 +347,15 @@ type GameplayScene struct {
 	tradeRejectBtn             *Button
 	tradeResultOkBtn           *Button
 
+	// Market UI (Trade phase, market setting)
+	marketBtn      *Button
+	showMarket     bool   // Show market popup
+	marketSell     string // Resource to sell to the bank
+	marketBuy      string // Resource to buy with the proceeds
+	marketAmount   int
+	marketTradeBtn *Button
+	marketCloseBtn *Button
+
 	// Pending horse selection (after trade dialog closes, select on map)
 	// "offer" = proposer selecting horses to give
 	// "request" = proposer selecting where to receive requested horses
@@ -414,6 +471,14 @@ type CombatResultData struct {
 	CapturedIron          int
 	CapturedTimber        int
 	CapturedFromTerritory string
+	// Vassal who won their freedom in this battle
+	FreedVassal string
+	// A raid: the captured resources are the loot and the territory stays put
+	Raid bool
+	// A sea battle: the territory stays put and the loser gives up a boat,
+	// which the winner captures if they have room for it
+	Naval        bool
+	BoatCaptured bool
 }
 
 // ProductionAnimData holds production animation data from server
@@ -422,6 +487,7 @@ type ProductionAnimData struct {
 	Productions            []ProductionItem
 	StockpileTerritoryID   string
 	StockpileTerritoryName string
+	EconomySummary         string // Upkeep, lost units, spoiled resources and tribute
 }
 
 // StockpileCaptureData holds data for stockpile capture animation
@@ -467,6 +533,7 @@ type AttackPreviewData struct {
 	DefenseStrength      int
 	AttackerAllyStrength int
 	DefenderAllyStrength int
+	WinChance            float64
 	CanAttack            bool
 	Reinforcements       []ReinforcementData
 }
@@ -545,6 +612,76 @@ func NewGameplayScene(game *Game) *GameplayScene {
 			}
 		},
 	}
+	s.devFortressBtn = &Button{
+		Text: "Fortress",
+		OnClick: func() {
+			if s.selectedBuildType == "fortress" {
+				s.selectedBuildType = ""
+			} else {
+				s.selectedBuildType = "fortress"
+			}
+		},
+	}
+	s.devSiegeBtn = &Button{
+		Text: "Siege",
+		OnClick: func() {
+			if s.selectedBuildType == "siege" {
+				s.selectedBuildType = ""
+			} else {
+				s.selectedBuildType = "siege"
+			}
+		},
+	}
+	s.devTransportBtn = &Button{
+		Text: "Transport",
+		OnClick: func() {
+			if s.selectedBuildType == "transport" {
+				s.selectedBuildType = ""
+			} else {
+				s.selectedBuildType = "transport"
+			}
+		},
+	}
+	s.devMineBtn = &Button{
+		Text: "Mine",
+		OnClick: func() {
+			if s.selectedBuildType == "mine" {
+				s.selectedBuildType = ""
+			} else {
+				s.selectedBuildType = "mine"
+			}
+		},
+	}
+	s.devLumberMillBtn = &Button{
+		Text: "Lumber Mill",
+		OnClick: func() {
+			if s.selectedBuildType == "lumber_mill" {
+				s.selectedBuildType = ""
+			} else {
+				s.selectedBuildType = "lumber_mill"
+			}
+		},
+	}
+	s.devStableBtn = &Button{
+		Text: "Stable",
+		OnClick: func() {
+			if s.selectedBuildType == "stable" {
+				s.selectedBuildType = ""
+			} else {
+				s.selectedBuildType = "stable"
+			}
+		},
+	}
+	s.devDepotBtn = &Button{
+		Text: "Depot",
+		OnClick: func() {
+			if s.selectedBuildType == "depot" {
+				s.selectedBuildType = ""
+			} else {
+				s.selectedBuildType = "depot"
+			}
+		},
+	}
 	s.devUseGoldBtn = &Button{
 		Text: "[ ] Use Gold",
 		OnClick: func() {
@@ -569,6 +706,20 @@ func NewGameplayScene(game *Game) *GameplayScene {
 			}
 		},
 	}
+	s.devDiscardCardBtn = &Button{
+		Text:    "Discard",
+		Tooltip: "Discard cards for the selected resource: 1 for a common, up to 4 for an ultra-rare",
+		OnClick: func() {
+			s.enterCardSelectionMode("discard", "Discard cards for "+s.cardBuyResource)
+		},
+	}
+	s.devCombineCardsBtn = &Button{
+		Text:    "Combine",
+		Tooltip: "Trade two common cards of the same type for an uncommon one",
+		OnClick: func() {
+			s.enterCardSelectionMode("combine", "Combine two common cards")
+		},
+	}
 	s.dismissCardDrawnBtn = &Button{
 		X: 0, Y: 0, W: 100, H: 35,
 		Text:    "OK",
@@ -662,6 +813,16 @@ func NewGameplayScene(game *Game) *GameplayScene {
 		Text:    "Cancel",
 		OnClick: func() { s.cancelAttackPlan() },
 	}
+	s.raidBtn = &Button{
+		X: 0, Y: 0, W: 150, H: 40,
+		Text:    "Raid",
+		OnClick: func() { s.doRaid() },
+	}
+	s.seaBattleBtn = &Button{
+		X: 0, Y: 0, W: 150, H: 40,
+		Text:    "Sea Battle",
+		OnClick: func() { s.doSeaBattle() },
+	}
 
 	// Attack confirmation buttons
 	s.confirmAttackBtn = &Button{
@@ -772,6 +933,32 @@ func NewGameplayScene(game *Game) *GameplayScene {
 		Text:    "Move Boat",
 		OnClick: func() { s.startShipmentMode("boat") },
 	}
+	s.moveSiegeBtn = &Button{
+		X: 0, Y: 0, W: 200, H: 40,
+		Text:    "Move Siege",
+		OnClick: func() { s.startShipmentMode("siege") },
+	}
+	s.moveTransportBtn = &Button{
+		X: 0, Y: 0, W: 200, H: 40,
+		Text:    "Move Transport",
+		OnClick: func() { s.startShipmentMode("transport") },
+	}
+	s.moveResourcesBtn = &Button{
+		X: 0, Y: 0, W: 200, H: 40,
+		Text:    "Resources",
+		OnClick: func() { s.startShipmentMode("resources") },
+	}
+	s.shipResourcesBtn = &Button{
+		X: 0, Y: 0, W: 120, H: 40,
+		Text:    "Ship",
+		Primary: true,
+		OnClick: func() { s.sendShipResources() },
+	}
+	s.shipCancelBtn = &Button{
+		X: 0, Y: 0, W: 100, H: 40,
+		Text:    "Cancel",
+		OnClick: func() { s.showShipResources = false },
+	}
 	s.cancelShipmentBtn = &Button{
 		X: 0, Y: 0, W: 200, H: 40,
 		Text:    "Cancel",
@@ -812,6 +999,22 @@ func NewGameplayScene(game *Game) *GameplayScene {
 		Text:    "Reject",
 		OnClick: func() { s.rejectTrade() },
 	}
+	s.marketBtn = &Button{
+		X: 0, Y: 0, W: 120, H: 40,
+		Text:    "Market",
+		OnClick: func() { s.showMarket = true; s.marketAmount = 0 },
+	}
+	s.marketTradeBtn = &Button{
+		X: 0, Y: 0, W: 120, H: 40,
+		Text:    "Trade",
+		Primary: true,
+		OnClick: func() { s.sendMarketTrade() },
+	}
+	s.marketCloseBtn = &Button{
+		X: 0, Y: 0, W: 100, H: 40,
+		Text:    "Close",
+		OnClick: func() { s.showMarket = false },
+	}
 	s.tradeResultOkBtn = &Button{
 		X: 0, Y: 0, W: 100, H: 40,
 		Text:    "OK",
@@ -1117,6 +1320,63 @@ func (s *GameplayScene) Update() error {
 		return nil
 	}
 
+	// Handle market popup
+	if s.showMarket {
+		s.marketTradeBtn.Update()
+		s.marketCloseBtn.Update()
+
+		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+			mx, my := ebiten.CursorPosition()
+			panelW, panelH := 460, 420
+			panelX, panelY := ScreenWidth/2-panelW/2, ScreenHeight/2-panelH/2
+
+			// Y positions must match drawMarket exactly
+			sellY := panelY + 50 + 110 + 25
+			buyY := sellY + 100
+			for i, resource := range marketResourceNames {
+				btnX := panelX + 20 + i*105
+				if mx >= btnX && mx < btnX+95 && my >= sellY && my < sellY+30 {
+					s.marketSell = resource
+					s.marketAmount = 0
+				}
+				if mx >= btnX && mx < btnX+95 && my >= buyY && my < buyY+30 {
+					s.marketBuy = resource
+				}
+			}
+
+			if s.marketSell != "" {
+				myStock := s.getMyResource(s.marketSell)
+				s.handleResourceAdjusterClick(mx, my, panelX+20, sellY+40, &s.marketAmount, 0, myStock)
+			}
+		}
+
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			s.showMarket = false
+		}
+		return nil
+	}
+
+	// Handle the resource shipping popup
+	if s.showShipResources {
+		s.shipResourcesBtn.Update()
+		s.shipCancelBtn.Update()
+
+		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+			mx, my := ebiten.CursorPosition()
+			panelX, panelY := shipResourcesPanelPos()
+			from := s.storeAt(s.game.config.PlayerID, s.shipmentFromTerritory)
+			for i, resource := range marketResourceNames {
+				// Positions must match drawShipResources
+				s.handleResourceAdjusterClick(mx, my, panelX+20+i*105, panelY+110, &s.shipAmounts[i], 0, from[resource])
+			}
+		}
+
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			s.showShipResources = false
+		}
+		return nil
+	}
+
 	// Handle waiting for trade response
 	if s.waitingForTrade {
 		// Block all input while waiting
@@ -1128,11 +1388,26 @@ func (s *GameplayScene) Update() error {
 		s.devCityBtn.Update()
 		s.devWeaponBtn.Update()
 		s.devBoatBtn.Update()
+		if s.extendedUnits {
+			s.devFortressBtn.Update()
+			s.devSiegeBtn.Update()
+			s.devTransportBtn.Update()
+		}
+		if s.economyRules {
+			s.devMineBtn.Update()
+			s.devLumberMillBtn.Update()
+			s.devStableBtn.Update()
+		}
+		if s.depotRules {
+			s.devDepotBtn.Update()
+		}
 		s.devUseGoldBtn.Update()
 		// Card combat: update buy card buttons
 		if s.combatMode == "cards" {
 			s.devBuyAttackCardBtn.Update()
 			s.devBuyDefenseCardBtn.Update()
+			s.devDiscardCardBtn.Update()
+			s.devCombineCardsBtn.Update()
 		}
 		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
 			s.selectedBuildType = ""
@@ -1187,6 +1462,12 @@ func (s *GameplayScene) Update() error {
 			s.attackNoReinfBtn.Update()
 		}
 		s.cancelAttackBtn.Update()
+		if s.canRaid(s.attackPlanTarget) {
+			s.raidBtn.Update()
+		}
+		if s.seaBattleWater(s.attackPlanTarget) != "" {
+			s.seaBattleBtn.Update()
+		}
 		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
 			s.cancelAttackPlan()
 		}
@@ -1215,6 +1496,9 @@ func (s *GameplayScene) Update() error {
 		for _, btn := range s.surrenderPlayerBtns {
 			btn.Update()
 		}
+		for _, btn := range s.vassalPlayerBtns {
+			btn.Update()
+		}
 		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
 			s.showAllyMenu = false
 		}
@@ -1228,6 +1512,13 @@ func (s *GameplayScene) Update() error {
 		s.moveStockpileBtn.Update()
 		s.moveHorseBtn.Update()
 		s.moveBoatBtn.Update()
+		if s.extendedUnits {
+			s.moveSiegeBtn.Update()
+			s.moveTransportBtn.Update()
+		}
+		if s.depotRules {
+			s.moveResourcesBtn.Update()
+		}
 		if s.shipmentMode != "" {
 			s.shipmentConfirmBtn.Update()
 			s.cancelShipmentBtn.Update()
@@ -1249,6 +1540,9 @@ func (s *GameplayScene) Update() error {
 	// Update trade button during trade phase
 	if isMyTurn && s.currentPhase == "Trade" {
 		s.proposeTradeBtn.Update()
+		if s.marketRules {
+			s.marketBtn.Update()
+		}
 	}
 
 	// Update Set Ally button (available anytime with 3+ players)
@@ -1337,6 +1631,8 @@ func (s *GameplayScene) isDialogOrAnimationShowing() bool {
 		s.showAllyMenu ||
 		s.showSurrenderConfirm ||
 		s.showTradePropose ||
+		s.showMarket ||
+		s.showShipResources ||
 		s.showColorPicker ||
 		s.showEditTerritory ||
 		s.pendingHorseSelection != "" ||
@@ -1414,6 +1710,12 @@ func (s *GameplayScene) Draw(screen *ebiten.Image) {
 	if s.showTradePropose {
 		s.drawTradePropose(screen)
 	}
+	if s.showMarket {
+		s.drawMarket(screen)
+	}
+	if s.showShipResources {
+		s.drawShipResources(screen)
+	}
 	// Trade incoming is now rendered via drawBottomBarMedium
 	// Trade result and trade waiting are now bottom bar notifications
 	// Draw edit territory dialog
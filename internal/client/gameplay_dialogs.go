@@ -209,7 +209,7 @@ func (s *GameplayScene) drawAttackPlan(screen *ebiten.Image) {
 	if s.attackPreview.DefenderAllyStrength > 0 {
 		defenseStr = fmt.Sprintf("%d (+%d allies)", s.attackPreview.DefenseStrength, s.attackPreview.DefenderAllyStrength)
 	}
-	strengthText := fmt.Sprintf("Atk: %s  vs  Def: %s", attackStr, defenseStr)
+	strengthText := fmt.Sprintf("Atk: %s  vs  Def: %s  (%.0f%% to win)", attackStr, defenseStr, s.attackPreview.WinChance*100)
 	DrawText(screen, strengthText, barX+20, barY+40, ColorTextMuted)
 
 	// === CENTER SECTION: Reinforcements ===
@@ -332,6 +332,25 @@ func (s *GameplayScene) drawAttackPlan(screen *ebiten.Image) {
 	s.cancelAttackBtn.Y = barY + 60
 	s.cancelAttackBtn.Draw(screen)
 
+	// Raid button, left of Plan Attack, when the target holds a stockpile or depot
+	if s.canRaid(s.attackPlanTarget) {
+		s.raidBtn.W = btnWidth
+		s.raidBtn.X = btnX - btnWidth - 10
+		s.raidBtn.Y = barY + 15
+		s.raidBtn.Tooltip = "Steal half the resources there without taking the territory"
+		s.raidBtn.Draw(screen)
+	}
+
+	// Sea Battle button, left of Cancel, when our fleet shares a water body
+	// with the target's boats
+	if s.seaBattleWater(s.attackPlanTarget) != "" {
+		s.seaBattleBtn.W = btnWidth
+		s.seaBattleBtn.X = btnX - btnWidth - 10
+		s.seaBattleBtn.Y = barY + 60
+		s.seaBattleBtn.Tooltip = "Attack the boats there with your fleet; the winner sinks or captures a boat"
+		s.seaBattleBtn.Draw(screen)
+	}
+
 	// Card selection hint (card combat mode only)
 	if s.combatMode == "cards" && len(s.myAttackCards) > 0 {
 		selectedCount := len(s.selectedCardIDs)
@@ -480,11 +499,31 @@ func (s *GameplayScene) showCombatResultAsNotification() {
 	}
 	r := s.combatResult
 	var msg string
-	if r.AttackerWins {
+	if r.Raid && r.AttackerWins {
+		loot := s.formatTradeResources(r.CapturedCoal, r.CapturedGold, r.CapturedIron, r.CapturedTimber, 0)
+		msg = fmt.Sprintf("RAID SUCCESSFUL! %s carried off %s from %s -- Atk: %d vs Def: %d", r.AttackerName, loot, r.TargetName, r.AttackStrength, r.DefenseStrength)
+	} else if r.Raid {
+		msg = fmt.Sprintf("RAID DRIVEN OFF! %s held %s -- Atk: %d vs Def: %d", r.DefenderName, r.TargetName, r.AttackStrength, r.DefenseStrength)
+	} else if r.Naval && r.AttackerWins {
+		fate := "sank"
+		if r.BoatCaptured {
+			fate = "captured"
+		}
+		msg = fmt.Sprintf("SEA BATTLE WON! %s %s a boat off %s -- Fleet: %d vs %d", r.AttackerName, fate, r.TargetName, r.AttackStrength, r.DefenseStrength)
+	} else if r.Naval {
+		msg = fmt.Sprintf("SEA BATTLE LOST! %s sank a boat of %s off %s -- Fleet: %d vs %d", r.DefenderName, r.AttackerName, r.TargetName, r.AttackStrength, r.DefenseStrength)
+	} else if r.AttackerWins {
 		msg = fmt.Sprintf("ATTACK SUCCESSFUL! %s captured %s -- Atk: %d vs Def: %d", r.AttackerName, r.TargetName, r.AttackStrength, r.DefenseStrength)
 	} else {
 		msg = fmt.Sprintf("ATTACK REPULSED! %s defended %s -- Atk: %d vs Def: %d", r.DefenderName, r.TargetName, r.AttackStrength, r.DefenseStrength)
 	}
+	switch r.FreedVassal {
+	case "":
+	case r.AttackerID:
+		msg += fmt.Sprintf(" -- %s breaks free!", r.AttackerName)
+	default:
+		msg += fmt.Sprintf(" -- %s breaks free!", r.DefenderName)
+	}
 	s.showBottomBarNotification(msg, "OK", func() {
 		s.dismissCombatResult()
 	})
@@ -729,6 +768,29 @@ func (s *GameplayScene) cancelAttackPlan() {
 	s.ResetBarHeight()
 }
 
+// doRaid raids the stockpile or depot in the planned attack's target. Raids
+// bring no reinforcements and skip alliance voting, so they go straight out.
+func (s *GameplayScene) doRaid() {
+	if !s.canRaid(s.attackPlanTarget) {
+		return
+	}
+	log.Printf("Raiding %s", s.attackPlanTarget)
+	s.game.Raid(s.attackPlanTarget)
+	s.cancelAttackPlan()
+}
+
+// doSeaBattle attacks the boats in the planned attack's target with our fleet.
+// Like raids, sea battles go straight out.
+func (s *GameplayScene) doSeaBattle() {
+	waterID := s.seaBattleWater(s.attackPlanTarget)
+	if waterID == "" {
+		return
+	}
+	log.Printf("Sea battle off %s in %s", s.attackPlanTarget, waterID)
+	s.game.NavalAttack(s.attackPlanTarget, waterID)
+	s.cancelAttackPlan()
+}
+
 // drawDiplomacyMenu draws the diplomacy menu (alliance + surrender options)
 // Uses two-column layout for player lists to fit 8 players on screen
 func (s *GameplayScene) drawDiplomacyMenu(screen *ebiten.Image) {
@@ -754,19 +816,29 @@ func (s *GameplayScene) drawDiplomacyMenu(screen *ebiten.Image) {
 
 	// Check if current player is eliminated (surrendered)
 	amEliminated := false
+	myOverlord := ""
 	if myPlayer, ok := s.players[s.game.config.PlayerID]; ok {
 		player := myPlayer.(map[string]interface{})
 		if eliminated, ok := player["eliminated"].(bool); ok && eliminated {
 			amEliminated = true
 		}
+		myOverlord, _ = player["overlord"].(string)
+	}
+
+	// Vassals can't take vassals, so only a free player without vassals can offer to serve
+	canServe := !amEliminated && myOverlord == ""
+	for _, playerData := range s.players {
+		if overlord, _ := playerData.(map[string]interface{})["overlord"].(string); overlord == s.game.config.PlayerID {
+			canServe = false
+		}
 	}
 
-	// Two-column layout for player lists
+	// Column layout for player lists
 	// Top section: Current alliance display + 3 mode buttons (2 rows)
-	// Bottom section: Two columns - "Ally with" on left, "Surrender to" on right
+	// Bottom section: "Ally with" on the left, "Surrender to" and "Serve" on the right
 	colWidth := 180
 	colGap := 20
-	menuW := colWidth*2 + colGap + 60 // Two columns + gap + margins
+	menuW := colWidth*3 + colGap*2 + 60 // Three columns + gaps + margins
 
 	// Calculate height based on player count (players shown in columns)
 	topSectionH := 45 + 90                     // Header/current + mode buttons (2 rows)
@@ -799,6 +871,9 @@ func (s *GameplayScene) drawDiplomacyMenu(screen *ebiten.Image) {
 			currentText += s.myAllianceSetting
 		}
 	}
+	if overlord, ok := s.players[myOverlord].(map[string]interface{}); ok {
+		currentText += fmt.Sprintf("  |  Vassal of %s", overlord["name"].(string))
+	}
 	DrawText(screen, currentText, menuX+20, menuY+35, ColorTextMuted)
 
 	// Mode buttons in a row (or two rows if needed)
@@ -834,6 +909,7 @@ func (s *GameplayScene) drawDiplomacyMenu(screen *ebiten.Image) {
 	// Two-column player section
 	leftColX := menuX + 20
 	rightColX := menuX + 20 + colWidth + colGap
+	vassalColX := rightColX + colWidth + colGap
 
 	// Left column: Ally with player
 	if otherPlayerCount > 0 {
@@ -843,6 +919,9 @@ func (s *GameplayScene) drawDiplomacyMenu(screen *ebiten.Image) {
 		if !amEliminated {
 			DrawText(screen, "Surrender to:", rightColX, btnY+5, ColorWarning)
 		}
+		if canServe {
+			DrawText(screen, "Serve as vassal:", vassalColX, btnY+5, ColorWarning)
+		}
 
 		playerBtnY := btnY + 25
 
@@ -850,6 +929,7 @@ func (s *GameplayScene) drawDiplomacyMenu(screen *ebiten.Image) {
 		s.allyPlayerBtns = make([]*Button, 0, otherPlayerCount)
 		s.allyPlayerIDs = make([]string, 0, otherPlayerCount)
 		s.surrenderPlayerBtns = make([]*Button, 0, otherPlayerCount)
+		s.vassalPlayerBtns = make([]*Button, 0, otherPlayerCount)
 
 		for _, playerIDInterface := range s.playerOrder {
 			playerID := playerIDInterface.(string)
@@ -888,6 +968,7 @@ func (s *GameplayScene) drawDiplomacyMenu(screen *ebiten.Image) {
 					surrenderBtn.OnClick = func() {
 						s.surrenderTargetID = pid
 						s.surrenderTargetName = pname
+						s.surrenderAsVassal = false
 						s.showSurrenderConfirm = true
 						s.showAllyMenu = false
 					}
@@ -895,6 +976,26 @@ func (s *GameplayScene) drawDiplomacyMenu(screen *ebiten.Image) {
 					s.surrenderPlayerBtns = append(s.surrenderPlayerBtns, surrenderBtn)
 				}
 
+				// Third column: offer to serve them (not another player's vassal)
+				if canServe {
+					theirOverlord, _ := player["overlord"].(string)
+					vassalBtn := &Button{
+						X: vassalColX, Y: playerBtnY, W: colWidth, H: 32,
+						Text:     playerName,
+						Disabled: theirOverlord != "",
+					}
+					pname := playerName
+					vassalBtn.OnClick = func() {
+						s.surrenderTargetID = pid
+						s.surrenderTargetName = pname
+						s.surrenderAsVassal = true
+						s.showSurrenderConfirm = true
+						s.showAllyMenu = false
+					}
+					vassalBtn.Draw(screen)
+					s.vassalPlayerBtns = append(s.vassalPlayerBtns, vassalBtn)
+				}
+
 				playerBtnY += 40
 			}
 		}
@@ -920,17 +1021,30 @@ func (s *GameplayScene) drawSurrenderConfirm(screen *ebiten.Image) {
 	panelX := ScreenWidth/2 - panelW/2
 	panelY := ScreenHeight/2 - panelH/2
 
-	DrawFancyPanel(screen, panelX, panelY, panelW, panelH, "Confirm Surrender")
-
-	// Warning text
 	y := panelY + 50
-	DrawTextCentered(screen, fmt.Sprintf("Surrender to %s?", s.surrenderTargetName), ScreenWidth/2, y, ColorWarning)
-	y += 30
-	DrawTextCentered(screen, "All your territories and resources", ScreenWidth/2, y, ColorText)
-	y += 20
-	DrawTextCentered(screen, "will be given to them.", ScreenWidth/2, y, ColorText)
-	y += 25
-	DrawTextCentered(screen, "You may continue watching the game.", ScreenWidth/2, y, ColorTextMuted)
+	if s.surrenderAsVassal {
+		DrawFancyPanel(screen, panelX, panelY, panelW, panelH, "Offer Vassalage")
+		DrawTextCentered(screen, fmt.Sprintf("Offer to serve %s?", s.surrenderTargetName), ScreenWidth/2, y, ColorWarning)
+		y += 30
+		DrawTextCentered(screen, fmt.Sprintf("You keep your territories, but pay 1 in %d", game.TributeShare), ScreenWidth/2, y, ColorText)
+		y += 20
+		DrawTextCentered(screen, "of your production and can't attack them.", ScreenWidth/2, y, ColorText)
+		y += 25
+		DrawTextCentered(screen, "Win a battle to break free.", ScreenWidth/2, y, ColorTextMuted)
+		s.confirmSurrenderBtn.Text = "Offer"
+	} else {
+		DrawFancyPanel(screen, panelX, panelY, panelW, panelH, "Confirm Surrender")
+
+		// Warning text
+		DrawTextCentered(screen, fmt.Sprintf("Surrender to %s?", s.surrenderTargetName), ScreenWidth/2, y, ColorWarning)
+		y += 30
+		DrawTextCentered(screen, "All your territories and resources", ScreenWidth/2, y, ColorText)
+		y += 20
+		DrawTextCentered(screen, "will be given to them.", ScreenWidth/2, y, ColorText)
+		y += 25
+		DrawTextCentered(screen, "You may continue watching the game.", ScreenWidth/2, y, ColorTextMuted)
+		s.confirmSurrenderBtn.Text = "Surrender"
+	}
 
 	// Buttons
 	btnY := panelY + panelH - 55
@@ -950,13 +1064,18 @@ func (s *GameplayScene) drawSurrenderConfirm(screen *ebiten.Image) {
 	s.cancelSurrenderBtn.Draw(screen)
 }
 
-// executeSurrender sends the surrender request to the server
+// executeSurrender sends the surrender request, or the offer of vassalage, to the server
 func (s *GameplayScene) executeSurrender() {
 	if s.surrenderTargetID == "" {
 		return
 	}
-	log.Printf("Surrendering to player %s", s.surrenderTargetID)
-	s.game.Surrender(s.surrenderTargetID)
+	if s.surrenderAsVassal {
+		log.Printf("Offering to serve player %s", s.surrenderTargetID)
+		s.game.OfferVassalage(s.surrenderTargetID)
+	} else {
+		log.Printf("Surrendering to player %s", s.surrenderTargetID)
+		s.game.Surrender(s.surrenderTargetID)
+	}
 	s.showSurrenderConfirm = false
 	s.surrenderTargetID = ""
 	s.surrenderTargetName = ""
@@ -1125,6 +1244,23 @@ func (s *GameplayScene) showNextPhaseSkip() {
 	})
 }
 
+// ShowWorldEvent announces a world event in the bottom bar, highlighting the
+// territory it hit. Dismissing it acknowledges the event.
+func (s *GameplayScene) ShowWorldEvent(payload *protocol.WorldEventPayload) {
+	if payload.TerritoryID != "" {
+		s.SetHighlightedTerritories([]TerritoryHighlight{
+			{TerritoryID: payload.TerritoryID, Color: color.RGBA{255, 200, 50, 255}},
+		})
+	}
+
+	eventID := payload.EventID
+	msg := fmt.Sprintf("%s! %s", payload.Name, payload.Description)
+	s.showBottomBarNotification(msg, "OK", func() {
+		s.ClearHighlightedTerritories()
+		s.game.SendClientReady(eventID, protocol.EventWorldEvent)
+	})
+}
+
 // drawPhaseSkip draws the phase skip popup.
 func (s *GameplayScene) drawPhaseSkip(screen *ebiten.Image) {
 	// Semi-transparent overlay
@@ -1370,6 +1506,129 @@ func (s *GameplayScene) drawTradePropose(screen *ebiten.Image) {
 	s.tradeCancelBtn.Draw(screen)
 }
 
+// drawMarket draws the popup for trading with the market.
+func (s *GameplayScene) drawMarket(screen *ebiten.Image) {
+	panelW, panelH := 460, 420
+	centerX, centerY := ScreenWidth/2, ScreenHeight/2
+	panelX, panelY := centerX-panelW/2, centerY-panelH/2
+
+	DrawFancyPanel(screen, panelX, panelY, panelW, panelH, "Market")
+
+	y := panelY + 50
+
+	// Prices: what the bank pays / what it charges
+	DrawText(screen, "Prices (sell / buy):", panelX+20, y, ColorText)
+	y += 25
+	for i, resource := range marketResourceNames {
+		x := panelX + 20 + i*105
+		price := s.marketPrices[resource]
+		DrawText(screen, strings.ToUpper(resource[:1])+resource[1:], x, y, ColorTextMuted)
+		DrawText(screen, fmt.Sprintf("%d / %d", price, price+game.MarketSpread), x, y+20, ColorText)
+	}
+
+	// Resource to sell
+	y = panelY + 50 + 110
+	DrawText(screen, "SELL:", panelX+20, y, ColorSuccess)
+	y += 25
+	s.drawMarketResourceButtons(screen, panelX+20, y, s.marketSell)
+
+	if s.marketSell != "" {
+		s.drawResourceAdjuster(screen, panelX+20, y+40, "Amount", &s.marketAmount, 0, s.getMyResource(s.marketSell))
+	}
+
+	// Resource to buy
+	DrawText(screen, "FOR:", panelX+20, y+75, ColorWarning)
+	y += 100
+	s.drawMarketResourceButtons(screen, panelX+20, y, s.marketBuy)
+
+	got := s.marketQuote(s.marketSell, s.marketAmount, s.marketBuy)
+	if s.marketSell != "" && s.marketBuy != "" && s.marketAmount > 0 {
+		quote := fmt.Sprintf("You get %d %s", got, s.marketBuy)
+		quoteColor := ColorText
+		if got == 0 {
+			quote = "Not enough to buy anything"
+			quoteColor = ColorTextMuted
+		}
+		DrawTextCentered(screen, quote, centerX, y+45, quoteColor)
+	}
+
+	s.marketTradeBtn.X = centerX - 130
+	s.marketTradeBtn.Y = panelY + panelH - 60
+	s.marketTradeBtn.Disabled = got == 0
+	s.marketTradeBtn.Draw(screen)
+
+	s.marketCloseBtn.X = centerX + 30
+	s.marketCloseBtn.Y = panelY + panelH - 60
+	s.marketCloseBtn.Draw(screen)
+}
+
+// shipResourcesPanelPos returns the top left corner of the resource shipping
+// popup.
+func shipResourcesPanelPos() (int, int) {
+	return ScreenWidth/2 - 230, ScreenHeight/2 - 120
+}
+
+// drawShipResources draws the popup for choosing what to ship between a
+// stockpile and a depot.
+func (s *GameplayScene) drawShipResources(screen *ebiten.Image) {
+	panelW, panelH := 460, 240
+	panelX, panelY := shipResourcesPanelPos()
+	DrawFancyPanel(screen, panelX, panelY, panelW, panelH, "Ship Resources")
+
+	fromName, toName := s.shipmentFromTerritory, s.selectedTerritory
+	if terr, ok := s.territories[fromName].(map[string]interface{}); ok {
+		fromName, _ = terr["name"].(string)
+	}
+	if terr, ok := s.territories[toName].(map[string]interface{}); ok {
+		toName, _ = terr["name"].(string)
+	}
+	DrawText(screen, fmt.Sprintf("From %s to %s", fromName, toName), panelX+20, panelY+50, ColorText)
+
+	total := 0
+	for _, n := range s.shipAmounts {
+		total += n
+	}
+	room := -1
+	if s.isDepot(s.game.config.PlayerID, s.selectedTerritory) {
+		held := 0
+		for _, n := range s.storeAt(s.game.config.PlayerID, s.selectedTerritory) {
+			held += n
+		}
+		room = game.DepotCapacity - held
+		DrawText(screen, fmt.Sprintf("The depot has room for %d more", room), panelX+20, panelY+72, ColorTextMuted)
+	}
+
+	from := s.storeAt(s.game.config.PlayerID, s.shipmentFromTerritory)
+	for i, resource := range marketResourceNames {
+		label := fmt.Sprintf("%s (%d)", strings.ToUpper(resource[:1])+resource[1:], from[resource])
+		s.drawResourceAdjuster(screen, panelX+20+i*105, panelY+110, label, &s.shipAmounts[i], 0, from[resource])
+	}
+
+	s.shipResourcesBtn.X = panelX + panelW/2 - 130
+	s.shipResourcesBtn.Y = panelY + panelH - 60
+	s.shipResourcesBtn.Disabled = total == 0 || (room >= 0 && total > room)
+	s.shipResourcesBtn.Draw(screen)
+
+	s.shipCancelBtn.X = panelX + panelW/2 + 30
+	s.shipCancelBtn.Y = panelY + panelH - 60
+	s.shipCancelBtn.Draw(screen)
+}
+
+// drawMarketResourceButtons draws a row of resource buttons, highlighting the
+// selected one. Clicks are handled in Update().
+func (s *GameplayScene) drawMarketResourceButtons(screen *ebiten.Image, x, y int, selected string) {
+	for i, resource := range marketResourceNames {
+		btnX := x + i*105
+		btnColor := ColorPanel
+		if resource == selected {
+			btnColor = ColorSuccess
+		}
+		vector.DrawFilledRect(screen, float32(btnX), float32(y), 95, 30, btnColor, false)
+		vector.StrokeRect(screen, float32(btnX), float32(y), 95, 30, 1, ColorBorder, false)
+		DrawTextCentered(screen, strings.ToUpper(resource[:1])+resource[1:], btnX+47, y+8, ColorText)
+	}
+}
+
 // drawResourceAdjuster draws a resource adjuster (+/- buttons with value).
 // Note: Click handling is done in Update() via handleResourceAdjusterClick().
 func (s *GameplayScene) drawResourceAdjuster(screen *ebiten.Image, x, y int, label string, value *int, min, max int) {
@@ -1531,6 +1790,7 @@ func (s *GameplayScene) StartProductionAnimation(payload *protocol.ProductionRes
 		Productions:            items,
 		StockpileTerritoryID:   payload.StockpileTerritoryID,
 		StockpileTerritoryName: payload.StockpileTerritoryName,
+		EconomySummary:         economySummary(payload),
 	}
 
 	s.productionAnimIndex = 0
@@ -1541,6 +1801,39 @@ func (s *GameplayScene) StartProductionAnimation(payload *protocol.ProductionRes
 	log.Printf("Starting production animation with %d items", len(items))
 }
 
+// economySummary describes what upkeep, storage caps and tribute took from a
+// player's production, or returns "" if they took nothing.
+func economySummary(payload *protocol.ProductionResultsPayload) string {
+	listResources := func(amounts map[string]int) string {
+		parts := []string{}
+		for _, r := range []string{"Coal", "Gold", "Iron", "Timber"} {
+			if n := amounts[r]; n > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", n, r))
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	parts := []string{}
+	if len(payload.Upkeep) > 0 {
+		parts = append(parts, "Upkeep: "+listResources(payload.Upkeep))
+	}
+	if len(payload.Disbanded) > 0 {
+		lost := make([]string, len(payload.Disbanded))
+		for i, d := range payload.Disbanded {
+			lost[i] = d.UnitType + " in " + d.TerritoryName
+		}
+		parts = append(parts, "Disbanded: "+strings.Join(lost, ", "))
+	}
+	if len(payload.Spoiled) > 0 {
+		parts = append(parts, "Over storage: "+listResources(payload.Spoiled))
+	}
+	if len(payload.Tribute) > 0 {
+		parts = append(parts, "Tribute: "+listResources(payload.Tribute))
+	}
+	return strings.Join(parts, "  |  ")
+}
+
 // updateProductionAnimation updates the production animation state.
 func (s *GameplayScene) updateProductionAnimation() {
 	if !s.showProductionAnim || s.productionAnimData == nil {
@@ -1603,6 +1896,9 @@ func (s *GameplayScene) finishProductionAnimation() {
 	// Send acknowledgment to server
 	if s.productionAnimData != nil {
 		s.game.SendClientReady(s.productionAnimData.EventID, protocol.EventProduction)
+		if summary := s.productionAnimData.EconomySummary; summary != "" {
+			s.showBottomBarNotification(summary, "OK", nil)
+		}
 	}
 
 	// Clear animation state
@@ -3317,8 +3613,24 @@ func (s *GameplayScene) updateCardHoverAnimation() {
 					// During attack planning, only attack cards
 					validSelection = newHoveredIsAtk
 				} else {
-					validSelection = (s.cardSelectionMode == "attack" && newHoveredIsAtk) ||
-						(s.cardSelectionMode == "defense" && !newHoveredIsAtk)
+					switch s.cardSelectionMode {
+					case "attack":
+						validSelection = newHoveredIsAtk
+					case "defense":
+						validSelection = !newHoveredIsAtk
+					case "discard":
+						validSelection = true
+					case "combine":
+						// Two commons of one type; a third pick isn't allowed until one is dropped
+						card := s.cardByID(cardID)
+						validSelection = card != nil && card.Rarity == "common" &&
+							(s.selectedCardIDs[cardID] || len(s.selectedCardIDs) < 2)
+						for id := range s.selectedCardIDs {
+							if other := s.cardByID(id); validSelection && other != nil && other.CardType != card.CardType {
+								validSelection = false
+							}
+						}
+					}
 				}
 				if validSelection {
 					if s.selectedCardIDs[cardID] {
@@ -3361,6 +3673,29 @@ func (s *GameplayScene) updateCardHoverAnimation() {
 	}
 }
 
+// cardByID finds a card in the player's hand by ID.
+func (s *GameplayScene) cardByID(cardID string) *CardDisplayInfo {
+	for _, hand := range [][]CardDisplayInfo{s.myAttackCards, s.myDefenseCards} {
+		for i := range hand {
+			if hand[i].ID == cardID {
+				return &hand[i]
+			}
+		}
+	}
+	return nil
+}
+
+// canCombineCards reports whether a hand has two common cards to combine.
+func canCombineCards(hand []CardDisplayInfo) bool {
+	commons := 0
+	for _, c := range hand {
+		if c.Rarity == "common" {
+			commons++
+		}
+	}
+	return commons >= 2
+}
+
 // isCardSelected returns whether a card ID is currently selected in the card hand.
 func (s *GameplayScene) isCardSelected(cardID string) bool {
 	return s.selectedCardIDs[cardID]
@@ -3422,6 +3757,17 @@ func (s *GameplayScene) confirmCardSelection() {
 		log.Printf("Committing defense card selection: %d cards", len(cardIDs))
 		s.game.SelectDefenseCards(cardIDs)
 		s.ClearHighlightedTerritories()
+	} else if s.cardSelectionMode == "discard" {
+		log.Printf("Discarding %d cards for %s", len(cardIDs), s.cardBuyResource)
+		for _, id := range cardIDs {
+			s.game.DiscardCard(id, s.cardBuyResource)
+		}
+	} else if s.cardSelectionMode == "combine" {
+		if len(cardIDs) != 2 {
+			return
+		}
+		log.Printf("Combining cards %v", cardIDs)
+		s.game.CombineCards(cardIDs[0], cardIDs[1])
 	}
 
 	s.exitCardSelectionMode()
@@ -3470,6 +3816,15 @@ func (s *GameplayScene) drawCardSelectionBar(screen *ebiten.Image) {
 	countText := fmt.Sprintf("%d cards selected -- Click cards below to toggle", selectedCount)
 	DrawText(screen, countText, barX+20, barY+45, ColorTextMuted)
 
+	// Managing cards in Development has its own confirm and cancel labels
+	confirmText, skipText := fmt.Sprintf("Play %d Cards", selectedCount), "No Cards"
+	switch s.cardSelectionMode {
+	case "discard":
+		confirmText, skipText = fmt.Sprintf("Discard %d", selectedCount), "Cancel"
+	case "combine":
+		confirmText, skipText = "Combine", "Cancel"
+	}
+
 	// Buttons
 	btnY := barY + 30
 	s.cardSelectConfirmBtn.X = barX + barW - 300
@@ -3477,14 +3832,15 @@ func (s *GameplayScene) drawCardSelectionBar(screen *ebiten.Image) {
 	s.cardSelectConfirmBtn.W = 140
 	s.cardSelectConfirmBtn.H = 35
 	if selectedCount > 0 {
-		s.cardSelectConfirmBtn.Text = fmt.Sprintf("Play %d Cards", selectedCount)
+		s.cardSelectConfirmBtn.Text = confirmText
 	} else {
 		s.cardSelectConfirmBtn.Text = "Confirm"
 	}
-	s.cardSelectConfirmBtn.Disabled = selectedCount == 0
+	s.cardSelectConfirmBtn.Disabled = selectedCount == 0 || (s.cardSelectionMode == "combine" && selectedCount != 2)
 	s.cardSelectConfirmBtn.Update()
 	s.cardSelectConfirmBtn.Draw(screen)
 
+	s.cardSelectSkipBtn.Text = skipText
 	s.cardSelectSkipBtn.X = barX + barW - 150
 	s.cardSelectSkipBtn.Y = btnY
 	s.cardSelectSkipBtn.W = 120
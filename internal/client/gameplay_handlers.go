@@ -5,6 +5,7 @@ import (
 	"log"
 	"sort"
 
+	"lords-of-conquest/internal/game"
 	"lords-of-conquest/internal/protocol"
 )
 
@@ -111,8 +112,12 @@ func (s *GameplayScene) handleShipment(territoryID string) {
 		s.handleStockpileMove(territoryID)
 	case "horse":
 		s.handleHorseMove(territoryID, terr)
-	case "boat":
+	case "boat", "transport":
 		s.handleBoatMove(territoryID, terr)
+	case "siege":
+		s.handleSiegeMove(territoryID, terr)
+	case "resources":
+		s.handleResourcesMove(territoryID)
 	}
 }
 
@@ -123,6 +128,7 @@ func (s *GameplayScene) startShipmentMode(mode string) {
 	s.selectedTerritory = ""
 	s.shipmentCarryHorse = false
 	s.shipmentCarryWeapon = false
+	s.shipmentCarrySiege = false
 	s.shipmentWaterBodyID = ""
 	log.Printf("Started shipment mode: %s", mode)
 }
@@ -134,6 +140,7 @@ func (s *GameplayScene) cancelShipmentMode() {
 	s.selectedTerritory = ""
 	s.shipmentCarryHorse = false
 	s.shipmentCarryWeapon = false
+	s.shipmentCarrySiege = false
 	s.shipmentWaterBodyID = ""
 }
 
@@ -177,25 +184,62 @@ func (s *GameplayScene) handleHorseMove(tid string, terr map[string]interface{})
 	}
 }
 
-// handleBoatMove handles boat movement selection.
+// handleSiegeMove handles siege engine movement selection.
+func (s *GameplayScene) handleSiegeMove(tid string, terr map[string]interface{}) {
+	if s.shipmentFromTerritory == "" {
+		// First click - select source territory with siege engines
+		if engines, _ := terr["siegeEngines"].(float64); engines == 0 {
+			log.Printf("No siege engines in %s", tid)
+			return
+		}
+		s.shipmentFromTerritory = tid
+		log.Printf("Selected siege engine from %s", tid)
+	} else {
+		// Second click - select destination
+		s.selectedTerritory = tid
+	}
+}
+
+// handleResourcesMove handles choosing the stockpile or depot to ship
+// resources from, then the one to ship them to.
+func (s *GameplayScene) handleResourcesMove(tid string) {
+	if s.storeAt(s.game.config.PlayerID, tid) == nil {
+		log.Printf("No stockpile or depot in %s", tid)
+		return
+	}
+	if s.shipmentFromTerritory == "" {
+		s.shipmentFromTerritory = tid
+		log.Printf("Selected resources from %s", tid)
+	} else if tid != s.shipmentFromTerritory {
+		s.selectedTerritory = tid
+	}
+}
+
+// handleBoatMove handles boat and transport movement selection.
 func (s *GameplayScene) handleBoatMove(tid string, terr map[string]interface{}) {
 	if s.shipmentFromTerritory == "" {
 		// First click - select source territory with boat
-		totalBoats, _ := terr["totalBoats"].(float64)
+		fleetKey := "boats"
+		if s.shipmentMode == "transport" {
+			fleetKey = "transports"
+		}
+		totalBoats := 0.0
+		fleet, _ := terr[fleetKey].(map[string]interface{})
+		for _, count := range fleet {
+			c, _ := count.(float64)
+			totalBoats += c
+		}
 		if totalBoats == 0 {
-			log.Printf("No boats in %s", tid)
+			log.Printf("No %s in %s", fleetKey, tid)
 			return
 		}
 		s.shipmentFromTerritory = tid
 
 		// Get the water body ID for the boat
-		boats, ok := terr["boats"].(map[string]interface{})
-		if ok {
-			for waterID, count := range boats {
-				if c, _ := count.(float64); c > 0 {
-					s.shipmentWaterBodyID = waterID
-					break
-				}
+		for waterID, count := range fleet {
+			if c, _ := count.(float64); c > 0 {
+				s.shipmentWaterBodyID = waterID
+				break
 			}
 		}
 
@@ -204,6 +248,7 @@ func (s *GameplayScene) handleBoatMove(tid string, terr map[string]interface{})
 		hasWeapon, _ := terr["hasWeapon"].(bool)
 		s.shipmentCarryHorse = hasHorse
 		s.shipmentCarryWeapon = hasWeapon
+		s.shipmentCarrySiege = s.shipmentMode == "transport"
 		log.Printf("Selected boat from %s (water body: %s)", tid, s.shipmentWaterBodyID)
 	} else {
 		// Second click - select destination
@@ -223,6 +268,16 @@ func (s *GameplayScene) confirmShipment() {
 		log.Printf("Moving stockpile to %s", s.selectedTerritory)
 		s.game.MoveStockpile(s.selectedTerritory)
 
+	case "resources":
+		if s.shipmentFromTerritory == "" {
+			log.Printf("No source territory selected")
+			return
+		}
+		// Choose what to ship before sending anything
+		s.shipAmounts = [4]int{}
+		s.showShipResources = true
+		return
+
 	case "horse":
 		if s.shipmentFromTerritory == "" {
 			log.Printf("No source territory selected")
@@ -231,18 +286,30 @@ func (s *GameplayScene) confirmShipment() {
 		log.Printf("Moving horse from %s to %s (carry weapon: %v)",
 			s.shipmentFromTerritory, s.selectedTerritory, s.shipmentCarryWeapon)
 		s.game.MoveUnit("horse", s.shipmentFromTerritory, s.selectedTerritory,
-			"", false, s.shipmentCarryWeapon)
+			"", false, s.shipmentCarryWeapon, 0)
 
-	case "boat":
+	case "siege":
 		if s.shipmentFromTerritory == "" {
 			log.Printf("No source territory selected")
 			return
 		}
-		log.Printf("Moving boat from %s to %s (water: %s, carry horse: %v, weapon: %v)",
-			s.shipmentFromTerritory, s.selectedTerritory, s.shipmentWaterBodyID,
-			s.shipmentCarryHorse, s.shipmentCarryWeapon)
-		s.game.MoveUnit("boat", s.shipmentFromTerritory, s.selectedTerritory,
-			s.shipmentWaterBodyID, s.shipmentCarryHorse, s.shipmentCarryWeapon)
+		log.Printf("Moving siege engine from %s to %s", s.shipmentFromTerritory, s.selectedTerritory)
+		s.game.MoveUnit("siege", s.shipmentFromTerritory, s.selectedTerritory, "", false, false, 0)
+
+	case "boat", "transport":
+		if s.shipmentFromTerritory == "" {
+			log.Printf("No source territory selected")
+			return
+		}
+		carrySiege := 0
+		if s.shipmentCarrySiege {
+			carrySiege = s.transportSiegeLoad()
+		}
+		log.Printf("Moving %s from %s to %s (water: %s, carry horse: %v, weapon: %v, siege: %d)",
+			s.shipmentMode, s.shipmentFromTerritory, s.selectedTerritory, s.shipmentWaterBodyID,
+			s.shipmentCarryHorse, s.shipmentCarryWeapon, carrySiege)
+		s.game.MoveUnit(s.shipmentMode, s.shipmentFromTerritory, s.selectedTerritory,
+			s.shipmentWaterBodyID, s.shipmentCarryHorse, s.shipmentCarryWeapon, carrySiege)
 	}
 
 	// Reset shipment state
@@ -251,9 +318,41 @@ func (s *GameplayScene) confirmShipment() {
 	s.selectedTerritory = ""
 	s.shipmentCarryHorse = false
 	s.shipmentCarryWeapon = false
+	s.shipmentCarrySiege = false
 	s.shipmentWaterBodyID = ""
 }
 
+// sendShipResources ships the amounts chosen in the popup and ends the
+// shipment selection.
+func (s *GameplayScene) sendShipResources() {
+	a := s.shipAmounts
+	log.Printf("Shipping resources from %s to %s: %v", s.shipmentFromTerritory, s.selectedTerritory, a)
+	s.game.ShipResources(s.shipmentFromTerritory, s.selectedTerritory, a[0], a[1], a[2], a[3])
+	s.showShipResources = false
+	s.cancelShipmentMode()
+}
+
+// transportSiegeLoad returns how many siege engines the selected transport
+// can take: as many as are there, in the room the horse and weapon leave.
+func (s *GameplayScene) transportSiegeLoad() int {
+	terr, ok := s.territories[s.shipmentFromTerritory].(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	engines, _ := terr["siegeEngines"].(float64)
+	room := game.TransportCapacity
+	if hasHorse, _ := terr["hasHorse"].(bool); hasHorse && s.shipmentCarryHorse {
+		room--
+	}
+	if hasWeapon, _ := terr["hasWeapon"].(bool); hasWeapon && s.shipmentCarryWeapon {
+		room--
+	}
+	if int(engines) < room {
+		return int(engines)
+	}
+	return room
+}
+
 func (s *GameplayScene) handleConquest(territoryID string) {
 	// Check if it's our turn
 	if s.currentTurn != s.game.config.PlayerID {
@@ -281,7 +380,7 @@ func (s *GameplayScene) handleDevelopment(territoryID string) {
 
 	// Must have a build type selected first
 	if s.selectedBuildType == "" {
-		log.Printf("Select what to build first (City, Weapon, or Boat)")
+		log.Printf("Select what to build first (City, Weapon, Boat or an extended unit)")
 		return
 	}
 
@@ -294,7 +393,7 @@ func (s *GameplayScene) handleDevelopment(territoryID string) {
 		}
 
 		// For boats, check if we need to select a water body
-		if s.selectedBuildType == "boat" {
+		if s.selectedBuildType == "boat" || s.selectedBuildType == "transport" {
 			if waterBodies, ok := terr["waterBodies"].([]interface{}); ok && len(waterBodies) > 1 {
 				// Multiple water bodies - show selection UI
 				s.waterBodyOptions = make([]string, len(waterBodies))
@@ -325,8 +424,8 @@ func (s *GameplayScene) doBuildBoatInWater(waterBodyID string) {
 
 	// Use the gold toggle setting
 	useGold := s.buildUseGold
-	log.Printf("Building boat at %s in water body %s (useGold: %v)", s.buildMenuTerritory, waterBodyID, useGold)
-	s.game.BuildBoatInWater(s.buildMenuTerritory, waterBodyID, useGold)
+	log.Printf("Building %s at %s in water body %s (useGold: %v)", s.selectedBuildType, s.buildMenuTerritory, waterBodyID, useGold)
+	s.game.BuildBoatInWater(s.selectedBuildType, s.buildMenuTerritory, waterBodyID, useGold)
 	s.showWaterBodySelect = false
 	s.waterBodyOptions = nil
 	s.buildMenuTerritory = ""
@@ -416,8 +515,12 @@ func (s *GameplayScene) calculateCombatStrength(targetTID string) (attack, defen
 	targetOwner := target["owner"].(string)
 	myID := s.game.config.PlayerID
 
-	// Defense: 1 for the territory itself
+	// Defense: 1 for the territory itself, plus its terrain with terrain rules on
 	defense = 1
+	if terrainName, ok := target["terrain"].(string); ok && s.terrainRules {
+		terrain, _ := game.ParseTerrain(terrainName)
+		defense += terrain.DefenseBonus()
+	}
 
 	// Add target's buildings and units
 	defense += s.getTerritoryStrength(target)
@@ -432,7 +535,20 @@ func (s *GameplayScene) calculateCombatStrength(targetTID string) (attack, defen
 		}
 	}
 
+	// Fortresses defend the territory they stand in
+	fortresses := 0
+	if f, ok := target["fortresses"].(float64); ok && s.extendedUnits {
+		fortresses = int(f)
+		defense += fortresses * game.FortressDefense
+	}
+
+	// Neutral garrisons hold their territory
+	if garrison, ok := target["garrison"].(float64); ok {
+		defense += int(garrison)
+	}
+
 	// Attack: count our adjacent territories
+	siegeEngines := 0
 	for _, terrData := range s.territories {
 		terr := terrData.(map[string]interface{})
 		if terr["owner"].(string) != myID {
@@ -444,9 +560,18 @@ func (s *GameplayScene) calculateCombatStrength(targetTID string) (attack, defen
 		if s.isAdjacent(terrID, targetTID) {
 			attack++ // Territory contribution
 			attack += s.getTerritoryStrength(terr)
+			if n, ok := terr["siegeEngines"].(float64); ok {
+				siegeEngines += int(n)
+			}
 		}
 	}
 
+	// Each adjacent siege engine cancels one fortress
+	if siegeEngines > fortresses {
+		siegeEngines = fortresses
+	}
+	attack += siegeEngines * game.FortressDefense
+
 	// Count defender's adjacent territories (only if territory has an owner)
 	// Unclaimed territories don't get reinforcements from other unclaimed territories
 	if targetOwner == "" {
@@ -615,6 +740,30 @@ func (s *GameplayScene) completeSendTradeOffer() {
 	s.showBottomBarNotification("Waiting for trade response...", "", nil)
 }
 
+// marketResourceNames are the resources the market deals in, in display order.
+var marketResourceNames = []string{"coal", "gold", "iron", "timber"}
+
+// marketQuote returns how much of the buy resource the bank gives for the
+// amount of the sell resource at current prices.
+func (s *GameplayScene) marketQuote(sell string, amount int, buy string) int {
+	buyPrice := s.marketPrices[buy] + game.MarketSpread
+	if sell == "" || buy == "" || sell == buy || buyPrice <= 0 {
+		return 0
+	}
+	return amount * s.marketPrices[sell] / buyPrice
+}
+
+// sendMarketTrade sends the market trade to the server. The popup stays
+// open so the player can keep trading.
+func (s *GameplayScene) sendMarketTrade() {
+	if s.marketQuote(s.marketSell, s.marketAmount, s.marketBuy) == 0 {
+		return
+	}
+	log.Printf("Selling %d %s to the market for %s", s.marketAmount, s.marketSell, s.marketBuy)
+	s.game.MarketTrade(s.marketSell, s.marketAmount, s.marketBuy)
+	s.marketAmount = 0
+}
+
 // acceptTrade accepts an incoming trade proposal.
 func (s *GameplayScene) acceptTrade() {
 	if s.tradeProposal == nil {
@@ -735,6 +884,36 @@ func (s *GameplayScene) ShowTradeResult(payload *protocol.TradeResultPayload) {
 	s.showBottomBarNotification(msg, "OK", nil)
 }
 
+// ShowVassalageOffer asks whether to take on a player who offers to serve us.
+func (s *GameplayScene) ShowVassalageOffer(payload *protocol.VassalageOfferPayload) {
+	vassalID := payload.VassalID
+	answer := func(accept bool) {
+		s.game.AnswerVassalage(vassalID, accept)
+		s.clearBottomBarMedium()
+	}
+	s.showBottomBarMedium("vassalage_offer",
+		fmt.Sprintf("%s offers to become your vassal", payload.VassalName),
+		fmt.Sprintf("They would pay 1 in %d of what they produce as tribute and couldn't attack you", game.TributeShare),
+		[]*Button{
+			{Text: "Accept", W: 100, H: 35, Primary: true,
+				OnClick: func() { answer(true) },
+			},
+			{Text: "Decline", W: 100, H: 35,
+				OnClick: func() { answer(false) },
+			},
+		},
+	)
+}
+
+// ShowVassalageResult reports the answer to an offer of vassalage.
+func (s *GameplayScene) ShowVassalageResult(payload *protocol.VassalageResultPayload) {
+	msg := fmt.Sprintf("%s turned down %s's offer to serve.", payload.OverlordName, payload.VassalName)
+	if payload.Result == protocol.VassalageSworn {
+		msg = fmt.Sprintf("%s is now the vassal of %s.", payload.VassalName, payload.OverlordName)
+	}
+	s.showBottomBarNotification(msg, "OK", nil)
+}
+
 // getOnlinePlayers returns a list of online player IDs (excluding self and AI).
 func (s *GameplayScene) getOnlinePlayers() []string {
 	players := make([]string, 0)
@@ -774,6 +953,22 @@ func (s *GameplayScene) getPlayerHorseTerritories() []string {
 	return terrs
 }
 
+// getMyResource returns how much of one resource the current player has.
+func (s *GameplayScene) getMyResource(resource string) int {
+	coal, gold, iron, timber := s.getMyStockpile()
+	switch resource {
+	case "coal":
+		return coal
+	case "gold":
+		return gold
+	case "iron":
+		return iron
+	case "timber":
+		return timber
+	}
+	return 0
+}
+
 // getMyStockpile returns the current player's stockpile resources.
 func (s *GameplayScene) getMyStockpile() (coal, gold, iron, timber int) {
 	if myPlayer, ok := s.players[s.game.config.PlayerID]; ok {
@@ -796,6 +991,93 @@ func (s *GameplayScene) getMyStockpile() (coal, gold, iron, timber int) {
 	return
 }
 
+// storeAt returns the resources a player keeps in a territory, from their
+// stockpile or a depot, or nil if they keep none there.
+func (s *GameplayScene) storeAt(playerID, tid string) map[string]int {
+	pData, ok := s.players[playerID].(map[string]interface{})
+	if !ok || tid == "" {
+		return nil
+	}
+	var store map[string]interface{}
+	if stockpileTerr, _ := pData["stockpileTerritory"].(string); stockpileTerr == tid {
+		store, _ = pData["stockpile"].(map[string]interface{})
+	} else if depots, ok := pData["depots"].(map[string]interface{}); ok {
+		store, _ = depots[tid].(map[string]interface{})
+	}
+	if store == nil {
+		return nil
+	}
+	amounts := make(map[string]int)
+	for _, resource := range marketResourceNames {
+		v, _ := store[resource].(float64)
+		amounts[resource] = int(v)
+	}
+	return amounts
+}
+
+// isDepot reports whether a player has a depot in a territory.
+func (s *GameplayScene) isDepot(playerID, tid string) bool {
+	pData, _ := s.players[playerID].(map[string]interface{})
+	depots, _ := pData["depots"].(map[string]interface{})
+	_, ok := depots[tid]
+	return ok
+}
+
+// canRaid reports whether an enemy keeps a stockpile or depot in a territory
+// we could raid.
+func (s *GameplayScene) canRaid(tid string) bool {
+	if !s.depotRules || tid == "" {
+		return false
+	}
+	terr, ok := s.territories[tid].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	owner, _ := terr["owner"].(string)
+	if owner == "" || owner == s.game.config.PlayerID {
+		return false
+	}
+	return s.storeAt(owner, tid) != nil
+}
+
+// seaBattleWater returns a water body where an enemy territory keeps boats
+// that our fleet could attack, or "" if there is none.
+func (s *GameplayScene) seaBattleWater(tid string) string {
+	if !s.navalRules || tid == "" {
+		return ""
+	}
+	terr, ok := s.territories[tid].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	owner, _ := terr["owner"].(string)
+	if owner == "" || owner == s.game.config.PlayerID {
+		return ""
+	}
+	boats, _ := terr["boats"].(map[string]interface{})
+	waters := make([]string, 0, len(boats))
+	for waterID, count := range boats {
+		if c, _ := count.(float64); c > 0 {
+			waters = append(waters, waterID)
+		}
+	}
+	sort.Strings(waters)
+
+	for _, waterID := range waters {
+		for _, tData := range s.territories {
+			t, ok := tData.(map[string]interface{})
+			if !ok || t["owner"] != s.game.config.PlayerID {
+				continue
+			}
+			ours, _ := t["boats"].(map[string]interface{})
+			if c, _ := ours[waterID].(float64); c > 0 {
+				return waterID
+			}
+		}
+	}
+	return ""
+}
+
 // getPlayerStockpile returns a player's stockpile resources.
 func (s *GameplayScene) getPlayerStockpile(playerID string) (coal, gold, iron, timber int) {
 	if pData, ok := s.players[playerID]; ok {
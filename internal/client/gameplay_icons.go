@@ -81,6 +81,12 @@ func (s *GameplayScene) drawIconFallback(screen *ebiten.Image, iconType, param s
 		s.drawBoatIconFallback(screen, x, y, size, count)
 	case "stockpile":
 		s.drawStockpileIconFallback(screen, param, x, y, size)
+	case "depot":
+		s.drawDepotIconFallback(screen, x, y, size)
+	case "fortress":
+		s.drawFortressIconFallback(screen, x, y, size)
+	case "siege":
+		s.drawSiegeIconFallback(screen, x, y, size)
 	}
 }
 
@@ -275,6 +281,47 @@ func (s *GameplayScene) drawStockpileIconFallback(screen *ebiten.Image, playerID
 	vector.StrokeLine(screen, x+size, y, x, y+size, 1, borderColor, false)
 }
 
+// drawDepotIconFallback draws a depot icon: two small crates side by side
+func (s *GameplayScene) drawDepotIconFallback(screen *ebiten.Image, x, y, size float32) {
+	crateColor := color.RGBA{170, 130, 70, 255}
+	borderColor := color.RGBA{120, 80, 30, 255}
+
+	crate := size * 0.45
+	for _, cx := range []float32{x, x + size - crate} {
+		cy := y + size - crate
+		vector.DrawFilledRect(screen, cx, cy, crate, crate, crateColor, false)
+		vector.StrokeRect(screen, cx, cy, crate, crate, 1, borderColor, false)
+		vector.StrokeLine(screen, cx, cy, cx+crate, cy+crate, 1, borderColor, false)
+	}
+}
+
+// drawFortressIconFallback draws a fortress icon
+func (s *GameplayScene) drawFortressIconFallback(screen *ebiten.Image, x, y, size float32) {
+	wallColor := color.RGBA{150, 150, 160, 255}
+	borderColor := color.RGBA{80, 80, 90, 255}
+
+	// Wall with three battlements on top
+	wallY := y + size*0.35
+	vector.DrawFilledRect(screen, x+size*0.1, wallY, size*0.8, size*0.55, wallColor, false)
+	vector.StrokeRect(screen, x+size*0.1, wallY, size*0.8, size*0.55, 1, borderColor, false)
+	for i := 0; i < 3; i++ {
+		bx := x + size*0.1 + float32(i)*size*0.3
+		vector.DrawFilledRect(screen, bx, y+size*0.15, size*0.2, size*0.2, wallColor, false)
+	}
+}
+
+// drawSiegeIconFallback draws a siege engine icon: a wheeled frame with a
+// throwing arm
+func (s *GameplayScene) drawSiegeIconFallback(screen *ebiten.Image, x, y, size float32) {
+	woodColor := color.RGBA{120, 80, 40, 255}
+
+	baseY := y + size*0.7
+	vector.StrokeLine(screen, x+size*0.15, baseY, x+size*0.85, baseY, 2, woodColor, false)
+	vector.StrokeLine(screen, x+size*0.5, baseY, x+size*0.8, y+size*0.15, 2, woodColor, false)
+	vector.DrawFilledCircle(screen, x+size*0.25, baseY+size*0.1, size*0.1, woodColor, false)
+	vector.DrawFilledCircle(screen, x+size*0.75, baseY+size*0.1, size*0.1, woodColor, false)
+}
+
 // findTerritoryCenter finds the center cell of a territory
 func (s *GameplayScene) findTerritoryCenter(territoryID string, grid []interface{}) (int, int) {
 	// Extract numeric ID from "t1", "t2", etc.
@@ -3,6 +3,7 @@ package client
 import (
 	"fmt"
 	"image/color"
+	"math"
 
 	"lords-of-conquest/internal/game"
 
@@ -51,7 +52,9 @@ func (s *GameplayScene) drawMap(screen *ebiten.Image) {
 			// Land - get owner color
 			if terr, ok := s.territories[tid].(map[string]interface{}); ok {
 				owner := terr["owner"].(string)
-				if owner != "" {
+				if owner == game.BarbarianID {
+					cellColor = BarbarianColor
+				} else if owner != "" {
 					if player, ok := s.players[owner].(map[string]interface{}); ok {
 						playerColor := player["color"].(string)
 						if pc, ok := PlayerColors[playerColor]; ok {
@@ -103,10 +106,11 @@ func (s *GameplayScene) drawMap(screen *ebiten.Image) {
 			if getTerritoryAt(x+1, y) != territoryID {
 				rightInset = borderInset
 			}
-			if getTerritoryAt(x, y-1) != territoryID {
+			upperLeft, upperRight := s.verticalNeighbors(x, y)
+			if getTerritoryAt(upperLeft, y-1) != territoryID || getTerritoryAt(upperRight, y-1) != territoryID {
 				topInset = borderInset
 			}
-			if getTerritoryAt(x, y+1) != territoryID {
+			if getTerritoryAt(upperLeft, y+1) != territoryID || getTerritoryAt(upperRight, y+1) != territoryID {
 				bottomInset = borderInset
 			}
 
@@ -209,7 +213,11 @@ func (s *GameplayScene) drawMap(screen *ebiten.Image) {
 	}
 
 	// Draw territory boundaries
-	s.drawTerritoryBoundaries(screen, width, height, grid)
+	if s.hexMap() {
+		s.drawHexBoundaries(screen, width, height, grid)
+	} else {
+		s.drawTerritoryBoundaries(screen, width, height, grid)
+	}
 
 	// Draw territory icons (resources, buildings, units - but not boats)
 	s.drawTerritoryIcons(screen)
@@ -217,6 +225,9 @@ func (s *GameplayScene) drawMap(screen *ebiten.Image) {
 	// Draw boats in water cells
 	s.drawBoatsInWater(screen)
 
+	// Label named seas
+	s.drawSeaNames(screen)
+
 	// Draw pulsing highlights on selected territories
 	s.drawTerritoryHighlights(screen, width, height, grid)
 }
@@ -326,6 +337,46 @@ func (s *GameplayScene) drawTerritoryBoundaries(screen *ebiten.Image, width, hei
 	}
 }
 
+// drawHexBoundaries draws lines between different territories on a hex map.
+// Each cell draws its right edge and the halves of its bottom edge that face
+// a different territory; the offset rows don't leave room for rounded corners.
+func (s *GameplayScene) drawHexBoundaries(screen *ebiten.Image, width, height int, grid []interface{}) {
+	borderColor := color.RGBA{0, 0, 0, 220}
+	lineWidth := float32(4)
+
+	getTerritoryAt := func(x, y int) int {
+		if x < 0 || x >= width || y < 0 || y >= height {
+			return -1
+		}
+		row := grid[y].([]interface{})
+		return int(row[x].(float64))
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			territoryID := getTerritoryAt(x, y)
+			sx, sy := s.gridToScreen(x, y)
+			left := float32(sx)
+			mid := float32(sx + s.cellSize/2)
+			right := float32(sx + s.cellSize)
+			top := float32(sy)
+			bottom := float32(sy + s.cellSize)
+
+			if getTerritoryAt(x+1, y) != territoryID {
+				vector.StrokeLine(screen, right, top, right, bottom, lineWidth, borderColor, false)
+			}
+
+			lowerLeft, lowerRight := s.verticalNeighbors(x, y)
+			if getTerritoryAt(lowerLeft, y+1) != territoryID {
+				vector.StrokeLine(screen, left, bottom, mid, bottom, lineWidth, borderColor, false)
+			}
+			if getTerritoryAt(lowerRight, y+1) != territoryID {
+				vector.StrokeLine(screen, mid, bottom, right, bottom, lineWidth, borderColor, false)
+			}
+		}
+	}
+}
+
 // drawCornerArc draws a rounded corner arc at the specified position
 func (s *GameplayScene) drawCornerArc(screen *ebiten.Image, cx, cy, radius, lineWidth float32, col color.RGBA, tl, tr, bl, br int) {
 	segments := 6 // Number of segments for the arc
@@ -397,12 +448,18 @@ func (s *GameplayScene) drawTerritoryIcons(screen *ebiten.Image) {
 
 	// Build a map of stockpile territories for quick lookup
 	stockpileTerritories := make(map[string]string) // territory ID -> player ID
+	depotTerritories := make(map[string]bool)
 	if s.players != nil {
 		for playerID, playerData := range s.players {
 			player := playerData.(map[string]interface{})
 			if stockpileTerr, ok := player["stockpileTerritory"]; ok && stockpileTerr != nil && stockpileTerr != "" {
 				stockpileTerritories[stockpileTerr.(string)] = playerID
 			}
+			if depots, ok := player["depots"].(map[string]interface{}); ok {
+				for tid := range depots {
+					depotTerritories[tid] = true
+				}
+			}
 		}
 	}
 
@@ -428,6 +485,9 @@ func (s *GameplayScene) drawTerritoryIcons(screen *ebiten.Image) {
 		if playerID, hasStockpile := stockpileTerritories[terrID]; hasStockpile {
 			icons = append(icons, iconInfo{"stockpile", playerID})
 		}
+		if depotTerritories[terrID] {
+			icons = append(icons, iconInfo{"depot", ""})
+		}
 
 		// City is shown via diagonal shading on the territory, not as an icon
 
@@ -441,6 +501,18 @@ func (s *GameplayScene) drawTerritoryIcons(screen *ebiten.Image) {
 			icons = append(icons, iconInfo{"horse", ""})
 		}
 
+		// Extended units: one icon per fortress and siege engine
+		if fortresses, ok := terr["fortresses"].(float64); ok {
+			for i := 0; i < int(fortresses); i++ {
+				icons = append(icons, iconInfo{"fortress", ""})
+			}
+		}
+		if engines, ok := terr["siegeEngines"].(float64); ok {
+			for i := 0; i < int(engines); i++ {
+				icons = append(icons, iconInfo{"siege", ""})
+			}
+		}
+
 		// Resource - show two icons if there's city influence (doubles production)
 		if resource, ok := terr["resource"].(string); ok && resource != "None" && resource != "" && resource != "Grassland" {
 			icons = append(icons, iconInfo{"resource", resource})
@@ -650,6 +722,59 @@ func (s *GameplayScene) drawBoatsInWater(screen *ebiten.Image) {
 	}
 }
 
+// drawSeaNames writes each named water body's name on the water cell
+// nearest its middle. Tiny lakes are left unlabelled to keep the map clear.
+func (s *GameplayScene) drawSeaNames(screen *ebiten.Image) {
+	waterBodies, ok := s.mapData["waterBodies"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	labelColor := color.RGBA{200, 225, 250, 190}
+
+	for _, wbData := range waterBodies {
+		wb, ok := wbData.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := wb["name"].(string)
+		cellsData, _ := wb["cells"].([]interface{})
+		if name == "" || len(cellsData) < 4 {
+			continue
+		}
+
+		cells := make([][2]int, 0, len(cellsData))
+		sumX, sumY := 0, 0
+		for _, c := range cellsData {
+			cell, ok := c.([]interface{})
+			if !ok || len(cell) < 2 {
+				continue
+			}
+			x, y := int(cell[0].(float64)), int(cell[1].(float64))
+			cells = append(cells, [2]int{x, y})
+			sumX += x
+			sumY += y
+		}
+		if len(cells) == 0 {
+			continue
+		}
+
+		// A bay can curve around land, so use the water cell closest to the
+		// average position rather than the average itself
+		cx, cy := float64(sumX)/float64(len(cells)), float64(sumY)/float64(len(cells))
+		best := cells[0]
+		bestDist := math.MaxFloat64
+		for _, c := range cells {
+			dx, dy := float64(c[0])-cx, float64(c[1])-cy
+			if d := dx*dx + dy*dy; d < bestDist {
+				best, bestDist = c, d
+			}
+		}
+
+		sx, sy := s.gridToScreen(best[0], best[1])
+		DrawTextCentered(screen, name, sx+s.cellSize/2, sy+s.cellSize/2, labelColor)
+	}
+}
+
 // drawBoatInWaterCell draws a single boat icon in a water cell with the owner's color
 func (s *GameplayScene) drawBoatInWaterCell(screen *ebiten.Image, cellX, cellY float32, boatColor color.RGBA) {
 	cellSize := float32(s.cellSize)
@@ -806,10 +931,19 @@ func (s *GameplayScene) drawTerritoryHighlights(screen *ebiten.Image, width, hei
 				fx := float32(sx)
 				fy := float32(sy)
 
+				// On hex maps the cells above and below are half a cell
+				// across, so the top and bottom edges are drawn in halves
+				upperLeft, upperRight := s.verticalNeighbors(x, y)
+				topLeft := getTerritoryAt(upperLeft, y-1) != numID
+				topRight := getTerritoryAt(upperRight, y-1) != numID
+				bottomLeft := getTerritoryAt(upperLeft, y+1) != numID
+				bottomRight := getTerritoryAt(upperRight, y+1) != numID
+
 				left := getTerritoryAt(x-1, y) != numID
 				right := getTerritoryAt(x+1, y) != numID
-				top := getTerritoryAt(x, y-1) != numID
-				bottom := getTerritoryAt(x, y+1) != numID
+				top := topLeft || topRight
+				bottom := bottomLeft || bottomRight
+				half := cs / 2
 
 				// Draw edge on each side that borders a different territory
 				if left {
@@ -818,11 +952,17 @@ func (s *GameplayScene) drawTerritoryHighlights(screen *ebiten.Image, width, hei
 				if right {
 					vector.StrokeLine(screen, fx+cs, fy, fx+cs, fy+cs, lineWidth, hlColor, false)
 				}
-				if top {
-					vector.StrokeLine(screen, fx, fy, fx+cs, fy, lineWidth, hlColor, false)
+				if topLeft {
+					vector.StrokeLine(screen, fx, fy, fx+half, fy, lineWidth, hlColor, false)
 				}
-				if bottom {
-					vector.StrokeLine(screen, fx, fy+cs, fx+cs, fy+cs, lineWidth, hlColor, false)
+				if topRight {
+					vector.StrokeLine(screen, fx+half, fy, fx+cs, fy, lineWidth, hlColor, false)
+				}
+				if bottomLeft {
+					vector.StrokeLine(screen, fx, fy+cs, fx+half, fy+cs, lineWidth, hlColor, false)
+				}
+				if bottomRight {
+					vector.StrokeLine(screen, fx+half, fy+cs, fx+cs, fy+cs, lineWidth, hlColor, false)
 				}
 
 				// Draw rounded corners where two border edges meet
@@ -926,6 +1066,9 @@ func (s *GameplayScene) AutoPanToTerritory(territoryID string) {
 
 	// Current screen position of territory center (with no pan)
 	terrScreenX := baseOffsetX + centerGridX*zoomedCellSize + zoomedCellSize/2
+	if centerGridY%2 != 0 && s.hexMap() {
+		terrScreenX += zoomedCellSize / 2
+	}
 	terrScreenY := baseOffsetY + centerGridY*zoomedCellSize + zoomedCellSize/2
 
 	// Calculate needed pan to center the territory
@@ -3,7 +3,9 @@ package client
 import (
 	"fmt"
 	"image/color"
+	"strings"
 
+	"lords-of-conquest/internal/game"
 	"lords-of-conquest/internal/protocol"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -70,12 +72,22 @@ func (s *GameplayScene) drawLeftSidebar(screen *ebiten.Image) {
 		col2X := sidebarX + sidebarW/2 + 5
 		iconSize := 16
 
+		// Storage cap per resource (economy rules only)
+		storageCap := 0
+		if v, ok := player["storageCap"].(float64); ok {
+			storageCap = int(v)
+		}
+
 		// Helper to draw resource with inverted (white) icon
 		drawResource := func(iconKey string, count int, x, y int) {
 			if icon := GetIcon(iconKey); icon != nil {
 				DrawIconInverted(screen, icon, x, y, iconSize)
 			}
-			DrawText(screen, fmt.Sprintf("%d", count), x+iconSize+6, y, ColorText)
+			text := fmt.Sprintf("%d", count)
+			if storageCap > 0 {
+				text = fmt.Sprintf("%d/%d", count, storageCap)
+			}
+			DrawText(screen, text, x+iconSize+6, y, ColorText)
 		}
 
 		// Row 1: Coal and Gold
@@ -170,17 +182,33 @@ func (s *GameplayScene) drawLeftSidebar(screen *ebiten.Image) {
 				if cityCount := cityCounts[playerID]; cityCount > 0 {
 					nameText += fmt.Sprintf(" (%d)", cityCount)
 				}
+				if overlord, _ := player["overlord"].(string); overlord != "" {
+					nameText += " [V]" // Vassal
+				}
 				if playerID == s.game.config.PlayerID {
 					nameText += " *"
 				}
 
-				// Truncate name if in two-column mode to fit
-				if useTwoColumns && len(nameText) > 10 {
-					nameText = nameText[:9] + ".."
+				// Truncate name if in two-column mode to fit, leaving room for the deck size
+				maxName := 10
+				if _, ok := player["deckSize"]; ok {
+					maxName = 8
+				}
+				if useTwoColumns && len(nameText) > maxName {
+					nameText = nameText[:maxName-1] + ".."
 				}
 
 				DrawText(screen, nameText, baseX+42, y, ColorText)
 
+				// Public deck size in card mode
+				if deckSize, ok := player["deckSize"].(float64); ok {
+					if useTwoColumns {
+						DrawText(screen, fmt.Sprintf("%dc", int(deckSize)), baseX+colWidth-22, y, ColorTextMuted)
+					} else {
+						DrawText(screen, fmt.Sprintf("%d cards", int(deckSize)), sidebarX+sidebarW-60, y, ColorTextMuted)
+					}
+				}
+
 				// Only advance Y in single-column mode
 				if !useTwoColumns {
 					y += rowHeight
@@ -310,11 +338,20 @@ func (s *GameplayScene) drawYearPhasePanel(screen *ebiten.Image, x, y, w int) in
 		phaseY := y + 8
 		for _, phase := range phases {
 			textColor := ColorTextMuted
-			displayText := "  " + phase
+			label := phase
+
+			// Mark this round's world events
+			if phase == "Production" && s.harvest {
+				label += " x2"
+			}
+			if phase == "Shipment" && s.stormWater != "" {
+				label += " (storm)"
+			}
+			displayText := "  " + label
 
 			if phase == s.currentPhase {
 				textColor = ColorSuccess
-				displayText = "> " + phase
+				displayText = "> " + label
 				vector.DrawFilledRect(screen, float32(phaseX-2), float32(phaseY-2),
 					float32(int(MeasureText(displayText, FontSizeBody))+4), float32(lineHeight), color.RGBA{40, 80, 40, 255}, false)
 			}
@@ -855,12 +892,18 @@ func (s *GameplayScene) drawShipmentControls(screen *ebiten.Image, startX, barY,
 	hasStockpile := false
 	hasHorse := false
 	hasBoat := false
+	hasSiege := false
+	hasTransport := false
+	hasDepot := false
 
 	if myPlayer, ok := s.players[s.game.config.PlayerID]; ok {
 		player := myPlayer.(map[string]interface{})
 		if stockpileTerr, ok := player["stockpileTerritory"].(string); ok && stockpileTerr != "" {
 			hasStockpile = true
 		}
+		if depots, ok := player["depots"].(map[string]interface{}); ok && len(depots) > 0 {
+			hasDepot = hasStockpile
+		}
 	}
 
 	for _, terrData := range s.territories {
@@ -875,6 +918,12 @@ func (s *GameplayScene) drawShipmentControls(screen *ebiten.Image, startX, barY,
 		if boats, ok := terr["totalBoats"].(float64); ok && boats > 0 {
 			hasBoat = true
 		}
+		if engines, ok := terr["siegeEngines"].(float64); ok && engines > 0 {
+			hasSiege = true
+		}
+		if transports, ok := terr["transports"].(map[string]interface{}); ok && len(transports) > 0 {
+			hasTransport = true
+		}
 	}
 
 	// Turn indicator at top with color block
@@ -923,6 +972,34 @@ func (s *GameplayScene) drawShipmentControls(screen *ebiten.Image, startX, barY,
 		s.moveBoatBtn.H = btnH
 		s.moveBoatBtn.Disabled = !hasBoat
 		s.moveBoatBtn.Draw(screen)
+		btnX += btnW + btnSpacing
+
+		if s.extendedUnits {
+			s.moveSiegeBtn.X = btnX
+			s.moveSiegeBtn.Y = btnY
+			s.moveSiegeBtn.W = btnW
+			s.moveSiegeBtn.H = btnH
+			s.moveSiegeBtn.Disabled = !hasSiege
+			s.moveSiegeBtn.Draw(screen)
+			btnX += btnW + btnSpacing
+
+			s.moveTransportBtn.X = btnX
+			s.moveTransportBtn.Y = btnY
+			s.moveTransportBtn.W = btnW + 20
+			s.moveTransportBtn.H = btnH
+			s.moveTransportBtn.Disabled = !hasTransport
+			s.moveTransportBtn.Draw(screen)
+			btnX += btnW + 20 + btnSpacing
+		}
+
+		if s.depotRules {
+			s.moveResourcesBtn.X = btnX
+			s.moveResourcesBtn.Y = btnY
+			s.moveResourcesBtn.W = btnW
+			s.moveResourcesBtn.H = btnH
+			s.moveResourcesBtn.Disabled = !hasDepot
+			s.moveResourcesBtn.Draw(screen)
+		}
 
 		DrawText(screen, "Select what to move, or End Turn to skip", startX, barY+72, ColorTextMuted)
 	} else {
@@ -935,6 +1012,12 @@ func (s *GameplayScene) drawShipmentControls(screen *ebiten.Image, startX, barY,
 			modeText = "Moving Horse"
 		case "boat":
 			modeText = "Moving Boat"
+		case "siege":
+			modeText = "Moving Siege"
+		case "transport":
+			modeText = "Moving Transport"
+		case "resources":
+			modeText = "Moving Resources"
 		}
 		DrawText(screen, modeText, startX, btnY+5, ColorPrimary)
 
@@ -979,10 +1062,11 @@ func (s *GameplayScene) drawShipmentControls(screen *ebiten.Image, startX, barY,
 					s.drawCheckbox(screen, startX, checkboxY, "Carry Weapon", &s.shipmentCarryWeapon)
 				}
 			}
-		} else if s.shipmentMode == "boat" && s.shipmentFromTerritory != "" {
+		} else if (s.shipmentMode == "boat" || s.shipmentMode == "transport") && s.shipmentFromTerritory != "" {
 			if terr, ok := s.territories[s.shipmentFromTerritory].(map[string]interface{}); ok {
 				hasHorseInTerr, _ := terr["hasHorse"].(bool)
 				hasWeaponInTerr, _ := terr["hasWeapon"].(bool)
+				enginesInTerr, _ := terr["siegeEngines"].(float64)
 
 				cbX := startX
 				if hasHorseInTerr {
@@ -991,6 +1075,10 @@ func (s *GameplayScene) drawShipmentControls(screen *ebiten.Image, startX, barY,
 				}
 				if hasWeaponInTerr {
 					s.drawCheckbox(screen, cbX, checkboxY, "Load Weapon", &s.shipmentCarryWeapon)
+					cbX += 120
+				}
+				if s.shipmentMode == "transport" && enginesInTerr > 0 {
+					s.drawCheckbox(screen, cbX, checkboxY, fmt.Sprintf("Load Siege (%d)", s.transportSiegeLoad()), &s.shipmentCarrySiege)
 				}
 			}
 		}
@@ -1038,6 +1126,13 @@ func (s *GameplayScene) drawTradeControls(screen *ebiten.Image, startX, barY, en
 	s.proposeTradeBtn.Y = barY + 35
 	s.proposeTradeBtn.Draw(screen)
 
+	// Market button, next to Propose Trade
+	if s.marketRules {
+		s.marketBtn.X = startX + 440
+		s.marketBtn.Y = barY + 35
+		s.marketBtn.Draw(screen)
+	}
+
 	// End Turn button
 	s.endPhaseBtn.X = endX - 170
 	s.endPhaseBtn.Y = barY + 30
@@ -1074,15 +1169,25 @@ func (s *GameplayScene) drawDevelopmentControls(screen *ebiten.Image, startX, ba
 
 	// Calculate affordability based on gold toggle
 	var canAffordCity, canAffordWeapon, canAffordBoat bool
+	var canAffordFortress, canAffordSiege, canAffordTransport bool
+	var canAffordDepot bool
 
 	if s.buildUseGold {
 		canAffordCity = gold >= 4
 		canAffordWeapon = gold >= 2
 		canAffordBoat = gold >= 3
+		canAffordFortress = gold >= 3
+		canAffordSiege = gold >= 2
+		canAffordTransport = gold >= 5
+		canAffordDepot = gold >= 3
 	} else {
 		canAffordCity = coal >= 1 && gold >= 1 && iron >= 1 && timber >= 1
 		canAffordWeapon = coal >= 1 && iron >= 1
 		canAffordBoat = timber >= 3
+		canAffordFortress = coal >= 1 && iron >= 1 && timber >= 1
+		canAffordSiege = iron >= 1 && timber >= 1
+		canAffordTransport = iron >= 1 && timber >= 4
+		canAffordDepot = iron >= 1 && timber >= 2
 	}
 
 	// === ROW 1: Title + status ===
@@ -1101,7 +1206,7 @@ func (s *GameplayScene) drawDevelopmentControls(screen *ebiten.Image, startX, ba
 
 	statusText := "Select what to build, then click a territory"
 	if s.selectedBuildType != "" {
-		statusText = "Click one of your territories to build " + s.selectedBuildType
+		statusText = "Click one of your territories to build " + strings.ReplaceAll(s.selectedBuildType, "_", " ")
 	}
 	DrawText(screen, statusText, startX, row1Y+24, ColorTextMuted)
 
@@ -1168,6 +1273,64 @@ func (s *GameplayScene) drawDevelopmentControls(screen *ebiten.Image, startX, ba
 	DrawText(screen, " / 3G", costX+14, row2Y+8, goldColor)
 	btnX = costX + 50 + 20
 
+	// Extended units: fortress, siege engine and transport buttons + costs
+	if s.extendedUnits {
+		s.devFortressBtn.X = btnX
+		s.devFortressBtn.Y = row2Y
+		s.devFortressBtn.W = btnW
+		s.devFortressBtn.H = btnH
+		s.devFortressBtn.Primary = s.selectedBuildType == "fortress"
+		s.devFortressBtn.Disabled = !canAffordFortress
+		s.devFortressBtn.Tooltip = fmt.Sprintf("+%d defense, up to %d per territory", game.FortressDefense, game.MaxFortresses)
+		s.devFortressBtn.Draw(screen)
+		costX = btnX + btnW + 6
+		DrawText(screen, "1C+1I+1T", costX, row2Y+8, normalColor)
+		DrawText(screen, " / 3G", costX+48, row2Y+8, goldColor)
+		btnX = costX + 83 + 20
+
+		s.devSiegeBtn.X = btnX
+		s.devSiegeBtn.Y = row2Y
+		s.devSiegeBtn.W = btnW
+		s.devSiegeBtn.H = btnH
+		s.devSiegeBtn.Primary = s.selectedBuildType == "siege"
+		s.devSiegeBtn.Disabled = !canAffordSiege
+		s.devSiegeBtn.Tooltip = "Cancels one fortress next to it"
+		s.devSiegeBtn.Draw(screen)
+		costX = btnX + btnW + 6
+		DrawText(screen, "1I+1T", costX, row2Y+8, normalColor)
+		DrawText(screen, " / 2G", costX+30, row2Y+8, goldColor)
+		btnX = costX + 65 + 20
+
+		s.devTransportBtn.X = btnX
+		s.devTransportBtn.Y = row2Y
+		s.devTransportBtn.W = btnW
+		s.devTransportBtn.H = btnH
+		s.devTransportBtn.Primary = s.selectedBuildType == "transport"
+		s.devTransportBtn.Disabled = !canAffordTransport
+		s.devTransportBtn.Tooltip = fmt.Sprintf("A boat that carries %d units, siege engines too", game.TransportCapacity)
+		s.devTransportBtn.Draw(screen)
+		costX = btnX + btnW + 6
+		DrawText(screen, "1I+4T", costX, row2Y+8, normalColor)
+		DrawText(screen, " / 5G", costX+30, row2Y+8, goldColor)
+		btnX = costX + 65 + 20
+	}
+
+	// Depot button + cost
+	if s.depotRules {
+		s.devDepotBtn.X = btnX
+		s.devDepotBtn.Y = row2Y
+		s.devDepotBtn.W = btnW
+		s.devDepotBtn.H = btnH
+		s.devDepotBtn.Primary = s.selectedBuildType == "depot"
+		s.devDepotBtn.Disabled = !canAffordDepot
+		s.devDepotBtn.Tooltip = fmt.Sprintf("Holds up to %d resources away from your stockpile", game.DepotCapacity)
+		s.devDepotBtn.Draw(screen)
+		costX = btnX + btnW + 6
+		DrawText(screen, "1I+2T", costX, row2Y+8, normalColor)
+		DrawText(screen, " / 3G", costX+30, row2Y+8, goldColor)
+		btnX = costX + 65 + 20
+	}
+
 	// Use Gold toggle
 	if s.buildUseGold {
 		s.devUseGoldBtn.Text = "[X] Use Gold"
@@ -1253,6 +1416,63 @@ func (s *GameplayScene) drawDevelopmentControls(screen *ebiten.Image, startX, ba
 			s.devBuyDefenseCardBtn.Tooltip = "Hand full (5/5)"
 		}
 		s.devBuyDefenseCardBtn.Draw(screen)
+		cardBtnX += 110
+
+		s.devDiscardCardBtn.X = cardBtnX
+		s.devDiscardCardBtn.Y = row3Y
+		s.devDiscardCardBtn.W = 70
+		s.devDiscardCardBtn.H = 26
+		s.devDiscardCardBtn.Disabled = len(s.myAttackCards)+len(s.myDefenseCards) == 0
+		s.devDiscardCardBtn.Draw(screen)
+		cardBtnX += 80
+
+		s.devCombineCardsBtn.X = cardBtnX
+		s.devCombineCardsBtn.Y = row3Y
+		s.devCombineCardsBtn.W = 70
+		s.devCombineCardsBtn.H = 26
+		s.devCombineCardsBtn.Disabled = !canCombineCards(s.myAttackCards) && !canCombineCards(s.myDefenseCards)
+		s.devCombineCardsBtn.Draw(screen)
+	}
+
+	// === ROW 3 (right of the cards): City specializations (economy rules only) ===
+	if s.economyRules {
+		row3Y := barY + 105
+		specX := startX
+		if s.combatMode == "cards" {
+			specX = startX + 680
+		}
+		DrawText(screen, "SPECIALIZE:", specX, row3Y+6, ColorText)
+		specX += 90
+
+		specs := []struct {
+			btn       *Button
+			buildType string
+			cost      string
+			canAfford bool
+			tooltip   string
+		}{
+			{s.devMineBtn, "mine", "1I+1T", iron >= 1 && timber >= 1, "+1 coal, gold and iron next to this city"},
+			{s.devLumberMillBtn, "lumber_mill", "1C+1I", coal >= 1 && iron >= 1, "+1 timber next to this city"},
+			{s.devStableBtn, "stable", "2T", timber >= 2, "A second horse from grassland next to this city"},
+		}
+		for _, spec := range specs {
+			canAfford := spec.canAfford
+			if s.buildUseGold {
+				canAfford = gold >= 2
+			}
+			spec.btn.X = specX
+			spec.btn.Y = row3Y
+			spec.btn.W = 100
+			spec.btn.H = 26
+			spec.btn.Primary = s.selectedBuildType == spec.buildType
+			spec.btn.Disabled = !canAfford
+			spec.btn.Tooltip = spec.tooltip
+			spec.btn.Draw(screen)
+			costX := specX + 100 + 6
+			DrawText(screen, spec.cost, costX, row3Y+6, normalColor)
+			DrawText(screen, " / 2G", costX+30, row3Y+6, goldColor)
+			specX = costX + 65 + 15
+		}
 	}
 }
 
@@ -1294,10 +1514,28 @@ func (s *GameplayScene) drawHoverInfo(screen *ebiten.Image) {
 			contents = append(contents, "Resource: "+resource)
 		}
 
+		// Terrain, with its defense bonus when terrain rules are on
+		if terrainName, ok := terr["terrain"].(string); ok && terrainName != "" {
+			terrain, _ := game.ParseTerrain(terrainName)
+			if bonus := terrain.DefenseBonus(); s.terrainRules && bonus > 0 {
+				contents = append(contents, fmt.Sprintf("Terrain: %s (+%d defense)", terrain, bonus))
+			} else {
+				contents = append(contents, "Terrain: "+terrain.String())
+			}
+		}
+
 		// City
 		if hasCity, ok := terr["hasCity"].(bool); ok && hasCity {
 			contents = append(contents, "[City] (+2 strength)")
 		}
+		switch spec, _ := terr["specialization"].(string); spec {
+		case "mine":
+			contents = append(contents, "[Mine] (+1 coal, gold, iron nearby)")
+		case "lumber_mill":
+			contents = append(contents, "[Lumber Mill] (+1 timber nearby)")
+		case "stable":
+			contents = append(contents, "[Stable] (+1 horse nearby)")
+		}
 
 		// Weapon
 		if hasWeapon, ok := terr["hasWeapon"].(bool); ok && hasWeapon {
@@ -1315,6 +1553,26 @@ func (s *GameplayScene) drawHoverInfo(screen *ebiten.Image) {
 			contents = append(contents, fmt.Sprintf("[Boats] x%d (+%d strength)", boatCount, boatCount*2))
 		}
 
+		// Extended units
+		if fortresses, ok := terr["fortresses"].(float64); ok && fortresses > 0 {
+			contents = append(contents, fmt.Sprintf("[Fortress] x%d (+%d defense)", int(fortresses), int(fortresses)*game.FortressDefense))
+		}
+		if engines, ok := terr["siegeEngines"].(float64); ok && engines > 0 {
+			contents = append(contents, fmt.Sprintf("[Siege] x%d (cancels fortresses)", int(engines)))
+		}
+		garrison, _ := terr["garrison"].(float64)
+		if garrison > 0 {
+			contents = append(contents, fmt.Sprintf("[Garrison] (+%d defense)", int(garrison)))
+		}
+		if transports, ok := terr["transports"].(map[string]interface{}); ok && len(transports) > 0 {
+			count := 0
+			for _, c := range transports {
+				n, _ := c.(float64)
+				count += int(n)
+			}
+			contents = append(contents, fmt.Sprintf("[Transports] x%d of the boats", count))
+		}
+
 		// Check for stockpile
 		for _, playerData := range s.players {
 			player := playerData.(map[string]interface{})
@@ -1324,6 +1582,17 @@ func (s *GameplayScene) drawHoverInfo(screen *ebiten.Image) {
 				break
 			}
 		}
+		if s.depotRules {
+			for playerID, playerData := range s.players {
+				if !s.isDepot(playerID, tid) {
+					continue
+				}
+				player := playerData.(map[string]interface{})
+				store := s.storeAt(playerID, tid)
+				contents = append(contents, fmt.Sprintf("[Depot] (%s): %s", player["name"].(string),
+					s.formatTradeResources(store["coal"], store["gold"], store["iron"], store["timber"], 0)))
+			}
+		}
 
 		// Coastal info
 		if coastalTiles, ok := terr["coastalTiles"].(float64); ok && int(coastalTiles) > 0 {
@@ -1334,6 +1603,11 @@ func (s *GameplayScene) drawHoverInfo(screen *ebiten.Image) {
 			contents = append(contents, fmt.Sprintf("Coastal (%d/%d boat slots)", boats, int(coastalTiles)))
 		}
 
+		// Straits let the holder's boats pass between the seas they join
+		if waters, ok := terr["waterBodies"].([]interface{}); ok && s.straitsRules && len(waters) > 1 {
+			contents = append(contents, fmt.Sprintf("[Strait] joins %d seas", len(waters)))
+		}
+
 		// Determine box height based on content
 		baseHeight := 48 // Name + Owner/Unclaimed
 		contentHeight := len(contents) * 16
@@ -1361,11 +1635,15 @@ func (s *GameplayScene) drawHoverInfo(screen *ebiten.Image) {
 		name := terr["name"].(string)
 		DrawText(screen, name, boxX+10, boxY+10, ColorText)
 
-		if owner != "" {
+		if owner == game.BarbarianID {
+			DrawText(screen, "Owner: "+game.BarbarianName, boxX+10, boxY+28, ColorTextMuted)
+		} else if owner != "" {
 			if player, ok := s.players[owner].(map[string]interface{}); ok {
 				playerName := player["name"].(string)
 				DrawText(screen, "Owner: "+playerName, boxX+10, boxY+28, ColorTextMuted)
 			}
+		} else if garrison > 0 {
+			DrawText(screen, "Neutral", boxX+10, boxY+28, ColorTextMuted)
 		} else {
 			DrawText(screen, "Unclaimed", boxX+10, boxY+28, ColorTextMuted)
 		}
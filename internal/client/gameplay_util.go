@@ -1,10 +1,14 @@
 package client
 
-import "log"
+import (
+	"log"
+
+	"lords-of-conquest/pkg/maps"
+)
 
 // gridToScreen converts grid coordinates to screen coordinates
 func (s *GameplayScene) gridToScreen(gridX, gridY int) (int, int) {
-	return s.offsetX + gridX*s.cellSize, s.offsetY + gridY*s.cellSize
+	return s.offsetX + gridX*s.cellSize + s.rowShift(gridY), s.offsetY + gridY*s.cellSize
 }
 
 // screenToGrid converts screen coordinates to grid coordinates
@@ -13,8 +17,12 @@ func (s *GameplayScene) screenToGrid(screenX, screenY int) [2]int {
 		return [2]int{-1, -1}
 	}
 
-	gridX := (screenX - s.offsetX) / s.cellSize
 	gridY := (screenY - s.offsetY) / s.cellSize
+	shiftedX := screenX - s.offsetX - s.rowShift(gridY)
+	if shiftedX < 0 {
+		return [2]int{-1, -1}
+	}
+	gridX := shiftedX / s.cellSize
 
 	width := int(s.mapData["width"].(float64))
 	height := int(s.mapData["height"].(float64))
@@ -26,6 +34,34 @@ func (s *GameplayScene) screenToGrid(screenX, screenY int) [2]int {
 	return [2]int{gridX, gridY}
 }
 
+// hexMap reports whether the map is a hex grid. Hex maps are drawn as rows of
+// square cells with every odd row shifted half a cell right, so each cell
+// touches the same six neighbours a hex would.
+func (s *GameplayScene) hexMap() bool {
+	topology, _ := s.mapData["topology"].(string)
+	return maps.Topology(topology) == maps.TopologyHex
+}
+
+// rowShift returns how many pixels row y is shifted right.
+func (s *GameplayScene) rowShift(y int) int {
+	if y%2 != 0 && s.hexMap() {
+		return s.cellSize / 2
+	}
+	return 0
+}
+
+// verticalNeighbors returns the columns of the cells in the rows above and
+// below x,y that touch its left and right halves.
+func (s *GameplayScene) verticalNeighbors(x, y int) (int, int) {
+	if !s.hexMap() {
+		return x, x
+	}
+	if y%2 == 0 {
+		return x - 1, x
+	}
+	return x, x + 1
+}
+
 // SetGameState updates the game state from the server.
 func (s *GameplayScene) SetGameState(state map[string]interface{}) {
 	s.applyGameState(state)
@@ -73,6 +109,7 @@ func (s *GameplayScene) applyGameState(state map[string]interface{}) {
 			s.shipmentFromTerritory = ""
 			s.shipmentCarryHorse = false
 			s.shipmentCarryWeapon = false
+			s.shipmentCarrySiege = false
 			s.shipmentWaterBodyID = ""
 
 			// Reset development phase UI state when entering Development phase
@@ -140,6 +177,28 @@ func (s *GameplayScene) applyGameState(state map[string]interface{}) {
 				s.combatMode = "classic"
 			}
 		}
+		s.terrainRules, _ = settings["terrain"].(bool)
+		s.straitsRules, _ = settings["straits"].(bool)
+		s.extendedUnits, _ = settings["extendedUnits"].(bool)
+		s.economyRules, _ = settings["economy"].(bool)
+		s.marketRules, _ = settings["market"].(bool)
+		s.depotRules, _ = settings["depots"].(bool)
+		s.navalRules, _ = settings["navalCombat"].(bool)
+	}
+	s.harvest, _ = state["harvest"].(bool)
+	s.stormWater, _ = state["stormWater"].(string)
+
+	// Parse market prices
+	s.marketPrices = nil
+	if market, ok := state["market"].(map[string]interface{}); ok {
+		if prices, ok := market["prices"].(map[string]interface{}); ok {
+			s.marketPrices = make(map[string]int)
+			for _, resource := range marketResourceNames {
+				if v, ok := prices[resource].(float64); ok {
+					s.marketPrices[resource] = int(v)
+				}
+			}
+		}
 	}
 }
 
@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 
+	"lords-of-conquest/internal/game"
 	"lords-of-conquest/internal/protocol"
 	"lords-of-conquest/pkg/maps"
 
@@ -1092,25 +1093,7 @@ func (s *LobbyScene) onCreateConfirm() {
 		MapID:         generatedMap.ID,
 	}
 
-	// Convert generated map to protocol MapData
-	mapData := &protocol.MapData{
-		ID:          generatedMap.ID,
-		Name:        generatedMap.Name,
-		Width:       generatedMap.Width,
-		Height:      generatedMap.Height,
-		Grid:        generatedMap.Grid,
-		Territories: make(map[string]protocol.TerritoryInfo),
-	}
-
-	// Add territory info
-	for id, t := range generatedMap.Territories {
-		mapData.Territories[fmt.Sprintf("%d", id)] = protocol.TerritoryInfo{
-			Name:     t.Name,
-			Resource: t.Resource.String(),
-		}
-	}
-
-	s.game.CreateGame(name, s.createPublic, settings, mapData)
+	s.game.CreateGame(name, s.createPublic, settings, toMapData(generatedMap))
 	s.showCreateDetails = false
 	s.mapGenDialog.Hide()
 }
@@ -1139,6 +1122,18 @@ type WaitingScene struct {
 	showSettings        bool
 	chanceLevelBtns     [3]*Button // Low, Medium, High
 	combatModeBtns      [2]*Button // Classic, Cards
+	terrainBtns         [2]*Button // Off, On
+	straitsBtns         [2]*Button // Off, On
+	startsBtns          [2]*Button // Draft, Preset
+	unitsBtns           [2]*Button // Classic, Extended
+	economyBtns         [2]*Button // Off, On
+	marketBtns          [2]*Button // Off, On
+	eventsBtns          [2]*Button // Off, On
+	neutralsBtns        [2]*Button // Off, On
+	barbariansBtns      [2]*Button // Off, On
+	depotsBtns          [2]*Button // Off, On
+	navalBtns           [2]*Button // Off, On
+	cardSetBtns         []*Button  // One per card set
 	victoryCitiesSlider *Slider
 	maxPlayersSlider    *Slider
 	settingsCloseBtn    *Button
@@ -1242,6 +1237,125 @@ func NewWaitingScene(game *Game) *WaitingScene {
 		}
 	}
 
+	// Terrain and straits rules buttons
+	for i, label := range []string{"Off", "On"} {
+		on := i == 1
+		s.terrainBtns[i] = &Button{
+			Text: label,
+			OnClick: func() {
+				s.game.UpdateGameSettings("terrain", fmt.Sprintf("%t", on))
+			},
+		}
+		s.straitsBtns[i] = &Button{
+			Text: label,
+			OnClick: func() {
+				s.game.UpdateGameSettings("straits", fmt.Sprintf("%t", on))
+			},
+		}
+	}
+
+	// Starting territories: drafted, or preset by a symmetric map
+	for i, label := range []string{"Draft", "Preset"} {
+		preset := i == 1
+		s.startsBtns[i] = &Button{
+			Text: label,
+			OnClick: func() {
+				s.game.UpdateGameSettings("presetStarts", fmt.Sprintf("%t", preset))
+			},
+		}
+	}
+
+	// Unit roster: classic, or with fortresses, siege engines and transports
+	for i, label := range []string{"Classic", "Extended"} {
+		extended := i == 1
+		s.unitsBtns[i] = &Button{
+			Text: label,
+			OnClick: func() {
+				s.game.UpdateGameSettings("extendedUnits", fmt.Sprintf("%t", extended))
+			},
+		}
+	}
+
+	// Economy rules: upkeep, storage caps and city specializations
+	for i, label := range []string{"Off", "On"} {
+		on := i == 1
+		s.economyBtns[i] = &Button{
+			Text: label,
+			OnClick: func() {
+				s.game.UpdateGameSettings("economy", fmt.Sprintf("%t", on))
+			},
+		}
+	}
+
+	// Market: a bank with moving prices in the Trade phase
+	for i, label := range []string{"Off", "On"} {
+		on := i == 1
+		s.marketBtns[i] = &Button{
+			Text: label,
+			OnClick: func() {
+				s.game.UpdateGameSettings("market", fmt.Sprintf("%t", on))
+			},
+		}
+	}
+
+	// World events: plagues, harvests, storms and rebellions
+	for i, label := range []string{"Off", "On"} {
+		on := i == 1
+		s.eventsBtns[i] = &Button{
+			Text: label,
+			OnClick: func() {
+				s.game.UpdateGameSettings("worldEvents", fmt.Sprintf("%t", on))
+			},
+		}
+	}
+
+	// Neutrals: some territories start held by neutral garrisons
+	for i, label := range []string{"Off", "On"} {
+		on := i == 1
+		s.neutralsBtns[i] = &Button{
+			Text: label,
+			OnClick: func() {
+				s.game.UpdateGameSettings("neutrals", fmt.Sprintf("%t", on))
+			},
+		}
+	}
+
+	// Barbarians: a neutral horde that raids its neighbours each round
+	for i, label := range []string{"Off", "On"} {
+		on := i == 1
+		s.barbariansBtns[i] = &Button{
+			Text: label,
+			OnClick: func() {
+				s.game.UpdateGameSettings("barbarians", fmt.Sprintf("%t", on))
+			},
+		}
+	}
+
+	// Depots: extra stockpiles, shipping between them and raids
+	for i, label := range []string{"Off", "On"} {
+		on := i == 1
+		s.depotsBtns[i] = &Button{
+			Text: label,
+			OnClick: func() {
+				s.game.UpdateGameSettings("depots", fmt.Sprintf("%t", on))
+			},
+		}
+	}
+
+	// Naval combat: sea battles and blockades
+	for i, label := range []string{"Off", "On"} {
+		on := i == 1
+		s.navalBtns[i] = &Button{
+			Text: label,
+			OnClick: func() {
+				s.game.UpdateGameSettings("navalCombat", fmt.Sprintf("%t", on))
+			},
+		}
+	}
+
+	// Card sets, for card combat mode
+	s.cardSetBtns = s.cardSetButtons()
+
 	s.victoryCitiesSlider = &Slider{
 		Min:   protocol.MinVictoryCities,
 		Max:   protocol.MaxVictoryCities,
@@ -1292,6 +1406,42 @@ func (s *WaitingScene) Update() error {
 		for _, btn := range s.combatModeBtns {
 			btn.Update()
 		}
+		for _, btn := range s.terrainBtns {
+			btn.Update()
+		}
+		for _, btn := range s.straitsBtns {
+			btn.Update()
+		}
+		for _, btn := range s.startsBtns {
+			btn.Update()
+		}
+		for _, btn := range s.unitsBtns {
+			btn.Update()
+		}
+		for _, btn := range s.economyBtns {
+			btn.Update()
+		}
+		for _, btn := range s.marketBtns {
+			btn.Update()
+		}
+		for _, btn := range s.eventsBtns {
+			btn.Update()
+		}
+		for _, btn := range s.neutralsBtns {
+			btn.Update()
+		}
+		for _, btn := range s.barbariansBtns {
+			btn.Update()
+		}
+		for _, btn := range s.depotsBtns {
+			btn.Update()
+		}
+		for _, btn := range s.navalBtns {
+			btn.Update()
+		}
+		for _, btn := range s.cardSetBtns {
+			btn.Update()
+		}
 		s.victoryCitiesSlider.Update()
 		s.maxPlayersSlider.Update()
 		s.settingsCloseBtn.Update()
@@ -1435,6 +1585,21 @@ func (s *WaitingScene) Draw(screen *ebiten.Image) {
 	s.mapGenDialog.Draw(screen, "Change Map")
 }
 
+// cardSetButtons makes a button for each card set that picks it.
+func (s *WaitingScene) cardSetButtons() []*Button {
+	var btns []*Button
+	for _, id := range game.CardSetIDs() {
+		setID := id
+		btns = append(btns, &Button{
+			Text: game.CardSets[id].Name,
+			OnClick: func() {
+				s.game.UpdateGameSettings("cardSet", setID)
+			},
+		})
+	}
+	return btns
+}
+
 func (s *WaitingScene) drawSettingsDialog(screen *ebiten.Image, lobby *protocol.LobbyStatePayload) {
 	// Semi-transparent overlay
 	vector.DrawFilledRect(screen, 0, 0, float32(ScreenWidth), float32(ScreenHeight),
@@ -1442,7 +1607,7 @@ func (s *WaitingScene) drawSettingsDialog(screen *ebiten.Image, lobby *protocol.
 
 	// Dialog panel
 	dialogW := 400
-	dialogH := 400
+	dialogH := 830
 	dialogX := (ScreenWidth - dialogW) / 2
 	dialogY := (ScreenHeight - dialogH) / 2
 
@@ -1481,6 +1646,155 @@ func (s *WaitingScene) drawSettingsDialog(screen *ebiten.Image, lobby *protocol.
 		btn.Draw(screen)
 	}
 
+	// Starts, on the same row
+	DrawText(screen, "Starts:", dialogX+210, y-25, ColorText)
+	for i, btn := range s.startsBtns {
+		btn.X = dialogX + 210 + i*(btnW+10)
+		btn.Y = y
+		btn.W = btnW
+		btn.H = btnH
+		btn.Primary = lobby.Settings.PresetStarts == (i == 1)
+		btn.Draw(screen)
+	}
+
+	y += 55
+	// Terrain rules
+	DrawText(screen, "Terrain:", dialogX+20, y, ColorText)
+	y += 25
+	for i, btn := range s.terrainBtns {
+		btn.X = dialogX + 20 + i*(btnW+10)
+		btn.Y = y
+		btn.W = btnW
+		btn.H = btnH
+		btn.Primary = lobby.Settings.Terrain == (i == 1)
+		btn.Draw(screen)
+	}
+
+	// Straits, on the same row
+	DrawText(screen, "Straits:", dialogX+210, y-25, ColorText)
+	for i, btn := range s.straitsBtns {
+		btn.X = dialogX + 210 + i*(btnW+10)
+		btn.Y = y
+		btn.W = btnW
+		btn.H = btnH
+		btn.Primary = lobby.Settings.Straits == (i == 1)
+		btn.Draw(screen)
+	}
+
+	y += 55
+	// Unit roster
+	DrawText(screen, "Units:", dialogX+20, y, ColorText)
+	y += 25
+	for i, btn := range s.unitsBtns {
+		btn.X = dialogX + 20 + i*(btnW+10)
+		btn.Y = y
+		btn.W = btnW
+		btn.H = btnH
+		btn.Primary = lobby.Settings.ExtendedUnits == (i == 1)
+		btn.Draw(screen)
+	}
+
+	// Economy, on the same row
+	DrawText(screen, "Economy:", dialogX+210, y-25, ColorText)
+	for i, btn := range s.economyBtns {
+		btn.X = dialogX + 210 + i*(btnW+10)
+		btn.Y = y
+		btn.W = btnW
+		btn.H = btnH
+		btn.Primary = lobby.Settings.Economy == (i == 1)
+		btn.Draw(screen)
+	}
+
+	y += 55
+	// Market
+	DrawText(screen, "Market:", dialogX+20, y, ColorText)
+	y += 25
+	for i, btn := range s.marketBtns {
+		btn.X = dialogX + 20 + i*(btnW+10)
+		btn.Y = y
+		btn.W = btnW
+		btn.H = btnH
+		btn.Primary = lobby.Settings.Market == (i == 1)
+		btn.Draw(screen)
+	}
+
+	// World events, on the same row
+	DrawText(screen, "Events:", dialogX+210, y-25, ColorText)
+	for i, btn := range s.eventsBtns {
+		btn.X = dialogX + 210 + i*(btnW+10)
+		btn.Y = y
+		btn.W = btnW
+		btn.H = btnH
+		btn.Primary = lobby.Settings.WorldEvents == (i == 1)
+		btn.Draw(screen)
+	}
+
+	y += 55
+	// Neutral territories
+	DrawText(screen, "Neutrals:", dialogX+20, y, ColorText)
+	y += 25
+	for i, btn := range s.neutralsBtns {
+		btn.X = dialogX + 20 + i*(btnW+10)
+		btn.Y = y
+		btn.W = btnW
+		btn.H = btnH
+		btn.Primary = lobby.Settings.Neutrals == (i == 1)
+		btn.Draw(screen)
+	}
+
+	// Barbarians, on the same row
+	DrawText(screen, "Barbarians:", dialogX+210, y-25, ColorText)
+	for i, btn := range s.barbariansBtns {
+		btn.X = dialogX + 210 + i*(btnW+10)
+		btn.Y = y
+		btn.W = btnW
+		btn.H = btnH
+		btn.Primary = lobby.Settings.Barbarians == (i == 1)
+		btn.Draw(screen)
+	}
+
+	y += 55
+	// Depots and raids
+	DrawText(screen, "Depots:", dialogX+20, y, ColorText)
+	y += 25
+	for i, btn := range s.depotsBtns {
+		btn.X = dialogX + 20 + i*(btnW+10)
+		btn.Y = y
+		btn.W = btnW
+		btn.H = btnH
+		btn.Primary = lobby.Settings.Depots == (i == 1)
+		btn.Draw(screen)
+	}
+
+	// Card set, on the same row; only used in card combat mode
+	DrawText(screen, "Card Set:", dialogX+210, y-25, ColorText)
+	cardSetSetting := lobby.Settings.CardSet
+	if cardSetSetting == "" {
+		cardSetSetting = game.DefaultCardSet
+	}
+	for i, btn := range s.cardSetBtns {
+		btn.X = dialogX + 210 + i*(btnW+10)
+		btn.Y = y
+		btn.W = btnW
+		btn.H = btnH
+		btn.Primary = game.CardSetIDs()[i] == cardSetSetting
+		btn.Disabled = combatModeSetting != "cards"
+		btn.Draw(screen)
+	}
+
+	y += 55
+	// Sea battles and blockades
+	DrawText(screen, "Naval Combat:", dialogX+20, y, ColorText)
+	y += 25
+	for i, btn := range s.navalBtns {
+		btn.X = dialogX + 20 + i*(btnW+10)
+		btn.Y = y
+		btn.W = btnW
+		btn.H = btnH
+		btn.Primary = lobby.Settings.NavalCombat == (i == 1)
+		btn.Draw(screen)
+	}
+
 	y += 55
 	// Victory Cities slider
 	s.victoryCitiesSlider.X = dialogX + 20
@@ -1536,8 +1850,12 @@ func (s *WaitingScene) drawInlineMapPreview(screen *ebiten.Image, panelX, panelY
 		offsetX := mapAreaX + (mapAreaW-actualW)/2
 		offsetY := mapAreaY + (mapAreaH-actualH)/2
 
-		// Draw the map grid
+		// Draw the map grid; odd rows of hex maps sit half a cell right
 		for y := 0; y < mapData.Height; y++ {
+			rowX := offsetX
+			if y%2 != 0 && maps.Topology(mapData.Topology) == maps.TopologyHex {
+				rowX += cellSize / 2
+			}
 			for x := 0; x < mapData.Width; x++ {
 				cell := mapData.Grid[y][x]
 				var c color.RGBA
@@ -1550,7 +1868,7 @@ func (s *WaitingScene) drawInlineMapPreview(screen *ebiten.Image, panelX, panelY
 					c = color.RGBA{base, base + 30, base, 255}
 				}
 				vector.DrawFilledRect(screen,
-					float32(offsetX+x*cellSize),
+					float32(rowX+x*cellSize),
 					float32(offsetY+y*cellSize),
 					float32(cellSize),
 					float32(cellSize),
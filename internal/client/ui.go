@@ -135,6 +135,9 @@ var PlayerColors = map[string]color.RGBA{
 	"sky":    {100, 180, 255, 255},
 }
 
+// BarbarianColor is the color of territories held by the barbarians.
+var BarbarianColor = color.RGBA{110, 60, 40, 255}
+
 // PlayerColorOrder defines the display order for color picker
 var PlayerColorOrder = []string{
 	"orange", "yellow", "lime", "green",
@@ -955,10 +958,14 @@ type MapGenDialog struct {
 	TerritoriesSlider *Slider
 	IslandsSlider     *Slider
 	ResourcesSlider   *Slider
+	TemplateSlider    *Slider
+	SymmetrySlider    *Slider
 
 	// Buttons
 	WaterBorderBtn *Button
 	WaterBorder    bool
+	TerrainBtn     *Button
+	Terrain        bool
 	GenerateBtn    *Button
 	ConfirmBtn     *Button
 	CancelBtn      *Button
@@ -999,12 +1006,28 @@ func NewMapGenDialog() *MapGenDialog {
 		Min: 10, Max: 100, Value: 40,
 		Label: "Resources %",
 	}
+	d.TemplateSlider = &Slider{
+		Min: 0, Max: len(maps.Templates), Value: 0,
+		Label:  "Layout",
+		Labels: []string{"Scattered", "Continents", "Archipelago", "Pangaea", "Ring", "Mirror"},
+	}
+	d.SymmetrySlider = &Slider{
+		Min: 0, Max: len(maps.Symmetries), Value: 0,
+		Label:  "Symmetry",
+		Labels: []string{"None", "Mirror (2)", "Rotate (2)", "Quarters (4)"},
+	}
 	d.WaterBorderBtn = &Button{
 		Text: "[ ] Water Border",
 		OnClick: func() {
 			d.WaterBorder = !d.WaterBorder
 		},
 	}
+	d.TerrainBtn = &Button{
+		Text: "[ ] Terrain",
+		OnClick: func() {
+			d.Terrain = !d.Terrain
+		},
+	}
 	d.GenerateBtn = &Button{
 		Text: "Generate",
 		OnClick: func() {
@@ -1056,6 +1079,13 @@ func (d *MapGenDialog) Generate() {
 		Islands:     d.IslandsSlider.Value,
 		Resources:   d.ResourcesSlider.Value,
 		WaterBorder: d.WaterBorder,
+		Terrain:     d.Terrain,
+	}
+	if d.TemplateSlider.Value > 0 {
+		opts.Template = maps.Templates[d.TemplateSlider.Value-1]
+	}
+	if d.SymmetrySlider.Value > 0 {
+		opts.Symmetry = maps.Symmetries[d.SymmetrySlider.Value-1]
 	}
 
 	gen := maps.NewGenerator(opts)
@@ -1083,7 +1113,10 @@ func (d *MapGenDialog) Update() {
 	d.TerritoriesSlider.Update()
 	d.IslandsSlider.Update()
 	d.ResourcesSlider.Update()
+	d.TemplateSlider.Update()
+	d.SymmetrySlider.Update()
 	d.WaterBorderBtn.Update()
+	d.TerrainBtn.Update()
 	d.GenerateBtn.Update()
 	d.ConfirmBtn.Update()
 	d.CancelBtn.Update()
@@ -1094,6 +1127,11 @@ func (d *MapGenDialog) Update() {
 	} else {
 		d.WaterBorderBtn.Text = "[ ] Water Border"
 	}
+	if d.Terrain {
+		d.TerrainBtn.Text = "[X] Terrain"
+	} else {
+		d.TerrainBtn.Text = "[ ] Terrain"
+	}
 
 	// Disable confirm if no map
 	d.ConfirmBtn.Disabled = d.GeneratedMap == nil
@@ -1211,11 +1249,32 @@ func (d *MapGenDialog) Draw(screen *ebiten.Image, title string) {
 	d.ResourcesSlider.Draw(screen)
 	y += 50
 
+	d.TemplateSlider.X = sliderX
+	d.TemplateSlider.Y = y
+	d.TemplateSlider.W = sliderW
+	d.TemplateSlider.H = sliderH
+	d.TemplateSlider.Draw(screen)
+	y += 50
+
+	d.SymmetrySlider.X = sliderX
+	d.SymmetrySlider.Y = y
+	d.SymmetrySlider.W = sliderW
+	d.SymmetrySlider.H = sliderH
+	d.SymmetrySlider.Draw(screen)
+	y += 50
+
 	d.WaterBorderBtn.X = sliderX
 	d.WaterBorderBtn.Y = y
 	d.WaterBorderBtn.W = sliderW
 	d.WaterBorderBtn.H = 35
 	d.WaterBorderBtn.Draw(screen)
+	y += 50
+
+	d.TerrainBtn.X = sliderX
+	d.TerrainBtn.Y = y
+	d.TerrainBtn.W = sliderW
+	d.TerrainBtn.H = 35
+	d.TerrainBtn.Draw(screen)
 
 	y += 50
 	d.GenerateBtn.X = sliderX
@@ -1,4 +1,4 @@
-// Package database provides SQLite persistence for game state.
+// Package database provides SQLite and PostgreSQL persistence for game state.
 package database
 
 import (
@@ -10,12 +10,14 @@ import (
 	_ "modernc.org/sqlite"
 )
 
-// DB wraps the SQLite database connection.
+// DB is the game's persistent storage. It speaks SQLite or PostgreSQL; the
+// queries in this package are written for SQLite and adapted by its dialect.
 type DB struct {
-	conn *sql.DB
+	conn    *sql.DB
+	dialect dialect
 }
 
-// New creates a new database connection.
+// New creates a new SQLite database connection.
 // If the database file doesn't exist, it will be created.
 func New(dbPath string) (*DB, error) {
 	// Ensure directory exists
@@ -32,13 +34,18 @@ func New(dbPath string) (*DB, error) {
 	// Limit concurrent connections to avoid lock contention
 	conn.SetMaxOpenConns(1)
 
+	return open(conn, dialectSQLite)
+}
+
+// open checks the connection and brings the schema up to date.
+func open(conn *sql.DB, d dialect) (*DB, error) {
 	// Test connection
 	if err := conn.Ping(); err != nil {
 		conn.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	db := &DB{conn: conn}
+	db := &DB{conn: conn, dialect: d}
 
 	// Run migrations
 	if err := db.migrate(); err != nil {
@@ -57,13 +64,13 @@ func (db *DB) Close() error {
 // migrate runs all database migrations.
 func (db *DB) migrate() error {
 	// Create migrations table if not exists
-	_, err := db.conn.Exec(`
+	_, err := db.conn.Exec(db.dialect.schema(`
 		CREATE TABLE IF NOT EXISTS migrations (
 			id INTEGER PRIMARY KEY,
 			name TEXT NOT NULL,
 			applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)
-	`)
+	`))
 	if err != nil {
 		return err
 	}
@@ -88,7 +95,7 @@ func (db *DB) migrate() error {
 
 func (db *DB) isMigrationApplied(id int) (bool, error) {
 	var count int
-	err := db.conn.QueryRow("SELECT COUNT(*) FROM migrations WHERE id = ?", id).Scan(&count)
+	err := db.queryRow("SELECT COUNT(*) FROM migrations WHERE id = ?", id).Scan(&count)
 	return count > 0, err
 }
 
@@ -99,14 +106,33 @@ func (db *DB) runMigration(m migration) error {
 	}
 	defer tx.Rollback()
 
-	if _, err := tx.Exec(m.sql); err != nil {
+	if _, err := tx.Exec(db.dialect.schema(m.sql)); err != nil {
 		return err
 	}
 
-	if _, err := tx.Exec("INSERT INTO migrations (id, name) VALUES (?, ?)", m.id, m.name); err != nil {
+	if _, err := tx.Exec(db.rebind("INSERT INTO migrations (id, name) VALUES (?, ?)"), m.id, m.name); err != nil {
 		return err
 	}
 
 	return tx.Commit()
 }
 
+// rebind adapts a query's placeholders to the database's dialect.
+func (db *DB) rebind(query string) string {
+	return db.dialect.rebind(query)
+}
+
+// exec runs a statement written with ? placeholders.
+func (db *DB) exec(query string, args ...any) (sql.Result, error) {
+	return db.conn.Exec(db.rebind(query), args...)
+}
+
+// query runs a query written with ? placeholders.
+func (db *DB) query(query string, args ...any) (*sql.Rows, error) {
+	return db.conn.Query(db.rebind(query), args...)
+}
+
+// queryRow runs a single-row query written with ? placeholders.
+func (db *DB) queryRow(query string, args ...any) *sql.Row {
+	return db.conn.QueryRow(db.rebind(query), args...)
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"strconv"
+	"strings"
+)
+
+// dialect identifies the SQL engine behind a DB.
+type dialect int
+
+const (
+	dialectSQLite dialect = iota
+	dialectPostgres
+)
+
+// postgresTypes rewrites the SQLite column types used by the migrations into
+// their PostgreSQL equivalents. Order matters: the longer forms come first.
+var postgresTypes = strings.NewReplacer(
+	"INTEGER PRIMARY KEY AUTOINCREMENT", "BIGSERIAL PRIMARY KEY",
+	"INTEGER", "BIGINT",
+	"DATETIME", "TIMESTAMPTZ",
+)
+
+// schema adapts DDL written for SQLite to the dialect.
+func (d dialect) schema(ddl string) string {
+	if d == dialectPostgres {
+		return postgresTypes.Replace(ddl)
+	}
+	return ddl
+}
+
+// rebind rewrites ? placeholders as $1, $2, ... for PostgreSQL.
+// None of the package's queries contain a literal question mark.
+func (d dialect) rebind(query string) string {
+	if d != dialectPostgres || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRebind(t *testing.T) {
+	query := "SELECT id FROM games WHERE status = ? AND host_player_id = ?"
+
+	if got := dialectSQLite.rebind(query); got != query {
+		t.Errorf("sqlite rebind changed the query: %q", got)
+	}
+
+	want := "SELECT id FROM games WHERE status = $1 AND host_player_id = $2"
+	if got := dialectPostgres.rebind(query); got != want {
+		t.Errorf("postgres rebind = %q, want %q", got, want)
+	}
+}
+
+func TestPostgresSchemaTranslatesEveryMigration(t *testing.T) {
+	for _, m := range migrations {
+		ddl := dialectPostgres.schema(m.sql)
+		for _, sqliteOnly := range []string{"AUTOINCREMENT", "DATETIME", "INTEGER"} {
+			if strings.Contains(ddl, sqliteOnly) {
+				t.Errorf("migration %d (%s) still contains %s for PostgreSQL", m.id, m.name, sqliteOnly)
+			}
+		}
+	}
+
+	got := dialectPostgres.schema("id INTEGER PRIMARY KEY AUTOINCREMENT, created_at DATETIME, round INTEGER")
+	want := "id BIGSERIAL PRIMARY KEY, created_at TIMESTAMPTZ, round BIGINT"
+	if got != want {
+		t.Errorf("schema = %q, want %q", got, want)
+	}
+}
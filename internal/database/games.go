@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -50,6 +51,18 @@ type GameSettings struct {
 	VictoryCities int    `json:"victory_cities"`
 	MapID         string `json:"map_id"`
 	CombatMode    string `json:"combat_mode"`
+	CardSet       string `json:"card_set,omitempty"`
+	Terrain       bool   `json:"terrain,omitempty"`
+	Straits       bool   `json:"straits,omitempty"`
+	PresetStarts  bool   `json:"preset_starts,omitempty"`
+	ExtendedUnits bool   `json:"extended_units,omitempty"`
+	Economy       bool   `json:"economy,omitempty"`
+	Market        bool   `json:"market,omitempty"`
+	WorldEvents   bool   `json:"world_events,omitempty"`
+	Neutrals      bool   `json:"neutrals,omitempty"`
+	Barbarians    bool   `json:"barbarians,omitempty"`
+	Depots        bool   `json:"depots,omitempty"`
+	NavalCombat   bool   `json:"naval_combat,omitempty"`
 }
 
 // GamePlayer represents a player in a game.
@@ -90,7 +103,7 @@ func (db *DB) CreateGame(name string, hostPlayerID string, settings GameSettings
 	}
 
 	now := time.Now()
-	_, err = db.conn.Exec(`
+	_, err = db.exec(`
 		INSERT INTO games (id, name, join_code, is_public, status, host_player_id, settings_json, max_players, map_json, created_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, id, name, joinCode, isPublic, GameStatusWaiting, hostPlayerID, string(settingsJSON), settings.MaxPlayers, mapJSON, now)
@@ -121,7 +134,7 @@ func (db *DB) GetGame(id string) (*Game, error) {
 	var joinCode sql.NullString
 	var startedAt, endedAt sql.NullTime
 
-	err := db.conn.QueryRow(`
+	err := db.queryRow(`
 		SELECT id, name, join_code, is_public, status, host_player_id, settings_json, 
 		       max_players, created_at, started_at, ended_at
 		FROM games WHERE id = ?
@@ -150,7 +163,7 @@ func (db *DB) GetGame(id string) (*Game, error) {
 	}
 
 	// Get player count
-	db.conn.QueryRow(`SELECT COUNT(*) FROM game_players WHERE game_id = ?`, id).Scan(&g.PlayerCount)
+	db.queryRow(`SELECT COUNT(*) FROM game_players WHERE game_id = ?`, id).Scan(&g.PlayerCount)
 
 	return &g, nil
 }
@@ -158,7 +171,7 @@ func (db *DB) GetGame(id string) (*Game, error) {
 // GetGameByJoinCode retrieves a game by its join code.
 func (db *DB) GetGameByJoinCode(code string) (*Game, error) {
 	var id string
-	err := db.conn.QueryRow(`SELECT id FROM games WHERE join_code = ?`, strings.ToUpper(code)).Scan(&id)
+	err := db.queryRow(`SELECT id FROM games WHERE join_code = ?`, strings.ToUpper(code)).Scan(&id)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrJoinCodeNotFound
 	}
@@ -196,6 +209,41 @@ func (db *DB) UpdateGameSetting(gameID, key, value string) error {
 		if value == "classic" || value == "cards" {
 			game.Settings.CombatMode = value
 		}
+	case "card_set":
+		game.Settings.CardSet = value
+	case "map_id":
+		game.Settings.MapID = value
+	case "terrain", "straits", "preset_starts", "extended_units", "economy", "market", "world_events", "neutrals", "barbarians", "depots", "naval_combat":
+		on, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s setting %q", key, value)
+		}
+		switch key {
+		case "terrain":
+			game.Settings.Terrain = on
+		case "straits":
+			game.Settings.Straits = on
+		case "extended_units":
+			game.Settings.ExtendedUnits = on
+		case "economy":
+			game.Settings.Economy = on
+		case "market":
+			game.Settings.Market = on
+		case "world_events":
+			game.Settings.WorldEvents = on
+		case "neutrals":
+			game.Settings.Neutrals = on
+		case "barbarians":
+			game.Settings.Barbarians = on
+		case "depots":
+			game.Settings.Depots = on
+		case "naval_combat":
+			game.Settings.NavalCombat = on
+		case "preset_starts":
+			game.Settings.PresetStarts = on
+		default:
+			return errors.New("unknown setting: " + key)
+		}
 	default:
 		return errors.New("unknown setting: " + key)
 	}
@@ -206,7 +254,7 @@ func (db *DB) UpdateGameSetting(gameID, key, value string) error {
 		return err
 	}
 
-	_, err = db.conn.Exec(`
+	_, err = db.exec(`
 		UPDATE games SET settings_json = ?, max_players = ? WHERE id = ?
 	`, string(settingsJSON), game.Settings.MaxPlayers, gameID)
 
@@ -215,7 +263,7 @@ func (db *DB) UpdateGameSetting(gameID, key, value string) error {
 
 // ListPublicGames returns all public games that are waiting for players.
 func (db *DB) ListPublicGames() ([]*GameInfo, error) {
-	rows, err := db.conn.Query(`
+	rows, err := db.query(`
 		SELECT g.id, g.name, g.join_code, g.is_public, g.status, 
 		       g.host_player_id, g.max_players, g.created_at,
 		       (SELECT COUNT(*) FROM game_players WHERE game_id = g.id) as player_count
@@ -259,7 +307,7 @@ func (db *DB) JoinGame(gameID, playerID, color string) error {
 
 	// Check if player already in game
 	var exists int
-	db.conn.QueryRow(`SELECT COUNT(*) FROM game_players WHERE game_id = ? AND player_id = ?`,
+	db.queryRow(`SELECT COUNT(*) FROM game_players WHERE game_id = ? AND player_id = ?`,
 		gameID, playerID).Scan(&exists)
 	if exists > 0 {
 		return ErrAlreadyInGame
@@ -272,13 +320,13 @@ func (db *DB) JoinGame(gameID, playerID, color string) error {
 
 	// Get next slot
 	var maxSlot sql.NullInt64
-	db.conn.QueryRow(`SELECT MAX(slot) FROM game_players WHERE game_id = ?`, gameID).Scan(&maxSlot)
+	db.queryRow(`SELECT MAX(slot) FROM game_players WHERE game_id = ?`, gameID).Scan(&maxSlot)
 	slot := 0
 	if maxSlot.Valid {
 		slot = int(maxSlot.Int64) + 1
 	}
 
-	_, err = db.conn.Exec(`
+	_, err = db.exec(`
 		INSERT INTO game_players (game_id, player_id, slot, color, is_ai, is_ready, is_connected, joined_at)
 		VALUES (?, ?, ?, ?, FALSE, FALSE, FALSE, ?)
 	`, gameID, playerID, slot, color, time.Now())
@@ -287,7 +335,7 @@ func (db *DB) JoinGame(gameID, playerID, color string) error {
 
 // LeaveGame removes a player from a game.
 func (db *DB) LeaveGame(gameID, playerID string) error {
-	result, err := db.conn.Exec(`
+	result, err := db.exec(`
 		DELETE FROM game_players WHERE game_id = ? AND player_id = ?
 	`, gameID, playerID)
 	if err != nil {
@@ -303,7 +351,7 @@ func (db *DB) LeaveGame(gameID, playerID string) error {
 
 // GetGamePlayers returns all players in a game.
 func (db *DB) GetGamePlayers(gameID string) ([]*GamePlayer, error) {
-	rows, err := db.conn.Query(`
+	rows, err := db.query(`
 		SELECT gp.game_id, gp.player_id, p.name, gp.slot, gp.color, 
 		       gp.is_ai, gp.ai_personality, gp.is_ready, gp.is_connected, gp.joined_at,
 		       COALESCE(gp.alliance_setting, 'ask')
@@ -342,7 +390,7 @@ func (db *DB) GetGamePlayers(gameID string) ([]*GamePlayer, error) {
 
 // SetPlayerReady sets a player's ready status.
 func (db *DB) SetPlayerReady(gameID, playerID string, ready bool) error {
-	_, err := db.conn.Exec(`
+	_, err := db.exec(`
 		UPDATE game_players SET is_ready = ? WHERE game_id = ? AND player_id = ?
 	`, ready, gameID, playerID)
 	return err
@@ -350,7 +398,7 @@ func (db *DB) SetPlayerReady(gameID, playerID string, ready bool) error {
 
 // SetPlayerConnected sets a player's connection status.
 func (db *DB) SetPlayerConnected(gameID, playerID string, connected bool) error {
-	_, err := db.conn.Exec(`
+	_, err := db.exec(`
 		UPDATE game_players SET is_connected = ? WHERE game_id = ? AND player_id = ?
 	`, connected, gameID, playerID)
 	return err
@@ -358,7 +406,7 @@ func (db *DB) SetPlayerConnected(gameID, playerID string, connected bool) error
 
 // UpdatePlayerColor updates a player's color in a game.
 func (db *DB) UpdatePlayerColor(gameID, playerID, color string) error {
-	_, err := db.conn.Exec(`
+	_, err := db.exec(`
 		UPDATE game_players SET color = ? WHERE game_id = ? AND player_id = ?
 	`, color, gameID, playerID)
 	return err
@@ -367,7 +415,7 @@ func (db *DB) UpdatePlayerColor(gameID, playerID, color string) error {
 // SetAllianceSetting sets a player's alliance preference.
 // setting can be "ask", "neutral", "defender", or a player_id
 func (db *DB) SetAllianceSetting(gameID, playerID, setting string) error {
-	_, err := db.conn.Exec(`
+	_, err := db.exec(`
 		UPDATE game_players SET alliance_setting = ? WHERE game_id = ? AND player_id = ?
 	`, setting, gameID, playerID)
 	return err
@@ -376,7 +424,7 @@ func (db *DB) SetAllianceSetting(gameID, playerID, setting string) error {
 // GetAllianceSetting gets a player's alliance preference.
 func (db *DB) GetAllianceSetting(gameID, playerID string) (string, error) {
 	var setting string
-	err := db.conn.QueryRow(`
+	err := db.queryRow(`
 		SELECT COALESCE(alliance_setting, 'ask') FROM game_players 
 		WHERE game_id = ? AND player_id = ?
 	`, gameID, playerID).Scan(&setting)
@@ -399,7 +447,7 @@ func (db *DB) AddAIPlayer(gameID, color, personality string) error {
 
 	// Get next slot
 	var maxSlot sql.NullInt64
-	db.conn.QueryRow(`SELECT MAX(slot) FROM game_players WHERE game_id = ?`, gameID).Scan(&maxSlot)
+	db.queryRow(`SELECT MAX(slot) FROM game_players WHERE game_id = ?`, gameID).Scan(&maxSlot)
 	slot := 0
 	if maxSlot.Valid {
 		slot = int(maxSlot.Int64) + 1
@@ -407,14 +455,14 @@ func (db *DB) AddAIPlayer(gameID, color, personality string) error {
 
 	// Count existing AI players in the game to number them
 	var aiCount int
-	db.conn.QueryRow(`SELECT COUNT(*) FROM game_players WHERE game_id = ? AND is_ai = TRUE`, gameID).Scan(&aiCount)
+	db.queryRow(`SELECT COUNT(*) FROM game_players WHERE game_id = ? AND is_ai = TRUE`, gameID).Scan(&aiCount)
 
 	// Create a player entry for the AI (required for foreign key)
 	aiID := fmt.Sprintf("ai-%s", uuid.New().String()[:8])
 	aiName := fmt.Sprintf("CPU%d", aiCount+1)
 	aiToken := uuid.New().String()
 
-	_, err = db.conn.Exec(`
+	_, err = db.exec(`
 		INSERT INTO players (id, token, name, created_at, last_seen_at)
 		VALUES (?, ?, ?, ?, ?)
 	`, aiID, aiToken, aiName, time.Now(), time.Now())
@@ -423,7 +471,7 @@ func (db *DB) AddAIPlayer(gameID, color, personality string) error {
 	}
 
 	// Add AI to game
-	_, err = db.conn.Exec(`
+	_, err = db.exec(`
 		INSERT INTO game_players (game_id, player_id, slot, color, is_ai, ai_personality, is_ready, is_connected, joined_at)
 		VALUES (?, ?, ?, ?, TRUE, ?, TRUE, TRUE, ?)
 	`, gameID, aiID, slot, color, personality, time.Now())
@@ -433,7 +481,7 @@ func (db *DB) AddAIPlayer(gameID, color, personality string) error {
 // StartGame marks a game as started.
 func (db *DB) StartGame(gameID string) error {
 	now := time.Now()
-	_, err := db.conn.Exec(`
+	_, err := db.exec(`
 		UPDATE games SET status = ?, started_at = ? WHERE id = ?
 	`, GameStatusStarted, now, gameID)
 	return err
@@ -442,7 +490,7 @@ func (db *DB) StartGame(gameID string) error {
 // EndGame marks a game as finished with a winner.
 func (db *DB) EndGame(gameID string, winnerID string, reason string) error {
 	now := time.Now()
-	_, err := db.conn.Exec(`
+	_, err := db.exec(`
 		UPDATE games SET status = ?, ended_at = ?, winner_id = ?, win_reason = ? WHERE id = ?
 	`, GameStatusFinished, now, winnerID, reason, gameID)
 	return err
@@ -450,7 +498,7 @@ func (db *DB) EndGame(gameID string, winnerID string, reason string) error {
 
 // SaveGameState saves the current game state.
 func (db *DB) SaveGameState(gameID string, stateJSON string, currentPlayerID string, round int, phase string) error {
-	_, err := db.conn.Exec(`
+	_, err := db.exec(`
 		INSERT INTO game_state (game_id, state_json, current_player_id, round, phase, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?)
 		ON CONFLICT(game_id) DO UPDATE SET
@@ -466,7 +514,7 @@ func (db *DB) SaveGameState(gameID string, stateJSON string, currentPlayerID str
 // GetGameState retrieves the current game state.
 func (db *DB) GetGameState(gameID string) (string, error) {
 	var stateJSON string
-	err := db.conn.QueryRow(`
+	err := db.queryRow(`
 		SELECT state_json FROM game_state WHERE game_id = ?
 	`, gameID).Scan(&stateJSON)
 	if errors.Is(err, sql.ErrNoRows) {
@@ -478,7 +526,7 @@ func (db *DB) GetGameState(gameID string) (string, error) {
 // GetGameMapJSON retrieves the stored map JSON for a game.
 func (db *DB) GetGameMapJSON(gameID string) (string, error) {
 	var mapJSON sql.NullString
-	err := db.conn.QueryRow(`
+	err := db.queryRow(`
 		SELECT map_json FROM games WHERE id = ?
 	`, gameID).Scan(&mapJSON)
 	if errors.Is(err, sql.ErrNoRows) {
@@ -495,7 +543,7 @@ func (db *DB) GetGameMapJSON(gameID string) (string, error) {
 
 // UpdateGameMap updates the map for a game.
 func (db *DB) UpdateGameMap(gameID, mapJSON string) error {
-	_, err := db.conn.Exec(`
+	_, err := db.exec(`
 		UPDATE games SET map_json = ? WHERE id = ?
 	`, mapJSON, gameID)
 	return err
@@ -503,7 +551,7 @@ func (db *DB) UpdateGameMap(gameID, mapJSON string) error {
 
 // LogAction logs a game action.
 func (db *DB) LogAction(gameID, playerID, actionType, actionJSON, resultJSON string) error {
-	_, err := db.conn.Exec(`
+	_, err := db.exec(`
 		INSERT INTO game_actions (game_id, player_id, action_type, action_json, result_json)
 		VALUES (?, ?, ?, ?, ?)
 	`, gameID, playerID, actionType, actionJSON, resultJSON)
@@ -519,22 +567,22 @@ func (db *DB) DeleteGame(gameID string) error {
 	defer tx.Rollback()
 
 	// Delete in order of dependencies
-	_, err = tx.Exec(`DELETE FROM game_actions WHERE game_id = ?`, gameID)
+	_, err = tx.Exec(db.rebind(`DELETE FROM game_actions WHERE game_id = ?`), gameID)
 	if err != nil {
 		return err
 	}
 
-	_, err = tx.Exec(`DELETE FROM game_state WHERE game_id = ?`, gameID)
+	_, err = tx.Exec(db.rebind(`DELETE FROM game_state WHERE game_id = ?`), gameID)
 	if err != nil {
 		return err
 	}
 
-	_, err = tx.Exec(`DELETE FROM game_players WHERE game_id = ?`, gameID)
+	_, err = tx.Exec(db.rebind(`DELETE FROM game_players WHERE game_id = ?`), gameID)
 	if err != nil {
 		return err
 	}
 
-	_, err = tx.Exec(`DELETE FROM games WHERE id = ?`, gameID)
+	_, err = tx.Exec(db.rebind(`DELETE FROM games WHERE id = ?`), gameID)
 	if err != nil {
 		return err
 	}
@@ -545,7 +593,7 @@ func (db *DB) DeleteGame(gameID string) error {
 // CleanupAbandonedLobbies removes lobby games where the host is offline.
 func (db *DB) CleanupAbandonedLobbies() error {
 	// Delete games that are in waiting status and have no connected players
-	_, err := db.conn.Exec(`
+	_, err := db.exec(`
 		DELETE FROM games 
 		WHERE id IN (
 			SELECT g.id FROM games g
@@ -553,16 +601,58 @@ func (db *DB) CleanupAbandonedLobbies() error {
 			AND NOT EXISTS (
 				SELECT 1 FROM game_players gp
 				WHERE gp.game_id = g.id
-				AND gp.is_connected = 1
+				AND gp.is_connected = TRUE
 			)
 		)
 	`, GameStatusWaiting)
 	return err
 }
 
+// GetStartedGameIDs returns the IDs of all games currently in progress.
+func (db *DB) GetStartedGameIDs() ([]string, error) {
+	rows, err := db.query(`
+		SELECT id FROM games WHERE status = ?
+	`, GameStatusStarted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetGameStateIDs returns the IDs of all games with a saved state.
+func (db *DB) GetGameStateIDs() ([]string, error) {
+	rows, err := db.query(`
+		SELECT game_id FROM game_state ORDER BY game_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 // GetPlayerGames retrieves all games a player is participating in.
 func (db *DB) GetPlayerGames(playerID string) ([]*GameInfo, error) {
-	rows, err := db.conn.Query(`
+	rows, err := db.query(`
 		SELECT DISTINCT
 			g.id, g.name, g.join_code, g.is_public, g.status,
 			g.host_player_id, g.max_players, g.created_at,
@@ -0,0 +1,41 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestUpdateGameSetting(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "lords.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	host, err := db.CreatePlayer("Host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	game, err := db.CreateGame("Game", host.ID, GameSettings{}, true, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.UpdateGameSetting(game.ID, "preset_starts", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.UpdateGameSetting(game.ID, "fog_of_war", "true"); err == nil {
+		t.Error("unknown setting was accepted")
+	}
+	if err := db.UpdateGameSetting(game.ID, "depots", "maybe"); err == nil {
+		t.Error("invalid bool was accepted")
+	}
+
+	got, err := db.GetGame(game.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Settings.PresetStarts || got.Settings.Depots {
+		t.Errorf("settings = %+v, want only preset starts on", got.Settings)
+	}
+}
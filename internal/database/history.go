@@ -20,10 +20,16 @@ const (
 	EventTerritorySelected = "territory_selected"
 	EventStockpilePlaced   = "stockpile_placed"
 	EventStockpileMoved    = "stockpile_moved"
+	EventResourcesShipped  = "resources_shipped"
 	EventAttackSuccess     = "attack_success"
 	EventAttackFailed      = "attack_failed"
+	EventRaid              = "raid"
+	EventNavalBattle       = "naval_battle"
 	EventProduction        = "production"
 	EventBuild             = "build"
+	EventMarketTrade       = "market_trade"
+	EventWorldEvent        = "world_event"
+	EventVassalage         = "vassalage"
 	EventPhaseStart        = "phase_start"
 	EventRoundStart        = "round_start"
 	EventPlayerEliminated  = "player_eliminated"
@@ -33,7 +39,7 @@ const (
 
 // AddHistoryEvent adds a new event to the game history.
 func (db *DB) AddHistoryEvent(gameID string, round int, phase string, playerID, playerName, eventType, message string) error {
-	_, err := db.conn.Exec(`
+	_, err := db.exec(`
 		INSERT INTO game_history (game_id, round, phase, player_id, player_name, event_type, message, created_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`, gameID, round, phase, playerID, playerName, eventType, message, time.Now())
@@ -42,7 +48,7 @@ func (db *DB) AddHistoryEvent(gameID string, round int, phase string, playerID,
 
 // GetGameHistory retrieves all history events for a game, ordered chronologically.
 func (db *DB) GetGameHistory(gameID string) ([]*HistoryEvent, error) {
-	rows, err := db.conn.Query(`
+	rows, err := db.query(`
 		SELECT id, game_id, round, phase, player_id, player_name, event_type, message, created_at
 		FROM game_history
 		WHERE game_id = ?
@@ -66,7 +72,7 @@ func (db *DB) GetGameHistory(gameID string) ([]*HistoryEvent, error) {
 
 // GetGameHistorySince retrieves history events after a given ID (for incremental updates).
 func (db *DB) GetGameHistorySince(gameID string, afterID int64) ([]*HistoryEvent, error) {
-	rows, err := db.conn.Query(`
+	rows, err := db.query(`
 		SELECT id, game_id, round, phase, player_id, player_name, event_type, message, created_at
 		FROM game_history
 		WHERE game_id = ? AND id > ?
@@ -90,6 +96,6 @@ func (db *DB) GetGameHistorySince(gameID string, afterID int64) ([]*HistoryEvent
 
 // ClearGameHistory deletes all history for a game (used when game is deleted).
 func (db *DB) ClearGameHistory(gameID string) error {
-	_, err := db.conn.Exec(`DELETE FROM game_history WHERE game_id = ?`, gameID)
+	_, err := db.exec(`DELETE FROM game_history WHERE game_id = ?`, gameID)
 	return err
 }
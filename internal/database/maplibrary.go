@@ -0,0 +1,133 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LibraryMap is a map published to the server's map library.
+type LibraryMap struct {
+	ID             string
+	Name           string
+	AuthorID       string
+	AuthorName     string
+	Width          int
+	Height         int
+	TerritoryCount int
+	PlayCount      int
+	Rating         float64 // Average rating, 0 if unrated
+	RatingCount    int
+	CreatedAt      time.Time
+	MapJSON        string // Only filled in by GetLibraryMap
+}
+
+// MapOrder is how ListLibraryMaps sorts the library.
+type MapOrder string
+
+const (
+	MapOrderTopRated   MapOrder = "rating"
+	MapOrderMostPlayed MapOrder = "plays"
+	MapOrderNewest     MapOrder = "newest"
+)
+
+// ErrMapNotFound is returned when a library map is not found.
+var ErrMapNotFound = errors.New("map not found")
+
+// libraryMapColumns selects a library map with its author and rating summary.
+const libraryMapColumns = `
+	m.id, m.name, m.author_id, COALESCE(p.name, ''), m.width, m.height,
+	m.territory_count, m.play_count, m.created_at,
+	COALESCE((SELECT AVG(rating) FROM map_ratings WHERE map_id = m.id), 0) AS avg_rating,
+	(SELECT COUNT(*) FROM map_ratings WHERE map_id = m.id) AS rating_count`
+
+// PublishMap adds a map to the library under a new ID.
+func (db *DB) PublishMap(authorID, name string, width, height, territoryCount int, mapJSON string) (*LibraryMap, error) {
+	id := uuid.New().String()
+	now := time.Now()
+
+	_, err := db.exec(`
+		INSERT INTO map_library (id, name, author_id, map_json, width, height, territory_count, play_count, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 0, ?)
+	`, id, name, authorID, mapJSON, width, height, territoryCount, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetLibraryMap(id)
+}
+
+// GetLibraryMap retrieves a library map, including its map JSON.
+func (db *DB) GetLibraryMap(id string) (*LibraryMap, error) {
+	var m LibraryMap
+	err := db.queryRow(`
+		SELECT `+libraryMapColumns+`, m.map_json
+		FROM map_library m LEFT JOIN players p ON p.id = m.author_id
+		WHERE m.id = ?
+	`, id).Scan(&m.ID, &m.Name, &m.AuthorID, &m.AuthorName, &m.Width, &m.Height,
+		&m.TerritoryCount, &m.PlayCount, &m.CreatedAt, &m.Rating, &m.RatingCount, &m.MapJSON)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMapNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ListLibraryMaps returns up to limit library maps, without their map JSON.
+func (db *DB) ListLibraryMaps(order MapOrder, limit int) ([]*LibraryMap, error) {
+	orderBy := "m.created_at DESC"
+	switch order {
+	case MapOrderTopRated:
+		orderBy = "avg_rating DESC, rating_count DESC, m.created_at DESC"
+	case MapOrderMostPlayed:
+		orderBy = "m.play_count DESC, m.created_at DESC"
+	}
+
+	rows, err := db.query(`
+		SELECT `+libraryMapColumns+`
+		FROM map_library m LEFT JOIN players p ON p.id = m.author_id
+		ORDER BY `+orderBy+`
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*LibraryMap
+	for rows.Next() {
+		var m LibraryMap
+		if err := rows.Scan(&m.ID, &m.Name, &m.AuthorID, &m.AuthorName, &m.Width, &m.Height,
+			&m.TerritoryCount, &m.PlayCount, &m.CreatedAt, &m.Rating, &m.RatingCount); err != nil {
+			return nil, err
+		}
+		result = append(result, &m)
+	}
+	return result, rows.Err()
+}
+
+// RateMap records a player's rating of a map, replacing any earlier rating.
+func (db *DB) RateMap(mapID, playerID string, rating int) error {
+	_, err := db.exec(`
+		INSERT INTO map_ratings (map_id, player_id, rating, rated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(map_id, player_id) DO UPDATE SET
+			rating = excluded.rating,
+			rated_at = excluded.rated_at
+	`, mapID, playerID, rating, time.Now())
+	return err
+}
+
+// IncrementMapPlays counts a game started on a library map. Maps that are
+// not in the library are ignored.
+func (db *DB) IncrementMapPlays(mapID string) error {
+	_, err := db.exec(`
+		UPDATE map_library SET play_count = play_count + 1 WHERE id = ?
+	`, mapID)
+	return err
+}
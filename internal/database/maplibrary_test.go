@@ -0,0 +1,84 @@
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestMapLibraryRatingsAndOrder(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "lords.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	author, err := db.CreatePlayer("Author")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rater, err := db.CreatePlayer("Rater")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := db.PublishMap(author.ID, "First", 10, 8, 5, `{"id":"a"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := db.PublishMap(author.ID, "Second", 12, 9, 7, `{"id":"b"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.AuthorName != "Author" || first.Rating != 0 || first.RatingCount != 0 {
+		t.Errorf("new map = %+v, want author name and no ratings", first)
+	}
+
+	// A second rating from the same player replaces the first
+	if err := db.RateMap(second.ID, author.ID, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.RateMap(second.ID, rater.ID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.RateMap(second.ID, rater.ID, 5); err != nil {
+		t.Fatal(err)
+	}
+	got, err := db.GetLibraryMap(second.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Rating != 3.5 || got.RatingCount != 2 {
+		t.Errorf("rating = %v from %d, want 3.5 from 2", got.Rating, got.RatingCount)
+	}
+	if got.MapJSON != `{"id":"b"}` {
+		t.Errorf("map JSON = %q", got.MapJSON)
+	}
+
+	if err := db.IncrementMapPlays(first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.IncrementMapPlays("not-in-library"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		order MapOrder
+		want  string
+	}{
+		{MapOrderTopRated, second.ID},
+		{MapOrderMostPlayed, first.ID},
+	} {
+		list, err := db.ListLibraryMaps(tc.order, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != 2 || list[0].ID != tc.want {
+			t.Errorf("%s: first map = %v, want %s", tc.order, list, tc.want)
+		}
+	}
+
+	if _, err := db.GetLibraryMap("missing"); !errors.Is(err, ErrMapNotFound) {
+		t.Errorf("missing map error = %v, want ErrMapNotFound", err)
+	}
+}
@@ -137,4 +137,49 @@ var migrations = []migration{
 			ALTER TABLE games ADD COLUMN win_reason TEXT;
 		`,
 	},
+	{
+		id:   7,
+		name: "add_game_owners",
+		sql: `
+			-- Game owners: which server instance runs each game (leases expire so another can take over)
+			CREATE TABLE game_owners (
+				game_id TEXT PRIMARY KEY,
+				node_id TEXT NOT NULL,
+				expires_at INTEGER NOT NULL, -- Unix milliseconds
+				FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
+			);
+			CREATE INDEX idx_game_owners_node ON game_owners(node_id);
+		`,
+	},
+	{
+		id:   8,
+		name: "add_map_library",
+		sql: `
+			-- Map library: maps published by players, reusable across games
+			CREATE TABLE map_library (
+				id TEXT PRIMARY KEY,
+				name TEXT NOT NULL,
+				author_id TEXT NOT NULL,
+				map_json TEXT NOT NULL,
+				width INTEGER NOT NULL,
+				height INTEGER NOT NULL,
+				territory_count INTEGER NOT NULL,
+				play_count INTEGER DEFAULT 0,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (author_id) REFERENCES players(id)
+			);
+			CREATE INDEX idx_map_library_author ON map_library(author_id);
+
+			-- Map ratings: one 1-5 rating per player per map
+			CREATE TABLE map_ratings (
+				map_id TEXT NOT NULL,
+				player_id TEXT NOT NULL,
+				rating INTEGER NOT NULL,
+				rated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (map_id, player_id),
+				FOREIGN KEY (map_id) REFERENCES map_library(id) ON DELETE CASCADE,
+				FOREIGN KEY (player_id) REFERENCES players(id)
+			);
+		`,
+	},
 }
@@ -0,0 +1,50 @@
+package database
+
+import "time"
+
+// ClaimGameOwner makes nodeID the owner of a game for ttl, unless another node
+// holds a lease that has not expired yet. Claiming a game the node already owns
+// renews its lease. Returns the owning node and when its lease runs out.
+func (db *DB) ClaimGameOwner(gameID, nodeID string, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(db.rebind(`
+		INSERT INTO game_owners (game_id, node_id, expires_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(game_id) DO UPDATE SET
+			node_id = excluded.node_id,
+			expires_at = excluded.expires_at
+		WHERE game_owners.node_id = excluded.node_id OR game_owners.expires_at < ?
+	`), gameID, nodeID, now.Add(ttl).UnixMilli(), now.UnixMilli())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	var owner string
+	var expiresAt int64
+	err = tx.QueryRow(db.rebind(`
+		SELECT node_id, expires_at FROM game_owners WHERE game_id = ?
+	`), gameID).Scan(&owner, &expiresAt)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", time.Time{}, err
+	}
+	return owner, time.UnixMilli(expiresAt), nil
+}
+
+// ReleaseGameOwner gives up a node's lease on a game so any node can claim it.
+func (db *DB) ReleaseGameOwner(gameID, nodeID string) error {
+	_, err := db.exec(`
+		DELETE FROM game_owners WHERE game_id = ? AND node_id = ?
+	`, gameID, nodeID)
+	return err
+}
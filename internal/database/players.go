@@ -31,7 +31,7 @@ func (db *DB) CreatePlayer(name string) (*Player, error) {
 	}
 
 	now := time.Now()
-	_, err = db.conn.Exec(`
+	_, err = db.exec(`
 		INSERT INTO players (id, token, name, created_at, last_seen_at)
 		VALUES (?, ?, ?, ?, ?)
 	`, id, token, name, now, now)
@@ -51,7 +51,7 @@ func (db *DB) CreatePlayer(name string) (*Player, error) {
 // GetPlayerByToken retrieves a player by their token.
 func (db *DB) GetPlayerByToken(token string) (*Player, error) {
 	var p Player
-	err := db.conn.QueryRow(`
+	err := db.queryRow(`
 		SELECT id, token, name, created_at, last_seen_at
 		FROM players WHERE token = ?
 	`, token).Scan(&p.ID, &p.Token, &p.Name, &p.CreatedAt, &p.LastSeenAt)
@@ -68,7 +68,7 @@ func (db *DB) GetPlayerByToken(token string) (*Player, error) {
 // GetPlayerByID retrieves a player by their ID.
 func (db *DB) GetPlayerByID(id string) (*Player, error) {
 	var p Player
-	err := db.conn.QueryRow(`
+	err := db.queryRow(`
 		SELECT id, token, name, created_at, last_seen_at
 		FROM players WHERE id = ?
 	`, id).Scan(&p.ID, &p.Token, &p.Name, &p.CreatedAt, &p.LastSeenAt)
@@ -84,7 +84,7 @@ func (db *DB) GetPlayerByID(id string) (*Player, error) {
 
 // UpdatePlayerName updates a player's display name.
 func (db *DB) UpdatePlayerName(id, name string) error {
-	result, err := db.conn.Exec(`
+	result, err := db.exec(`
 		UPDATE players SET name = ? WHERE id = ?
 	`, name, id)
 	if err != nil {
@@ -103,7 +103,7 @@ func (db *DB) UpdatePlayerName(id, name string) error {
 
 // UpdatePlayerLastSeen updates the last seen timestamp.
 func (db *DB) UpdatePlayerLastSeen(id string) error {
-	_, err := db.conn.Exec(`
+	_, err := db.exec(`
 		UPDATE players SET last_seen_at = ? WHERE id = ?
 	`, time.Now(), id)
 	return err
@@ -0,0 +1,20 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/lib/pq"
+)
+
+// NewPostgres connects to a PostgreSQL database, e.g.
+// "postgres://lords:secret@db:5432/lords?sslmode=disable", and runs the same
+// migrations as the SQLite backend.
+func NewPostgres(dsn string) (*DB, error) {
+	conn, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	return open(conn, dialectPostgres)
+}
@@ -0,0 +1,65 @@
+package database
+
+import "time"
+
+// Store is the storage the server runs on. DB implements it for SQLite (New)
+// and PostgreSQL (NewPostgres).
+type Store interface {
+	Close() error
+
+	// Players
+	CreatePlayer(name string) (*Player, error)
+	GetPlayerByToken(token string) (*Player, error)
+	GetPlayerByID(id string) (*Player, error)
+	UpdatePlayerName(id, name string) error
+	UpdatePlayerLastSeen(id string) error
+
+	// Games and lobbies
+	CreateGame(name string, hostPlayerID string, settings GameSettings, isPublic bool, mapJSON string) (*Game, error)
+	GetGame(id string) (*Game, error)
+	GetGameByJoinCode(code string) (*Game, error)
+	UpdateGameSetting(gameID, key, value string) error
+	ListPublicGames() ([]*GameInfo, error)
+	GetPlayerGames(playerID string) ([]*GameInfo, error)
+	GetStartedGameIDs() ([]string, error)
+	StartGame(gameID string) error
+	EndGame(gameID string, winnerID string, reason string) error
+	DeleteGame(gameID string) error
+	CleanupAbandonedLobbies() error
+	GetGameMapJSON(gameID string) (string, error)
+	UpdateGameMap(gameID, mapJSON string) error
+
+	// Players in games
+	JoinGame(gameID, playerID, color string) error
+	LeaveGame(gameID, playerID string) error
+	GetGamePlayers(gameID string) ([]*GamePlayer, error)
+	SetPlayerReady(gameID, playerID string, ready bool) error
+	SetPlayerConnected(gameID, playerID string, connected bool) error
+	UpdatePlayerColor(gameID, playerID, color string) error
+	SetAllianceSetting(gameID, playerID, setting string) error
+	GetAllianceSetting(gameID, playerID string) (string, error)
+	AddAIPlayer(gameID, color, personality string) error
+
+	// Game state and logs
+	SaveGameState(gameID string, stateJSON string, currentPlayerID string, round int, phase string) error
+	GetGameState(gameID string) (string, error)
+	GetGameStateIDs() ([]string, error)
+	LogAction(gameID, playerID, actionType, actionJSON, resultJSON string) error
+	AddHistoryEvent(gameID string, round int, phase string, playerID, playerName, eventType, message string) error
+	GetGameHistory(gameID string) ([]*HistoryEvent, error)
+	GetGameHistorySince(gameID string, afterID int64) ([]*HistoryEvent, error)
+	ClearGameHistory(gameID string) error
+
+	// Map library
+	PublishMap(authorID, name string, width, height, territoryCount int, mapJSON string) (*LibraryMap, error)
+	GetLibraryMap(id string) (*LibraryMap, error)
+	ListLibraryMaps(order MapOrder, limit int) ([]*LibraryMap, error)
+	RateMap(mapID, playerID string, rating int) error
+	IncrementMapPlays(mapID string) error
+
+	// Game ownership when running several server instances
+	ClaimGameOwner(gameID, nodeID string, ttl time.Duration) (string, time.Time, error)
+	ReleaseGameOwner(gameID, nodeID string) error
+}
+
+var _ Store = (*DB)(nil)
@@ -0,0 +1,260 @@
+package game
+
+import "math/rand"
+
+// cardStep is when in a card battle an effect is applied. The steps run in
+// order, each over the attack cards and then the defense cards in play.
+type cardStep int
+
+const (
+	stepAutoWin  cardStep = iota // May decide the battle outright (Bribe)
+	stepNegate                   // Cancels the other side's cards or units
+	stepSynergy                  // Bonuses that count units on the map
+	stepMultiply                 // Multiplies the base strength
+	stepBonus                    // Flat bonuses of the card's value
+	stepAfter                    // Runs once the winner is known
+)
+
+// cardBattle is a card battle being resolved, for effects to work on.
+type cardBattle struct {
+	g          *GameState
+	attackerID string
+	target     *Territory
+	brought    *BroughtUnit
+
+	played  []CombatCard // Attack cards as played, before any were negated
+	attack  []CombatCard // Attack cards still in play
+	defense []CombatCard // Defense cards in play
+
+	attackMultiplier  int
+	defenseMultiplier int
+	attackBonus       int // Added to attack after the multiplier
+	attackPenalty     int // Taken from attack after the multiplier
+	defenseBonus      int // Added to defense after the multiplier
+
+	result *CardResolutionResult
+}
+
+// cardEffect is how an effect plays out: the step it belongs to and what it
+// does to the battle for each card with it in play.
+type cardEffect struct {
+	step  cardStep
+	apply func(b *cardBattle, c CombatCard)
+}
+
+// cardEffects holds every effect a card set can use. A new effect only
+// needs an entry here and a card in a set that uses it.
+var cardEffects = map[CardEffect]cardEffect{
+	// Flat bonuses
+	EffectSkirmish:  {stepBonus, addCardValue},
+	EffectAdvance:   {stepBonus, addCardValue},
+	EffectCharge:    {stepBonus, addCardValue},
+	EffectAssault:   {stepBonus, addCardValue},
+	EffectFortify:   {stepBonus, addCardValue},
+	EffectBarricade: {stepBonus, addCardValue},
+	EffectEntrench:  {stepBonus, addCardValue},
+	EffectBunker:    {stepBonus, addCardValue},
+
+	// Attack
+	EffectRallyCavalry: {stepSynergy, func(b *cardBattle, c CombatCard) {
+		b.attackBonus += b.countAdjacent(b.attackerID, func(t *Territory) int { return boolInt(t.HasHorse) })
+	}},
+	EffectArsenal: {stepSynergy, func(b *cardBattle, c CombatCard) {
+		b.attackBonus += b.countAdjacent(b.attackerID, func(t *Territory) int { return boolInt(t.HasWeapon) })
+	}},
+	EffectNavalBombardment: {stepSynergy, func(b *cardBattle, c CombatCard) {
+		b.attackBonus += 2 * b.countAdjacent(b.attackerID, (*Territory).TotalBoats)
+	}},
+	EffectFlank: {stepSynergy, func(b *cardBattle, c CombatCard) {
+		b.attackBonus += c.Value * b.countAdjacent(b.attackerID, func(*Territory) int { return 1 })
+	}},
+	EffectDoubleAttack: {stepMultiply, func(b *cardBattle, c CombatCard) {
+		b.attackMultiplier = 2
+	}},
+	EffectSafeRetreat: {stepAfter, func(b *cardBattle, c CombatCard) {
+		if !b.result.AttackerWins {
+			b.result.SafeRetreat = true
+		}
+	}},
+	EffectBlitz: {stepAfter, func(b *cardBattle, c CombatCard) {
+		if !b.result.AttackerWins || b.result.BlitzReturn != nil {
+			return
+		}
+		// Return all the played attack cards except Blitz itself
+		for _, rc := range b.played {
+			if rc.Effect != EffectBlitz {
+				b.result.BlitzReturn = append(b.result.BlitzReturn, rc)
+			}
+		}
+	}},
+
+	// Defense
+	EffectBribe: {stepAutoWin, func(b *cardBattle, c CombatCard) {
+		defender := b.g.Players[b.target.Owner]
+		if b.result.BribeActivated || defender == nil || defender.Stockpile.Gold < BribeCost {
+			// Bribe fizzles if can't afford -- card is still consumed
+			return
+		}
+		defender.Stockpile.Gold -= BribeCost
+		b.result.BribeActivated = true
+	}},
+	EffectShieldWall: {stepNegate, func(b *cardBattle, c CombatCard) {
+		if len(b.attack) == 0 {
+			return
+		}
+		negIdx := rand.Intn(len(b.attack))
+		b.result.NegatedCards = append(b.result.NegatedCards, b.attack[negIdx])
+		b.attack = append(b.attack[:negIdx:negIdx], b.attack[negIdx+1:]...)
+	}},
+	EffectSabotage: {stepNegate, func(b *cardBattle, c CombatCard) {
+		// Count weapons contributing to the attack from adjacent territories
+		weaponCount := b.countAdjacent(b.attackerID, func(t *Territory) int { return boolInt(t.HasWeapon) })
+		// Also count a brought weapon
+		if brought := b.brought; brought != nil {
+			if brought.UnitType == UnitWeapon && !b.g.IsAdjacent(brought.FromTerritory, b.target.ID) {
+				weaponCount++
+			}
+			if brought.CarryingWeapon && !b.g.IsAdjacent(brought.WeaponFromTerritory, b.target.ID) {
+				weaponCount++
+			}
+		}
+		b.attackPenalty += weaponCount * 3
+		b.result.SabotageCount = weaponCount
+	}},
+	EffectAmbush: {stepSynergy, func(b *cardBattle, c CombatCard) {
+		b.defenseBonus += c.Value * b.countAdjacent(b.target.Owner, func(*Territory) int { return 1 })
+	}},
+	EffectDoubleDefense: {stepMultiply, func(b *cardBattle, c CombatCard) {
+		b.defenseMultiplier = 2
+	}},
+	EffectMilitia: {stepSynergy, func(b *cardBattle, c CombatCard) {
+		if b.target.HasCity {
+			b.defenseBonus += c.Value
+		} else {
+			b.defenseBonus++
+		}
+	}},
+	EffectCounterAttack: {stepAfter, func(b *cardBattle, c CombatCard) {
+		if b.result.AttackerWins || b.result.CounterAttackTerr != "" {
+			return
+		}
+		// Capture a random attacker territory adjacent to the target
+		adjTerritories := make([]string, 0)
+		for _, adjID := range b.target.Adjacent {
+			if b.g.Territories[adjID].Owner == b.attackerID {
+				adjTerritories = append(adjTerritories, adjID)
+			}
+		}
+		if len(adjTerritories) > 0 {
+			b.result.CounterAttackTerr = adjTerritories[rand.Intn(len(adjTerritories))]
+		}
+	}},
+}
+
+// addCardValue adds a card's value to its side's strength.
+func addCardValue(b *cardBattle, c CombatCard) {
+	if c.CardType == CardTypeAttack {
+		b.attackBonus += c.Value
+	} else {
+		b.defenseBonus += c.Value
+	}
+}
+
+// countAdjacent sums count over the territories next to the target that a
+// player owns.
+func (b *cardBattle) countAdjacent(playerID string, count func(*Territory) int) int {
+	total := 0
+	for _, adjID := range b.target.Adjacent {
+		if adj := b.g.Territories[adjID]; adj.Owner == playerID {
+			total += count(adj)
+		}
+	}
+	return total
+}
+
+// boolInt returns 1 for true and 0 for false.
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// applyStep applies the effects of one step, attack cards first. It works
+// on a copy of the hands as they stood, so a card negated during the step
+// still has its own effect this step.
+func (b *cardBattle) applyStep(step cardStep) {
+	attack := append([]CombatCard(nil), b.attack...)
+	for _, hand := range [][]CombatCard{attack, b.defense} {
+		for _, c := range hand {
+			if effect, ok := cardEffects[c.Effect]; ok && effect.step == step {
+				effect.apply(b, c)
+			}
+		}
+	}
+}
+
+// CardResolutionResult contains the output of card-based combat resolution.
+type CardResolutionResult struct {
+	FinalAttack       int          // Attack strength after all card effects
+	FinalDefense      int          // Defense strength after all card effects
+	AttackerWins      bool         // Result of the combat
+	BribeActivated    bool         // True if Bribe auto-won defense
+	CounterAttackTerr string       // Territory captured by counter-attack (empty if none)
+	SafeRetreat       bool         // True if brought unit should be protected on loss
+	BlitzReturn       []CombatCard // Cards returned to attacker by Blitz
+	NegatedCards      []CombatCard // Attack cards negated by Shield Wall
+	SabotageCount     int          // Number of weapons negated by Sabotage
+}
+
+// ResolveCards applies card effects and determines combat outcome.
+// baseAttack/baseDefense are the pre-card strength values from CalculateAttack/DefenseStrength.
+// Each card's effect is looked up in cardEffects and applied in step order;
+// negated cards have no effect at all. Multipliers apply to the base strength
+// only. The final comparison uses the game's existing ResolveCombat
+// (ChanceLevel) formula.
+func (g *GameState) ResolveCards(
+	attackerID string,
+	target *Territory,
+	baseAttack, baseDefense int,
+	attackCards, defenseCards []CombatCard,
+	brought *BroughtUnit,
+) *CardResolutionResult {
+	b := &cardBattle{
+		g:                 g,
+		attackerID:        attackerID,
+		target:            target,
+		brought:           brought,
+		played:            attackCards,
+		attack:            append([]CombatCard(nil), attackCards...),
+		defense:           append([]CombatCard(nil), defenseCards...),
+		attackMultiplier:  1,
+		defenseMultiplier: 1,
+		result:            &CardResolutionResult{},
+	}
+	result := b.result
+
+	b.applyStep(stepAutoWin)
+	if result.BribeActivated {
+		result.FinalAttack = baseAttack
+		result.FinalDefense = baseDefense
+		// Safe Retreat still saves the brought unit
+		for _, c := range b.attack {
+			if c.Effect == EffectSafeRetreat {
+				result.SafeRetreat = true
+			}
+		}
+		return result
+	}
+
+	for _, step := range []cardStep{stepNegate, stepSynergy, stepMultiply, stepBonus} {
+		b.applyStep(step)
+	}
+
+	result.FinalAttack = max(baseAttack*b.attackMultiplier+b.attackBonus-b.attackPenalty, 0)
+	result.FinalDefense = max(baseDefense*b.defenseMultiplier+b.defenseBonus, 0)
+	result.AttackerWins = g.ResolveCombat(result.FinalAttack, result.FinalDefense)
+
+	b.applyStep(stepAfter)
+	return result
+}
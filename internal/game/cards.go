@@ -1,9 +1,6 @@
 package game
 
-import (
-	"fmt"
-	"math/rand"
-)
+import "fmt"
 
 // CombatMode determines which combat system is used.
 type CombatMode int
@@ -66,6 +63,7 @@ const (
 	EffectSafeRetreat       CardEffect = "safe_retreat"       // Brought unit returns home on loss
 	EffectDoubleAttack      CardEffect = "double_attack"      // 2x base attack
 	EffectBlitz             CardEffect = "blitz"              // Win = return played attack cards
+	EffectFlank             CardEffect = "flank"              // +value per adjacent territory
 )
 
 // Defense card effects
@@ -79,6 +77,8 @@ const (
 	EffectDoubleDefense CardEffect = "double_defense" // 2x base defense
 	EffectCounterAttack CardEffect = "counter_attack" // Win = capture random attacker territory
 	EffectBribe         CardEffect = "bribe"          // Pay 3 gold to auto-win defense
+	EffectAmbush        CardEffect = "ambush"         // +value per neighbouring territory held
+	EffectMilitia       CardEffect = "militia"        // +value in a city, +1 elsewhere
 )
 
 // MaxAttackCards is the maximum attack cards a player can hold.
@@ -93,6 +93,15 @@ const CardBuyCost = 2
 // BribeCost is the gold cost to activate the Bribe card effect.
 const BribeCost = 3
 
+// discardValues is how many of a resource a card of each rarity is
+// discarded for.
+var discardValues = map[CardRarity]int{
+	RarityCommon:    1,
+	RarityUncommon:  2,
+	RarityRare:      3,
+	RarityUltraRare: 4,
+}
+
 // CombatCard represents a card in a player's hand.
 type CombatCard struct {
 	ID          string     `json:"id"`          // Unique instance ID (e.g., "atk_skirmish_1")
@@ -104,86 +113,9 @@ type CombatCard struct {
 	Value       int        `json:"value"`       // Flat bonus value (0 for special effects)
 }
 
-// cardTemplate defines a card type in the catalog.
-type cardTemplate struct {
-	Name        string
-	Description string
-	CardType    CardType
-	Rarity      CardRarity
-	Effect      CardEffect
-	Value       int
-	Weight      int // Relative draw weight within catalog (out of 1000)
-}
-
-// attackCardTemplates defines all attack card types with their draw weights.
-var attackCardTemplates = []cardTemplate{
-	// Common (50% total) -- 25% each
-	{Name: "Skirmish", Description: "+1 attack", CardType: CardTypeAttack, Rarity: RarityCommon, Effect: EffectSkirmish, Value: 1, Weight: 250},
-	{Name: "Advance", Description: "+2 attack", CardType: CardTypeAttack, Rarity: RarityCommon, Effect: EffectAdvance, Value: 2, Weight: 250},
-	// Uncommon (30% total) -- 10% each
-	{Name: "Charge", Description: "+3 attack", CardType: CardTypeAttack, Rarity: RarityUncommon, Effect: EffectCharge, Value: 3, Weight: 100},
-	{Name: "Rally Cavalry", Description: "+1 attack per adjacent horse", CardType: CardTypeAttack, Rarity: RarityUncommon, Effect: EffectRallyCavalry, Value: 0, Weight: 100},
-	{Name: "Arsenal", Description: "+1 attack per adjacent weapon", CardType: CardTypeAttack, Rarity: RarityUncommon, Effect: EffectArsenal, Value: 0, Weight: 100},
-	// Rare (15% total) -- 5% each
-	{Name: "Assault", Description: "+5 attack", CardType: CardTypeAttack, Rarity: RarityRare, Effect: EffectAssault, Value: 5, Weight: 50},
-	{Name: "Naval Bombardment", Description: "Adjacent boats join attack (+2 each)", CardType: CardTypeAttack, Rarity: RarityRare, Effect: EffectNavalBombardment, Value: 0, Weight: 50},
-	{Name: "Safe Retreat", Description: "Brought unit returns home on loss", CardType: CardTypeAttack, Rarity: RarityRare, Effect: EffectSafeRetreat, Value: 0, Weight: 50},
-	// Ultra-Rare (5% total) -- 2.5% each
-	{Name: "Double Attack", Description: "Double base attack strength", CardType: CardTypeAttack, Rarity: RarityUltraRare, Effect: EffectDoubleAttack, Value: 0, Weight: 25},
-	{Name: "Blitz", Description: "Win: return all other played attack cards", CardType: CardTypeAttack, Rarity: RarityUltraRare, Effect: EffectBlitz, Value: 0, Weight: 25},
-}
-
-// defenseCardTemplates defines all defense card types with their draw weights.
-var defenseCardTemplates = []cardTemplate{
-	// Common (50% total) -- 25% each
-	{Name: "Fortify", Description: "+1 defense", CardType: CardTypeDefense, Rarity: RarityCommon, Effect: EffectFortify, Value: 1, Weight: 250},
-	{Name: "Barricade", Description: "+2 defense", CardType: CardTypeDefense, Rarity: RarityCommon, Effect: EffectBarricade, Value: 2, Weight: 250},
-	// Uncommon (30% total) -- 10% each
-	{Name: "Entrench", Description: "+3 defense", CardType: CardTypeDefense, Rarity: RarityUncommon, Effect: EffectEntrench, Value: 3, Weight: 100},
-	{Name: "Shield Wall", Description: "Negate one random attack card", CardType: CardTypeDefense, Rarity: RarityUncommon, Effect: EffectShieldWall, Value: 0, Weight: 100},
-	{Name: "Sabotage", Description: "Negate weapon contributions to attack", CardType: CardTypeDefense, Rarity: RarityUncommon, Effect: EffectSabotage, Value: 0, Weight: 100},
-	// Rare (15% total) -- 7.5% each
-	{Name: "Bunker", Description: "+5 defense", CardType: CardTypeDefense, Rarity: RarityRare, Effect: EffectBunker, Value: 5, Weight: 75},
-	{Name: "Double Defense", Description: "Double base defense strength", CardType: CardTypeDefense, Rarity: RarityRare, Effect: EffectDoubleDefense, Value: 0, Weight: 75},
-	// Ultra-Rare (5% total) -- 2.5% each
-	{Name: "Counter-Attack", Description: "Win: capture random attacker territory", CardType: CardTypeDefense, Rarity: RarityUltraRare, Effect: EffectCounterAttack, Value: 0, Weight: 25},
-	{Name: "Bribe", Description: "Pay 3 gold to auto-win defense", CardType: CardTypeDefense, Rarity: RarityUltraRare, Effect: EffectBribe, Value: 0, Weight: 25},
-}
-
 // cardIDCounter is used to generate unique card instance IDs.
 var cardIDCounter int
 
-// DrawCard randomly draws a card of the given type based on rarity weights.
-func DrawCard(cardType CardType) CombatCard {
-	var templates []cardTemplate
-	if cardType == CardTypeAttack {
-		templates = attackCardTemplates
-	} else {
-		templates = defenseCardTemplates
-	}
-
-	// Sum total weight
-	totalWeight := 0
-	for _, t := range templates {
-		totalWeight += t.Weight
-	}
-
-	// Roll random number
-	roll := rand.Intn(totalWeight)
-
-	// Find the card
-	cumulative := 0
-	for _, t := range templates {
-		cumulative += t.Weight
-		if roll < cumulative {
-			return newCardFromTemplate(t)
-		}
-	}
-
-	// Fallback (shouldn't happen)
-	return newCardFromTemplate(templates[0])
-}
-
 // newCardFromTemplate creates a card instance from a template.
 func newCardFromTemplate(t cardTemplate) CombatCard {
 	cardIDCounter++
@@ -198,235 +130,6 @@ func newCardFromTemplate(t cardTemplate) CombatCard {
 	}
 }
 
-// CardResolutionResult contains the output of card-based combat resolution.
-type CardResolutionResult struct {
-	FinalAttack       int          // Attack strength after all card effects
-	FinalDefense      int          // Defense strength after all card effects
-	AttackerWins      bool         // Result of the combat
-	BribeActivated    bool         // True if Bribe auto-won defense
-	CounterAttackTerr string       // Territory captured by counter-attack (empty if none)
-	SafeRetreat       bool         // True if brought unit should be protected on loss
-	BlitzReturn       []CombatCard // Cards returned to attacker by Blitz
-	NegatedCards      []CombatCard // Attack cards negated by Shield Wall
-	SabotageCount     int          // Number of weapons negated by Sabotage
-}
-
-// ResolveCards applies card effects and determines combat outcome.
-// baseAttack/baseDefense are the pre-card strength values from CalculateAttack/DefenseStrength.
-// The final comparison uses the game's existing ResolveCombat (ChanceLevel) formula.
-func (g *GameState) ResolveCards(
-	attackerID string,
-	target *Territory,
-	baseAttack, baseDefense int,
-	attackCards, defenseCards []CombatCard,
-	brought *BroughtUnit,
-) *CardResolutionResult {
-	result := &CardResolutionResult{
-		FinalAttack:  baseAttack,
-		FinalDefense: baseDefense,
-	}
-
-	// Build working copies of card lists (so we can remove negated cards)
-	activeAttackCards := make([]CombatCard, len(attackCards))
-	copy(activeAttackCards, attackCards)
-	activeDefenseCards := make([]CombatCard, len(defenseCards))
-	copy(activeDefenseCards, defenseCards)
-
-	// --- Step 2: Auto-win check (Bribe) ---
-	bribeIdx := -1
-	for i, c := range activeDefenseCards {
-		if c.Effect == EffectBribe {
-			bribeIdx = i
-			break
-		}
-	}
-	if bribeIdx >= 0 {
-		defender := g.Players[target.Owner]
-		if defender != nil && defender.Stockpile.Gold >= BribeCost {
-			defender.Stockpile.Gold -= BribeCost
-			result.BribeActivated = true
-			result.AttackerWins = false
-
-			// Check for Safe Retreat even on bribe
-			for _, c := range activeAttackCards {
-				if c.Effect == EffectSafeRetreat {
-					result.SafeRetreat = true
-					break
-				}
-			}
-			return result
-		}
-		// Bribe fizzles if can't afford -- card is still consumed (already removed from hand)
-	}
-
-	// --- Step 3: Negation effects ---
-
-	// Shield Wall: negate one random attack card
-	for _, c := range activeDefenseCards {
-		if c.Effect == EffectShieldWall && len(activeAttackCards) > 0 {
-			negIdx := rand.Intn(len(activeAttackCards))
-			result.NegatedCards = append(result.NegatedCards, activeAttackCards[negIdx])
-			activeAttackCards = append(activeAttackCards[:negIdx], activeAttackCards[negIdx+1:]...)
-		}
-	}
-
-	// Sabotage: negate weapon contributions to attack strength
-	for _, c := range activeDefenseCards {
-		if c.Effect == EffectSabotage {
-			// Count weapons that were contributing to attack (adjacent territories)
-			weaponCount := 0
-			for _, adjID := range target.Adjacent {
-				adj := g.Territories[adjID]
-				if adj.Owner == attackerID && adj.HasWeapon {
-					weaponCount++
-				}
-			}
-			// Also count brought weapon if applicable
-			if brought != nil {
-				if brought.UnitType == UnitWeapon && !g.IsAdjacent(brought.FromTerritory, target.ID) {
-					weaponCount++
-				}
-				if brought.CarryingWeapon && !g.IsAdjacent(brought.WeaponFromTerritory, target.ID) {
-					weaponCount++
-				}
-			}
-			reduction := weaponCount * 3
-			result.FinalAttack -= reduction
-			result.SabotageCount = weaponCount
-		}
-	}
-
-	// --- Step 4: Unit synergy ---
-	for _, c := range activeAttackCards {
-		switch c.Effect {
-		case EffectRallyCavalry:
-			// +1 per adjacent horse owned by attacker
-			for _, adjID := range target.Adjacent {
-				adj := g.Territories[adjID]
-				if adj.Owner == attackerID && adj.HasHorse {
-					result.FinalAttack++
-				}
-			}
-		case EffectArsenal:
-			// +1 per adjacent weapon owned by attacker
-			for _, adjID := range target.Adjacent {
-				adj := g.Territories[adjID]
-				if adj.Owner == attackerID && adj.HasWeapon {
-					result.FinalAttack++
-				}
-			}
-		case EffectNavalBombardment:
-			// +2 per boat on adjacent territories owned by attacker
-			for _, adjID := range target.Adjacent {
-				adj := g.Territories[adjID]
-				if adj.Owner == attackerID {
-					result.FinalAttack += adj.TotalBoats() * 2
-				}
-			}
-		}
-	}
-
-	// --- Step 5: Multipliers (applied to base strength, before flat bonuses) ---
-	attackMultiplier := 1
-	defenseMultiplier := 1
-
-	for _, c := range activeAttackCards {
-		if c.Effect == EffectDoubleAttack {
-			attackMultiplier = 2
-		}
-	}
-	for _, c := range activeDefenseCards {
-		if c.Effect == EffectDoubleDefense {
-			defenseMultiplier = 2
-		}
-	}
-
-	// Apply multipliers to the current strength (which is base + sabotage reduction + synergy)
-	// We want to multiply only the base, so recalculate:
-	// finalAttack = baseAttack * multiplier + (synergy bonuses) - (sabotage reduction)
-	// Actually, per the plan: multipliers apply to base strength only, before flat bonuses.
-	// So we need to track separately.
-
-	// Recalculate: start from base, apply multiplier, then re-add synergy and sabotage
-	synergyBonus := result.FinalAttack - baseAttack + (result.SabotageCount * 3) // undo sabotage to get synergy
-	sabotageReduction := result.SabotageCount * 3
-
-	result.FinalAttack = (baseAttack * attackMultiplier) + synergyBonus - sabotageReduction
-	result.FinalDefense = baseDefense * defenseMultiplier
-
-	// --- Step 6: Flat bonuses ---
-	for _, c := range activeAttackCards {
-		if c.Value > 0 {
-			result.FinalAttack += c.Value
-		}
-	}
-	for _, c := range activeDefenseCards {
-		if c.Value > 0 {
-			result.FinalDefense += c.Value
-		}
-	}
-
-	// Ensure minimum of 0
-	if result.FinalAttack < 0 {
-		result.FinalAttack = 0
-	}
-	if result.FinalDefense < 0 {
-		result.FinalDefense = 0
-	}
-
-	// --- Step 7: Final comparison using existing ChanceLevel formula ---
-	result.AttackerWins = g.ResolveCombat(result.FinalAttack, result.FinalDefense)
-
-	// --- Step 8: Post-combat effects ---
-
-	// Safe Retreat: if attacker loses, brought unit is not destroyed
-	if !result.AttackerWins {
-		for _, c := range activeAttackCards {
-			if c.Effect == EffectSafeRetreat {
-				result.SafeRetreat = true
-				break
-			}
-		}
-	}
-
-	// Blitz: if attacker wins, return all other played attack cards to hand
-	if result.AttackerWins {
-		for _, c := range attackCards {
-			if c.Effect == EffectBlitz {
-				// Return all attack cards EXCEPT Blitz itself
-				for _, rc := range attackCards {
-					if rc.Effect != EffectBlitz {
-						result.BlitzReturn = append(result.BlitzReturn, rc)
-					}
-				}
-				break
-			}
-		}
-	}
-
-	// Counter-Attack: if defender wins, capture a random attacker adjacent territory
-	if !result.AttackerWins {
-		for _, c := range defenseCards {
-			if c.Effect == EffectCounterAttack {
-				// Find attacker's territories adjacent to the target
-				adjTerritories := make([]string, 0)
-				for _, adjID := range target.Adjacent {
-					adj := g.Territories[adjID]
-					if adj.Owner == attackerID {
-						adjTerritories = append(adjTerritories, adjID)
-					}
-				}
-				if len(adjTerritories) > 0 {
-					result.CounterAttackTerr = adjTerritories[rand.Intn(len(adjTerritories))]
-				}
-				break
-			}
-		}
-	}
-
-	return result
-}
-
 // CanBuyCard checks if a player can buy a card of the given type.
 func (g *GameState) CanBuyCard(playerID string, cardType CardType, resource ResourceType) error {
 	if g.Phase != PhaseDevelopment {
@@ -484,16 +187,152 @@ func (g *GameState) BuyCard(playerID string, cardType CardType, resource Resourc
 	player.Stockpile.Remove(resource, CardBuyCost)
 
 	// Draw a random card
-	card := DrawCard(cardType)
+	card := g.CardSet().Draw(cardType, "")
 
 	// Add to hand
 	if cardType == CardTypeAttack {
-		player.AttackCards = append(player.AttackCards, card)
+		player.AttackCards = append(player.AttackCards, *card)
 	} else {
-		player.DefenseCards = append(player.DefenseCards, card)
+		player.DefenseCards = append(player.DefenseCards, *card)
 	}
 
-	return &card, nil
+	return card, nil
+}
+
+// checkCardTurn checks that a player may manage their cards: it's card mode
+// and their turn in the Development phase.
+func (g *GameState) checkCardTurn(playerID string) (*Player, error) {
+	if g.Phase != PhaseDevelopment || g.Settings.CombatMode != CombatModeCards {
+		return nil, ErrInvalidAction
+	}
+	if g.CurrentPlayerID != playerID {
+		return nil, ErrNotYourTurn
+	}
+	player := g.Players[playerID]
+	if player == nil {
+		return nil, ErrInvalidTarget
+	}
+	return player, nil
+}
+
+// DiscardCard discards a card from a player's hand during the Development
+// phase for some of a resource: one for a common card, up to four for an
+// ultra-rare. With the economy ruleset on, whatever would go over the storage
+// cap spoils, as it does at production, and a player already at the cap keeps
+// the card. Returns how many were gained.
+func (g *GameState) DiscardCard(playerID, cardID string, resource ResourceType) (int, error) {
+	player, err := g.checkCardTurn(playerID)
+	if err != nil {
+		return 0, err
+	}
+	if !resource.IsStockpilable() {
+		return 0, ErrInvalidTarget
+	}
+
+	card := player.GetCardByID(cardID)
+	if card == nil {
+		return 0, ErrInvalidTarget
+	}
+	amount := discardValues[card.Rarity]
+	if limit := g.StorageCap(playerID); limit > 0 {
+		amount = max(min(amount, limit-player.Holding(resource)), 0)
+		if amount == 0 {
+			return 0, ErrStackFull
+		}
+	}
+	player.RemoveCardFromHand(cardID)
+	player.Stockpile.Add(resource, amount)
+	return amount, nil
+}
+
+// CombineCards trades two common cards of the same type in a player's hand
+// for a random uncommon card of that type during the Development phase.
+// Returns the new card.
+func (g *GameState) CombineCards(playerID, firstID, secondID string) (*CombatCard, error) {
+	player, err := g.checkCardTurn(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	first, second := player.GetCardByID(firstID), player.GetCardByID(secondID)
+	if first == nil || second == nil || firstID == secondID {
+		return nil, ErrInvalidTarget
+	}
+	if first.Rarity != RarityCommon || second.Rarity != RarityCommon || first.CardType != second.CardType {
+		return nil, ErrInvalidAction
+	}
+
+	card := g.CardSet().Draw(first.CardType, RarityUncommon)
+	if card == nil {
+		return nil, ErrInvalidAction
+	}
+	player.RemoveCardsFromHand([]string{firstID, secondID})
+	if card.CardType == CardTypeAttack {
+		player.AttackCards = append(player.AttackCards, *card)
+	} else {
+		player.DefenseCards = append(player.DefenseCards, *card)
+	}
+	return card, nil
+}
+
+// PendingCardBattle is a card battle waiting for a human defender to choose
+// defense cards. It is kept in the game state so that it survives a restart.
+type PendingCardBattle struct {
+	ID              string       `json:"id"`
+	AttackerID      string       `json:"attackerId"`
+	DefenderID      string       `json:"defenderId"`
+	TargetTerritory string       `json:"targetTerritory"`
+	BroughtUnit     *BroughtUnit `json:"broughtUnit,omitempty"`
+	AttackerAllies  []string     `json:"attackerAllies,omitempty"`
+	DefenderAllies  []string     `json:"defenderAllies,omitempty"`
+	AttackCards     []CombatCard `json:"attackCards,omitempty"` // Already out of the attacker's hand
+}
+
+// BeginCardBattle commits the attacker's cards to a card battle that waits on
+// the defender: the attack cards among cardIDs leave the attacker's hand and
+// the battle is kept in the state until EndCardBattle. No attack can be made,
+// nor the attacker's turn ended, while it waits.
+func (g *GameState) BeginCardBattle(battle *PendingCardBattle, cardIDs []string) error {
+	if g.Phase != PhaseConquest {
+		return ErrInvalidAction
+	}
+	if g.PendingCardBattle != nil {
+		return ErrCardBattlePending
+	}
+	if g.CurrentPlayerID != battle.AttackerID {
+		return ErrNotYourTurn
+	}
+	attacker := g.Players[battle.AttackerID]
+	if attacker == nil {
+		return ErrInvalidTarget
+	}
+	if attacker.AttacksRemaining <= 0 {
+		return ErrNoAttacksRemaining
+	}
+
+	battle.AttackCards = attacker.TakeCards(CardTypeAttack, cardIDs)
+	g.PendingCardBattle = battle
+	return nil
+}
+
+// EndCardBattle clears the card battle waiting on a defender and returns it,
+// or nil if there is none.
+func (g *GameState) EndCardBattle() *PendingCardBattle {
+	battle := g.PendingCardBattle
+	g.PendingCardBattle = nil
+	return battle
+}
+
+// TakeCards removes the cards of one type among the given IDs from a
+// player's hand and returns them. Other IDs are ignored.
+func (p *Player) TakeCards(cardType CardType, cardIDs []string) []CombatCard {
+	var taken []CombatCard
+	for _, id := range cardIDs {
+		if c := p.GetCardByID(id); c != nil && c.CardType == cardType {
+			taken = append(taken, *p.RemoveCardFromHand(id))
+		}
+	}
+	return taken
 }
 
 // RemoveCardFromHand removes a card by ID from the appropriate hand.
@@ -563,6 +402,12 @@ func (p *Player) ReturnCardsToHand(cards []CombatCard) {
 	}
 }
 
+// DeckSize returns how many combat cards a player holds. Unlike the cards
+// themselves, this is public.
+func (p *Player) DeckSize() int {
+	return len(p.AttackCards) + len(p.DefenseCards)
+}
+
 // GetCardByID finds a card in the player's hand by ID.
 func (p *Player) GetCardByID(cardID string) *CombatCard {
 	for i := range p.AttackCards {
@@ -0,0 +1,151 @@
+package game
+
+import "testing"
+
+// testCard makes a card from the first template in a set with an effect.
+func testCard(t *testing.T, set string, effect CardEffect) CombatCard {
+	t.Helper()
+	for _, tmpl := range CardSets[set].Cards {
+		if tmpl.Effect == effect {
+			return newCardFromTemplate(tmpl)
+		}
+	}
+	t.Fatalf("set %s has no %s card", set, effect)
+	return CombatCard{}
+}
+
+func TestCardSets(t *testing.T) {
+	for _, id := range CardSetIDs() {
+		set := CardSets[id]
+		for _, cardType := range []CardType{CardTypeAttack, CardTypeDefense} {
+			if card := set.Draw(cardType, RarityUncommon); card == nil || card.CardType != cardType {
+				t.Errorf("%s: drew %v for an uncommon %s card", id, card, cardType)
+			}
+		}
+	}
+
+	if _, err := LoadCardSet([]byte(`{"id": "x", "name": "X", "cards": [
+		{"name": "Skirmish", "type": "attack", "rarity": "common", "effect": "skirmish", "weight": 1},
+		{"name": "Curse", "type": "defense", "rarity": "common", "effect": "curse", "weight": 1}]}`)); err == nil {
+		t.Error("loaded a set with an unknown effect")
+	}
+}
+
+func TestResolveCards(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	g.Settings.ChanceLevel = ChanceLow
+	g.Players["B"] = &Player{ID: "B", Stockpile: NewStockpile()}
+	target := g.Territories["c"]
+	target.HasCity = true
+
+	// Attack 2 doubled, +2; defense 2, +1, +1 for b next door, +3 in a city
+	result := g.ResolveCards("B", target, 2, 2,
+		[]CombatCard{testCard(t, "expanded", EffectAdvance), testCard(t, "expanded", EffectDoubleAttack)},
+		[]CombatCard{testCard(t, "expanded", EffectFortify), testCard(t, "expanded", EffectAmbush), testCard(t, "expanded", EffectMilitia)},
+		nil)
+	if result.FinalAttack != 6 || result.FinalDefense != 7 || result.AttackerWins {
+		t.Errorf("result = %d vs %d, attacker wins %v; want 6 vs 7 and a loss",
+			result.FinalAttack, result.FinalDefense, result.AttackerWins)
+	}
+
+	// Bribe wins outright and is paid for
+	result = g.ResolveCards("B", target, 9, 1,
+		[]CombatCard{testCard(t, "classic", EffectSafeRetreat)},
+		[]CombatCard{testCard(t, "classic", EffectBribe)}, nil)
+	if !result.BribeActivated || result.AttackerWins || !result.SafeRetreat {
+		t.Errorf("bribe: activated %v, attacker wins %v, safe retreat %v", result.BribeActivated, result.AttackerWins, result.SafeRetreat)
+	}
+	if g.Players["A"].Stockpile.Gold != 5-BribeCost {
+		t.Errorf("defender gold = %d, want %d", g.Players["A"].Stockpile.Gold, 5-BribeCost)
+	}
+}
+
+func TestDiscardAndCombineCards(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	g.Settings.CombatMode = CombatModeCards
+	g.CurrentPlayerID = "A"
+	a := g.Players["A"]
+	skirmish, advance := testCard(t, "classic", EffectSkirmish), testCard(t, "classic", EffectAdvance)
+	fortify, assault := testCard(t, "classic", EffectFortify), testCard(t, "classic", EffectAssault)
+	a.AttackCards = []CombatCard{skirmish, advance, assault}
+	a.DefenseCards = []CombatCard{fortify}
+
+	if n, err := g.DiscardCard("A", assault.ID, ResourceIron); err != nil || n != 3 || a.Stockpile.Iron != 8 {
+		t.Errorf("discarding a rare: got %d (err %v), iron %d; want 3 and 8", n, err, a.Stockpile.Iron)
+	}
+	// With the economy on, iron over the storage cap spoils
+	g.Settings.Economy = true
+	a.Stockpile.Iron = BaseStorage - 1
+	a.AttackCards = append(a.AttackCards, assault)
+	if n, err := g.DiscardCard("A", assault.ID, ResourceIron); err != nil || n != 1 || a.Stockpile.Iron != BaseStorage {
+		t.Errorf("discarding a rare near the cap: got %d (err %v), iron %d; want 1 and %d", n, err, a.Stockpile.Iron, BaseStorage)
+	}
+	// At the cap there's nothing to gain, and the card stays in hand
+	a.AttackCards = append(a.AttackCards, assault)
+	if n, err := g.DiscardCard("A", assault.ID, ResourceIron); err != ErrStackFull || n != 0 || a.Stockpile.Iron != BaseStorage {
+		t.Errorf("discarding at the cap: got %d (err %v), iron %d; want %v", n, err, a.Stockpile.Iron, ErrStackFull)
+	}
+	if a.GetCardByID(assault.ID) == nil {
+		t.Error("card discarded at the cap left the hand")
+	}
+	a.RemoveCardFromHand(assault.ID)
+	g.Settings.Economy = false
+
+	if _, err := g.CombineCards("A", skirmish.ID, fortify.ID); err != ErrInvalidAction {
+		t.Errorf("combining attack and defense: err = %v, want %v", err, ErrInvalidAction)
+	}
+
+	card, err := g.CombineCards("A", skirmish.ID, advance.ID)
+	if err != nil {
+		t.Fatalf("CombineCards: %v", err)
+	}
+	if card.Rarity != RarityUncommon || card.CardType != CardTypeAttack {
+		t.Errorf("combined into %s %s card", card.Rarity, card.CardType)
+	}
+	if len(a.AttackCards) != 1 || a.AttackCards[0].ID != card.ID || a.DeckSize() != 2 {
+		t.Errorf("after combining: attack cards %v, deck size %d", a.AttackCards, a.DeckSize())
+	}
+}
+
+func TestCardBattleWaitsForDefender(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	g.Settings.CombatMode = CombatModeCards
+	g.Settings.Depots = true
+	g.Phase = PhaseConquest
+	g.CurrentPlayerID = "A"
+	g.Players["B"] = &Player{ID: "B", Stockpile: NewStockpile(), StockpileTerritory: "d"}
+	a := g.Players["A"]
+	a.AttacksRemaining = 2
+	skirmish, fortify := testCard(t, "classic", EffectSkirmish), testCard(t, "classic", EffectFortify)
+	a.AttackCards = []CombatCard{skirmish}
+	a.DefenseCards = []CombatCard{fortify}
+
+	battle := &PendingCardBattle{ID: "battle", AttackerID: "A", DefenderID: "B", TargetTerritory: "d"}
+	if err := g.BeginCardBattle(battle, []string{skirmish.ID, fortify.ID}); err != nil {
+		t.Fatalf("BeginCardBattle: %v", err)
+	}
+	if len(a.AttackCards) != 0 || len(a.DefenseCards) != 1 || len(battle.AttackCards) != 1 || g.PendingCardBattle != battle {
+		t.Errorf("after committing: hand %v / %v, battle cards %v", a.AttackCards, a.DefenseCards, battle.AttackCards)
+	}
+
+	// Nothing else happens on the attacker's turn until the defender answers
+	if _, err := g.Attack("A", "d", nil); err != ErrCardBattlePending {
+		t.Errorf("attack: err = %v, want %v", err, ErrCardBattlePending)
+	}
+	if _, err := g.Raid("A", "d"); err != ErrCardBattlePending {
+		t.Errorf("raid: err = %v, want %v", err, ErrCardBattlePending)
+	}
+	if err := g.EndConquest("A"); err != ErrCardBattlePending || g.CurrentPlayerID != "A" {
+		t.Errorf("ending the turn: err = %v, current player %s", err, g.CurrentPlayerID)
+	}
+	if err := g.BeginCardBattle(&PendingCardBattle{AttackerID: "A"}, nil); err != ErrCardBattlePending {
+		t.Errorf("second card battle: err = %v, want %v", err, ErrCardBattlePending)
+	}
+
+	if got := g.EndCardBattle(); got != battle || g.PendingCardBattle != nil {
+		t.Errorf("EndCardBattle returned %v, pending %v", got, g.PendingCardBattle)
+	}
+	if _, err := g.Raid("A", "d"); err == ErrCardBattlePending {
+		t.Error("raid still blocked after the battle ended")
+	}
+}
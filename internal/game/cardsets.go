@@ -0,0 +1,160 @@
+package game
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"path"
+	"sort"
+)
+
+// Card sets are catalogs of combat cards kept as JSON in the cardsets
+// directory. A game in card mode draws from the set named in its settings.
+// Each card's effect must be one the resolution engine knows (see
+// cardEffects); its weight is how often it's drawn relative to the other
+// cards of its type.
+
+//go:embed cardsets/*.json
+var cardSetFiles embed.FS
+
+// DefaultCardSet is the set used when a game doesn't pick one.
+const DefaultCardSet = "classic"
+
+// CardSet is a catalog of combat cards to draw from.
+type CardSet struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Cards       []cardTemplate `json:"cards"`
+}
+
+// cardTemplate defines a card type in a set.
+type cardTemplate struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	CardType    CardType   `json:"type"`
+	Rarity      CardRarity `json:"rarity"`
+	Effect      CardEffect `json:"effect"`
+	Value       int        `json:"value"`
+	Weight      int        `json:"weight"` // Relative draw weight among cards of the same type
+}
+
+// CardSets holds every card set by ID.
+var CardSets = mustLoadCardSets()
+
+// mustLoadCardSets loads the embedded card sets. They ship with the binary,
+// so a bad one is a programming error.
+func mustLoadCardSets() map[string]*CardSet {
+	entries, err := cardSetFiles.ReadDir("cardsets")
+	if err != nil {
+		panic(fmt.Sprintf("failed to read card sets: %v", err))
+	}
+
+	sets := make(map[string]*CardSet)
+	for _, entry := range entries {
+		data, err := cardSetFiles.ReadFile(path.Join("cardsets", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("failed to read card set %s: %v", entry.Name(), err))
+		}
+		set, err := LoadCardSet(data)
+		if err != nil {
+			panic(fmt.Sprintf("card set %s: %v", entry.Name(), err))
+		}
+		sets[set.ID] = set
+	}
+	if sets[DefaultCardSet] == nil {
+		panic("missing default card set " + DefaultCardSet)
+	}
+	return sets
+}
+
+// LoadCardSet parses and checks a card set.
+func LoadCardSet(data []byte) (*CardSet, error) {
+	var set CardSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse card set JSON: %w", err)
+	}
+	if set.ID == "" || set.Name == "" {
+		return nil, fmt.Errorf("card set needs an id and a name")
+	}
+
+	types := make(map[CardType]bool)
+	for _, t := range set.Cards {
+		if t.Name == "" {
+			return nil, fmt.Errorf("card with no name")
+		}
+		if t.CardType != CardTypeAttack && t.CardType != CardTypeDefense {
+			return nil, fmt.Errorf("%s: unknown card type %q", t.Name, t.CardType)
+		}
+		if _, ok := discardValues[t.Rarity]; !ok {
+			return nil, fmt.Errorf("%s: unknown rarity %q", t.Name, t.Rarity)
+		}
+		if _, ok := cardEffects[t.Effect]; !ok {
+			return nil, fmt.Errorf("%s: unknown effect %q", t.Name, t.Effect)
+		}
+		if t.Weight <= 0 {
+			return nil, fmt.Errorf("%s: weight must be positive", t.Name)
+		}
+		types[t.CardType] = true
+	}
+	if !types[CardTypeAttack] || !types[CardTypeDefense] {
+		return nil, fmt.Errorf("card set needs both attack and defense cards")
+	}
+	return &set, nil
+}
+
+// CardSetIDs returns the IDs of all card sets, sorted.
+func CardSetIDs() []string {
+	ids := make([]string, 0, len(CardSets))
+	for id := range CardSets {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// CardSet returns the card set this game draws from.
+func (g *GameState) CardSet() *CardSet {
+	if set := CardSets[g.Settings.CardSet]; set != nil {
+		return set
+	}
+	return CardSets[DefaultCardSet]
+}
+
+// templates returns the set's cards of a type and, if rarity isn't empty,
+// of that rarity.
+func (s *CardSet) templates(cardType CardType, rarity CardRarity) []cardTemplate {
+	var templates []cardTemplate
+	for _, t := range s.Cards {
+		if t.CardType == cardType && (rarity == "" || t.Rarity == rarity) {
+			templates = append(templates, t)
+		}
+	}
+	return templates
+}
+
+// Draw randomly draws a card of the given type, and of the given rarity if
+// it isn't empty, based on the cards' weights. It returns nil if the set has
+// no such card.
+func (s *CardSet) Draw(cardType CardType, rarity CardRarity) *CombatCard {
+	templates := s.templates(cardType, rarity)
+	if len(templates) == 0 {
+		return nil
+	}
+
+	totalWeight := 0
+	for _, t := range templates {
+		totalWeight += t.Weight
+	}
+
+	roll := rand.Intn(totalWeight)
+	for _, t := range templates {
+		roll -= t.Weight
+		if roll < 0 {
+			card := newCardFromTemplate(t)
+			return &card
+		}
+	}
+	return nil
+}
@@ -11,7 +11,10 @@ type CombatResult struct {
 	DefenseStrength   int
 	UnitsDestroyed    []UnitInfo // Attacker's brought-in units if attack fails
 	UnitsCaptured     []UnitInfo // Defender's units if attack succeeds
-	StockpileCaptured *Stockpile // Defender's stockpile if captured
+	StockpileCaptured *Stockpile // Defender's stockpile if captured, or the loot from a raid
+	FreedVassal       string     // Player who won their freedom in this battle
+	Raid              bool       // The attacker only raided the target's stockpile
+	Naval             bool       // A sea battle between fleets; no territory changes hands
 }
 
 // UnitInfo describes a unit involved in combat.
@@ -27,6 +30,7 @@ const (
 	UnitHorse UnitType = iota
 	UnitWeapon
 	UnitBoat
+	UnitSiege
 )
 
 // AttackPlan represents a planned attack with optional reinforcements.
@@ -69,6 +73,9 @@ func (g *GameState) CalculateAttackStrength(attackerID string, target *Territory
 		}
 	}
 
+	// Siege engines next to the target break down its fortresses
+	strength += g.SiegeBonus(attackerID, target)
+
 	// Add strength from brought unit
 	if brought != nil {
 		fromAdj := g.IsAdjacent(brought.FromTerritory, target.ID)
@@ -106,6 +113,8 @@ func (g *GameState) CalculateAttackStrength(attackerID string, target *Territory
 // CalculateDefenseStrength calculates the defender's combat strength.
 func (g *GameState) CalculateDefenseStrength(target *Territory) int {
 	strength := 1 // The territory itself
+	strength += g.TerrainOf(target).DefenseBonus()
+	strength += g.FortressBonus(target)
 
 	// Units in the territory
 	if target.HasCity {
@@ -118,6 +127,7 @@ func (g *GameState) CalculateDefenseStrength(target *Territory) int {
 		strength += 1
 	}
 	strength += target.TotalBoats() * 2
+	strength += target.Garrison
 
 	// Adjacent territories owned by defender (only if territory has an owner)
 	// Unclaimed territories don't get reinforcements from other unclaimed territories
@@ -298,6 +308,7 @@ func (g *GameState) ExecuteAttackWithAllies(attackerID string, plan *AttackPlan,
 
 		// Transfer territory
 		target.Owner = attackerID
+		target.Garrison = 0
 
 		// Capture units (if attacker didn't bring their own)
 		if plan.BroughtUnit == nil || plan.BroughtUnit.UnitType != UnitHorse {
@@ -310,6 +321,9 @@ func (g *GameState) ExecuteAttackWithAllies(attackerID string, plan *AttackPlan,
 				result.UnitsCaptured = append(result.UnitsCaptured, UnitInfo{UnitWeapon, target.ID})
 			}
 		}
+		if g.Settings.ExtendedUnits && target.SiegeEngines > 0 {
+			result.UnitsCaptured = append(result.UnitsCaptured, UnitInfo{UnitSiege, target.ID})
+		}
 
 		// Move brought unit into territory
 		if plan.BroughtUnit != nil {
@@ -317,19 +331,7 @@ func (g *GameState) ExecuteAttackWithAllies(attackerID string, plan *AttackPlan,
 		}
 
 		// Check for stockpile capture
-		defender := g.Players[defenderID]
-		if defender != nil && defender.StockpileTerritory == target.ID {
-			result.StockpileCaptured = defender.Stockpile.Clone()
-			attacker := g.Players[attackerID]
-			// Transfer resources
-			attacker.Stockpile.Coal += defender.Stockpile.Coal
-			attacker.Stockpile.Gold += defender.Stockpile.Gold
-			attacker.Stockpile.Iron += defender.Stockpile.Iron
-			attacker.Stockpile.Timber += defender.Stockpile.Timber
-			// Clear defender's stockpile
-			defender.Stockpile = NewStockpile()
-			defender.StockpileTerritory = ""
-		}
+		g.captureStockpile(attackerID, defenderID, target, result)
 
 		// Check if defender is eliminated
 		g.checkElimination(defenderID)
@@ -368,6 +370,7 @@ func (g *GameState) ExecuteAttackWithAllies(attackerID string, plan *AttackPlan,
 			}
 		}
 	}
+	result.FreedVassal = g.settleVassalBattle(attackerID, defenderID, result.AttackerWins)
 
 	return result
 }
@@ -396,6 +399,7 @@ func (g *GameState) ExecuteCardAttackWithAllies(attackerID string, plan *AttackP
 	if cardResult.AttackerWins {
 		// Transfer territory
 		target.Owner = attackerID
+		target.Garrison = 0
 
 		// Capture units (same as classic)
 		if plan.BroughtUnit == nil || plan.BroughtUnit.UnitType != UnitHorse {
@@ -408,6 +412,9 @@ func (g *GameState) ExecuteCardAttackWithAllies(attackerID string, plan *AttackP
 				result.UnitsCaptured = append(result.UnitsCaptured, UnitInfo{UnitWeapon, target.ID})
 			}
 		}
+		if g.Settings.ExtendedUnits && target.SiegeEngines > 0 {
+			result.UnitsCaptured = append(result.UnitsCaptured, UnitInfo{UnitSiege, target.ID})
+		}
 
 		// Move brought unit into territory
 		if plan.BroughtUnit != nil {
@@ -415,17 +422,7 @@ func (g *GameState) ExecuteCardAttackWithAllies(attackerID string, plan *AttackP
 		}
 
 		// Check for stockpile capture
-		defender := g.Players[defenderID]
-		if defender != nil && defender.StockpileTerritory == target.ID {
-			result.StockpileCaptured = defender.Stockpile.Clone()
-			attacker := g.Players[attackerID]
-			attacker.Stockpile.Coal += defender.Stockpile.Coal
-			attacker.Stockpile.Gold += defender.Stockpile.Gold
-			attacker.Stockpile.Iron += defender.Stockpile.Iron
-			attacker.Stockpile.Timber += defender.Stockpile.Timber
-			defender.Stockpile = NewStockpile()
-			defender.StockpileTerritory = ""
-		}
+		g.captureStockpile(attackerID, defenderID, target, result)
 
 		// Blitz: return attack cards to attacker's hand
 		attacker := g.Players[attackerID]
@@ -482,6 +479,7 @@ func (g *GameState) ExecuteCardAttackWithAllies(attackerID string, plan *AttackP
 			}
 		}
 	}
+	result.FreedVassal = g.settleVassalBattle(attackerID, defenderID, result.AttackerWins)
 
 	return result, cardResult
 }
@@ -500,6 +498,21 @@ func (g *GameState) IsAdjacent(id1, id2 string) bool {
 	return false
 }
 
+// captureStockpile hands the attacker any stockpile or depot the defender kept
+// in the captured territory.
+func (g *GameState) captureStockpile(attackerID, defenderID string, target *Territory, result *CombatResult) {
+	taken := g.takeStockpile(defenderID, target.ID)
+	if taken == nil {
+		return
+	}
+	result.StockpileCaptured = taken
+	attacker := g.Players[attackerID]
+	attacker.Stockpile.Coal += taken.Coal
+	attacker.Stockpile.Gold += taken.Gold
+	attacker.Stockpile.Iron += taken.Iron
+	attacker.Stockpile.Timber += taken.Timber
+}
+
 // moveBroughtUnit moves a brought unit into the captured territory.
 func (g *GameState) moveBroughtUnit(brought *BroughtUnit, target *Territory) {
 	from := g.Territories[brought.FromTerritory]
@@ -517,9 +530,13 @@ func (g *GameState) moveBroughtUnit(brought *BroughtUnit, target *Territory) {
 		from.HasWeapon = false
 		target.HasWeapon = true
 	case UnitBoat:
-		// Boat stays in the same water body
-		from.RemoveBoat(brought.WaterBodyID)
-		target.AddBoat(brought.WaterBodyID)
+		// Boat stays in the same water body, unless it came through a strait
+		landing := g.landingWater(from.Owner, brought.WaterBodyID, target)
+		if landing == "" {
+			landing = brought.WaterBodyID // Landed on an inland target
+		}
+		// A transport only goes when there is no plain boat to send
+		transferBoat(from, target, brought.WaterBodyID, landing, !from.hasPlainBoat(brought.WaterBodyID))
 		if brought.CarryingHorse {
 			horseFrom := g.Territories[brought.HorseFromTerritory]
 			horseFrom.HasHorse = false
@@ -542,5 +559,6 @@ func (g *GameState) checkElimination(playerID string) {
 	}
 	if player := g.Players[playerID]; player != nil {
 		player.Eliminated = true
+		g.releaseVassals(playerID)
 	}
 }
@@ -11,6 +11,9 @@ func (g *GameState) CanAttack(attackerID, targetID string) bool {
 	if target == nil || target.Owner == attackerID {
 		return false // Can't attack own territory
 	}
+	if g.IsVassalOf(attackerID, target.Owner) {
+		return false // Vassals can't attack their overlord
+	}
 
 	// Must have adjacent territory
 	for _, adjID := range target.Adjacent {
@@ -134,10 +137,15 @@ func (g *GameState) canHorseReachTarget(playerID, fromID, targetID string) bool
 		return true
 	}
 
+	// Rough ground stops a horse after one move
+	if g.TerrainOf(from).SlowsHorses() {
+		return false
+	}
+
 	// 2 moves through owned territory, ending adjacent to target
 	for _, midID := range from.Adjacent {
 		mid := g.Territories[midID]
-		if mid.Owner == playerID {
+		if mid.Owner == playerID && !g.TerrainOf(mid).SlowsHorses() {
 			if g.IsAdjacent(midID, targetID) {
 				return true
 			}
@@ -151,16 +159,19 @@ func (g *GameState) canHorseReachTarget(playerID, fromID, targetID string) bool
 // Boats can attack:
 // 1. Coastal territories that share the same water body (direct water attack)
 // 2. Truly landlocked territories (no water bodies at all) adjacent to a coastal territory the attacker owns
+// With straits on, any water body the boat can sail to through the attacker's straits counts as its own.
+// With naval combat on, a blockaded boat can't launch, and it can't land through a water body
+// where the defender's fleet is the stronger one.
 func (g *GameState) canBoatReachTargetViaWater(attackerID, fromID, targetID, waterBodyID string) bool {
 	target := g.Territories[targetID]
-	water := g.WaterBodies[waterBodyID]
-	if water == nil {
+	if g.WaterBodies[waterBodyID] == nil || g.IsVassalOf(attackerID, target.Owner) || g.Blockaded(fromID, waterBodyID) {
 		return false
 	}
+	waters := g.NavigableWaters(attackerID, waterBodyID)
 
-	// Check if target borders this water body (boat can attack directly from water)
+	// Check if target borders a reachable water body (boat can attack directly from water)
 	for _, tw := range target.WaterBodies {
-		if tw == waterBodyID {
+		if waters[tw] && !g.controlsWater(target.Owner, attackerID, tw) {
 			return true
 		}
 	}
@@ -173,12 +184,18 @@ func (g *GameState) canBoatReachTargetViaWater(attackerID, fromID, targetID, wat
 	}
 
 	// Target is truly landlocked (no water bodies at all).
-	// Check if it's adjacent to a coastal territory the attacker owns in this water body.
+	// Check if it's adjacent to a coastal territory the attacker owns in a reachable water body.
 	// This allows boats to "land" at an owned coastal territory and attack adjacent inland territories.
-	for _, coastalID := range water.Territories {
-		coastal := g.Territories[coastalID]
-		if coastal != nil && coastal.Owner == attackerID && g.IsAdjacent(coastalID, targetID) {
-			return true
+	for waterID := range waters {
+		water := g.WaterBodies[waterID]
+		if water == nil {
+			continue
+		}
+		for _, coastalID := range water.Territories {
+			coastal := g.Territories[coastalID]
+			if coastal != nil && coastal.Owner == attackerID && g.IsAdjacent(coastalID, targetID) {
+				return true
+			}
 		}
 	}
 
@@ -201,6 +218,9 @@ func (g *GameState) AttackWithAllies(attackerID, targetID string, brought *Broug
 	if g.CurrentPlayerID != attackerID {
 		return nil, ErrNotYourTurn
 	}
+	if g.PendingCardBattle != nil {
+		return nil, ErrCardBattlePending
+	}
 
 	attacker := g.Players[attackerID]
 	if attacker == nil {
@@ -211,6 +231,11 @@ func (g *GameState) AttackWithAllies(attackerID, targetID string, brought *Broug
 		return nil, ErrNoAttacksRemaining
 	}
 
+	// A blockaded boat can't join the attack at all
+	if brought != nil && brought.UnitType == UnitBoat && g.Blockaded(brought.FromTerritory, brought.WaterBodyID) {
+		return nil, ErrBlockaded
+	}
+
 	// Check if attack is valid - either has adjacent territory OR is bringing a boat
 	canAttack := g.CanAttack(attackerID, targetID)
 	if !canAttack && brought != nil && brought.UnitType == UnitBoat {
@@ -259,6 +284,9 @@ func (g *GameState) EndConquest(playerID string) error {
 	if g.CurrentPlayerID != playerID {
 		return ErrNotYourTurn
 	}
+	if g.PendingCardBattle != nil {
+		return ErrCardBattlePending
+	}
 
 	player := g.Players[playerID]
 	if player != nil {
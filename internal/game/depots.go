@@ -0,0 +1,191 @@
+package game
+
+import "log"
+
+// With Settings.Depots on, players can build depots: extra stockpiles that
+// keep part of their resources away from the main one. Production, building
+// and trading still go through the main stockpile; resources are shipped
+// between it and the depots in the Shipment phase. Losing the main stockpile
+// promotes the fullest depot in its place, so only a player with no depots
+// left has to place a new one. A raid steals part of an enemy stockpile or
+// depot without taking the territory.
+const BuildDepot BuildType = "depot"
+
+const (
+	// MaxDepots is how many depots a player can have besides their main
+	// stockpile.
+	MaxDepots = 2
+	// DepotCapacity is how many resources in all a depot holds.
+	DepotCapacity = 8
+)
+
+// StockpileAt returns the resources a player keeps in a territory: their main
+// stockpile, a depot, or nil if they keep none there.
+func (p *Player) StockpileAt(territoryID string) *Stockpile {
+	if territoryID == "" {
+		return nil
+	}
+	if p.StockpileTerritory == territoryID {
+		return p.Stockpile
+	}
+	return p.Depots[territoryID]
+}
+
+// Stores returns every stockpile a player keeps: the main one first, then
+// their depots in territory order.
+func (p *Player) Stores() []*Stockpile {
+	stores := []*Stockpile{p.Stockpile}
+	for _, id := range sortedKeys(p.Depots) {
+		stores = append(stores, p.Depots[id])
+	}
+	return stores
+}
+
+// Holding returns how much of a resource a player keeps in all, counting
+// their depots.
+func (p *Player) Holding(resource ResourceType) int {
+	total := 0
+	for _, store := range p.Stores() {
+		total += store.Get(resource)
+	}
+	return total
+}
+
+// ShipResources moves resources between a player's stockpile and depots. The
+// two must be connected the same way a stockpile move would need, and a depot
+// can't be filled past DepotCapacity. It takes the player's shipment for the
+// turn.
+func (g *GameState) ShipResources(playerID, fromID, toID string, load Stockpile) error {
+	if g.Phase != PhaseShipment || !g.Settings.Depots {
+		return ErrInvalidAction
+	}
+	if g.CurrentPlayerID != playerID {
+		return ErrNotYourTurn
+	}
+
+	player := g.Players[playerID]
+	if player == nil || fromID == toID {
+		return ErrInvalidTarget
+	}
+	from, to := player.StockpileAt(fromID), player.StockpileAt(toID)
+	if from == nil || to == nil {
+		return ErrInvalidTarget
+	}
+	if load.Coal < 0 || load.Gold < 0 || load.Iron < 0 || load.Timber < 0 || load.Total() == 0 {
+		return ErrInvalidAction
+	}
+	if !from.CanAffordStockpile(&load) {
+		return ErrInsufficientResources
+	}
+	if toID != player.StockpileTerritory && to.Total()+load.Total() > DepotCapacity {
+		return ErrStackFull
+	}
+	if !g.canReachTerritory(playerID, fromID, toID) {
+		return ErrCannotReach
+	}
+
+	from.Subtract(&load)
+	to.Coal += load.Coal
+	to.Gold += load.Gold
+	to.Iron += load.Iron
+	to.Timber += load.Timber
+
+	g.advanceShipmentTurn()
+	return nil
+}
+
+// takeStockpile empties whatever stockpile or depot a player keeps in a
+// territory they've lost and returns what was in it, or nil if there was
+// none. Losing the main stockpile promotes the fullest depot in its place.
+func (g *GameState) takeStockpile(playerID, territoryID string) *Stockpile {
+	player := g.Players[playerID]
+	if player == nil {
+		return nil
+	}
+	if depot := player.Depots[territoryID]; depot != nil {
+		delete(player.Depots, territoryID)
+		return depot
+	}
+	if player.StockpileTerritory != territoryID || territoryID == "" {
+		return nil
+	}
+
+	taken := player.Stockpile
+	player.Stockpile = NewStockpile()
+	player.StockpileTerritory = ""
+
+	var best string
+	for _, id := range sortedKeys(player.Depots) {
+		if best == "" || player.Depots[id].Total() > player.Depots[best].Total() {
+			best = id
+		}
+	}
+	if best != "" {
+		player.Stockpile = player.Depots[best]
+		player.StockpileTerritory = best
+		delete(player.Depots, best)
+		log.Printf("takeStockpile: %s falls back on their depot in %s", player.Name, best)
+	}
+	return taken
+}
+
+// Raid strikes at the stockpile or depot in a territory without trying to take
+// it. Strength is worked out as for an attack with no units brought along. If
+// the raid succeeds, the attacker carries off half of each resource there,
+// rounded up. A raid uses up an attack like any other.
+func (g *GameState) Raid(attackerID, targetID string) (*CombatResult, error) {
+	if g.Phase != PhaseConquest || !g.Settings.Depots {
+		return nil, ErrInvalidAction
+	}
+	if g.CurrentPlayerID != attackerID {
+		return nil, ErrNotYourTurn
+	}
+	if g.PendingCardBattle != nil {
+		return nil, ErrCardBattlePending
+	}
+
+	attacker := g.Players[attackerID]
+	if attacker == nil {
+		return nil, ErrInvalidTarget
+	}
+	if attacker.AttacksRemaining <= 0 {
+		return nil, ErrNoAttacksRemaining
+	}
+	target := g.Territories[targetID]
+	if target == nil || !g.CanAttack(attackerID, targetID) {
+		return nil, ErrInvalidTarget
+	}
+	var store *Stockpile
+	if defender := g.Players[target.Owner]; defender != nil {
+		store = defender.StockpileAt(targetID)
+	}
+	if store == nil {
+		return nil, ErrInvalidTarget
+	}
+
+	result := &CombatResult{
+		Raid:            true,
+		AttackStrength:  g.CalculateAttackStrength(attackerID, target, nil),
+		DefenseStrength: g.CalculateDefenseStrength(target),
+	}
+	result.AttackerWins = g.ResolveCombat(result.AttackStrength, result.DefenseStrength)
+	if result.AttackerWins {
+		loot := NewStockpile()
+		for _, resource := range []ResourceType{ResourceCoal, ResourceGold, ResourceIron, ResourceTimber} {
+			if n := (store.Get(resource) + 1) / 2; n > 0 && store.Remove(resource, n) {
+				loot.Add(resource, n)
+				attacker.Stockpile.Add(resource, n)
+			}
+		}
+		result.StockpileCaptured = loot
+	}
+
+	attacker.AttacksRemaining--
+	if !result.AttackerWins && attacker.AttacksRemaining == 1 {
+		attacker.AttacksRemaining = 0
+	}
+	if attacker.AttacksRemaining <= 0 {
+		g.AdvanceConquestTurn()
+	}
+	return result, nil
+}
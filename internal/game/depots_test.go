@@ -0,0 +1,76 @@
+package game
+
+import "testing"
+
+func TestDepots(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	g.Settings.Depots = true
+	g.CurrentPlayerID = "A"
+	a := g.Players["A"]
+	a.StockpileTerritory = "a"
+
+	if err := g.Build("A", BuildDepot, "a", false); err != ErrAlreadyHasUnit {
+		t.Errorf("depot on the main stockpile: err = %v, want %v", err, ErrAlreadyHasUnit)
+	}
+	if err := g.Build("A", BuildDepot, "c", false); err != nil {
+		t.Fatalf("Build depot: %v", err)
+	}
+	if *a.Stockpile != (Stockpile{Coal: 5, Gold: 5, Iron: 4, Timber: 3}) || a.StockpileAt("c") == nil {
+		t.Fatalf("after building: stockpile %+v, depots %v", *a.Stockpile, a.Depots)
+	}
+
+	g.Phase = PhaseShipment
+	if err := g.ShipResources("A", "a", "c", Stockpile{Coal: 5, Gold: 4}); err != ErrStackFull {
+		t.Errorf("overfilled depot: err = %v, want %v", err, ErrStackFull)
+	}
+	if err := g.ShipResources("A", "a", "c", Stockpile{Gold: 4, Iron: 1}); err != nil {
+		t.Fatalf("ShipResources: %v", err)
+	}
+	if a.Stockpile.Gold != 1 || *a.Depots["c"] != (Stockpile{Gold: 4, Iron: 1}) {
+		t.Errorf("after shipping: stockpile %+v, depot %+v", *a.Stockpile, *a.Depots["c"])
+	}
+
+	// Losing the main stockpile falls back on the depot
+	taken := g.takeStockpile("A", "a")
+	if taken == nil || taken.Coal != 5 {
+		t.Errorf("taken = %v, want the main stockpile", taken)
+	}
+	if a.StockpileTerritory != "c" || a.Stockpile.Gold != 4 || len(a.Depots) != 0 {
+		t.Errorf("after capture: stockpile in %q with %+v, depots %v", a.StockpileTerritory, *a.Stockpile, a.Depots)
+	}
+	if g.NeedsStockpilePlacement() {
+		t.Error("player with a depot needs to place a stockpile")
+	}
+}
+
+func TestRaid(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	g.Settings.Depots = true
+	g.Settings.ChanceLevel = ChanceLow
+	g.Phase = PhaseConquest
+	g.CurrentPlayerID = "B"
+	g.Players["B"] = &Player{ID: "B", Stockpile: NewStockpile(), AttacksRemaining: 2}
+	a, b := g.Players["A"], g.Players["B"]
+	a.StockpileTerritory = "a"
+	a.Depots = map[string]*Stockpile{"c": {Gold: 3, Iron: 1}}
+
+	if _, err := g.Raid("B", "b"); err != ErrInvalidTarget {
+		t.Errorf("raid on a territory without a depot: err = %v, want %v", err, ErrInvalidTarget)
+	}
+
+	// d attacks with 1 + weapon 3 against c's 1 + b 1
+	g.Territories["d"].HasWeapon = true
+	result, err := g.Raid("B", "c")
+	if err != nil {
+		t.Fatalf("Raid: %v", err)
+	}
+	if !result.AttackerWins || *result.StockpileCaptured != (Stockpile{Gold: 2, Iron: 1}) {
+		t.Fatalf("raid result: wins %v, loot %+v", result.AttackerWins, result.StockpileCaptured)
+	}
+	if *a.Depots["c"] != (Stockpile{Gold: 1}) || *b.Stockpile != (Stockpile{Gold: 2, Iron: 1}) {
+		t.Errorf("after raid: depot %+v, raider %+v", *a.Depots["c"], *b.Stockpile)
+	}
+	if g.Territories["c"].Owner != "A" || b.AttacksRemaining != 1 {
+		t.Errorf("after raid: c owned by %q, %d attacks left", g.Territories["c"].Owner, b.AttacksRemaining)
+	}
+}
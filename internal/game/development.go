@@ -23,6 +23,27 @@ func GetBuildCost(buildType BuildType) *Stockpile {
 	case BuildBoat:
 		// Boat costs: 3 Timber
 		return &Stockpile{Timber: 3}
+	case BuildFortress:
+		// Fortress costs: 1 Coal + 1 Iron + 1 Timber
+		return &Stockpile{Coal: 1, Iron: 1, Timber: 1}
+	case BuildSiege:
+		// Siege engine costs: 1 Iron + 1 Timber
+		return &Stockpile{Iron: 1, Timber: 1}
+	case BuildTransport:
+		// Transport costs: 1 Iron + 4 Timber
+		return &Stockpile{Iron: 1, Timber: 4}
+	case BuildMine:
+		// Mine costs: 1 Iron + 1 Timber
+		return &Stockpile{Iron: 1, Timber: 1}
+	case BuildLumberMill:
+		// Lumber mill costs: 1 Coal + 1 Iron
+		return &Stockpile{Coal: 1, Iron: 1}
+	case BuildStable:
+		// Stable costs: 2 Timber
+		return &Stockpile{Timber: 2}
+	case BuildDepot:
+		// Depot costs: 1 Iron + 2 Timber
+		return &Stockpile{Iron: 1, Timber: 2}
 	default:
 		return nil
 	}
@@ -37,6 +58,16 @@ func GoldCost(buildType BuildType) int {
 		return 2
 	case BuildBoat:
 		return 3
+	case BuildFortress:
+		return 3
+	case BuildSiege:
+		return 2
+	case BuildTransport:
+		return 5
+	case BuildMine, BuildLumberMill, BuildStable:
+		return 2
+	case BuildDepot:
+		return 3
 	default:
 		return 0
 	}
@@ -59,8 +90,20 @@ func (g *GameState) CanBuild(playerID string, buildType BuildType, territoryID s
 	}
 
 	// Check build restrictions
+	if buildType.IsExtended() && !g.Settings.ExtendedUnits {
+		return ErrInvalidAction
+	}
+	if buildType.IsSpecialization() && !g.Settings.Economy {
+		return ErrInvalidAction
+	}
+	if buildType == BuildDepot && !g.Settings.Depots {
+		return ErrInvalidAction
+	}
+	if !g.TerrainOf(territory).AllowsBuild(buildType) {
+		return ErrTerrainForbids
+	}
 	switch buildType {
-	case BuildBoat:
+	case BuildBoat, BuildTransport:
 		if !territory.IsCoastal() || !territory.CanAddBoat() {
 			return ErrInvalidTarget
 		}
@@ -72,6 +115,28 @@ func (g *GameState) CanBuild(playerID string, buildType BuildType, territoryID s
 		if territory.HasCity {
 			return ErrAlreadyHasUnit
 		}
+	case BuildFortress:
+		if territory.Fortresses >= MaxFortresses {
+			return ErrStackFull
+		}
+	case BuildSiege:
+		if territory.SiegeEngines >= MaxSiegeEngines {
+			return ErrStackFull
+		}
+	case BuildMine, BuildLumberMill, BuildStable:
+		if !territory.HasCity {
+			return ErrInvalidTarget
+		}
+		if territory.Specialization == buildType {
+			return ErrAlreadyHasUnit
+		}
+	case BuildDepot:
+		if player.StockpileAt(territoryID) != nil {
+			return ErrAlreadyHasUnit
+		}
+		if len(player.Depots) >= MaxDepots {
+			return ErrStackFull
+		}
 	}
 
 	// Check resources
@@ -94,14 +159,14 @@ func (g *GameState) CanBuild(playerID string, buildType BuildType, territoryID s
 // For boats, use BuildBoatInWater instead to specify the water body.
 func (g *GameState) Build(playerID string, buildType BuildType, territoryID string, useGold bool) error {
 	// For boats, require water body specification if multiple options exist
-	if buildType == BuildBoat {
+	if buildType == BuildBoat || buildType == BuildTransport {
 		territory := g.Territories[territoryID]
 		if territory != nil && len(territory.WaterBodies) > 1 {
 			return ErrInvalidAction // Must use BuildBoatInWater
 		}
 		// If only one water body, auto-select it
 		if territory != nil && len(territory.WaterBodies) == 1 {
-			return g.BuildBoatInWater(playerID, territoryID, territory.WaterBodies[0], useGold)
+			return g.buildInWater(playerID, buildType, territoryID, territory.WaterBodies[0], useGold)
 		}
 	}
 
@@ -142,6 +207,21 @@ func (g *GameState) Build(playerID string, buildType BuildType, territoryID stri
 		if len(territory.WaterBodies) > 0 {
 			territory.AddBoat(territory.WaterBodies[0])
 		}
+	case BuildTransport:
+		if len(territory.WaterBodies) > 0 {
+			territory.AddTransport(territory.WaterBodies[0])
+		}
+	case BuildFortress:
+		territory.Fortresses++
+	case BuildSiege:
+		territory.SiegeEngines++
+	case BuildMine, BuildLumberMill, BuildStable:
+		territory.Specialization = buildType
+	case BuildDepot:
+		if player.Depots == nil {
+			player.Depots = make(map[string]*Stockpile)
+		}
+		player.Depots[territoryID] = NewStockpile()
 	}
 
 	return nil
@@ -149,6 +229,16 @@ func (g *GameState) Build(playerID string, buildType BuildType, territoryID stri
 
 // BuildBoatInWater builds a boat in a specific water body.
 func (g *GameState) BuildBoatInWater(playerID string, territoryID string, waterBodyID string, useGold bool) error {
+	return g.buildInWater(playerID, BuildBoat, territoryID, waterBodyID, useGold)
+}
+
+// BuildTransportInWater builds a transport in a specific water body.
+func (g *GameState) BuildTransportInWater(playerID string, territoryID string, waterBodyID string, useGold bool) error {
+	return g.buildInWater(playerID, BuildTransport, territoryID, waterBodyID, useGold)
+}
+
+// buildInWater builds a boat or transport in a specific water body.
+func (g *GameState) buildInWater(playerID string, buildType BuildType, territoryID string, waterBodyID string, useGold bool) error {
 	// Validate phase
 	if g.Phase != PhaseDevelopment {
 		return ErrInvalidAction
@@ -160,7 +250,7 @@ func (g *GameState) BuildBoatInWater(playerID string, territoryID string, waterB
 	}
 
 	// Check if can build
-	if err := g.CanBuild(playerID, BuildBoat, territoryID, useGold); err != nil {
+	if err := g.CanBuild(playerID, buildType, territoryID, useGold); err != nil {
 		return err
 	}
 
@@ -174,14 +264,18 @@ func (g *GameState) BuildBoatInWater(playerID string, territoryID string, waterB
 
 	// Deduct resources
 	if useGold {
-		player.Stockpile.Gold -= GoldCost(BuildBoat)
+		player.Stockpile.Gold -= GoldCost(buildType)
 	} else {
-		cost := GetBuildCost(BuildBoat)
+		cost := GetBuildCost(buildType)
 		player.Stockpile.Subtract(cost)
 	}
 
 	// Build the boat
-	territory.AddBoat(waterBodyID)
+	if buildType == BuildTransport {
+		territory.AddTransport(waterBodyID)
+	} else {
+		territory.AddBoat(waterBodyID)
+	}
 
 	return nil
 }
@@ -192,6 +286,9 @@ func (g *GameState) GetWaterBodiesForBoat(territoryID string) []string {
 	if territory == nil || !territory.IsCoastal() || !territory.CanAddBoat() {
 		return nil
 	}
+	if !g.TerrainOf(territory).AllowsBuild(BuildBoat) {
+		return nil
+	}
 
 	return territory.WaterBodies
 }
@@ -307,7 +404,7 @@ func (g *GameState) transitionToProduction() {
 
 // skipToAfterProduction advances past production, checking for additional skips.
 func (g *GameState) skipToAfterProduction() {
-	if len(g.Players) >= 3 {
+	if g.HasTradePhase() {
 		if ShouldSkipPhase(PhaseTrade, g.Settings.ChanceLevel) {
 			g.SkippedPhases = append(g.SkippedPhases, PhaseSkipInfo{
 				Phase:  PhaseTrade,
@@ -390,7 +487,7 @@ func (g *GameState) startNewRound() {
 		})
 		log.Printf("startNewRound: Skipping production phase - %s", g.SkippedPhases[len(g.SkippedPhases)-1].Reason)
 
-		if len(g.Players) >= 3 {
+		if g.HasTradePhase() {
 			// Check if trade should also be skipped
 			if ShouldSkipPhase(PhaseTrade, g.Settings.ChanceLevel) {
 				g.SkippedPhases = append(g.SkippedPhases, PhaseSkipInfo{
@@ -434,9 +531,10 @@ func (g *GameState) startNewRound() {
 // It clears the pending flag and advances to the next phase.
 func (g *GameState) CompleteProduction() {
 	g.ProductionPending = false
+	g.ProductionApplied = false
 
 	// Advance to next phase after production
-	if len(g.Players) >= 3 {
+	if g.HasTradePhase() {
 		// Check if trade should be skipped
 		if ShouldSkipPhase(PhaseTrade, g.Settings.ChanceLevel) {
 			g.SkippedPhases = append(g.SkippedPhases, PhaseSkipInfo{
@@ -485,14 +583,21 @@ func (g *GameState) GetBuildOptions(playerID string) []map[string]interface{} {
 	canAffordCity := player.Stockpile.CanAffordStockpile(GetBuildCost(BuildCity)) || player.Stockpile.Gold >= GoldCost(BuildCity)
 	canAffordWeapon := player.Stockpile.CanAffordStockpile(GetBuildCost(BuildWeapon)) || player.Stockpile.Gold >= GoldCost(BuildWeapon)
 	canAffordBoat := player.Stockpile.CanAffordStockpile(GetBuildCost(BuildBoat)) || player.Stockpile.Gold >= GoldCost(BuildBoat)
+	affordable := func(b BuildType) bool {
+		return player.Stockpile.CanAffordStockpile(GetBuildCost(b)) || player.Stockpile.Gold >= GoldCost(b)
+	}
+	canAfford := func(b BuildType) bool {
+		return g.Settings.ExtendedUnits && affordable(b)
+	}
 
 	// Find valid territories for each build type
 	for id, t := range g.Territories {
 		if t.Owner != playerID {
 			continue
 		}
+		terrain := g.TerrainOf(t)
 
-		if canAffordCity && !t.HasCity {
+		if canAffordCity && !t.HasCity && terrain.AllowsBuild(BuildCity) {
 			options = append(options, map[string]interface{}{
 				"type":        "city",
 				"territory":   id,
@@ -501,7 +606,7 @@ func (g *GameState) GetBuildOptions(playerID string) []map[string]interface{} {
 			})
 		}
 
-		if canAffordWeapon && !t.HasWeapon {
+		if canAffordWeapon && !t.HasWeapon && terrain.AllowsBuild(BuildWeapon) {
 			options = append(options, map[string]interface{}{
 				"type":        "weapon",
 				"territory":   id,
@@ -510,7 +615,7 @@ func (g *GameState) GetBuildOptions(playerID string) []map[string]interface{} {
 			})
 		}
 
-		if canAffordBoat && t.IsCoastal() && t.CanAddBoat() {
+		if canAffordBoat && t.IsCoastal() && t.CanAddBoat() && terrain.AllowsBuild(BuildBoat) {
 			options = append(options, map[string]interface{}{
 				"type":        "boat",
 				"territory":   id,
@@ -518,6 +623,58 @@ func (g *GameState) GetBuildOptions(playerID string) []map[string]interface{} {
 				"gold_cost":   GoldCost(BuildBoat),
 			})
 		}
+
+		// Extended units
+		if canAfford(BuildFortress) && t.Fortresses < MaxFortresses {
+			options = append(options, map[string]interface{}{
+				"type":      "fortress",
+				"territory": id,
+				"cost":      GetBuildCost(BuildFortress),
+				"gold_cost": GoldCost(BuildFortress),
+			})
+		}
+
+		if canAfford(BuildSiege) && t.SiegeEngines < MaxSiegeEngines {
+			options = append(options, map[string]interface{}{
+				"type":      "siege",
+				"territory": id,
+				"cost":      GetBuildCost(BuildSiege),
+				"gold_cost": GoldCost(BuildSiege),
+			})
+		}
+
+		if canAfford(BuildTransport) && t.IsCoastal() && t.CanAddBoat() && terrain.AllowsBuild(BuildTransport) {
+			options = append(options, map[string]interface{}{
+				"type":      "transport",
+				"territory": id,
+				"cost":      GetBuildCost(BuildTransport),
+				"gold_cost": GoldCost(BuildTransport),
+			})
+		}
+
+		// City specializations
+		if g.Settings.Economy && t.HasCity {
+			for _, spec := range []BuildType{BuildMine, BuildLumberMill, BuildStable} {
+				if t.Specialization == spec || !affordable(spec) {
+					continue
+				}
+				options = append(options, map[string]interface{}{
+					"type":      string(spec),
+					"territory": id,
+					"cost":      GetBuildCost(spec),
+					"gold_cost": GoldCost(spec),
+				})
+			}
+		}
+
+		if g.Settings.Depots && affordable(BuildDepot) && len(player.Depots) < MaxDepots && player.StockpileAt(id) == nil {
+			options = append(options, map[string]interface{}{
+				"type":      string(BuildDepot),
+				"territory": id,
+				"cost":      GetBuildCost(BuildDepot),
+				"gold_cost": GoldCost(BuildDepot),
+			})
+		}
 	}
 
 	return options
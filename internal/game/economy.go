@@ -0,0 +1,198 @@
+package game
+
+import "log"
+
+// The economy ruleset adds unit upkeep, storage caps that grow with cities and
+// city specializations. It only applies with Settings.Economy on, so classic
+// games produce exactly as before.
+//
+// A specialization is built in a city like any other development and
+// replaces the city's previous one.
+const (
+	BuildMine       BuildType = "mine"        // +1 coal, gold and iron nearby
+	BuildLumberMill BuildType = "lumber_mill" // +1 timber nearby
+	BuildStable     BuildType = "stable"      // A second horse from grassland nearby
+)
+
+const (
+	// BaseStorage is how much of each resource a player can keep without any
+	// cities. StoragePerCity is added for every city they own.
+	BaseStorage    = 5
+	StoragePerCity = 3
+)
+
+// IsSpecialization reports whether the build type is a city specialization.
+func (b BuildType) IsSpecialization() bool {
+	return b == BuildMine || b == BuildLumberMill || b == BuildStable
+}
+
+// specializationFor returns the specialization that boosts a resource.
+func specializationFor(resource ResourceType) BuildType {
+	switch resource {
+	case ResourceCoal, ResourceGold, ResourceIron:
+		return BuildMine
+	case ResourceTimber:
+		return BuildLumberMill
+	case ResourceGrassland:
+		return BuildStable
+	default:
+		return ""
+	}
+}
+
+// HasSpecializationBoost reports whether a territory's production is boosted
+// by a specialized city in it or next to it, owned by the same player.
+func (g *GameState) HasSpecializationBoost(t *Territory) bool {
+	if !g.Settings.Economy {
+		return false
+	}
+	spec := specializationFor(t.Resource)
+	if spec == "" {
+		return false
+	}
+	if t.HasCity && t.Specialization == spec {
+		return true
+	}
+	for _, adjID := range t.Adjacent {
+		adj := g.Territories[adjID]
+		if adj != nil && adj.Owner == t.Owner && adj.HasCity && adj.Specialization == spec {
+			return true
+		}
+	}
+	return false
+}
+
+// StorageCap returns how much of each resource a player can keep, counting
+// their depots, or 0 if stockpiles are unlimited.
+func (g *GameState) StorageCap(playerID string) int {
+	if !g.Settings.Economy {
+		return 0
+	}
+	cities := 0
+	for _, t := range g.Territories {
+		if t.Owner == playerID && t.HasCity {
+			cities++
+		}
+	}
+	return BaseStorage + cities*StoragePerCity
+}
+
+// UpkeepResource returns the resource a unit costs each round, or
+// ResourceNone for units that cost nothing. Horses graze for free.
+func UpkeepResource(unit string) ResourceType {
+	switch unit {
+	case "weapon":
+		return ResourceIron
+	case "boat", "siege":
+		return ResourceTimber
+	case "fortress":
+		return ResourceCoal
+	default:
+		return ResourceNone
+	}
+}
+
+// DisbandedUnit is a unit lost because its upkeep couldn't be paid.
+type DisbandedUnit struct {
+	TerritoryID   string
+	TerritoryName string
+	Unit          string // weapon, boat, siege or fortress
+}
+
+// EconomyReport is what the economy ruleset did to a player at production.
+type EconomyReport struct {
+	Upkeep    *Stockpile      // Resources paid to keep units
+	Disbanded []DisbandedUnit // Units that couldn't be paid for
+	Spoiled   *Stockpile      // Resources over the storage cap, thrown away
+}
+
+// SettleEconomy charges a player's upkeep and trims their stockpile to the
+// storage cap. It runs after production has been added, so this round's
+// production can pay for this round's upkeep. Each unit costs one of its
+// upkeep resource; units the stockpile can't pay for are disbanded. Depots
+// count too: upkeep is paid from the main stockpile first and then the
+// depots, and anything over the cap spoils in the depots before the main
+// stockpile. Returns nil with the economy ruleset off.
+func (g *GameState) SettleEconomy(playerID string) *EconomyReport {
+	player := g.Players[playerID]
+	if !g.Settings.Economy || player == nil || player.Eliminated {
+		return nil
+	}
+
+	report := &EconomyReport{Upkeep: NewStockpile(), Spoiled: NewStockpile()}
+	stores := player.Stores()
+	pay := func(t *Territory, unit string) bool {
+		resource := UpkeepResource(unit)
+		for _, store := range stores {
+			if store.Remove(resource, 1) {
+				report.Upkeep.Add(resource, 1)
+				return true
+			}
+		}
+		report.Disbanded = append(report.Disbanded, DisbandedUnit{
+			TerritoryID:   t.ID,
+			TerritoryName: t.Name,
+			Unit:          unit,
+		})
+		return false
+	}
+
+	for _, id := range sortedKeys(g.Territories) {
+		t := g.Territories[id]
+		if t.Owner != playerID {
+			continue
+		}
+		if t.HasWeapon && !pay(t, "weapon") {
+			t.HasWeapon = false
+		}
+		for _, wb := range sortedKeys(t.Boats) {
+			for range t.Boats[wb] {
+				if !pay(t, "boat") {
+					t.RemoveBoat(wb)
+				}
+			}
+		}
+		for range t.SiegeEngines {
+			if !pay(t, "siege") {
+				t.SiegeEngines--
+			}
+		}
+		for range t.Fortresses {
+			if !pay(t, "fortress") {
+				t.Fortresses--
+			}
+		}
+	}
+
+	limit := g.StorageCap(playerID)
+	for _, resource := range []ResourceType{ResourceCoal, ResourceGold, ResourceIron, ResourceTimber} {
+		extra := player.Holding(resource) - limit
+		for i := len(stores) - 1; i >= 0 && extra > 0; i-- {
+			n := min(extra, stores[i].Get(resource))
+			stores[i].Remove(resource, n)
+			report.Spoiled.Add(resource, n)
+			extra -= n
+		}
+	}
+
+	log.Printf("SettleEconomy: Player %s paid %+v upkeep, lost %d units, spoiled %+v",
+		player.Name, *report.Upkeep, len(report.Disbanded), *report.Spoiled)
+	return report
+}
+
+// SettleEconomies settles every player's economy once all production and
+// tribute is in, in player ID order so the outcome doesn't depend on map
+// iteration. Returns the reports by player ID, or nil with the economy
+// ruleset off.
+func (g *GameState) SettleEconomies() map[string]*EconomyReport {
+	if !g.Settings.Economy {
+		return nil
+	}
+	reports := make(map[string]*EconomyReport)
+	for _, id := range sortedKeys(g.Players) {
+		if report := g.SettleEconomy(id); report != nil {
+			reports[id] = report
+		}
+	}
+	return reports
+}
@@ -0,0 +1,112 @@
+package game
+
+import "testing"
+
+func TestSettleEconomy(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	a, b := g.Territories["a"], g.Territories["b"]
+	a.HasWeapon = true
+	b.HasWeapon = true
+	a.Fortresses = 1
+	player := g.Players["A"]
+	player.Stockpile = &Stockpile{Coal: 9, Iron: 1}
+
+	if g.SettleEconomy("A") != nil {
+		t.Fatal("classic rules settled an economy")
+	}
+
+	g.Settings.Economy = true
+	report := g.SettleEconomy("A")
+	if report.Upkeep.Iron != 1 || report.Upkeep.Coal != 1 {
+		t.Errorf("upkeep = %+v, want 1 iron and 1 coal", *report.Upkeep)
+	}
+	if len(report.Disbanded) != 1 || report.Disbanded[0].TerritoryID != "b" || b.HasWeapon {
+		t.Errorf("disbanded = %+v, want b's weapon", report.Disbanded)
+	}
+	if !a.HasWeapon || a.Fortresses != 1 {
+		t.Error("a lost units it paid for")
+	}
+
+	// No cities: everything over BaseStorage spoils
+	if player.Stockpile.Coal != BaseStorage || report.Spoiled.Coal != 8-BaseStorage {
+		t.Errorf("coal = %d, spoiled %d; want %d, %d", player.Stockpile.Coal, report.Spoiled.Coal, BaseStorage, 8-BaseStorage)
+	}
+	a.HasCity = true
+	if got, want := g.StorageCap("A"), BaseStorage+StoragePerCity; got != want {
+		t.Errorf("storage cap with a city = %d, want %d", got, want)
+	}
+}
+
+func TestSettleEconomyWithDepots(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	g.Settings.Economy = true
+	g.Settings.Depots = true
+	g.Territories["a"].HasWeapon = true
+	player := g.Players["A"]
+	player.Stockpile = &Stockpile{Coal: 4}
+	player.StockpileTerritory = "a"
+	player.Depots = map[string]*Stockpile{"c": {Coal: 4, Iron: 1}}
+
+	// The weapon is paid for from the depot; the coal over the cap spoils there
+	report := g.SettleEconomy("A")
+	if report.Upkeep.Iron != 1 || len(report.Disbanded) != 0 {
+		t.Errorf("upkeep = %+v, disbanded %v; want 1 iron paid from the depot", *report.Upkeep, report.Disbanded)
+	}
+	if report.Spoiled.Coal != 8-BaseStorage || player.Holding(ResourceCoal) != BaseStorage {
+		t.Errorf("spoiled %d coal, holding %d; want %d and %d", report.Spoiled.Coal, player.Holding(ResourceCoal), 8-BaseStorage, BaseStorage)
+	}
+	if player.Stockpile.Coal != 4 || player.Depots["c"].Coal != BaseStorage-4 {
+		t.Errorf("coal: stockpile %d, depot %d; want the depot to spoil first", player.Stockpile.Coal, player.Depots["c"].Coal)
+	}
+}
+
+func TestSpecializationBoost(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	a, b := g.Territories["a"], g.Territories["b"]
+	b.Resource = ResourceIron
+	g.CurrentPlayerID = "A"
+	pm := NewPhaseManager(g)
+
+	if err := g.CanBuild("A", BuildMine, "a", false); err != ErrInvalidAction {
+		t.Errorf("mine with classic rules: err = %v, want ErrInvalidAction", err)
+	}
+	g.Settings.Economy = true
+	if err := g.CanBuild("A", BuildMine, "a", false); err != ErrInvalidTarget {
+		t.Errorf("mine without a city: err = %v, want ErrInvalidTarget", err)
+	}
+
+	a.HasCity = true
+	if err := g.Build("A", BuildMine, "a", false); err != nil {
+		t.Fatalf("mine: %v", err)
+	}
+	if got := pm.productionAmount(b, "A"); got != 3 {
+		t.Errorf("iron next to a mining city = %d, want 3", got)
+	}
+
+	// A lumber mill replaces the mine and doesn't help iron
+	if err := g.Build("A", BuildLumberMill, "a", false); err != nil {
+		t.Fatalf("lumber mill: %v", err)
+	}
+	if got := pm.productionAmount(b, "A"); got != 2 {
+		t.Errorf("iron next to a lumber mill = %d, want 2", got)
+	}
+}
+
+func TestTributeBeforeStorageCap(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	g.Settings.Economy = true
+	g.Players["B"] = &Player{ID: "B", Stockpile: NewStockpile(), Overlord: "A"}
+	d := g.Territories["d"]
+	d.Resource = ResourceGold
+	d.HasCity = true
+
+	// The vassal's tribute lands on an overlord already at the cap, and
+	// spoils whichever of them is settled first
+	NewPhaseManager(g).ProcessProduction()
+	if gold := g.Players["A"].Stockpile.Gold; gold != BaseStorage {
+		t.Errorf("overlord holds %d gold after tribute, want the cap of %d", gold, BaseStorage)
+	}
+	if gold := g.Players["B"].Stockpile.Gold; gold != 1 {
+		t.Errorf("vassal holds %d gold, want 1 after paying tribute", gold)
+	}
+}
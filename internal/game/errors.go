@@ -17,5 +17,10 @@ var (
 	ErrGameOver              = errors.New("game is over")
 	ErrPlayerEliminated      = errors.New("player has been eliminated")
 	ErrHandFull              = errors.New("card hand is full")
+	ErrTerrainForbids        = errors.New("terrain does not allow this")
+	ErrStackFull             = errors.New("territory cannot hold more of this unit")
+	ErrTooMuchCargo          = errors.New("boat cannot carry that much")
+	ErrBlockaded             = errors.New("boats are blockaded by a stronger fleet")
+	ErrCardBattlePending     = errors.New("a card battle is waiting for the defender")
 )
 
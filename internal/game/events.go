@@ -0,0 +1,230 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+)
+
+// World events are drawn from a deck at the start of each round, with
+// Settings.WorldEvents on. How often one is drawn depends on the chance
+// level. An event that has nothing to act on (a plague with no horses on the
+// map, say) is discarded, so some draws come to nothing.
+
+// WorldEventType identifies what a world event does.
+type WorldEventType string
+
+const (
+	EventPlague    WorldEventType = "plague"    // Horses die in and around a territory
+	EventHarvest   WorldEventType = "harvest"   // Every territory produces double this round
+	EventStorm     WorldEventType = "storm"     // Boats in a water body can't sail this round
+	EventRebellion WorldEventType = "rebellion" // A territory without a city rises up and goes neutral
+)
+
+// worldEventTemplate is one kind of card in the world event deck.
+type worldEventTemplate struct {
+	Type   WorldEventType
+	Name   string
+	Weight int      // Relative chance of being drawn
+	Lines  []string // Flavour text, one picked at random
+}
+
+// worldEventDeck defines all world events with their draw weights.
+var worldEventDeck = []worldEventTemplate{
+	{Type: EventPlague, Name: "Plague", Weight: 30, Lines: []string{
+		"A wasting sickness spreads through the herds",
+		"Flies carry a fever from stable to stable",
+		"The horses have eaten something they shouldn't",
+	}},
+	{Type: EventHarvest, Name: "Bountiful Harvest", Weight: 30, Lines: []string{
+		"Perfect weather blesses the land",
+		"The mines strike rich seams everywhere",
+		"Workers discover the joy of overtime",
+	}},
+	{Type: EventStorm, Name: "Storms", Weight: 25, Lines: []string{
+		"Gales lash the coast",
+		"A sea monster has been sighted",
+		"Fog so thick the gulls walk",
+	}},
+	{Type: EventRebellion, Name: "Rebellion", Weight: 15, Lines: []string{
+		"Peasants take up pitchforks",
+		"A local hero declares independence",
+		"Taxes were one goat too many",
+	}},
+}
+
+// WorldEvent is a world event that happened at the start of a round.
+type WorldEvent struct {
+	Type        WorldEventType `json:"type"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`           // Flavour text and what happened
+	TerritoryID string         `json:"territoryId,omitempty"` // Territory hit, for plague and rebellion
+	WaterBodyID string         `json:"waterBodyId,omitempty"` // Water body hit, for storms
+}
+
+// WorldEventChance is the chance of drawing a world event each round.
+func WorldEventChance(chanceLevel ChanceLevel) float32 {
+	switch chanceLevel {
+	case ChanceLow:
+		return 0.15
+	case ChanceHigh:
+		return 0.5
+	default:
+		return 0.3
+	}
+}
+
+// drawWorldEvents ends last round's events and maybe draws a new one, adding
+// it to WorldEvents for the server to announce.
+func (g *GameState) drawWorldEvents() {
+	g.Harvest = false
+	g.StormWater = ""
+	if !g.Settings.WorldEvents || rand.Float32() >= WorldEventChance(g.Settings.ChanceLevel) {
+		return
+	}
+
+	totalWeight := 0
+	for _, t := range worldEventDeck {
+		totalWeight += t.Weight
+	}
+	roll := rand.Intn(totalWeight)
+	template := worldEventDeck[0]
+	for _, t := range worldEventDeck {
+		if roll < t.Weight {
+			template = t
+			break
+		}
+		roll -= t.Weight
+	}
+
+	event := WorldEvent{Type: template.Type, Name: template.Name}
+	if !g.applyWorldEvent(&event) {
+		log.Printf("drawWorldEvents: Drew %s but nothing was affected", template.Name)
+		return
+	}
+	event.Description = template.Lines[rand.Intn(len(template.Lines))] + ". " + event.Description
+	g.WorldEvents = append(g.WorldEvents, event)
+	log.Printf("drawWorldEvents: Round %d - %s: %s", g.Round, event.Name, event.Description)
+}
+
+// applyWorldEvent carries out an event, filling in what it hit. It returns
+// false if the event has nothing to act on.
+func (g *GameState) applyWorldEvent(event *WorldEvent) bool {
+	switch event.Type {
+	case EventPlague:
+		return g.applyPlague(event)
+	case EventHarvest:
+		g.Harvest = true
+		event.Description = "Every territory produces double this round."
+		return true
+	case EventStorm:
+		return g.applyStorm(event)
+	case EventRebellion:
+		return g.applyRebellion(event)
+	}
+	return false
+}
+
+// applyPlague kills the horses in a random territory with a horse and in
+// every territory next to it.
+func (g *GameState) applyPlague(event *WorldEvent) bool {
+	var candidates []string
+	for _, id := range sortedKeys(g.Territories) {
+		if g.Territories[id].HasHorse {
+			candidates = append(candidates, id)
+		}
+	}
+	if len(candidates) == 0 {
+		return false
+	}
+
+	center := g.Territories[candidates[rand.Intn(len(candidates))]]
+	died := 0
+	for _, id := range append([]string{center.ID}, center.Adjacent...) {
+		if t := g.Territories[id]; t != nil && t.HasHorse {
+			t.HasHorse = false
+			died++
+		}
+	}
+	event.TerritoryID = center.ID
+	event.Description = fmt.Sprintf("%d horses die in and around %s.", died, center.Name)
+	return true
+}
+
+// applyStorm keeps boats in a random water body with boats in it from
+// sailing this round.
+func (g *GameState) applyStorm(event *WorldEvent) bool {
+	var candidates []string
+	for _, id := range sortedKeys(g.WaterBodies) {
+		for _, tid := range g.WaterBodies[id].Territories {
+			if t := g.Territories[tid]; t != nil && t.BoatsInWater(id) > 0 {
+				candidates = append(candidates, id)
+				break
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return false
+	}
+
+	water := g.WaterBodies[candidates[rand.Intn(len(candidates))]]
+	name := water.Name
+	if name == "" {
+		name = "the sea"
+	}
+	g.StormWater = water.ID
+	event.WaterBodyID = water.ID
+	event.Description = fmt.Sprintf("Boats on %s can't sail this round.", name)
+	return true
+}
+
+// applyRebellion turns a random territory without a city neutral. Stockpile
+// and depot territories never rebel, so nobody loses their last territory to it. The
+// rebels keep any horse, weapon and fortresses to defend with; boats and
+// siege engines are lost. With neutrals on they also get a garrison.
+func (g *GameState) applyRebellion(event *WorldEvent) bool {
+	stockpiles := make(map[string]bool)
+	for _, p := range g.Players {
+		stockpiles[p.StockpileTerritory] = true
+		for id := range p.Depots {
+			stockpiles[id] = true
+		}
+	}
+	held := make(map[string]int)
+	for _, t := range g.Territories {
+		held[t.Owner]++
+	}
+
+	var candidates []string
+	for _, id := range sortedKeys(g.Territories) {
+		t := g.Territories[id]
+		if t.Owner != "" && !t.IsBarbarian() && !t.HasCity && !stockpiles[id] && held[t.Owner] > 1 {
+			candidates = append(candidates, id)
+		}
+	}
+	if len(candidates) == 0 {
+		return false
+	}
+
+	t := g.Territories[candidates[rand.Intn(len(candidates))]]
+	owner := g.Players[t.Owner]
+	t.Owner = ""
+	t.Boats = make(map[string]int)
+	t.Transports = nil
+	t.SiegeEngines = 0
+	if g.HasNeutrals() {
+		t.Garrison = NeutralGarrison
+	}
+	event.TerritoryID = t.ID
+	if owner != nil {
+		event.Description = fmt.Sprintf("%s breaks away from %s.", t.Name, owner.Name)
+	} else {
+		event.Description = fmt.Sprintf("%s breaks away.", t.Name)
+	}
+	return true
+}
+
+// IsStormy reports whether a storm keeps boats in a water body from sailing.
+func (g *GameState) IsStormy(waterID string) bool {
+	return waterID != "" && g.StormWater == waterID
+}
@@ -0,0 +1,94 @@
+package game
+
+import "testing"
+
+func TestPlagueAndHarvest(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	b, c := g.Territories["b"], g.Territories["c"]
+	b.HasHorse = true
+	c.HasHorse = true
+	c.Resource = ResourceCoal
+	pm := NewPhaseManager(g)
+
+	// Whichever horse the plague strikes, the other is next to it
+	event := WorldEvent{Type: EventPlague}
+	if !g.applyWorldEvent(&event) {
+		t.Fatal("plague with horses on the map did nothing")
+	}
+	if b.HasHorse || c.HasHorse {
+		t.Error("plague spared a neighbouring horse")
+	}
+	if g.applyWorldEvent(&event) {
+		t.Error("plague with no horses left wasn't discarded")
+	}
+
+	event = WorldEvent{Type: EventHarvest}
+	g.applyWorldEvent(&event)
+	if got := pm.productionAmount(c, "A"); got != 2 {
+		t.Errorf("coal in a harvest = %d, want 2", got)
+	}
+
+	// The next round ends the harvest; with events off nothing new is drawn
+	g.drawWorldEvents()
+	if g.Harvest || len(g.WorldEvents) != 0 {
+		t.Errorf("harvest = %v, events = %v after a round with events off", g.Harvest, g.WorldEvents)
+	}
+}
+
+func TestRebellion(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	g.Players["A"].StockpileTerritory = "a"
+	g.Territories["b"].HasCity = true
+	c := g.Territories["c"]
+	c.HasWeapon = true
+	c.SiegeEngines = 1
+
+	// a holds the stockpile, b a city, and d is B's only territory
+	event := WorldEvent{Type: EventRebellion}
+	if !g.applyWorldEvent(&event) {
+		t.Fatal("rebellion found nowhere to rise")
+	}
+	if event.TerritoryID != "c" || c.Owner != "" {
+		t.Errorf("rebellion in %q, c owned by %q; want c gone neutral", event.TerritoryID, c.Owner)
+	}
+	if !c.HasWeapon || c.SiegeEngines != 0 {
+		t.Error("rebels should keep the weapon and lose the siege engine")
+	}
+}
+
+func TestStormKeepsBoatsIn(t *testing.T) {
+	g := straitTestState(true)
+	event := WorldEvent{Type: EventStorm}
+	if !g.applyWorldEvent(&event) {
+		t.Fatal("storm with boats on the map did nothing")
+	}
+	if event.WaterBodyID != "west" {
+		t.Fatalf("storm on %q, want the west sea with the only boat", event.WaterBodyID)
+	}
+	if g.canBoatReachTargetViaWater("A", "a", "e", "west") {
+		t.Error("boat in a storm can still attack")
+	}
+	if err := g.moveBoat(g.Players["A"], g.Territories["a"], g.Territories["s"], "west", false, false, false, 0); err != ErrCannotReach {
+		t.Errorf("boat in a storm: err = %v, want ErrCannotReach", err)
+	}
+}
+
+func TestWorldEventsDrawnAtRoundStart(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	g.Settings.WorldEvents = true
+	g.Settings.ChanceLevel = ChanceHigh
+	g.Players["B"] = &Player{ID: "B"}
+	g.PlayerOrder = []string{"A", "B"}
+	g.Territories["c"].Resource = ResourceCoal
+	g.Territories["b"].HasHorse = true
+
+	// A harvest always applies, so half the rounds at high chance end up
+	// with an event
+	for i := 0; i < 50 && len(g.WorldEvents) == 0; i++ {
+		g.Phase = PhaseConquest
+		NewPhaseManager(g).NextPhase()
+	}
+	if len(g.WorldEvents) == 0 {
+		t.Fatal("no world event in 50 rounds at high chance")
+	}
+}
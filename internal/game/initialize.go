@@ -25,6 +25,9 @@ func InitializeGame(mapData MapData, players []*Player, settings Settings) (*Gam
 		Territories: make(map[string]*Territory),
 		WaterBodies: make(map[string]*WaterBody),
 	}
+	if settings.Market {
+		state.Market = NewMarket(1)
+	}
 
 	// Add players
 	state.PlayerOrder = make([]string, len(players))
@@ -45,6 +48,7 @@ func InitializeGame(mapData MapData, players []*Player, settings Settings) (*Gam
 			Name:         t.Name,
 			Owner:        "", // Unclaimed
 			Resource:     t.Resource,
+			Terrain:      t.Terrain,
 			Boats:        make(map[string]int), // Initialize empty boat map
 			Adjacent:     t.Adjacent,
 			CoastalTiles: t.CoastalTiles,
@@ -56,10 +60,27 @@ func InitializeGame(mapData MapData, players []*Player, settings Settings) (*Gam
 	for id, w := range mapData.WaterBodies {
 		state.WaterBodies[id] = &WaterBody{
 			ID:          id,
+			Name:        w.Name,
 			Territories: w.Territories,
 		}
 	}
 
+	// Preset starts replace the territory draft: seats go to players in
+	// the random order, and the first round begins straight away
+	if settings.PresetStarts {
+		if len(mapData.Starts) != len(players) {
+			return nil, fmt.Errorf("map has starting territories for %d players, not %d", len(mapData.Starts), len(players))
+		}
+		for i, pid := range state.PlayerOrder {
+			for _, tid := range mapData.Starts[i] {
+				if t := state.Territories[tid]; t != nil {
+					t.Owner = pid
+				}
+			}
+		}
+		state.startFirstRound()
+	}
+
 	return state, nil
 }
 
@@ -69,12 +90,14 @@ type MapData struct {
 	Name        string
 	Territories map[string]TerritoryData
 	WaterBodies map[string]WaterBodyData
+	Starts      [][]string // Starting territories of each seat, if the map has them
 }
 
 // TerritoryData contains territory information from the map.
 type TerritoryData struct {
 	Name         string
 	Resource     ResourceType
+	Terrain      Terrain
 	Adjacent     []string
 	CoastalTiles int
 	WaterBodies  []string
@@ -82,6 +105,7 @@ type TerritoryData struct {
 
 // WaterBodyData contains water body information.
 type WaterBodyData struct {
+	Name        string
 	Territories []string
 }
 
@@ -0,0 +1,46 @@
+package game
+
+import "testing"
+
+func TestPresetStarts(t *testing.T) {
+	mapData := MapData{
+		Territories: map[string]TerritoryData{
+			"1": {Adjacent: []string{"2"}}, "2": {Adjacent: []string{"1", "3"}},
+			"3": {Adjacent: []string{"2", "4"}}, "4": {Adjacent: []string{"3"}},
+		},
+		Starts: [][]string{{"1", "2"}, {"3", "4"}},
+	}
+	players := func() []*Player {
+		return []*Player{NewPlayer("A", "A", "red"), NewPlayer("B", "B", "blue")}
+	}
+
+	g, err := InitializeGame(mapData, players(), Settings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Phase != PhaseTerritorySelection {
+		t.Errorf("without preset starts phase = %v, want territory selection", g.Phase)
+	}
+
+	g, err = InitializeGame(mapData, players(), Settings{PresetStarts: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Phase != PhaseProduction || g.Round != 1 || !g.StockpilePlacementPending {
+		t.Errorf("phase = %v round %d, want round 1 production with stockpiles to place", g.Phase, g.Round)
+	}
+	for _, seat := range mapData.Starts {
+		owner := g.Territories[seat[0]].Owner
+		if owner == "" || g.Territories[seat[1]].Owner != owner {
+			t.Errorf("seat %v is not held by one player", seat)
+		}
+	}
+	if g.Territories["1"].Owner == g.Territories["3"].Owner {
+		t.Error("both seats went to the same player")
+	}
+
+	mapData.Starts = mapData.Starts[:1]
+	if _, err := InitializeGame(mapData, players(), Settings{PresetStarts: true}); err == nil {
+		t.Error("expected an error when the map has starts for the wrong number of players")
+	}
+}
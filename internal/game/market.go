@@ -0,0 +1,153 @@
+package game
+
+import "log"
+
+// The market is a bank every player can trade with during the Trade phase.
+// It only exists with Settings.Market on. Each resource has a price in the
+// same abstract unit; selling to the bank earns the price, and buying from
+// it costs the price plus MarketSpread, so there is nothing to gain by
+// trading back and forth. Prices move each round with what was bought and
+// sold, and drift back towards BasePrice when nobody trades.
+const (
+	BasePrice    = 4
+	MinPrice     = 1
+	MaxPrice     = 10
+	MarketSpread = 1
+
+	// MarketDepth is how many units of net buying or selling move a price by
+	// one. MaxPriceMove caps how far a price moves in a round.
+	MarketDepth  = 3
+	MaxPriceMove = 2
+)
+
+// marketResources are the resources the bank deals in.
+var marketResources = []ResourceType{ResourceCoal, ResourceGold, ResourceIron, ResourceTimber}
+
+// Market is the shared bank's prices and this round's trading.
+type Market struct {
+	Prices  *Stockpile     `json:"prices"`
+	Sold    *Stockpile     `json:"sold"`    // Sold to the bank this round
+	Bought  *Stockpile     `json:"bought"`  // Bought from the bank this round
+	History []MarketRecord `json:"history"` // Prices at the start of each round, oldest first
+}
+
+// MarketRecord is the market's prices in one round.
+type MarketRecord struct {
+	Round  int       `json:"round"`
+	Prices Stockpile `json:"prices"`
+}
+
+// NewMarket creates a market with every price at BasePrice.
+func NewMarket(round int) *Market {
+	prices := &Stockpile{Coal: BasePrice, Gold: BasePrice, Iron: BasePrice, Timber: BasePrice}
+	return &Market{
+		Prices:  prices,
+		Sold:    NewStockpile(),
+		Bought:  NewStockpile(),
+		History: []MarketRecord{{Round: round, Prices: *prices}},
+	}
+}
+
+// BuyPrice is what the bank charges for one unit of a resource.
+func (m *Market) BuyPrice(resource ResourceType) int {
+	return m.Prices.Get(resource) + MarketSpread
+}
+
+// SellPrice is what the bank pays for one unit of a resource.
+func (m *Market) SellPrice(resource ResourceType) int {
+	return m.Prices.Get(resource)
+}
+
+// Quote returns how much of one resource the bank gives for an amount of
+// another.
+func (m *Market) Quote(sell ResourceType, amount int, buy ResourceType) int {
+	return amount * m.SellPrice(sell) / m.BuyPrice(buy)
+}
+
+// AveragePrice is a resource's mean price over the last rounds of history.
+func (m *Market) AveragePrice(resource ResourceType, rounds int) float64 {
+	history := m.History
+	if len(history) > rounds {
+		history = history[len(history)-rounds:]
+	}
+	if len(history) == 0 {
+		return float64(m.Prices.Get(resource))
+	}
+	total := 0
+	for _, r := range history {
+		total += r.Prices.Get(resource)
+	}
+	return float64(total) / float64(len(history))
+}
+
+// MarketTrade sells an amount of one resource to the bank and buys as much
+// of another as that pays for, rounded down. It returns how much was bought.
+// Trading with the bank doesn't end the player's Trade turn.
+func (g *GameState) MarketTrade(playerID string, sell ResourceType, amount int, buy ResourceType) (int, error) {
+	if !g.Settings.Market || g.Market == nil || g.Phase != PhaseTrade {
+		return 0, ErrInvalidAction
+	}
+	if g.CurrentPlayerID != playerID {
+		return 0, ErrNotYourTurn
+	}
+	player := g.Players[playerID]
+	if player == nil {
+		return 0, ErrInvalidTarget
+	}
+	if sell == buy || !sell.IsStockpilable() || !buy.IsStockpilable() || amount <= 0 {
+		return 0, ErrInvalidTarget
+	}
+	if player.Stockpile.Get(sell) < amount {
+		return 0, ErrInsufficientResources
+	}
+
+	got := g.Market.Quote(sell, amount, buy)
+	if got == 0 {
+		return 0, ErrInsufficientResources
+	}
+
+	player.Stockpile.Remove(sell, amount)
+	player.Stockpile.Add(buy, got)
+	g.Market.Sold.Add(sell, amount)
+	g.Market.Bought.Add(buy, got)
+	log.Printf("MarketTrade: Player %s sold %d %s for %d %s", player.Name, amount, sell, got, buy)
+	return got, nil
+}
+
+// updateMarket moves prices with the round's trading and records them for
+// the next round. Net buying raises a price, net selling lowers it, and a
+// resource nobody traded drifts one step back towards BasePrice.
+func (g *GameState) updateMarket() {
+	if !g.Settings.Market || g.Market == nil {
+		return
+	}
+	m := g.Market
+	for _, r := range marketResources {
+		price := m.Prices.Get(r)
+		pressure := m.Bought.Get(r) - m.Sold.Get(r)
+		traded := m.Bought.Get(r) + m.Sold.Get(r)
+		move := 0
+		switch {
+		case pressure > 0:
+			move = min((pressure+MarketDepth-1)/MarketDepth, MaxPriceMove)
+		case pressure < 0:
+			move = -min((-pressure+MarketDepth-1)/MarketDepth, MaxPriceMove)
+		case traded == 0 && price > BasePrice:
+			move = -1
+		case traded == 0 && price < BasePrice:
+			move = 1
+		}
+		m.Prices.Add(r, max(MinPrice, min(MaxPrice, price+move))-price)
+	}
+	m.Sold = NewStockpile()
+	m.Bought = NewStockpile()
+	m.History = append(m.History, MarketRecord{Round: g.Round + 1, Prices: *m.Prices})
+	log.Printf("updateMarket: Prices for round %d - Coal:%d Gold:%d Iron:%d Timber:%d",
+		g.Round+1, m.Prices.Coal, m.Prices.Gold, m.Prices.Iron, m.Prices.Timber)
+}
+
+// HasTradePhase reports whether rounds include a Trade phase: with three or
+// more players, or with the market on.
+func (g *GameState) HasTradePhase() bool {
+	return len(g.Players) >= 3 || g.Settings.Market
+}
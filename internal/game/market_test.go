@@ -0,0 +1,69 @@
+package game
+
+import "testing"
+
+func TestMarketTrade(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	g.Phase = PhaseTrade
+	g.CurrentPlayerID = "A"
+	player := g.Players["A"]
+	player.Stockpile = &Stockpile{Coal: 6}
+
+	if _, err := g.MarketTrade("A", ResourceCoal, 5, ResourceIron); err != ErrInvalidAction {
+		t.Errorf("trade without a market: err = %v, want ErrInvalidAction", err)
+	}
+
+	g.Settings.Market = true
+	g.Market = NewMarket(g.Round)
+	if !g.HasTradePhase() {
+		t.Error("two-player game with a market has no Trade phase")
+	}
+
+	// 5 coal at 4 pays 20, iron costs 4+1: 4 iron
+	got, err := g.MarketTrade("A", ResourceCoal, 5, ResourceIron)
+	if err != nil {
+		t.Fatalf("trade: %v", err)
+	}
+	if got != 4 || player.Stockpile.Coal != 1 || player.Stockpile.Iron != 4 {
+		t.Errorf("got %d iron, stockpile %+v; want 4 iron and 1 coal left", got, *player.Stockpile)
+	}
+	if _, err := g.MarketTrade("A", ResourceCoal, 1, ResourceIron); err != ErrInsufficientResources {
+		t.Errorf("trade worth nothing: err = %v, want ErrInsufficientResources", err)
+	}
+	if _, err := g.MarketTrade("B", ResourceIron, 1, ResourceCoal); err != ErrNotYourTurn {
+		t.Errorf("trade out of turn: err = %v, want ErrNotYourTurn", err)
+	}
+}
+
+func TestUpdateMarket(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	g.Settings.Market = true
+	g.Market = NewMarket(g.Round)
+	m := g.Market
+	m.Prices.Gold = BasePrice + 3
+
+	m.Sold.Add(ResourceCoal, 10)
+	m.Bought.Add(ResourceIron, 4)
+	m.Sold.Add(ResourceTimber, 2)
+	m.Bought.Add(ResourceTimber, 2)
+	g.updateMarket()
+
+	want := Stockpile{
+		Coal:   BasePrice - MaxPriceMove, // Heavy selling, capped
+		Gold:   BasePrice + 2,            // Untraded, drifts back
+		Iron:   BasePrice + 2,            // 4 bought moves it 2
+		Timber: BasePrice,                // Balanced trading holds it
+	}
+	if *m.Prices != want {
+		t.Errorf("prices = %+v, want %+v", *m.Prices, want)
+	}
+	if m.Sold.Coal != 0 || m.Bought.Iron != 0 {
+		t.Error("round's trading wasn't reset")
+	}
+	if len(m.History) != 2 || m.History[1].Round != g.Round+1 {
+		t.Errorf("history = %+v, want a record for round %d", m.History, g.Round+1)
+	}
+	if got := m.AveragePrice(ResourceIron, 5); got != float64(BasePrice)+1 {
+		t.Errorf("average iron price = %v, want %v", got, BasePrice+1)
+	}
+}
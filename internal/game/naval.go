@@ -0,0 +1,160 @@
+package game
+
+import "sort"
+
+// With Settings.NavalCombat on, fleets sharing a water body can fight. A
+// player's fleet in a water body is all their boats there, across every
+// coastal territory they hold on it, and its naval strength is the number of
+// boats. A sea battle pits the attacker's fleet against the fleet of one of
+// the defender's coastal territories; the loser gives up a boat. A fleet that
+// outnumbers a hostile one blockades it: the weaker side's boats can't launch
+// into that water body, neither to move nor to join an attack.
+
+// NavalStrength returns how many boats a player has in a water body.
+func (g *GameState) NavalStrength(playerID, waterBodyID string) int {
+	water := g.WaterBodies[waterBodyID]
+	if water == nil || playerID == "" {
+		return 0
+	}
+	strength := 0
+	for _, tid := range water.Territories {
+		if t := g.Territories[tid]; t != nil && t.Owner == playerID {
+			strength += t.BoatsInWater(waterBodyID)
+		}
+	}
+	return strength
+}
+
+// hostileFleets reports whether two players' fleets would fight: they belong
+// to different players and neither is the other's vassal.
+func (g *GameState) hostileFleets(playerID, otherID string) bool {
+	return playerID != "" && otherID != "" && playerID != otherID &&
+		!g.IsVassalOf(playerID, otherID) && !g.IsVassalOf(otherID, playerID)
+}
+
+// Blockaded reports whether the boats a territory keeps in a water body are
+// blockaded: some hostile player has more boats there than its owner.
+func (g *GameState) Blockaded(territoryID, waterBodyID string) bool {
+	t := g.Territories[territoryID]
+	water := g.WaterBodies[waterBodyID]
+	if !g.Settings.NavalCombat || t == nil || water == nil || t.Owner == "" {
+		return false
+	}
+	own := g.NavalStrength(t.Owner, waterBodyID)
+	for _, tid := range water.Territories {
+		other := g.Territories[tid]
+		if other != nil && g.hostileFleets(t.Owner, other.Owner) && g.NavalStrength(other.Owner, waterBodyID) > own {
+			return true
+		}
+	}
+	return false
+}
+
+// controlsWater reports whether a defender's fleet in a water body is stronger
+// than the attacker's, keeping the attacker's boats from landing through it.
+func (g *GameState) controlsWater(defenderID, attackerID, waterBodyID string) bool {
+	return g.Settings.NavalCombat && g.hostileFleets(defenderID, attackerID) &&
+		g.NavalStrength(defenderID, waterBodyID) > g.NavalStrength(attackerID, waterBodyID)
+}
+
+// CanNavalAttack checks if a player's fleet can attack the boats a territory
+// keeps in a water body.
+func (g *GameState) CanNavalAttack(attackerID, targetID, waterBodyID string) bool {
+	attacker := g.Players[attackerID]
+	target := g.Territories[targetID]
+	if !g.Settings.NavalCombat || attacker == nil || attacker.Eliminated || target == nil {
+		return false
+	}
+	if g.Players[target.Owner] == nil || !g.hostileFleets(attackerID, target.Owner) {
+		return false
+	}
+	return target.BoatsInWater(waterBodyID) > 0 && g.NavalStrength(attackerID, waterBodyID) > 0
+}
+
+// NavalAttack fights a sea battle between the attacker's fleet and the boats
+// a territory keeps in a water body. The two sides' naval strengths are
+// compared as in any other battle. If the attacker wins, one of the target's
+// boats there is captured by a coastal territory of theirs with room for it,
+// or sunk if none has room; if the attacker loses, a boat from their
+// territory with the most boats there is sunk. Neither territory changes
+// hands. A sea battle uses up an attack like any other.
+func (g *GameState) NavalAttack(attackerID, targetID, waterBodyID string) (*CombatResult, error) {
+	if g.Phase != PhaseConquest || !g.Settings.NavalCombat {
+		return nil, ErrInvalidAction
+	}
+	if g.CurrentPlayerID != attackerID {
+		return nil, ErrNotYourTurn
+	}
+	if g.PendingCardBattle != nil {
+		return nil, ErrCardBattlePending
+	}
+
+	attacker := g.Players[attackerID]
+	if attacker == nil {
+		return nil, ErrInvalidTarget
+	}
+	if attacker.AttacksRemaining <= 0 {
+		return nil, ErrNoAttacksRemaining
+	}
+	if !g.CanNavalAttack(attackerID, targetID, waterBodyID) {
+		return nil, ErrInvalidTarget
+	}
+	target := g.Territories[targetID]
+
+	result := &CombatResult{
+		Naval:           true,
+		AttackStrength:  g.NavalStrength(attackerID, waterBodyID),
+		DefenseStrength: g.NavalStrength(target.Owner, waterBodyID),
+	}
+	result.AttackerWins = g.ResolveCombat(result.AttackStrength, result.DefenseStrength)
+
+	if result.AttackerWins {
+		target.RemoveBoat(waterBodyID)
+		if port := g.fleetPort(attackerID, waterBodyID, true); port != "" {
+			g.Territories[port].AddBoat(waterBodyID)
+			result.UnitsCaptured = append(result.UnitsCaptured, UnitInfo{Type: UnitBoat, TerritoryID: port})
+		} else {
+			result.UnitsDestroyed = append(result.UnitsDestroyed, UnitInfo{Type: UnitBoat, TerritoryID: targetID})
+		}
+	} else if port := g.fleetPort(attackerID, waterBodyID, false); port != "" {
+		g.Territories[port].RemoveBoat(waterBodyID)
+		result.UnitsDestroyed = append(result.UnitsDestroyed, UnitInfo{Type: UnitBoat, TerritoryID: port})
+	}
+
+	attacker.AttacksRemaining--
+	if !result.AttackerWins && attacker.AttacksRemaining == 1 {
+		attacker.AttacksRemaining = 0
+	}
+	if attacker.AttacksRemaining <= 0 {
+		g.AdvanceConquestTurn()
+	}
+	return result, nil
+}
+
+// fleetPort picks one of a player's coastal territories on a water body: with
+// room set, the first that can take another boat there, otherwise the one
+// with the most boats there. It returns "" if there is none.
+func (g *GameState) fleetPort(playerID, waterBodyID string, room bool) string {
+	water := g.WaterBodies[waterBodyID]
+	if water == nil {
+		return ""
+	}
+	ids := append([]string(nil), water.Territories...)
+	sort.Strings(ids)
+
+	best := ""
+	for _, tid := range ids {
+		t := g.Territories[tid]
+		if t == nil || t.Owner != playerID {
+			continue
+		}
+		if room {
+			if t.CanAddBoatToWater(waterBodyID) {
+				return tid
+			}
+		} else if t.BoatsInWater(waterBodyID) > 0 && (best == "" || t.BoatsInWater(waterBodyID) > g.Territories[best].BoatsInWater(waterBodyID)) {
+			best = tid
+		}
+	}
+	return best
+}
@@ -0,0 +1,68 @@
+package game
+
+import "testing"
+
+// navalTestState puts A's boat at b and two of B's boats at e, all in the
+// east sea of straitTestState.
+func navalTestState() *GameState {
+	g := straitTestState(false)
+	g.Settings.NavalCombat = true
+	g.Settings.ChanceLevel = ChanceLow
+	g.Territories["a"].Boats = nil
+	g.Territories["b"].Boats = map[string]int{"east": 1}
+	g.Territories["e"].Boats = map[string]int{"east": 2}
+	return g
+}
+
+func TestBlockade(t *testing.T) {
+	g := navalTestState()
+	if !g.Blockaded("b", "east") || g.Blockaded("e", "east") {
+		t.Errorf("blockaded: b %v, e %v; want b only", g.Blockaded("b", "east"), g.Blockaded("e", "east"))
+	}
+	if err := g.moveBoat(g.Players["A"], g.Territories["b"], g.Territories["s"], "east", false, false, false, 0); err != ErrBlockaded {
+		t.Errorf("moving a blockaded boat: err = %v, want %v", err, ErrBlockaded)
+	}
+	if g.canBoatReachTargetViaWater("A", "b", "e", "east") {
+		t.Error("blockaded boat should not reach e")
+	}
+
+	// Evenly matched fleets neither blockade nor hold the water
+	g.Territories["s"].Boats = map[string]int{"east": 1}
+	if g.Blockaded("b", "east") || !g.canBoatReachTargetViaWater("A", "b", "e", "east") {
+		t.Error("with two boats each, A's boats should launch and reach e")
+	}
+
+	g.Settings.NavalCombat = false
+	g.Territories["s"].Boats = nil
+	if g.Blockaded("b", "east") {
+		t.Error("blockade without naval combat")
+	}
+}
+
+func TestNavalAttack(t *testing.T) {
+	g := navalTestState()
+	g.Phase = PhaseConquest
+	g.CurrentPlayerID = "A"
+	g.Players["A"].AttacksRemaining = 2
+	g.Territories["s"].Boats = map[string]int{"east": 1}
+	g.Territories["b"].Boats["east"] = 2
+
+	if _, err := g.NavalAttack("A", "e", "west"); err != ErrInvalidTarget {
+		t.Errorf("attack in a sea e has no boats in: err = %v, want %v", err, ErrInvalidTarget)
+	}
+
+	// Three boats against two; the captured boat goes to s, the first with room
+	result, err := g.NavalAttack("A", "e", "east")
+	if err != nil {
+		t.Fatalf("NavalAttack: %v", err)
+	}
+	if !result.Naval || !result.AttackerWins || result.AttackStrength != 3 || result.DefenseStrength != 2 {
+		t.Fatalf("result: naval %v, wins %v, %d vs %d", result.Naval, result.AttackerWins, result.AttackStrength, result.DefenseStrength)
+	}
+	if g.Territories["e"].BoatsInWater("east") != 1 || g.Territories["s"].BoatsInWater("east") != 2 {
+		t.Errorf("after battle: e %v, s %v", g.Territories["e"].Boats, g.Territories["s"].Boats)
+	}
+	if g.Territories["e"].Owner != "B" || g.Players["A"].AttacksRemaining != 1 {
+		t.Errorf("after battle: e owned by %q, %d attacks left", g.Territories["e"].Owner, g.Players["A"].AttacksRemaining)
+	}
+}
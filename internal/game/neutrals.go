@@ -0,0 +1,196 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+)
+
+// With Settings.Neutrals on, territory selection stops early and leaves one
+// territory in NeutralShare to neutral forces, which defend with a fixed
+// garrison on top of the usual defense and never help each other. Players
+// have to conquer them in Conquest.
+//
+// Settings.Barbarians goes further: half the neutral territories, in one
+// connected horde, belong to the barbarians instead. The barbarians aren't a
+// player - they own territories under BarbarianID but have no entry in
+// Players, so they take no turns, can't be eliminated and can't win. At the
+// end of each round they strike the neighbour they have the best odds
+// against, if they aren't outmatched.
+const (
+	BarbarianID   = "barbarians"
+	BarbarianName = "Barbarians"
+
+	// NeutralShare is how many territories there are for each one left neutral.
+	NeutralShare = 5
+
+	// NeutralGarrison is the defense a neutral territory's garrison adds.
+	NeutralGarrison = 3
+
+	// BarbarianFury is added to the barbarians' attack strength.
+	BarbarianFury = 2
+)
+
+// EventRaid reports the barbarians' attack at the end of a round.
+const EventRaid WorldEventType = "raid"
+
+// HasNeutrals reports whether some territories are left to neutral forces.
+func (g *GameState) HasNeutrals() bool {
+	return g.Settings.Neutrals || g.Settings.Barbarians
+}
+
+// IsBarbarian reports whether a territory is held by the barbarians.
+func (t *Territory) IsBarbarian() bool {
+	return t.Owner == BarbarianID
+}
+
+// OwnerName returns the display name of a territory owner: the player's
+// name, BarbarianName, or "" for an unclaimed territory or unknown player.
+func (g *GameState) OwnerName(ownerID string) string {
+	if ownerID == BarbarianID {
+		return BarbarianName
+	}
+	if p := g.Players[ownerID]; p != nil {
+		return p.Name
+	}
+	return ""
+}
+
+// unclaimedQuota is how many territories territory selection leaves
+// unclaimed: fewer than the number of players, or with neutrals one in
+// NeutralShare if that's more.
+func (g *GameState) unclaimedQuota() int {
+	quota := len(g.Players) - 1
+	if g.HasNeutrals() {
+		quota = max(quota, len(g.Territories)/NeutralShare)
+	}
+	return quota
+}
+
+// garrisonNeutrals hands the territories nobody claimed to neutral forces
+// when the first round starts, and with barbarians on, raises the horde.
+func (g *GameState) garrisonNeutrals() {
+	if !g.HasNeutrals() {
+		return
+	}
+
+	var unclaimed []string
+	for _, id := range sortedKeys(g.Territories) {
+		if g.Territories[id].Owner == "" {
+			unclaimed = append(unclaimed, id)
+		}
+	}
+	if len(unclaimed) == 0 {
+		return
+	}
+
+	if g.Settings.Barbarians {
+		// Preset starts leave everything else unclaimed; the horde stays the
+		// same size as after a draft
+		size := (min(len(unclaimed), g.unclaimedQuota()) + 1) / 2
+		g.raiseHorde(unclaimed[rand.Intn(len(unclaimed))], size)
+	}
+	for _, id := range unclaimed {
+		if t := g.Territories[id]; t.Owner == "" {
+			t.Garrison = NeutralGarrison
+		}
+	}
+}
+
+// raiseHorde gives the barbarians up to size unclaimed territories,
+// spreading out from home.
+func (g *GameState) raiseHorde(home string, size int) {
+	queue := []string{home}
+	seen := map[string]bool{home: true}
+	for len(queue) > 0 && size > 0 {
+		t := g.Territories[queue[0]]
+		queue = queue[1:]
+		t.Owner = BarbarianID
+		size--
+		for _, adjID := range t.Adjacent {
+			if adj := g.Territories[adjID]; adj != nil && adj.Owner == "" && !seen[adjID] {
+				seen[adjID] = true
+				queue = append(queue, adjID)
+			}
+		}
+	}
+	log.Printf("raiseHorde: Barbarians rise in %s", g.Territories[home].Name)
+}
+
+// barbarianTurn lets the barbarians strike at the end of a round. They
+// attack the neighbouring territory with the best margin of attack over
+// defense, and only if the margin isn't negative. The attack is reported as
+// a world event.
+func (g *GameState) barbarianTurn() {
+	if !g.Settings.Barbarians {
+		return
+	}
+
+	var target *Territory
+	bestAttack, bestDefense := 0, 0
+	ids := sortedKeys(g.Territories)
+	for _, id := range ids {
+		t := g.Territories[id]
+		if t.IsBarbarian() || !g.bordersBarbarians(t) {
+			continue
+		}
+		attack := g.CalculateAttackStrength(BarbarianID, t, nil) + BarbarianFury
+		defense := g.CalculateDefenseStrength(t)
+		if attack < defense {
+			continue
+		}
+		if target == nil || attack-defense > bestAttack-bestDefense {
+			target, bestAttack, bestDefense = t, attack, defense
+		}
+	}
+	if target == nil {
+		return
+	}
+
+	event := WorldEvent{Type: EventRaid, Name: "Barbarian Raid", TerritoryID: target.ID}
+	victim := g.OwnerName(target.Owner)
+	if victim == "" {
+		victim = "the neutrals"
+	}
+	if !g.ResolveCombat(bestAttack, bestDefense) {
+		event.Description = fmt.Sprintf("%s drives the barbarians back from %s (%d vs %d).",
+			capitalize(victim), target.Name, bestAttack, bestDefense)
+		g.WorldEvents = append(g.WorldEvents, event)
+		log.Printf("barbarianTurn: Raid on %s failed", target.Name)
+		return
+	}
+
+	defenderID := target.Owner
+	target.Owner = BarbarianID
+	target.Garrison = 0
+	target.Boats = make(map[string]int)
+	target.Transports = nil
+	target.SiegeEngines = 0
+
+	// The barbarians burn whatever stockpile they find
+	g.takeStockpile(defenderID, target.ID)
+	g.checkElimination(defenderID)
+
+	event.Description = fmt.Sprintf("The barbarians overrun %s, taking it from %s (%d vs %d).",
+		target.Name, victim, bestAttack, bestDefense)
+	g.WorldEvents = append(g.WorldEvents, event)
+	log.Printf("barbarianTurn: Barbarians took %s from %s", target.Name, victim)
+}
+
+// bordersBarbarians reports whether a territory is next to the barbarians.
+func (g *GameState) bordersBarbarians(t *Territory) bool {
+	for _, adjID := range t.Adjacent {
+		if adj := g.Territories[adjID]; adj != nil && adj.IsBarbarian() {
+			return true
+		}
+	}
+	return false
+}
+
+// capitalize upper-cases the first letter of s.
+func capitalize(s string) string {
+	if s == "" || s[0] < 'a' || s[0] > 'z' {
+		return s
+	}
+	return string(s[0]-'a'+'A') + s[1:]
+}
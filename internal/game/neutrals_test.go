@@ -0,0 +1,79 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNeutralGarrisons(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	c, d := g.Territories["c"], g.Territories["d"]
+	c.Owner = ""
+	d.Owner = ""
+	for i := 0; i < 6; i++ {
+		id := fmt.Sprintf("x%d", i)
+		g.Territories[id] = &Territory{ID: id, Owner: "A"}
+	}
+
+	// One player leaves nothing unclaimed; neutrals leave one in five
+	if g.isTerritorySelectionComplete() {
+		t.Error("selection complete with 2 of 10 unclaimed and neutrals off")
+	}
+	g.Settings.Neutrals = true
+	if !g.isTerritorySelectionComplete() {
+		t.Error("selection not complete with 2 of 10 unclaimed and neutrals on")
+	}
+
+	before := g.CalculateDefenseStrength(c)
+	g.garrisonNeutrals()
+	if c.Garrison != NeutralGarrison || d.Garrison != NeutralGarrison {
+		t.Fatalf("garrisons = %d, %d; want %d", c.Garrison, d.Garrison, NeutralGarrison)
+	}
+	if got := g.CalculateDefenseStrength(c); got != before+NeutralGarrison {
+		t.Errorf("garrisoned defense = %d, want %d", got, before+NeutralGarrison)
+	}
+	if g.Territories["a"].Garrison != 0 {
+		t.Error("a player's territory got a garrison")
+	}
+}
+
+func TestBarbarianRaid(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	g.Settings.Barbarians = true
+	g.Settings.ChanceLevel = ChanceLow
+	player := g.Players["A"]
+	player.StockpileTerritory = "c"
+	c, d := g.Territories["c"], g.Territories["d"]
+	d.Owner = BarbarianID
+	c.HasWeapon = true
+	c.HasCity = true
+
+	// c defends with 1 + city 2 + weapon 3 + b 1 = 7 against 1 + fury 2
+	g.barbarianTurn()
+	if c.Owner != "A" || len(g.WorldEvents) != 0 {
+		t.Fatalf("outmatched barbarians attacked: c owned by %q, events %v", c.Owner, g.WorldEvents)
+	}
+
+	// Now 1 + weapon 3 + city 2 + fury 2 = 8 against 7
+	d.HasWeapon = true
+	d.HasCity = true
+	g.barbarianTurn()
+	if c.Owner != BarbarianID {
+		t.Fatalf("c owned by %q after the raid, want barbarians", c.Owner)
+	}
+	if len(g.WorldEvents) != 1 || g.WorldEvents[0].Type != EventRaid || g.WorldEvents[0].TerritoryID != "c" {
+		t.Errorf("events = %+v, want a raid on c", g.WorldEvents)
+	}
+	if player.StockpileTerritory != "" || player.Stockpile.Coal != 0 {
+		t.Error("the barbarians left the stockpile in c alone")
+	}
+	if player.Eliminated {
+		t.Error("A still holds a and b but was eliminated")
+	}
+	if name := g.OwnerName(c.Owner); name != BarbarianName {
+		t.Errorf("owner name = %q, want %q", name, BarbarianName)
+	}
+	if errs := g.Validate(); len(errs) > 0 {
+		t.Errorf("state with barbarians doesn't validate: %v", errs)
+	}
+}
@@ -0,0 +1,108 @@
+package game
+
+// CombatOdds returns the chance that an attack of the given strength beats
+// the given defense, using the same rules as ResolveCombat.
+//
+// cards is how many defense cards the defender holds in card mode, or 0 in
+// classic mode. The cards themselves are hidden, so each is taken as an
+// independent draw from the defense cards of the default set and the
+// defender is assumed to play them all. Flat bonuses, Double Defense and
+// Bribe are counted, and a Bribe is assumed to be paid for. Shield Wall and
+// Sabotage work on the attacker's cards and units rather than on strength
+// totals, so they are left out, as are effects that depend on the board and
+// Counter-Attack, which doesn't change who wins.
+func CombatOdds(attack, defense int, chance ChanceLevel, cards int) float64 {
+	return CardSets[DefaultCardSet].combatOdds(attack, defense, chance, cards)
+}
+
+// combatOdds is CombatOdds with the defense cards drawn from this set.
+func (s *CardSet) combatOdds(attack, defense int, chance ChanceLevel, cards int) float64 {
+	if cards <= 0 {
+		return winChance(attack, defense, chance)
+	}
+
+	odds := 0.0
+	for hand, p := range s.defenseHands(cards) {
+		if hand.bribe {
+			continue
+		}
+		d := defense
+		if hand.double {
+			d *= 2
+		}
+		d += hand.bonus
+		odds += p * winChance(max(attack, 0), max(d, 0), chance)
+	}
+	return odds
+}
+
+// winChance is the chance that attack beats defense in a single comparison.
+func winChance(attack, defense int, chance ChanceLevel) float64 {
+	switch chance {
+	case ChanceMedium:
+		if attack > defense {
+			return 1
+		}
+		if attack == defense {
+			return 0.5
+		}
+		return 0
+	case ChanceHigh:
+		if attack == 0 && defense == 0 {
+			return 0.5
+		}
+		return float64(attack) / float64(attack+defense)
+	default:
+		if attack >= defense {
+			return 1
+		}
+		return 0
+	}
+}
+
+// defenseHand is what a hand of defense cards does to the defense total.
+type defenseHand struct {
+	bonus  int
+	double bool
+	bribe  bool
+}
+
+// defenseHands returns every distinct hand of n defense cards from the set
+// with the chance of drawing it.
+func (s *CardSet) defenseHands(n int) map[defenseHand]float64 {
+	templates := s.templates(CardTypeDefense, "")
+	totalWeight := 0
+	for _, t := range templates {
+		totalWeight += t.Weight
+	}
+
+	hands := map[defenseHand]float64{{}: 1}
+	for range n {
+		next := make(map[defenseHand]float64)
+		for hand, p := range hands {
+			for _, t := range templates {
+				h := hand
+				if cardEffects[t.Effect].step == stepBonus {
+					h.bonus += t.Value
+				}
+				h.double = h.double || t.Effect == EffectDoubleDefense
+				h.bribe = h.bribe || t.Effect == EffectBribe
+				next[h] += p * float64(t.Weight) / float64(totalWeight)
+			}
+		}
+		hands = next
+	}
+	return hands
+}
+
+// AttackOdds is CombatOdds for an attack on target under this game's
+// settings, counting the defender's hand in card mode.
+func (g *GameState) AttackOdds(attack, defense int, target *Territory) float64 {
+	cards := 0
+	if g.Settings.CombatMode == CombatModeCards {
+		if defender := g.Players[target.Owner]; defender != nil {
+			cards = len(defender.DefenseCards)
+		}
+	}
+	return g.CardSet().combatOdds(attack, defense, g.Settings.ChanceLevel, cards)
+}
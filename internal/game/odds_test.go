@@ -0,0 +1,46 @@
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCombatOddsChanceLevels(t *testing.T) {
+	tests := []struct {
+		attack, defense int
+		chance          ChanceLevel
+		want            float64
+	}{
+		{4, 4, ChanceLow, 1},
+		{3, 4, ChanceLow, 0},
+		{4, 4, ChanceMedium, 0.5},
+		{5, 4, ChanceMedium, 1},
+		{3, 1, ChanceHigh, 0.75},
+		{0, 0, ChanceHigh, 0.5},
+	}
+	for _, tt := range tests {
+		if got := CombatOdds(tt.attack, tt.defense, tt.chance, 0); got != tt.want {
+			t.Errorf("CombatOdds(%d, %d, %d, 0) = %v, want %v", tt.attack, tt.defense, tt.chance, got, tt.want)
+		}
+	}
+}
+
+func TestCombatOddsWithCards(t *testing.T) {
+	// Against one hidden card, attack 3 vs defense 2 under low chance wins
+	// unless the card adds 2 or more, doubles the defense or is a Bribe:
+	// Fortify, Shield Wall, Sabotage and Counter-Attack leave it winning
+	want := float64(250+100+100+25) / 1000
+	if got := CombatOdds(3, 2, ChanceLow, 1); math.Abs(got-want) > 1e-9 {
+		t.Errorf("CombatOdds with one card = %v, want %v", got, want)
+	}
+
+	// More hidden cards never help the attacker
+	prev := CombatOdds(6, 2, ChanceHigh, 0)
+	for cards := 1; cards <= MaxDefenseCards; cards++ {
+		got := CombatOdds(6, 2, ChanceHigh, cards)
+		if got > prev {
+			t.Errorf("odds rose from %v to %v with %d cards", prev, got, cards)
+		}
+		prev = got
+	}
+}
@@ -169,8 +169,8 @@ func (pm *PhaseManager) NextPhase() (Phase, bool) {
 		return s.Phase, false
 
 	case PhaseProduction:
-		// After Production → Trade (if 3+ players) or Shipment
-		if len(s.Players) >= 3 {
+		// After Production → Trade (if 3+ players or a market) or Shipment
+		if s.HasTradePhase() {
 			s.Phase = PhaseTrade
 		} else {
 			s.Phase = PhaseShipment
@@ -196,6 +196,11 @@ func (pm *PhaseManager) NextPhase() (Phase, bool) {
 		return s.Phase, false
 
 	case PhaseConquest:
+		// The barbarians strike once everyone has had their turn
+		if !s.IsGameOver() {
+			s.barbarianTurn()
+		}
+
 		// End of round - check for victory BEFORE advancing
 		// Victory conditions are checked at end of round so all players have equal chances
 		if s.IsGameOver() {
@@ -204,11 +209,13 @@ func (pm *PhaseManager) NextPhase() (Phase, bool) {
 			return s.Phase, false
 		}
 
-		// Game continues - increment round and go to Development
+		// Game continues - settle market prices, increment round and go to Development
+		s.updateMarket()
 		s.Round++
 		log.Printf("NextPhase: Advancing to round %d, Development phase", s.Round)
 		pm.rotatePlayerOrder() // Rotate instead of shuffle for Year 2+
 		pm.resetPlayerTurns()
+		s.drawWorldEvents()
 
 		// Always go to Development first (Year 2+)
 		// Stockpile placement will be handled when Production phase starts
@@ -227,8 +234,8 @@ func (pm *PhaseManager) allTerritoriesClaimed() bool {
 			unclaimed++
 		}
 	}
-	// Leave territories unclaimed if fewer than player count
-	return unclaimed < len(pm.State.Players)
+	// Leave territories unclaimed if fewer than player count, or more with neutrals on
+	return unclaimed <= pm.State.unclaimedQuota()
 }
 
 // shufflePlayerOrder randomizes player order for the new round.
@@ -318,12 +325,14 @@ func (pm *PhaseManager) ProcessProduction() {
 	}
 	log.Printf("ProcessProduction: Found %d territories with resources", resourceCount)
 
-	for _, player := range pm.State.Players {
+	for _, id := range sortedKeys(pm.State.Players) {
+		player := pm.State.Players[id]
 		if player.Eliminated {
 			continue
 		}
 
 		produced := 0
+		yield := NewStockpile()
 		for _, territory := range pm.State.Territories {
 			if territory.Owner != player.ID {
 				continue
@@ -335,27 +344,53 @@ func (pm *PhaseManager) ProcessProduction() {
 			// Grassland produces horses that spread on the map
 			if territory.Resource == ResourceGrassland {
 				pm.spreadHorses(player.ID, territory)
+				if pm.State.HasSpecializationBoost(territory) {
+					pm.spreadHorses(player.ID, territory)
+				}
 				continue
 			}
 
-			// Calculate production amount
-			amount := 1
-			if pm.hasAdjacentCity(territory, player.ID) {
-				amount = 2
-			}
-
+			amount := pm.productionAmount(territory, player.ID)
 			player.Stockpile.Add(territory.Resource, amount)
+			yield.Add(territory.Resource, amount)
 			produced += amount
 			log.Printf("ProcessProduction: Player %s produced %d %s from %s",
 				player.Name, amount, territory.Resource.String(), territory.Name)
 		}
+		pm.State.payTribute(player.ID, yield)
+	}
 
+	// Upkeep and storage caps apply once every vassal has paid tribute, so an
+	// overlord's stockpile is trimmed after it's all in
+	pm.State.SettleEconomies()
+	for _, id := range sortedKeys(pm.State.Players) {
+		player := pm.State.Players[id]
+		if player.Eliminated {
+			continue
+		}
 		log.Printf("ProcessProduction: Player %s total stockpile - Coal:%d Gold:%d Iron:%d Timber:%d",
 			player.Name, player.Stockpile.Coal, player.Stockpile.Gold,
 			player.Stockpile.Iron, player.Stockpile.Timber)
 	}
 }
 
+// productionAmount is how much a territory produces in a round: 1, or 2
+// with a city in or next to it, plus 1 for a matching specialized city, all
+// doubled by a bountiful harvest.
+func (pm *PhaseManager) productionAmount(t *Territory, playerID string) int {
+	amount := 1
+	if pm.hasAdjacentCity(t, playerID) {
+		amount = 2
+	}
+	if pm.State.HasSpecializationBoost(t) {
+		amount++
+	}
+	if pm.State.Harvest {
+		amount *= 2
+	}
+	return amount
+}
+
 // hasAdjacentCity checks if a territory has an adjacent city.
 func (pm *PhaseManager) hasAdjacentCity(t *Territory, playerID string) bool {
 	if t.HasCity {
@@ -419,11 +454,20 @@ func (pm *PhaseManager) CalculateProductionForPlayer(playerID string) ([]Product
 			continue
 		}
 
-		// Grassland produces horses that spread on the map
+		// Grassland produces horses that spread on the map, two of them
+		// next to a stable
 		if territory.Resource == ResourceGrassland {
-			// Calculate where horse will go
-			destID, destName := pm.calculateHorseDestination(playerID, territory)
-			if destID != "" {
+			horses := 1
+			if pm.State.HasSpecializationBoost(territory) {
+				horses = 2
+			}
+			taken := ""
+			for range horses {
+				// Calculate where horse will go
+				destID, destName := pm.calculateHorseDestination(playerID, territory, taken)
+				if destID == "" {
+					break
+				}
 				results = append(results, ProductionResult{
 					TerritoryID:     terrID,
 					TerritoryName:   territory.Name,
@@ -432,16 +476,12 @@ func (pm *PhaseManager) CalculateProductionForPlayer(playerID string) ([]Product
 					DestinationID:   destID,
 					DestinationName: destName,
 				})
+				taken = destID
 			}
 			continue
 		}
 
-		// Calculate production amount
-		amount := 1
-		if pm.hasAdjacentCity(territory, playerID) {
-			amount = 2
-		}
-
+		amount := pm.productionAmount(territory, playerID)
 		results = append(results, ProductionResult{
 			TerritoryID:   terrID,
 			TerritoryName: territory.Name,
@@ -453,10 +493,11 @@ func (pm *PhaseManager) CalculateProductionForPlayer(playerID string) ([]Product
 	return results, player.StockpileTerritory
 }
 
-// calculateHorseDestination determines where a horse will be placed.
-func (pm *PhaseManager) calculateHorseDestination(playerID string, source *Territory) (string, string) {
+// calculateHorseDestination determines where a horse will be placed. taken is
+// a territory already promised another horse this round, or empty.
+func (pm *PhaseManager) calculateHorseDestination(playerID string, source *Territory, taken string) (string, string) {
 	// If source doesn't have a horse yet, horse goes there
-	if !source.HasHorse {
+	if !source.HasHorse && source.ID != taken {
 		// Find the territory ID for this source
 		for id, t := range pm.State.Territories {
 			if t == source {
@@ -470,7 +511,7 @@ func (pm *PhaseManager) calculateHorseDestination(playerID string, source *Terri
 	candidates := []string{}
 	for _, adjID := range source.Adjacent {
 		adj := pm.State.Territories[adjID]
-		if adj.Owner == playerID && !adj.HasHorse {
+		if adj.Owner == playerID && !adj.HasHorse && adjID != taken {
 			candidates = append(candidates, adjID)
 		}
 	}
@@ -51,6 +51,11 @@ type Player struct {
 	IsOnline           bool            `json:"isOnline"`     // Connection status
 	AttackCards        []CombatCard    `json:"attackCards"`   // Combat cards (card mode only)
 	DefenseCards       []CombatCard    `json:"defenseCards"`  // Combat cards (card mode only)
+
+	Overlord    string `json:"overlord,omitempty"`    // Player this one serves as a vassal
+	VassalOffer string `json:"vassalOffer,omitempty"` // Player this one has offered to serve, awaiting an answer
+
+	Depots map[string]*Stockpile `json:"depots,omitempty"` // Territory ID -> resources kept in a depot there
 }
 
 // AIPersonality defines AI behavior type.
@@ -58,8 +58,8 @@ func (g *GameState) isTerritorySelectionComplete() bool {
 	}
 
 	// Leave territories unclaimed if fewer than player count
-	// (following original game rules)
-	return unclaimed < len(g.Players)
+	// (following original game rules), or more with neutrals on
+	return unclaimed <= g.unclaimedQuota()
 }
 
 // startFirstRound transitions from territory selection to first game round.
@@ -74,6 +74,8 @@ func (g *GameState) startFirstRound() {
 	// Randomize player order for the game
 	shufflePlayerOrder(g)
 	g.CurrentPlayerID = g.PlayerOrder[0]
+
+	g.garrisonNeutrals()
 }
 
 // PlaceStockpile places a player's stockpile during the production phase.
@@ -53,8 +53,9 @@ func (g *GameState) MoveStockpile(playerID, destinationID string) error {
 	return nil
 }
 
-// MoveUnit moves a unit from one territory to another.
-func (g *GameState) MoveUnit(playerID, unitType, fromID, toID, waterBodyID string, carryHorse, carryWeapon bool) error {
+// MoveUnit moves a unit from one territory to another. carrySiege is how
+// many siege engines a transport takes along.
+func (g *GameState) MoveUnit(playerID, unitType, fromID, toID, waterBodyID string, carryHorse, carryWeapon bool, carrySiege int) error {
 	// Validate phase
 	if g.Phase != PhaseShipment {
 		return ErrInvalidAction
@@ -92,7 +93,11 @@ func (g *GameState) MoveUnit(playerID, unitType, fromID, toID, waterBodyID strin
 	case "weapon":
 		return g.moveWeapon(player, from, to)
 	case "boat":
-		return g.moveBoat(player, from, to, waterBodyID, carryHorse, carryWeapon)
+		return g.moveBoat(player, from, to, waterBodyID, false, carryHorse, carryWeapon, carrySiege)
+	case "transport":
+		return g.moveBoat(player, from, to, waterBodyID, true, carryHorse, carryWeapon, carrySiege)
+	case "siege":
+		return g.moveSiege(from, to)
 	default:
 		return ErrInvalidTarget
 	}
@@ -153,66 +158,83 @@ func (g *GameState) moveWeapon(player *Player, from, to *Territory) error {
 }
 
 // moveBoat moves a boat via water to another coastal territory.
-// The boat stays in the same water body it was in.
-func (g *GameState) moveBoat(player *Player, from, to *Territory, waterBodyID string, carryHorse, carryWeapon bool) error {
+// The boat stays in the same water body it was in, unless it passes through
+// a strait into another one. With transport set it moves a transport, which
+// has room for siege engines as well as a horse and a weapon; a plain boat
+// move sends a transport only when no plain boat is there.
+func (g *GameState) moveBoat(player *Player, from, to *Territory, waterBodyID string, transport, carryHorse, carryWeapon bool, carrySiege int) error {
 	if from.TotalBoats() == 0 {
 		return ErrInvalidTarget
 	}
+	if transport && (!g.Settings.ExtendedUnits || from.TotalTransports() == 0) {
+		return ErrInvalidTarget
+	}
 
 	// Both territories must be coastal
 	if !from.IsCoastal() || !to.IsCoastal() {
 		return ErrCannotReach
 	}
 
-	// If waterBodyID specified, use that; otherwise find a shared one
-	sharedWaterBody := waterBodyID
-	if sharedWaterBody == "" {
-		// Find a shared water body that has a boat
+	// If waterBodyID specified, use that; otherwise find one with a boat
+	// that can reach the destination
+	sourceWater, destWater := waterBodyID, ""
+	if sourceWater == "" {
 		for wID, count := range from.Boats {
+			if transport {
+				count = from.TransportsInWater(wID)
+			}
 			if count > 0 {
-				// Check if destination also borders this water body
-				for _, destWater := range to.WaterBodies {
-					if destWater == wID {
-						sharedWaterBody = wID
-						break
-					}
+				if landing := g.landingWater(player.ID, wID, to); landing != "" {
+					sourceWater, destWater = wID, landing
+					break
 				}
 			}
-			if sharedWaterBody != "" {
-				break
-			}
 		}
+	} else {
+		destWater = g.landingWater(player.ID, sourceWater, to)
 	}
 
-	if sharedWaterBody == "" {
+	if sourceWater == "" || destWater == "" {
 		return ErrCannotReach
 	}
 
 	// Verify the boat exists in the specified water body
-	if from.BoatsInWater(sharedWaterBody) == 0 {
+	if from.BoatsInWater(sourceWater) == 0 {
 		return ErrInvalidTarget
 	}
-
-	// Verify destination borders this water body
-	destBordersWater := false
-	for _, wb := range to.WaterBodies {
-		if wb == sharedWaterBody {
-			destBordersWater = true
-			break
-		}
+	if g.Blockaded(from.ID, sourceWater) {
+		return ErrBlockaded
 	}
-	if !destBordersWater {
-		return ErrCannotReach
+	if transport && from.TransportsInWater(sourceWater) == 0 {
+		return ErrInvalidTarget
 	}
+	transport = transport || !from.hasPlainBoat(sourceWater)
 
 	// Destination must have room for another boat
 	if !to.CanAddBoat() {
 		return ErrTerritoryOccupied
 	}
 
-	// Move boat (stays in same water body)
-	from.RemoveBoat(sharedWaterBody)
-	to.AddBoat(sharedWaterBody)
+	// The cargo must fit: siege engines only go by transport
+	capacity, cargo := BoatCapacity, carrySiege
+	if transport {
+		capacity = TransportCapacity
+	}
+	if carryHorse && from.HasHorse {
+		cargo++
+	}
+	if carryWeapon && from.HasWeapon {
+		cargo++
+	}
+	if carrySiege < 0 || carrySiege > from.SiegeEngines || (carrySiege > 0 && !transport) {
+		return ErrInvalidTarget
+	}
+	if cargo > capacity {
+		return ErrTooMuchCargo
+	}
+
+	// Move boat
+	transferBoat(from, to, sourceWater, destWater, transport)
 
 	// Optionally carry horse (lost if destination already has one)
 	if carryHorse && from.HasHorse {
@@ -232,6 +254,12 @@ func (g *GameState) moveBoat(player *Player, from, to *Territory, waterBodyID st
 		// If destination already has weapon, the carried one is lost
 	}
 
+	// Siege engines that don't fit in the destination are lost
+	if carrySiege > 0 {
+		from.SiegeEngines -= carrySiege
+		to.SiegeEngines = min(to.SiegeEngines+carrySiege, MaxSiegeEngines)
+	}
+
 	g.advanceShipmentTurn()
 	return nil
 }
@@ -326,18 +354,24 @@ func (g *GameState) canReachTerritory(playerID, fromID, toID string) bool {
 			}
 		}
 
-		// Check territories via shared water bodies (if we have boats in that water body)
+		// Check territories via shared water bodies (if we have boats in that
+		// water body), including water bodies our boats reach through straits
 		if territory.IsCoastal() {
 			for _, waterID := range territory.WaterBodies {
 				// Only use water routes if we have a boat in this water body
 				if territory.BoatsInWater(waterID) > 0 {
-					water := g.WaterBodies[waterID]
-					for _, coastalID := range water.Territories {
-						if !visited[coastalID] {
-							coastal := g.Territories[coastalID]
-							if coastal.Owner == playerID {
-								visited[coastalID] = true
-								queue = append(queue, coastalID)
+					for reachable := range g.NavigableWaters(playerID, waterID) {
+						water := g.WaterBodies[reachable]
+						if water == nil {
+							continue
+						}
+						for _, coastalID := range water.Territories {
+							if !visited[coastalID] {
+								coastal := g.Territories[coastalID]
+								if coastal.Owner == playerID {
+									visited[coastalID] = true
+									queue = append(queue, coastalID)
+								}
 							}
 						}
 					}
@@ -358,10 +392,15 @@ func (g *GameState) canHorseReach(playerID, fromID, toID string) bool {
 		return true
 	}
 
+	// Rough ground stops a horse after one move
+	if g.TerrainOf(from).SlowsHorses() {
+		return false
+	}
+
 	// 2 moves through owned territory
 	for _, midID := range from.Adjacent {
 		mid := g.Territories[midID]
-		if mid.Owner == playerID {
+		if mid.Owner == playerID && !g.TerrainOf(mid).SlowsHorses() {
 			if isAdjacent(mid, toID) {
 				return true
 			}
@@ -444,6 +483,24 @@ func (g *GameState) GetMovableUnits(playerID string) []map[string]interface{} {
 				"can_carry":   t.HasWeapon || t.HasHorse,
 			})
 		}
+
+		if g.Settings.ExtendedUnits && t.SiegeEngines > 0 {
+			units = append(units, map[string]interface{}{
+				"type":      "siege",
+				"territory": id,
+				"count":     t.SiegeEngines,
+			})
+		}
+
+		if g.Settings.ExtendedUnits && t.TotalTransports() > 0 {
+			units = append(units, map[string]interface{}{
+				"type":       "transport",
+				"territory":  id,
+				"count":      t.TotalTransports(),
+				"transports": t.Transports, // Map of water body -> count
+				"can_carry":  t.HasWeapon || t.HasHorse || t.SiegeEngines > 0,
+			})
+		}
 	}
 
 	return units
@@ -9,6 +9,7 @@ import (
 
 // GameState represents the complete state of a game.
 type GameState struct {
+	Version                   int                   `json:"version"` // Schema version, see StateVersion
 	ID                        string                `json:"id"`
 	Settings                  Settings              `json:"settings"`
 	Round                     int                   `json:"round"`
@@ -20,7 +21,13 @@ type GameState struct {
 	WaterBodies               map[string]*WaterBody `json:"waterBodies"`
 	SkippedPhases             []PhaseSkipInfo       `json:"skippedPhases,omitempty"`             // Phases skipped in last transition
 	ProductionPending         bool                  `json:"productionPending,omitempty"`         // True when production animation should play
+	ProductionApplied         bool                  `json:"productionApplied,omitempty"`         // True once production is in the stockpiles, until CompleteProduction
 	StockpilePlacementPending bool                  `json:"stockpilePlacementPending,omitempty"` // True when players need to place stockpiles
+	Market                    *Market               `json:"market,omitempty"`                    // The bank's prices and history, with the market on
+	WorldEvents               []WorldEvent          `json:"worldEvents,omitempty"`               // Events drawn this round, until announced
+	Harvest                   bool                  `json:"harvest,omitempty"`                   // A bountiful harvest doubles production this round
+	StormWater                string                `json:"stormWater,omitempty"`                // Water body whose boats can't sail this round
+	PendingCardBattle         *PendingCardBattle    `json:"pendingCardBattle,omitempty"`         // Card battle waiting for the defender's cards
 }
 
 // Settings contains the configurable game parameters.
@@ -30,6 +37,18 @@ type Settings struct {
 	MapID         string      `json:"mapId"`
 	MaxPlayers    int         `json:"maxPlayers"`
 	CombatMode    CombatMode  `json:"combatMode"`
+	CardSet       string      `json:"cardSet,omitempty"`       // Card set drawn from in card mode; empty is DefaultCardSet
+	Terrain       bool        `json:"terrain,omitempty"`       // Terrain affects defense, horses and building
+	Straits       bool        `json:"straits,omitempty"`       // Boats can pass through held straits
+	PresetStarts  bool        `json:"presetStarts,omitempty"`  // Players start with the map's starting territories instead of drafting
+	ExtendedUnits bool        `json:"extendedUnits,omitempty"` // Fortresses, siege engines and transports can be built
+	Economy       bool        `json:"economy,omitempty"`       // Unit upkeep, storage caps and city specializations
+	Market        bool        `json:"market,omitempty"`        // A bank with moving prices trades during the Trade phase
+	WorldEvents   bool        `json:"worldEvents,omitempty"`   // Plagues, harvests, storms and rebellions strike at round start
+	Neutrals      bool        `json:"neutrals,omitempty"`      // Some territories start neutral with a fixed garrison
+	Barbarians    bool        `json:"barbarians,omitempty"`    // Some neutral territories form a barbarian horde that raids each round
+	Depots        bool        `json:"depots,omitempty"`        // Players can build depots and raid stockpiles
+	NavalCombat   bool        `json:"navalCombat,omitempty"`   // Fleets in a shared water body fight and blockade each other
 }
 
 // ChanceLevel determines randomness in combat.
@@ -101,11 +120,12 @@ func (g *GameState) IsGameOver() bool {
 }
 
 // IsEliminationVictory checks if only one player remains (elimination victory).
+// Vassals don't count: they serve whoever remains.
 // This should be checked immediately after combat.
 func (g *GameState) IsEliminationVictory() bool {
 	activePlayers := 0
 	for _, p := range g.Players {
-		if !p.Eliminated {
+		if !p.Eliminated && !p.IsVassal() {
 			activePlayers++
 		}
 	}
@@ -160,7 +180,7 @@ func (g *GameState) GetWinner() *Player {
 	// Check for last player standing (elimination victory)
 	var lastPlayer *Player
 	for _, p := range g.Players {
-		if !p.Eliminated {
+		if !p.Eliminated && !p.IsVassal() {
 			if lastPlayer != nil {
 				lastPlayer = nil // More than one player, not this victory type
 				break
@@ -209,20 +229,28 @@ func (g *GameState) Surrender(surrenderPlayerID, targetPlayerID string) int {
 		}
 	}
 
-	// Transfer stockpile resources
+	// Transfer stockpile resources, along with anything kept in depots
 	if surrenderPlayer.Stockpile != nil && targetPlayer.Stockpile != nil {
-		targetPlayer.Stockpile.Coal += surrenderPlayer.Stockpile.Coal
-		targetPlayer.Stockpile.Gold += surrenderPlayer.Stockpile.Gold
-		targetPlayer.Stockpile.Iron += surrenderPlayer.Stockpile.Iron
-		targetPlayer.Stockpile.Timber += surrenderPlayer.Stockpile.Timber
+		stores := []*Stockpile{surrenderPlayer.Stockpile}
+		for _, depot := range surrenderPlayer.Depots {
+			stores = append(stores, depot)
+		}
+		for _, store := range stores {
+			targetPlayer.Stockpile.Coal += store.Coal
+			targetPlayer.Stockpile.Gold += store.Gold
+			targetPlayer.Stockpile.Iron += store.Iron
+			targetPlayer.Stockpile.Timber += store.Timber
+		}
 	}
 
 	// Clear surrendered player's stockpile
 	surrenderPlayer.Stockpile = NewStockpile()
 	surrenderPlayer.StockpileTerritory = ""
+	surrenderPlayer.Depots = nil
 
 	// Mark player as eliminated
 	surrenderPlayer.Eliminated = true
+	g.releaseVassals(surrenderPlayerID)
 
 	return territoriesTransferred
 }
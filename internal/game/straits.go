@@ -0,0 +1,65 @@
+package game
+
+// A strait is a territory that borders more than one water body. With
+// Settings.Straits on, a player's boats can pass through any strait the
+// player holds, from one of its water bodies into another; without it, boats
+// stay in the water body they were built in.
+
+// IsStrait reports whether a territory links two or more water bodies.
+func (t *Territory) IsStrait() bool {
+	return len(t.WaterBodies) > 1
+}
+
+// NavigableWaters returns the water bodies a player's boat in waterID can
+// sail to: waterID itself plus, with straits on, every water body joined to
+// it through straits the player holds. A boat in stormy water can't sail
+// anywhere, and boats can't pass through stormy water.
+func (g *GameState) NavigableWaters(playerID, waterID string) map[string]bool {
+	if g.IsStormy(waterID) {
+		return map[string]bool{}
+	}
+	reached := map[string]bool{waterID: true}
+	if !g.Settings.Straits {
+		return reached
+	}
+
+	queue := []string{waterID}
+	for len(queue) > 0 {
+		water := g.WaterBodies[queue[0]]
+		queue = queue[1:]
+		if water == nil {
+			continue
+		}
+		for _, tid := range water.Territories {
+			t := g.Territories[tid]
+			if t == nil || t.Owner != playerID || !t.IsStrait() {
+				continue
+			}
+			for _, next := range t.WaterBodies {
+				if !reached[next] && !g.IsStormy(next) {
+					reached[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+	return reached
+}
+
+// landingWater returns the water body a player's boat from waterID ends up
+// in when it sails to territory t: waterID if t borders it, otherwise the
+// first water body of t the boat can reach through straits. It returns ""
+// if the boat can't reach t.
+func (g *GameState) landingWater(playerID, waterID string, t *Territory) string {
+	waters := g.NavigableWaters(playerID, waterID)
+	landing := ""
+	for _, w := range t.WaterBodies {
+		if w == waterID && waters[w] {
+			return w
+		}
+		if landing == "" && waters[w] {
+			landing = w
+		}
+	}
+	return landing
+}
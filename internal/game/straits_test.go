@@ -0,0 +1,52 @@
+package game
+
+import "testing"
+
+// straitTestState builds two seas joined by strait s: a and s border the
+// west sea, s and b the east sea. A holds a, s and b, and has a boat at a.
+func straitTestState(straits bool) *GameState {
+	g := &GameState{
+		Settings: Settings{Straits: straits},
+		Phase:    PhaseShipment,
+		Players:  map[string]*Player{"A": {ID: "A"}, "B": {ID: "B"}},
+		Territories: map[string]*Territory{
+			"a": {ID: "a", Owner: "A", CoastalTiles: 2, WaterBodies: []string{"west"}, Boats: map[string]int{"west": 1}},
+			"s": {ID: "s", Owner: "A", CoastalTiles: 2, WaterBodies: []string{"west", "east"}},
+			"b": {ID: "b", Owner: "A", CoastalTiles: 2, WaterBodies: []string{"east"}},
+			"e": {ID: "e", Owner: "B", CoastalTiles: 2, WaterBodies: []string{"east"}},
+		},
+		WaterBodies: map[string]*WaterBody{
+			"west": {ID: "west", Territories: []string{"a", "s"}},
+			"east": {ID: "east", Territories: []string{"s", "b", "e"}},
+		},
+	}
+	return g
+}
+
+func TestBoatsPassThroughStraits(t *testing.T) {
+	classic := straitTestState(false)
+	if err := classic.moveBoat(classic.Players["A"], classic.Territories["a"], classic.Territories["b"], "west", false, false, false, 0); err != ErrCannotReach {
+		t.Errorf("without straits: err = %v, want ErrCannotReach", err)
+	}
+	if classic.canBoatReachTargetViaWater("A", "a", "e", "west") {
+		t.Error("without straits a boat in the west sea should not reach e")
+	}
+
+	g := straitTestState(true)
+	if !g.canBoatReachTargetViaWater("A", "a", "e", "west") {
+		t.Error("boat should reach e through the strait")
+	}
+	if err := g.moveBoat(g.Players["A"], g.Territories["a"], g.Territories["b"], "west", false, false, false, 0); err != nil {
+		t.Fatalf("move through strait: %v", err)
+	}
+	if g.Territories["b"].BoatsInWater("east") != 1 {
+		t.Errorf("boat at b = %v, want one in the east sea", g.Territories["b"].Boats)
+	}
+
+	// Losing the strait closes the passage
+	g = straitTestState(true)
+	g.Territories["s"].Owner = "B"
+	if g.canBoatReachTargetViaWater("A", "a", "e", "west") {
+		t.Error("boat should not pass a strait held by another player")
+	}
+}
@@ -0,0 +1,86 @@
+package game
+
+// Terrain is the ground a territory covers. Maps may give every territory a
+// terrain, but it only affects play when Settings.Terrain is on, so classic
+// games behave exactly as before.
+type Terrain string
+
+const (
+	TerrainNone      Terrain = ""
+	TerrainPlains    Terrain = "plains"
+	TerrainForest    Terrain = "forest"
+	TerrainMountains Terrain = "mountains"
+	TerrainMarsh     Terrain = "marsh"
+)
+
+// Terrains lists every terrain a territory can have, in display order.
+var Terrains = []Terrain{TerrainPlains, TerrainForest, TerrainMountains, TerrainMarsh}
+
+// ParseTerrain converts a terrain name to a Terrain. Empty and "none" give
+// TerrainNone; ok is false for names it doesn't know.
+func ParseTerrain(s string) (Terrain, bool) {
+	switch Terrain(s) {
+	case TerrainNone, "none":
+		return TerrainNone, true
+	case TerrainPlains, TerrainForest, TerrainMountains, TerrainMarsh:
+		return Terrain(s), true
+	default:
+		return TerrainNone, false
+	}
+}
+
+// String returns the terrain name for display.
+func (t Terrain) String() string {
+	switch t {
+	case TerrainPlains:
+		return "Plains"
+	case TerrainForest:
+		return "Forest"
+	case TerrainMountains:
+		return "Mountains"
+	case TerrainMarsh:
+		return "Marsh"
+	default:
+		return "None"
+	}
+}
+
+// DefenseBonus is the extra strength a territory gets when defending.
+func (t Terrain) DefenseBonus() int {
+	switch t {
+	case TerrainMountains:
+		return 2
+	case TerrainForest:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// SlowsHorses reports whether horses lose their second move in this terrain:
+// a horse can move into it, but not ride through it or out of it in one turn.
+func (t Terrain) SlowsHorses() bool {
+	return t == TerrainMountains || t == TerrainMarsh
+}
+
+// AllowsBuild reports whether something can be built on this terrain.
+// Marshes can't hold a city and mountain coasts have nowhere to launch boats.
+func (t Terrain) AllowsBuild(buildType BuildType) bool {
+	switch t {
+	case TerrainMarsh:
+		return buildType != BuildCity
+	case TerrainMountains:
+		return buildType != BuildBoat && buildType != BuildTransport
+	default:
+		return true
+	}
+}
+
+// TerrainOf returns the terrain that applies to a territory: its own terrain
+// with terrain rules on, TerrainNone without.
+func (g *GameState) TerrainOf(t *Territory) Terrain {
+	if !g.Settings.Terrain || t == nil {
+		return TerrainNone
+	}
+	return t.Terrain
+}
@@ -0,0 +1,70 @@
+package game
+
+import "testing"
+
+// terrainTestState builds a row of territories a-b-c-d, all owned by A except
+// d, with the given terrain on b.
+func terrainTestState(rules bool, middle Terrain) *GameState {
+	g := &GameState{
+		Settings:    Settings{Terrain: rules},
+		Phase:       PhaseDevelopment,
+		Players:     map[string]*Player{"A": {ID: "A", Stockpile: &Stockpile{Coal: 5, Gold: 5, Iron: 5, Timber: 5}}},
+		Territories: make(map[string]*Territory),
+	}
+	ids := []string{"a", "b", "c", "d"}
+	for i, id := range ids {
+		t := &Territory{ID: id, Owner: "A"}
+		if i > 0 {
+			t.Adjacent = append(t.Adjacent, ids[i-1])
+		}
+		if i < len(ids)-1 {
+			t.Adjacent = append(t.Adjacent, ids[i+1])
+		}
+		g.Territories[id] = t
+	}
+	g.Territories["b"].Terrain = middle
+	g.Territories["d"].Owner = "B"
+	return g
+}
+
+func TestTerrainDefenseBonus(t *testing.T) {
+	classic := terrainTestState(false, TerrainMountains)
+	rules := terrainTestState(true, TerrainMountains)
+	got := rules.CalculateDefenseStrength(rules.Territories["b"])
+	if want := classic.CalculateDefenseStrength(classic.Territories["b"]) + 2; got != want {
+		t.Errorf("mountain defense = %d, want %d", got, want)
+	}
+}
+
+func TestTerrainSlowsHorses(t *testing.T) {
+	for _, tc := range []struct {
+		rules   bool
+		terrain Terrain
+		want    bool
+	}{
+		{false, TerrainMarsh, true},
+		{true, TerrainPlains, true},
+		{true, TerrainMarsh, false},
+		{true, TerrainMountains, false},
+	} {
+		g := terrainTestState(tc.rules, tc.terrain)
+		if got := g.canHorseReach("A", "a", "c"); got != tc.want {
+			t.Errorf("rules=%v %s: horse through b = %v, want %v", tc.rules, tc.terrain, got, tc.want)
+		}
+	}
+}
+
+func TestTerrainBuildRestrictions(t *testing.T) {
+	g := terrainTestState(true, TerrainMarsh)
+	if err := g.CanBuild("A", BuildCity, "b", false); err != ErrTerrainForbids {
+		t.Errorf("city in marsh: err = %v, want ErrTerrainForbids", err)
+	}
+	if err := g.CanBuild("A", BuildWeapon, "b", false); err != nil {
+		t.Errorf("weapon in marsh: err = %v", err)
+	}
+
+	g.Settings.Terrain = false
+	if err := g.CanBuild("A", BuildCity, "b", false); err != nil {
+		t.Errorf("city in marsh with classic rules: err = %v", err)
+	}
+}
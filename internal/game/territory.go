@@ -9,23 +9,30 @@ const MaxDrawingColorIndex = 10
 
 // Territory represents a single territory on the map.
 type Territory struct {
-	ID           string            `json:"id"`
-	Name         string            `json:"name"`
-	Owner        string            `json:"owner"` // Player ID, empty if unclaimed
-	Resource     ResourceType      `json:"resource"`
-	HasCity      bool              `json:"hasCity"`
-	HasWeapon    bool              `json:"hasWeapon"`
-	HasHorse     bool              `json:"hasHorse"`
-	Boats        map[string]int    `json:"boats"`       // Water body ID -> boat count
-	Adjacent     []string          `json:"adjacent"`    // IDs of adjacent territories
-	CoastalTiles int               `json:"coastalTiles"` // Number of coastal tiles (limits boats)
-	WaterBodies  []string          `json:"waterBodies"`  // IDs of connected water bodies
-	Drawing      map[string]int    `json:"drawing,omitempty"` // "x,y" -> colorIndex (1-10), drawing pixel coords
+	ID             string         `json:"id"`
+	Name           string         `json:"name"`
+	Owner          string         `json:"owner"` // Player ID, empty if unclaimed
+	Resource       ResourceType   `json:"resource"`
+	Terrain        Terrain        `json:"terrain,omitempty"`
+	HasCity        bool           `json:"hasCity"`
+	Specialization BuildType      `json:"specialization,omitempty"` // What the city specializes in (economy rules)
+	HasWeapon      bool           `json:"hasWeapon"`
+	HasHorse       bool           `json:"hasHorse"`
+	Boats          map[string]int `json:"boats"`                // Water body ID -> boat count
+	Transports     map[string]int `json:"transports,omitempty"` // Water body ID -> how many of the boats are transports
+	Fortresses     int            `json:"fortresses,omitempty"`
+	SiegeEngines   int            `json:"siegeEngines,omitempty"`
+	Garrison       int            `json:"garrison,omitempty"` // Fixed defense of a neutral territory
+	Adjacent       []string       `json:"adjacent"`           // IDs of adjacent territories
+	CoastalTiles   int            `json:"coastalTiles"`       // Number of coastal tiles (limits boats)
+	WaterBodies    []string       `json:"waterBodies"`        // IDs of connected water bodies
+	Drawing        map[string]int `json:"drawing,omitempty"`  // "x,y" -> colorIndex (1-10), drawing pixel coords
 }
 
 // WaterBody represents a connected body of water.
 type WaterBody struct {
 	ID          string   `json:"id"`
+	Name        string   `json:"name,omitempty"`
 	Territories []string `json:"territories"` // IDs of territories bordering this water
 }
 
@@ -90,6 +97,10 @@ func (t *Territory) RemoveBoat(waterBodyID string) bool {
 	if t.Boats[waterBodyID] == 0 {
 		delete(t.Boats, waterBodyID)
 	}
+	// Plain boats go first; a transport is only lost when none are left
+	if t.TransportsInWater(waterBodyID) > t.Boats[waterBodyID] {
+		t.dropTransport(waterBodyID)
+	}
 	return true
 }
 
@@ -113,4 +124,3 @@ func (t *Territory) BaseStrength() int {
 	strength += t.TotalBoats() * 2
 	return strength
 }
-
@@ -0,0 +1,141 @@
+package game
+
+// The extended unit roster adds fortresses, siege engines and transports.
+// They can only be built with Settings.ExtendedUnits on, so classic games
+// behave exactly as before.
+const (
+	BuildFortress  BuildType = "fortress"
+	BuildSiege     BuildType = "siege"
+	BuildTransport BuildType = "transport"
+)
+
+const (
+	// MaxFortresses is how many fortresses a territory can hold.
+	MaxFortresses = 2
+
+	// MaxSiegeEngines is how many siege engines can stack in a territory.
+	MaxSiegeEngines = 3
+
+	// FortressDefense is the strength each fortress adds to the territory it
+	// stands in when that territory is attacked.
+	FortressDefense = 2
+
+	// BoatCapacity is how many land units a boat carries: a horse and a
+	// weapon. TransportCapacity is the same for a transport, which can also
+	// carry siege engines.
+	BoatCapacity      = 2
+	TransportCapacity = 4
+)
+
+// IsExtended reports whether the build type belongs to the extended roster.
+func (b BuildType) IsExtended() bool {
+	return b == BuildFortress || b == BuildSiege || b == BuildTransport
+}
+
+// TransportsInWater returns how many of the boats in a water body are
+// transports.
+func (t *Territory) TransportsInWater(waterBodyID string) int {
+	if t.Transports == nil {
+		return 0
+	}
+	return t.Transports[waterBodyID]
+}
+
+// TotalTransports returns the number of transports at this territory.
+func (t *Territory) TotalTransports() int {
+	total := 0
+	for _, count := range t.Transports {
+		total += count
+	}
+	return total
+}
+
+// AddTransport adds a transport to a specific water body. A transport is
+// also a boat, so it takes up a boat's place on the coast.
+func (t *Territory) AddTransport(waterBodyID string) {
+	t.AddBoat(waterBodyID)
+	if t.Transports == nil {
+		t.Transports = make(map[string]int)
+	}
+	t.Transports[waterBodyID]++
+}
+
+// RemoveTransport removes a transport from a specific water body.
+func (t *Territory) RemoveTransport(waterBodyID string) bool {
+	if t.TransportsInWater(waterBodyID) == 0 {
+		return false
+	}
+	t.dropTransport(waterBodyID)
+	return t.RemoveBoat(waterBodyID)
+}
+
+// dropTransport takes one transport off a water body's count without
+// removing its boat, for when the boat has already gone.
+func (t *Territory) dropTransport(waterBodyID string) {
+	t.Transports[waterBodyID]--
+	if t.Transports[waterBodyID] == 0 {
+		delete(t.Transports, waterBodyID)
+	}
+}
+
+// hasPlainBoat reports whether a water body holds a boat that isn't a
+// transport.
+func (t *Territory) hasPlainBoat(waterBodyID string) bool {
+	return t.BoatsInWater(waterBodyID) > t.TransportsInWater(waterBodyID)
+}
+
+// transferBoat moves a boat from one territory's water to another's, keeping
+// it a transport if it was one.
+func transferBoat(from, to *Territory, fromWater, toWater string, transport bool) {
+	if transport {
+		from.RemoveTransport(fromWater)
+		to.AddTransport(toWater)
+	} else {
+		from.RemoveBoat(fromWater)
+		to.AddBoat(toWater)
+	}
+}
+
+// FortressBonus is the strength a territory's fortresses add to its
+// defense.
+func (g *GameState) FortressBonus(target *Territory) int {
+	if !g.Settings.ExtendedUnits {
+		return 0
+	}
+	return target.Fortresses * FortressDefense
+}
+
+// SiegeBonus is the strength an attacker's siege engines win back from the
+// target's fortresses. Each siege engine next to the target cancels one
+// fortress; spare siege engines add nothing.
+func (g *GameState) SiegeBonus(attackerID string, target *Territory) int {
+	if !g.Settings.ExtendedUnits || target.Fortresses == 0 {
+		return 0
+	}
+	engines := 0
+	for _, adjID := range target.Adjacent {
+		if adj := g.Territories[adjID]; adj.Owner == attackerID {
+			engines += adj.SiegeEngines
+		}
+	}
+	return min(engines, target.Fortresses) * FortressDefense
+}
+
+// moveSiege moves a siege engine to an adjacent territory.
+func (g *GameState) moveSiege(from, to *Territory) error {
+	if !g.Settings.ExtendedUnits || from.SiegeEngines == 0 {
+		return ErrInvalidTarget
+	}
+	if !isAdjacent(from, to.ID) {
+		return ErrCannotReach
+	}
+	if to.SiegeEngines >= MaxSiegeEngines {
+		return ErrStackFull
+	}
+
+	from.SiegeEngines--
+	to.SiegeEngines++
+
+	g.advanceShipmentTurn()
+	return nil
+}
@@ -0,0 +1,80 @@
+package game
+
+import "testing"
+
+func TestFortressesAndSiege(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	d := g.Territories["d"]
+	d.Fortresses = 2
+	classic := g.CalculateDefenseStrength(d)
+	if err := g.CanBuild("A", BuildFortress, "a", false); err != ErrInvalidAction {
+		t.Errorf("fortress with classic units: err = %v, want ErrInvalidAction", err)
+	}
+
+	g.Settings.ExtendedUnits = true
+	if got, want := g.CalculateDefenseStrength(d), classic+2*FortressDefense; got != want {
+		t.Errorf("defense behind two fortresses = %d, want %d", got, want)
+	}
+
+	// One siege engine next to d cancels one fortress; a second engine
+	// further away doesn't help
+	base := g.CalculateAttackStrength("A", d, nil)
+	g.Territories["c"].SiegeEngines = 1
+	g.Territories["b"].SiegeEngines = 1
+	if got, want := g.CalculateAttackStrength("A", d, nil), base+FortressDefense; got != want {
+		t.Errorf("attack with one siege engine = %d, want %d", got, want)
+	}
+
+	if err := g.CanBuild("A", BuildSiege, "c", false); err != nil {
+		t.Errorf("siege engine: err = %v", err)
+	}
+	g.Territories["c"].SiegeEngines = MaxSiegeEngines
+	if err := g.CanBuild("A", BuildSiege, "c", false); err != ErrStackFull {
+		t.Errorf("siege engine on a full stack: err = %v, want ErrStackFull", err)
+	}
+}
+
+func TestTransportCarriesSiege(t *testing.T) {
+	g := straitTestState(false)
+	g.Settings.ExtendedUnits = true
+	a, s := g.Territories["a"], g.Territories["s"]
+	a.Boats = map[string]int{"west": 2}
+	a.Transports = map[string]int{"west": 1}
+	a.HasHorse = true
+	a.SiegeEngines = 2
+
+	if err := g.moveBoat(g.Players["A"], a, s, "west", false, false, false, 1); err != ErrInvalidTarget {
+		t.Errorf("siege engine on a plain boat: err = %v, want ErrInvalidTarget", err)
+	}
+	a.HasWeapon = true
+	a.SiegeEngines = 3
+	if err := g.moveBoat(g.Players["A"], a, s, "west", true, true, true, 3); err != ErrTooMuchCargo {
+		t.Errorf("five units on a transport: err = %v, want ErrTooMuchCargo", err)
+	}
+	a.HasWeapon = false
+	a.SiegeEngines = 2
+
+	if err := g.moveBoat(g.Players["A"], a, s, "west", true, true, false, 2); err != nil {
+		t.Fatalf("transport move: %v", err)
+	}
+	if s.TransportsInWater("west") != 1 || !s.HasHorse || s.SiegeEngines != 2 {
+		t.Errorf("s = %d transports, horse %v, %d siege engines; want 1, true, 2", s.TransportsInWater("west"), s.HasHorse, s.SiegeEngines)
+	}
+	if a.BoatsInWater("west") != 1 || a.TotalTransports() != 0 || a.SiegeEngines != 0 {
+		t.Errorf("a = %v boats, %v transports, %d siege engines; want one plain boat left", a.Boats, a.Transports, a.SiegeEngines)
+	}
+}
+
+func TestRemoveBoatKeepsTransports(t *testing.T) {
+	terr := &Territory{CoastalTiles: 3}
+	terr.AddBoat("sea")
+	terr.AddTransport("sea")
+	terr.RemoveBoat("sea")
+	if terr.BoatsInWater("sea") != 1 || terr.TransportsInWater("sea") != 1 {
+		t.Errorf("after losing a boat: %v boats, %v transports; want the transport left", terr.Boats, terr.Transports)
+	}
+	terr.RemoveBoat("sea")
+	if terr.TotalBoats() != 0 || terr.TotalTransports() != 0 {
+		t.Errorf("after losing both: %v boats, %v transports; want none", terr.Boats, terr.Transports)
+	}
+}
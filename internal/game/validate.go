@@ -0,0 +1,153 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Validate checks the internal consistency of a game state: that every ID it
+// refers to exists and that the per-player bookkeeping agrees with the board.
+// It returns one error per problem found, or nil if the state is sound.
+func (g *GameState) Validate() []error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if g.Phase < PhaseTerritorySelection || g.Phase > PhaseConquest {
+		fail("invalid phase %d", g.Phase)
+	}
+
+	for _, id := range sortedKeys(g.Players) {
+		p := g.Players[id]
+		if p == nil {
+			fail("player %s is nil", id)
+			continue
+		}
+		if p.ID != id {
+			fail("player %s is stored under key %s", p.ID, id)
+		}
+		if p.Stockpile == nil {
+			fail("player %s has no stockpile", id)
+		}
+		if p.StockpileTerritory != "" && !p.Eliminated {
+			t, ok := g.Territories[p.StockpileTerritory]
+			if !ok {
+				fail("player %s has stockpile in unknown territory %s", id, p.StockpileTerritory)
+			} else if t != nil && t.Owner != id {
+				fail("player %s has stockpile in %s, owned by %q", id, t.ID, t.Owner)
+			}
+		}
+		for _, tid := range sortedKeys(p.Depots) {
+			if t, ok := g.Territories[tid]; !ok {
+				fail("player %s has depot in unknown territory %s", id, tid)
+			} else if t != nil && t.Owner != id {
+				fail("player %s has depot in %s, owned by %q", id, tid, t.Owner)
+			} else if p.Depots[tid] == nil {
+				fail("player %s has an empty depot entry for %s", id, tid)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, id := range g.PlayerOrder {
+		if _, ok := g.Players[id]; !ok {
+			fail("player order contains unknown player %s", id)
+		}
+		if seen[id] {
+			fail("player order contains %s twice", id)
+		}
+		seen[id] = true
+	}
+	if g.CurrentPlayerID != "" {
+		if _, ok := g.Players[g.CurrentPlayerID]; !ok {
+			fail("current player %s does not exist", g.CurrentPlayerID)
+		}
+	}
+
+	for _, id := range sortedKeys(g.Territories) {
+		t := g.Territories[id]
+		if t == nil {
+			fail("territory %s is nil", id)
+			continue
+		}
+		if t.ID != id {
+			fail("territory %s is stored under key %s", t.ID, id)
+		}
+		if t.Owner != "" && t.Owner != BarbarianID {
+			if _, ok := g.Players[t.Owner]; !ok {
+				fail("territory %s is owned by unknown player %s", id, t.Owner)
+			}
+		}
+		for _, adj := range t.Adjacent {
+			if _, ok := g.Territories[adj]; !ok {
+				fail("territory %s is adjacent to unknown territory %s", id, adj)
+			}
+		}
+		for _, wb := range t.WaterBodies {
+			if _, ok := g.WaterBodies[wb]; !ok {
+				fail("territory %s borders unknown water body %s", id, wb)
+			}
+		}
+		for _, wb := range sortedKeys(t.Boats) {
+			if _, ok := g.WaterBodies[wb]; !ok {
+				fail("territory %s has boats in unknown water body %s", id, wb)
+			}
+			if t.Boats[wb] < 0 {
+				fail("territory %s has %d boats in %s", id, t.Boats[wb], wb)
+			}
+		}
+		for _, wb := range sortedKeys(t.Transports) {
+			if n := t.Transports[wb]; n < 0 || n > t.Boats[wb] {
+				fail("territory %s has %d transports but %d boats in %s", id, n, t.Boats[wb], wb)
+			}
+		}
+		if t.Fortresses < 0 || t.Fortresses > MaxFortresses {
+			fail("territory %s has %d fortresses", id, t.Fortresses)
+		}
+		if t.SiegeEngines < 0 || t.SiegeEngines > MaxSiegeEngines {
+			fail("territory %s has %d siege engines", id, t.SiegeEngines)
+		}
+		if t.Specialization != "" && (!t.HasCity || !t.Specialization.IsSpecialization()) {
+			fail("territory %s has specialization %q without a city", id, t.Specialization)
+		}
+	}
+
+	for _, id := range sortedKeys(g.WaterBodies) {
+		wb := g.WaterBodies[id]
+		if wb == nil {
+			fail("water body %s is nil", id)
+			continue
+		}
+		for _, tid := range wb.Territories {
+			if _, ok := g.Territories[tid]; !ok {
+				fail("water body %s borders unknown territory %s", id, tid)
+			}
+		}
+	}
+
+	if b := g.PendingCardBattle; b != nil {
+		if b.AttackerID != g.CurrentPlayerID || g.Phase != PhaseConquest {
+			fail("card battle %s waits outside %s's conquest turn", b.ID, b.AttackerID)
+		}
+		if _, ok := g.Players[b.DefenderID]; !ok {
+			fail("card battle %s has unknown defender %s", b.ID, b.DefenderID)
+		}
+		if _, ok := g.Territories[b.TargetTerritory]; !ok {
+			fail("card battle %s targets unknown territory %s", b.ID, b.TargetTerritory)
+		}
+	}
+
+	return errs
+}
+
+// sortedKeys returns the keys of a map in order, so problems are reported
+// in the same order every run.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,150 @@
+package game
+
+import "log"
+
+// Vassalage is a softer surrender. A player offers to serve another, and if
+// the offer is accepted they become the other player's vassal: they keep
+// their territories and play on, but pay tribute from each round's
+// production and can't attack their overlord. A vassal wins their freedom by
+// winning a battle - taking a territory, or holding one against their
+// overlord. Vassals don't count as rivals for an elimination victory, so an
+// overlord whose only opponents are their own vassals has won.
+
+// TributeShare is the part of their production a vassal pays: 1 in
+// TributeShare of each resource, rounded down.
+const TributeShare = 2
+
+// IsVassal reports whether the player serves an overlord.
+func (p *Player) IsVassal() bool {
+	return p.Overlord != ""
+}
+
+// IsVassalOf reports whether a player is the vassal of another.
+func (g *GameState) IsVassalOf(vassalID, overlordID string) bool {
+	vassal := g.Players[vassalID]
+	return vassal != nil && overlordID != "" && vassal.Overlord == overlordID
+}
+
+// Vassals returns the IDs of an overlord's vassals.
+func (g *GameState) Vassals(overlordID string) []string {
+	var ids []string
+	for _, id := range sortedKeys(g.Players) {
+		if g.Players[id].Overlord == overlordID {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// OfferVassalage records a player's offer to become another's vassal. It
+// replaces any earlier offer. Vassals can't take vassals of their own, so a
+// player who already has vassals, or is one, can't offer, and a vassal can't
+// be offered to.
+func (g *GameState) OfferVassalage(vassalID, overlordID string) error {
+	vassal, overlord := g.Players[vassalID], g.Players[overlordID]
+	if vassal == nil || overlord == nil || vassalID == overlordID {
+		return ErrInvalidTarget
+	}
+	if vassal.Eliminated || overlord.Eliminated {
+		return ErrPlayerEliminated
+	}
+	if vassal.IsVassal() || overlord.IsVassal() || len(g.Vassals(vassalID)) > 0 {
+		return ErrInvalidAction
+	}
+	vassal.VassalOffer = overlordID
+	return nil
+}
+
+// AnswerVassalage accepts or declines a player's offer to become the
+// overlord's vassal.
+func (g *GameState) AnswerVassalage(overlordID, vassalID string, accept bool) error {
+	vassal := g.Players[vassalID]
+	if vassal == nil || vassal.VassalOffer != overlordID || overlordID == "" {
+		return ErrInvalidTarget
+	}
+	vassal.VassalOffer = ""
+	if !accept {
+		return nil
+	}
+
+	// Things may have changed since the offer was made
+	overlord := g.Players[overlordID]
+	if overlord == nil || overlord.Eliminated || vassal.Eliminated {
+		return ErrPlayerEliminated
+	}
+	if overlord.IsVassal() || len(g.Vassals(vassalID)) > 0 {
+		return ErrInvalidAction
+	}
+	vassal.Overlord = overlordID
+	log.Printf("AnswerVassalage: %s is now the vassal of %s", vassal.Name, overlord.Name)
+	return nil
+}
+
+// PayTribute hands the overlord their share of what a vassal produced and
+// returns it, or nil if the player isn't a vassal.
+func (g *GameState) PayTribute(playerID string, results []ProductionResult) *Stockpile {
+	produced := NewStockpile()
+	for _, r := range results {
+		if r.Resource != ResourceGrassland {
+			produced.Add(r.Resource, r.Amount)
+		}
+	}
+	return g.payTribute(playerID, produced)
+}
+
+// payTribute moves the tribute on produced from a vassal to their overlord.
+func (g *GameState) payTribute(playerID string, produced *Stockpile) *Stockpile {
+	vassal := g.Players[playerID]
+	if vassal == nil || !vassal.IsVassal() {
+		return nil
+	}
+	overlord := g.Players[vassal.Overlord]
+	if overlord == nil {
+		return nil
+	}
+
+	tribute := NewStockpile()
+	for _, resource := range []ResourceType{ResourceCoal, ResourceGold, ResourceIron, ResourceTimber} {
+		if n := produced.Get(resource) / TributeShare; n > 0 && vassal.Stockpile.Remove(resource, n) {
+			overlord.Stockpile.Add(resource, n)
+			tribute.Add(resource, n)
+		}
+	}
+	log.Printf("payTribute: %s paid %+v to %s", vassal.Name, *tribute, overlord.Name)
+	return tribute
+}
+
+// settleVassalBattle frees a vassal who won a battle: a vassal who took a
+// territory, or one who held a territory against their overlord. It returns
+// the freed player's ID, or "".
+func (g *GameState) settleVassalBattle(attackerID, defenderID string, attackerWins bool) string {
+	freed := defenderID
+	if attackerWins {
+		freed = attackerID
+	} else if !g.IsVassalOf(defenderID, attackerID) {
+		return ""
+	}
+	if p := g.Players[freed]; p != nil && p.IsVassal() && !p.Eliminated {
+		log.Printf("settleVassalBattle: %s breaks free of %s", p.Name, p.Overlord)
+		p.Overlord = ""
+		return freed
+	}
+	return ""
+}
+
+// releaseVassals frees an eliminated player's vassals and ends any vassalage
+// or offer of their own.
+func (g *GameState) releaseVassals(playerID string) {
+	for _, p := range g.Players {
+		if p.Overlord == playerID {
+			p.Overlord = ""
+		}
+		if p.VassalOffer == playerID {
+			p.VassalOffer = ""
+		}
+	}
+	if p := g.Players[playerID]; p != nil {
+		p.Overlord = ""
+		p.VassalOffer = ""
+	}
+}
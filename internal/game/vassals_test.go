@@ -0,0 +1,57 @@
+package game
+
+import "testing"
+
+func TestVassalage(t *testing.T) {
+	g := terrainTestState(false, TerrainNone)
+	g.Players["B"] = &Player{ID: "B", Stockpile: NewStockpile()}
+	a, b := g.Players["A"], g.Players["B"]
+	a.AttacksRemaining = 2
+
+	if err := g.AnswerVassalage("B", "A", true); err == nil {
+		t.Error("accepted an offer that was never made")
+	}
+	if err := g.OfferVassalage("A", "B"); err != nil {
+		t.Fatalf("OfferVassalage: %v", err)
+	}
+	if err := g.AnswerVassalage("B", "A", true); err != nil {
+		t.Fatalf("AnswerVassalage: %v", err)
+	}
+	if !g.IsVassalOf("A", "B") || a.VassalOffer != "" {
+		t.Fatalf("A not sworn to B: overlord %q, offer %q", a.Overlord, a.VassalOffer)
+	}
+	if err := g.OfferVassalage("B", "A"); err == nil {
+		t.Error("overlord offered to serve their own vassal")
+	}
+
+	// A vassal can't attack their overlord, and doesn't stop them winning
+	if g.CanAttack("A", "d") {
+		t.Error("vassal can attack their overlord")
+	}
+	if !g.IsEliminationVictory() {
+		t.Error("no elimination victory with only a vassal left")
+	}
+
+	// Half of each resource produced goes to the overlord
+	tribute := g.payTribute("A", &Stockpile{Coal: 3, Gold: 1, Timber: 4})
+	if *tribute != (Stockpile{Coal: 1, Timber: 2}) {
+		t.Errorf("tribute = %+v, want 1 coal and 2 timber", *tribute)
+	}
+	if *a.Stockpile != (Stockpile{Coal: 4, Gold: 5, Iron: 5, Timber: 3}) || *b.Stockpile != *tribute {
+		t.Errorf("stockpiles after tribute: A %+v, B %+v", *a.Stockpile, *b.Stockpile)
+	}
+	if g.payTribute("B", &Stockpile{Coal: 4}) != nil {
+		t.Error("overlord paid tribute")
+	}
+
+	// Holding a territory against the overlord wins freedom
+	if freed := g.settleVassalBattle("B", "A", false); freed != "A" || a.IsVassal() {
+		t.Errorf("freed = %q, overlord %q; want A free", freed, a.Overlord)
+	}
+	if !g.CanAttack("A", "d") {
+		t.Error("freed vassal still can't attack")
+	}
+	if g.IsEliminationVictory() {
+		t.Error("elimination victory with two free players")
+	}
+}
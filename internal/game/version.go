@@ -0,0 +1,131 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// StateVersion is the schema version of GameState written by this engine.
+// Bump it whenever a change to GameState (or anything stored in it) would
+// alter how existing saves load, and append the matching upgrade to
+// stateUpgrades.
+const StateVersion = 1
+
+// stateUpgrades converts a saved state from one schema version to the next:
+// stateUpgrades[v] turns version v into version v+1. Upgrades work on the
+// decoded JSON object rather than GameState, so they keep working however the
+// struct changes later.
+var stateUpgrades = []func(state map[string]any) error{
+	upgradeStateV0,
+}
+
+// ErrStateTooNew is returned when a save was written by a newer engine.
+var ErrStateTooNew = errors.New("game state was saved by a newer version")
+
+// MarshalState encodes a game state for storage, stamped with StateVersion.
+func MarshalState(g *GameState) ([]byte, error) {
+	g.Version = StateVersion
+	return json.Marshal(g)
+}
+
+// UnmarshalState decodes a stored game state, upgrading it to StateVersion first.
+func UnmarshalState(data []byte) (*GameState, error) {
+	raw, err := UpgradeState(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var g GameState
+	if err := json.Unmarshal(raw, &g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// UpgradeState runs every upgrade a stored state needs and returns it as
+// StateVersion JSON. States already at StateVersion are returned unchanged.
+func UpgradeState(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var state map[string]any
+	if err := dec.Decode(&state); err != nil {
+		return nil, err
+	}
+
+	version, err := stateVersion(state)
+	if err != nil {
+		return nil, err
+	}
+	if version > StateVersion {
+		return nil, fmt.Errorf("%w (version %d, engine supports %d)", ErrStateTooNew, version, StateVersion)
+	}
+	if version == StateVersion {
+		return data, nil
+	}
+
+	for v := version; v < StateVersion; v++ {
+		if err := stateUpgrades[v](state); err != nil {
+			return nil, fmt.Errorf("upgrading game state from version %d: %w", v, err)
+		}
+	}
+	state["version"] = StateVersion
+
+	return json.Marshal(state)
+}
+
+// SavedStateVersion returns the schema version a stored state was written with.
+func SavedStateVersion(data []byte) (int, error) {
+	var header struct {
+		Version *json.Number `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return 0, err
+	}
+	if header.Version == nil {
+		return 0, nil
+	}
+	return stateVersion(map[string]any{"version": *header.Version})
+}
+
+// stateVersion reads the schema version of a decoded state. Saves from before
+// versioning have no version field and count as version 0.
+func stateVersion(state map[string]any) (int, error) {
+	v, ok := state["version"]
+	if !ok {
+		return 0, nil
+	}
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("invalid game state version %v", v)
+	}
+	version, err := n.Int64()
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("invalid game state version %v", v)
+	}
+	return int(version), nil
+}
+
+// upgradeStateV0 upgrades saves from before versioning. Those can predate card
+// combat and have players without card hands, or null stockpiles; fill both in
+// so the engine never sees a nil stockpile.
+func upgradeStateV0(state map[string]any) error {
+	players, _ := state["players"].(map[string]any)
+	for id, p := range players {
+		player, ok := p.(map[string]any)
+		if !ok {
+			return fmt.Errorf("player %s is not an object", id)
+		}
+		if player["stockpile"] == nil {
+			player["stockpile"] = map[string]any{}
+		}
+		for _, hand := range []string{"attackCards", "defenseCards"} {
+			if player[hand] == nil {
+				player[hand] = []any{}
+			}
+		}
+	}
+	return nil
+}
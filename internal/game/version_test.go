@@ -0,0 +1,64 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUnmarshalStateUpgradesUnversionedSave(t *testing.T) {
+	// A save from before versioning: no version, null stockpile and card hands
+	data := []byte(`{
+		"id": "g1",
+		"phase": 3,
+		"currentPlayerId": "p1",
+		"playerOrder": ["p1"],
+		"players": {"p1": {"id": "p1", "name": "Alice", "stockpile": null}},
+		"territories": {},
+		"waterBodies": {}
+	}`)
+
+	if v, err := SavedStateVersion(data); err != nil || v != 0 {
+		t.Fatalf("SavedStateVersion = %d, %v; want 0", v, err)
+	}
+
+	state, err := UnmarshalState(data)
+	if err != nil {
+		t.Fatalf("UnmarshalState: %v", err)
+	}
+	if state.Version != StateVersion {
+		t.Errorf("Version = %d, want %d", state.Version, StateVersion)
+	}
+	p := state.Players["p1"]
+	if p.Stockpile == nil {
+		t.Error("stockpile was not filled in")
+	}
+	if p.AttackCards == nil || p.DefenseCards == nil {
+		t.Error("card hands were not filled in")
+	}
+	if errs := state.Validate(); len(errs) > 0 {
+		t.Errorf("upgraded state is invalid: %v", errs)
+	}
+}
+
+func TestUnmarshalStateRejectsNewerVersion(t *testing.T) {
+	_, err := UnmarshalState([]byte(`{"version": 999}`))
+	if !errors.Is(err, ErrStateTooNew) {
+		t.Errorf("err = %v, want ErrStateTooNew", err)
+	}
+}
+
+func TestValidateReportsDanglingReferences(t *testing.T) {
+	state := &GameState{
+		CurrentPlayerID: "ghost",
+		Players:         map[string]*Player{"p1": {ID: "p1", Stockpile: NewStockpile(), StockpileTerritory: "t1"}},
+		Territories: map[string]*Territory{
+			"t1": {ID: "t1", Owner: "p2", Adjacent: []string{"t9"}},
+		},
+		WaterBodies: map[string]*WaterBody{},
+	}
+
+	// Unknown current player, unknown owner, stockpile on someone else's land, unknown neighbour
+	if errs := state.Validate(); len(errs) != 4 {
+		t.Errorf("got %d problems, want 4: %v", len(errs), errs)
+	}
+}
@@ -19,6 +19,10 @@ const (
 	DefaultVictoryCities = 6
 
 	DefaultChanceLevel = "high"
+
+	MinMapRating = 1
+	MaxMapRating = 5
+	MapListLimit = 50 // Default and largest page of the map library
 )
 
 // MessageType identifies the type of message.
@@ -55,11 +59,25 @@ const (
 	TypePlayerLeft     MessageType = "player_left"
 )
 
+// Map library message types
+const (
+	TypeListMaps     MessageType = "list_maps"
+	TypeMapList      MessageType = "map_list"
+	TypeGetMap       MessageType = "get_map"
+	TypeMapPreview   MessageType = "map_preview"
+	TypePublishMap   MessageType = "publish_map"
+	TypeMapPublished MessageType = "map_published"
+	TypeRateMap      MessageType = "rate_map"
+	TypeMapRated     MessageType = "map_rated"
+	TypeSelectMap    MessageType = "select_map" // Host picks a library map for the lobby
+)
+
 // Game flow message types
 const (
 	TypeGameStarted       MessageType = "game_started"
 	TypePhaseChanged      MessageType = "phase_changed"
 	TypePhaseSkipped      MessageType = "phase_skipped"
+	TypeWorldEvent        MessageType = "world_event"
 	TypeTurnChanged       MessageType = "turn_changed"
 	TypeActionResult      MessageType = "action_result"
 	TypeProductionResults MessageType = "production_results"
@@ -78,8 +96,10 @@ const (
 	TypeRespondTrade       MessageType = "respond_trade"     // Target's response
 	TypeTradeResult        MessageType = "trade_result"      // Result sent to proposer
 	TypeSelectHorseDest    MessageType = "select_horse_dest" // Where to place received horses
+	TypeMarketTrade        MessageType = "market_trade"      // Trade with the bank (market setting)
 	TypeMoveStockpile      MessageType = "move_stockpile"
 	TypeMoveUnit           MessageType = "move_unit"
+	TypeShipResources      MessageType = "ship_resources" // Move resources between depots (depots setting)
 	TypePlanAttack         MessageType = "plan_attack"
 	TypeAttackPreview      MessageType = "attack_preview"
 	TypeBringForces        MessageType = "bring_forces"
@@ -87,6 +107,8 @@ const (
 	TypeAttackPlanResolved MessageType = "attack_plan_resolved" // Server returns resolved alliance totals
 	TypeExecuteAttack      MessageType = "execute_attack"
 	TypeCancelAttack       MessageType = "cancel_attack"
+	TypeRaid               MessageType = "raid"         // Steal from a stockpile or depot (depots setting)
+	TypeNavalAttack        MessageType = "naval_attack" // Attack a territory's boats (naval combat setting)
 	TypeSetAlliance        MessageType = "set_alliance"
 	TypeAllianceRequest    MessageType = "alliance_request"
 	TypeAllianceVote       MessageType = "alliance_vote"
@@ -94,6 +116,10 @@ const (
 	TypeBuild              MessageType = "build"
 	TypeSurrender          MessageType = "surrender"
 	TypeSurrenderResult    MessageType = "surrender_result"
+	TypeOfferVassalage     MessageType = "offer_vassalage"  // Offer to become another player's vassal
+	TypeVassalageOffer     MessageType = "vassalage_offer"  // Server asks the would-be overlord
+	TypeAnswerVassalage    MessageType = "answer_vassalage" // Overlord accepts or declines
+	TypeVassalageResult    MessageType = "vassalage_result" // Everyone hears how it went
 	TypeRenameTerritory    MessageType = "rename_territory"
 	TypeDrawTerritory      MessageType = "draw_territory"
 	TypeTerritoryDrawing   MessageType = "territory_drawing"
@@ -101,6 +127,8 @@ const (
 	// Card combat actions
 	TypeBuyCard            MessageType = "buy_card"
 	TypeCardDrawn          MessageType = "card_drawn"
+	TypeDiscardCard        MessageType = "discard_card"
+	TypeCombineCards       MessageType = "combine_cards"
 	TypeSelectAttackCards  MessageType = "select_attack_cards"
 	TypeSelectDefenseCards MessageType = "select_defense_cards"
 	TypeDefenseCardRequest MessageType = "defense_card_request"
@@ -35,12 +35,23 @@ type MapData struct {
 	Height      int                      `json:"height"`
 	Grid        [][]int                  `json:"grid"`
 	Territories map[string]TerritoryInfo `json:"territories"`
+	Topology    string                   `json:"topology,omitempty"` // "square" (default) or "hex"
+	Seas        []SeaInfo                `json:"seas,omitempty"`
+	Symmetry    string                   `json:"symmetry,omitempty"` // "mirror", "rotate" or "quad"
+	Starts      [][]int                  `json:"starts,omitempty"`   // Starting territories of each seat
+}
+
+// SeaInfo names the water body containing a cell.
+type SeaInfo struct {
+	Name string `json:"name"`
+	Cell [2]int `json:"cell"` // [x,y] of any water cell in the sea
 }
 
 // TerritoryInfo contains territory metadata.
 type TerritoryInfo struct {
 	Name     string `json:"name"`
 	Resource string `json:"resource,omitempty"`
+	Terrain  string `json:"terrain,omitempty"`
 }
 
 // GameCreatedPayload is the response when a game is created.
@@ -55,7 +66,19 @@ type GameSettings struct {
 	ChanceLevel   string `json:"chance_level"`   // low, medium, high
 	VictoryCities int    `json:"victory_cities"` // 3-10
 	MapID         string `json:"map_id"`
-	CombatMode    string `json:"combat_mode"`    // "classic", "cards"
+	CombatMode    string `json:"combat_mode"`              // "classic", "cards"
+	CardSet       string `json:"card_set,omitempty"`       // Card set drawn from in card mode; empty is the classic set
+	Terrain       bool   `json:"terrain,omitempty"`        // Terrain affects defense, horses and building
+	Straits       bool   `json:"straits,omitempty"`        // Boats can pass through held straits
+	PresetStarts  bool   `json:"preset_starts,omitempty"`  // Use the map's starting territories instead of a draft
+	ExtendedUnits bool   `json:"extended_units,omitempty"` // Fortresses, siege engines and transports can be built
+	Economy       bool   `json:"economy,omitempty"`        // Unit upkeep, storage caps and city specializations
+	Market        bool   `json:"market,omitempty"`         // A bank with moving prices trades during the Trade phase
+	WorldEvents   bool   `json:"world_events,omitempty"`   // Plagues, harvests, storms and rebellions strike at round start
+	Neutrals      bool   `json:"neutrals,omitempty"`       // Some territories start neutral with a fixed garrison
+	Barbarians    bool   `json:"barbarians,omitempty"`     // Some neutral territories form a barbarian horde that raids each round
+	Depots        bool   `json:"depots,omitempty"`         // Players can build depots and raid stockpiles
+	NavalCombat   bool   `json:"naval_combat,omitempty"`   // Fleets in a shared water body fight and blockade each other
 }
 
 // UpdateMapPayload is sent by the host to change the game's map.
@@ -187,6 +210,70 @@ type PlayerLeftPayload struct {
 	PlayerID string `json:"player_id"`
 }
 
+// ==================== Map Library Payloads ====================
+
+// ListMapsPayload is sent to browse the map library.
+type ListMapsPayload struct {
+	Sort  string `json:"sort,omitempty"`  // "rating", "plays" or "newest" (default)
+	Limit int    `json:"limit,omitempty"` // Defaults to MapListLimit
+}
+
+// MapListPayload contains a page of the map library.
+type MapListPayload struct {
+	Maps []MapListItem `json:"maps"`
+}
+
+// MapListItem is a summary of a library map.
+type MapListItem struct {
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	AuthorName     string  `json:"author_name"`
+	Width          int     `json:"width"`
+	Height         int     `json:"height"`
+	TerritoryCount int     `json:"territory_count"`
+	PlayCount      int     `json:"play_count"`
+	Rating         float64 `json:"rating"` // Average rating, 0 if unrated
+	RatingCount    int     `json:"rating_count"`
+}
+
+// GetMapPayload is sent to fetch a library map for preview.
+type GetMapPayload struct {
+	MapID string `json:"map_id"`
+}
+
+// MapPreviewPayload contains a library map and its full map data.
+type MapPreviewPayload struct {
+	Map     MapListItem `json:"map"`
+	MapData *MapData    `json:"map_data"`
+}
+
+// PublishMapPayload is sent to add a map to the library.
+type PublishMapPayload struct {
+	Name    string   `json:"name"`
+	MapData *MapData `json:"map_data"`
+}
+
+// MapPublishedPayload is the response when a map is published.
+type MapPublishedPayload struct {
+	Map MapListItem `json:"map"`
+}
+
+// RateMapPayload is sent to rate a library map.
+type RateMapPayload struct {
+	MapID  string `json:"map_id"`
+	Rating int    `json:"rating"` // 1-5
+}
+
+// MapRatedPayload is the response when a map is rated.
+type MapRatedPayload struct {
+	Map MapListItem `json:"map"`
+}
+
+// SelectMapPayload is sent by the host to use a library map for the game.
+type SelectMapPayload struct {
+	MapID string `json:"map_id"`
+}
+
 // ==================== Game Flow Payloads ====================
 
 // GameStartedPayload is sent when the game begins.
@@ -252,6 +339,16 @@ type PhaseSkippedPayload struct {
 	Reason  string `json:"reason"`   // Funny reason for skipping
 }
 
+// WorldEventPayload is sent when a world event strikes at the start of a round.
+type WorldEventPayload struct {
+	EventID     string `json:"event_id"` // For sync acknowledgment
+	Type        string `json:"type"`     // plague, harvest, storm, rebellion
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	TerritoryID string `json:"territory_id,omitempty"`  // Territory hit, for plague and rebellion
+	WaterBodyID string `json:"water_body_id,omitempty"` // Water body hit, for storms
+}
+
 // ==================== Action Payloads ====================
 
 // SelectTerritoryPayload selects a territory.
@@ -327,11 +424,28 @@ type TradeResultPayload struct {
 	Message  string `json:"message,omitempty"`
 }
 
+// MarketTradePayload sells resources to the bank for another resource.
+type MarketTradePayload struct {
+	Sell   string `json:"sell"` // "coal", "gold", "iron", "timber"
+	Amount int    `json:"amount"`
+	Buy    string `json:"buy"`
+}
+
 // MoveStockpilePayload moves the stockpile.
 type MoveStockpilePayload struct {
 	Destination string `json:"destination"`
 }
 
+// ShipResourcesPayload moves resources between a player's stockpile and depots.
+type ShipResourcesPayload struct {
+	From   string `json:"from"` // Territory ID of the stockpile or depot
+	To     string `json:"to"`
+	Coal   int    `json:"coal,omitempty"`
+	Gold   int    `json:"gold,omitempty"`
+	Iron   int    `json:"iron,omitempty"`
+	Timber int    `json:"timber,omitempty"`
+}
+
 // MoveUnitPayload moves a unit.
 type MoveUnitPayload struct {
 	UnitType    string `json:"unit_type"`
@@ -340,6 +454,7 @@ type MoveUnitPayload struct {
 	WaterBodyID string `json:"water_body_id,omitempty"` // For boats: which water body
 	CarryHorse  bool   `json:"carry_horse,omitempty"`   // For boats: load horse
 	CarryWeapon bool   `json:"carry_weapon,omitempty"`  // For boats/horses: load weapon
+	CarrySiege  int    `json:"carry_siege,omitempty"`   // For transports: how many siege engines to load
 }
 
 // PlanAttackPayload begins attack planning.
@@ -354,6 +469,7 @@ type AttackPreviewPayload struct {
 	DefenseStrength         int                   `json:"defense_strength"`
 	AttackerAllyStrength    int                   `json:"attacker_ally_strength"` // Strength from allies
 	DefenderAllyStrength    int                   `json:"defender_ally_strength"` // Strength from allies
+	WinChance               float64               `json:"win_chance"`             // Chance of winning without reinforcements, 0 to 1
 	CanAttack               bool                  `json:"can_attack"`
 	AvailableReinforcements []ReinforcementOption `json:"available_reinforcements"`
 }
@@ -413,7 +529,7 @@ type AllianceResultPayload struct {
 
 // BuildPayload builds a unit or city.
 type BuildPayload struct {
-	Type        string `json:"type"` // "city", "weapon", or "boat"
+	Type        string `json:"type"` // "city", "weapon", "boat", with extended units "fortress", "siege" or "transport", with economy rules "mine", "lumber_mill" or "stable"
 	Territory   string `json:"territory"`
 	WaterBodyID string `json:"water_body_id,omitempty"` // Required for boats when multiple water bodies available
 	UseGold     bool   `json:"use_gold"`
@@ -433,6 +549,18 @@ type ExecuteAttackPayload struct {
 	AttackCardIDs   []string `json:"attack_card_ids,omitempty"` // Card combat: IDs of attack cards to play
 }
 
+// RaidPayload raids the stockpile or depot in a territory.
+type RaidPayload struct {
+	TargetTerritory string `json:"target_territory"`
+}
+
+// NavalAttackPayload attacks the boats a territory keeps in a water body with
+// the attacker's fleet there.
+type NavalAttackPayload struct {
+	TargetTerritory string `json:"target_territory"`
+	WaterBodyID     string `json:"water_body_id"`
+}
+
 // RequestAttackPlanPayload requests alliance resolution before committing to attack.
 type RequestAttackPlanPayload struct {
 	TargetTerritory string `json:"target_territory"`
@@ -472,6 +600,38 @@ type SurrenderResultPayload struct {
 	TerritoriesGained     int    `json:"territories_gained"`
 }
 
+// OfferVassalagePayload is sent to offer to become another player's vassal.
+type OfferVassalagePayload struct {
+	TargetPlayerID string `json:"target_player_id"` // Player to serve
+}
+
+// VassalageOfferPayload asks a player whether to take on a vassal.
+type VassalageOfferPayload struct {
+	VassalID   string `json:"vassal_id"`
+	VassalName string `json:"vassal_name"`
+}
+
+// AnswerVassalagePayload accepts or declines an offer of vassalage.
+type AnswerVassalagePayload struct {
+	VassalID string `json:"vassal_id"`
+	Accept   bool   `json:"accept"`
+}
+
+// Vassalage results.
+const (
+	VassalageSworn    = "sworn"    // The offer was accepted
+	VassalageDeclined = "declined" // The offer was turned down
+)
+
+// VassalageResultPayload reports the answer to an offer of vassalage.
+type VassalageResultPayload struct {
+	Result       string `json:"result"` // VassalageSworn or VassalageDeclined
+	VassalID     string `json:"vassal_id"`
+	VassalName   string `json:"vassal_name"`
+	OverlordID   string `json:"overlord_id"`
+	OverlordName string `json:"overlord_name"`
+}
+
 // CombatResultPayload reports the result of combat.
 type CombatResultPayload struct {
 	EventID         string   `json:"event_id"` // For sync acknowledgment
@@ -493,6 +653,9 @@ type CombatResultPayload struct {
 	CapturedIron          int    `json:"captured_iron,omitempty"`
 	CapturedTimber        int    `json:"captured_timber,omitempty"`
 	CapturedFromTerritory string `json:"captured_from_territory,omitempty"` // Where the stockpile was
+	FreedVassal           string `json:"freed_vassal,omitempty"`            // Vassal who won their freedom in this battle
+	Raid                  bool   `json:"raid,omitempty"`                    // A raid: the captured resources are the loot, the territory changes no hands
+	Naval                 bool   `json:"naval,omitempty"`                   // A sea battle: the units are boats, the territory changes no hands
 }
 
 // ==================== Card Combat Payloads ====================
@@ -503,6 +666,18 @@ type BuyCardPayload struct {
 	Resource string `json:"resource"`  // "coal", "gold", "iron", "timber"
 }
 
+// DiscardCardPayload discards a combat card for resources during Development.
+type DiscardCardPayload struct {
+	CardID   string `json:"card_id"`
+	Resource string `json:"resource"` // "coal", "gold", "iron", "timber"
+}
+
+// CombineCardsPayload trades two common combat cards of the same type for an
+// uncommon one during Development.
+type CombineCardsPayload struct {
+	CardIDs []string `json:"card_ids"` // Exactly two
+}
+
 // CardDrawnPayload is sent when a card is successfully purchased.
 type CardDrawnPayload struct {
 	Card CardInfo `json:"card"`
@@ -535,6 +710,8 @@ type DefenseCardRequestPayload struct {
 	BaseAttackStr     int    `json:"base_attack_strength"`
 	BaseDefenseStr    int    `json:"base_defense_strength"`
 	AttackerCardCount int    `json:"attacker_card_count"` // How many cards attacker committed (hidden)
+	TimeLimit         int    `json:"time_limit"`          // seconds
+	ExpiresAt         int64  `json:"expires_at"`          // unix timestamp
 }
 
 // CardRevealPayload is sent to both players after cards are resolved.
@@ -565,6 +742,7 @@ const (
 	EventPhaseChange = "phase_change" // Phase transition
 	EventTurnChange  = "turn_change"  // Turn changed to new player
 	EventProduction  = "production"   // Production animation
+	EventWorldEvent  = "world_event"  // World event notification
 )
 
 // ==================== Production Payloads ====================
@@ -587,6 +765,20 @@ type ProductionResultsPayload struct {
 	Productions            []TerritoryProduction `json:"productions"`
 	StockpileTerritoryID   string                `json:"stockpile_territory_id"`
 	StockpileTerritoryName string                `json:"stockpile_territory_name"`
+	// Economy rules only: resources paid in upkeep and thrown away over the
+	// storage cap (resource -> amount), and units lost for unpaid upkeep
+	Upkeep    map[string]int  `json:"upkeep,omitempty"`
+	Spoiled   map[string]int  `json:"spoiled,omitempty"`
+	Disbanded []DisbandedUnit `json:"disbanded,omitempty"`
+	// Vassals only: resources paid to their overlord as tribute
+	Tribute map[string]int `json:"tribute,omitempty"`
+}
+
+// DisbandedUnit is a unit lost because its upkeep couldn't be paid.
+type DisbandedUnit struct {
+	TerritoryID   string `json:"territory_id"`
+	TerritoryName string `json:"territory_name"`
+	UnitType      string `json:"unit_type"` // weapon, boat, siege, fortress
 }
 
 // ClientReadyPayload is sent by client to acknowledge an event.
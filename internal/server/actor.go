@@ -0,0 +1,301 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"lords-of-conquest/internal/game"
+)
+
+// GameActor owns a single game. Every read and write of the game's state,
+// its pending interactions and its timers runs on the actor's goroutine, one
+// command at a time, so human actions, AI turns and timeouts never race.
+type GameActor struct {
+	id  string
+	hub *Hub
+
+	// Mailbox: an unbounded queue so posting from inside a command never blocks.
+	mu      sync.Mutex
+	queue   []func()
+	wake    chan struct{}
+	stopped bool
+
+	// Everything below is only touched on the actor goroutine.
+
+	// state is the last saved snapshot; nil until first loaded from the database.
+	state *game.GameState
+
+	// timers counts scheduled callbacks that have not fired or been cancelled.
+	timers int
+
+	pendingBattles     map[string]*PendingBattle
+	pendingTrades      map[string]*PendingTrade
+	pendingEvents      map[string]*PendingEvent
+	pendingAttackPlans map[string]*PendingAttackPlan
+	pendingCardBattles map[string]*PendingCardBattle
+}
+
+// errNoGameState is returned when a game has no saved state yet (still in lobby).
+var errNoGameState = errors.New("game has no saved state")
+
+// errActorStopped is returned when posting to an actor that has been stopped.
+var errActorStopped = errors.New("game actor stopped")
+
+// newGameActor creates an actor for a game and starts its goroutine.
+func newGameActor(hub *Hub, gameID string) *GameActor {
+	g := &GameActor{
+		id:                 gameID,
+		hub:                hub,
+		wake:               make(chan struct{}, 1),
+		pendingBattles:     make(map[string]*PendingBattle),
+		pendingTrades:      make(map[string]*PendingTrade),
+		pendingEvents:      make(map[string]*PendingEvent),
+		pendingAttackPlans: make(map[string]*PendingAttackPlan),
+		pendingCardBattles: make(map[string]*PendingCardBattle),
+	}
+	go g.run()
+	return g
+}
+
+// run processes queued commands in order until the actor is stopped.
+func (g *GameActor) run() {
+	for range g.wake {
+		for {
+			g.mu.Lock()
+			if g.stopped {
+				g.mu.Unlock()
+				return
+			}
+			if len(g.queue) == 0 {
+				g.mu.Unlock()
+				break
+			}
+			fn := g.queue[0]
+			g.queue[0] = nil
+			g.queue = g.queue[1:]
+			g.mu.Unlock()
+
+			g.exec(fn)
+		}
+	}
+}
+
+// exec runs a single command, keeping a panic from killing the game.
+func (g *GameActor) exec(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Game %s: command panicked: %v", g.id, r)
+		}
+	}()
+	fn()
+}
+
+// Post queues fn to run on the actor goroutine. It returns errActorStopped,
+// and fn never runs, if the actor has stopped; use Hub.PostToGame to reach
+// whichever actor runs the game now.
+func (g *GameActor) Post(fn func()) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.stopped {
+		return errActorStopped
+	}
+	g.queue = append(g.queue, fn)
+	select {
+	case g.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// After schedules fn to run on the actor goroutine once d has elapsed.
+// Must be called from the actor goroutine.
+func (g *GameActor) After(d time.Duration, fn func()) *time.Timer {
+	g.timers++
+	return time.AfterFunc(d, func() {
+		g.Post(func() {
+			g.timers--
+			fn()
+		})
+	})
+}
+
+// Cancel stops a timer created by After if it has not fired yet.
+// Must be called from the actor goroutine.
+func (g *GameActor) Cancel(t *time.Timer) {
+	if t != nil && t.Stop() {
+		g.timers--
+	}
+}
+
+// Stop shuts the actor down. Queued commands and timers are discarded.
+func (g *GameActor) Stop() {
+	g.mu.Lock()
+	if g.stopped {
+		g.mu.Unlock()
+		return
+	}
+	g.stopped = true
+	g.queue = nil
+	g.mu.Unlock()
+	close(g.wake)
+}
+
+// stopIfEmpty stops the actor only if no command is waiting in its mailbox,
+// so a command posted just before an idle release is never dropped. Reports
+// whether it stopped.
+func (g *GameActor) stopIfEmpty() bool {
+	g.mu.Lock()
+	if g.stopped || len(g.queue) > 0 {
+		g.mu.Unlock()
+		return false
+	}
+	g.stopped = true
+	g.mu.Unlock()
+	close(g.wake)
+	return true
+}
+
+// idle reports whether the actor has nothing in flight and can be discarded.
+// Must be called from the actor goroutine.
+func (g *GameActor) idle() bool {
+	return g.timers == 0 &&
+		len(g.pendingBattles) == 0 &&
+		len(g.pendingTrades) == 0 &&
+		len(g.pendingEvents) == 0 &&
+		len(g.pendingAttackPlans) == 0 &&
+		len(g.pendingCardBattles) == 0
+}
+
+// loadState returns a working copy of the game state. The first call reads
+// it from the database; later calls are served from memory. Changes to the
+// copy only take effect once passed to saveState.
+func (g *GameActor) loadState() (*game.GameState, error) {
+	if g.state == nil {
+		stateJSON, err := g.hub.server.db.GetGameState(g.id)
+		if err != nil {
+			return nil, err
+		}
+		if stateJSON == "" {
+			return nil, errNoGameState
+		}
+		state, err := game.UnmarshalState([]byte(stateJSON))
+		if err != nil {
+			return nil, err
+		}
+		g.state = state
+	}
+	return cloneState(g.state)
+}
+
+// saveState writes the state to the database and makes it the actor's snapshot.
+func (g *GameActor) saveState(state *game.GameState) error {
+	stateJSON, err := game.MarshalState(state)
+	if err != nil {
+		return err
+	}
+	if err := g.hub.server.db.SaveGameState(g.id, string(stateJSON),
+		state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
+		return err
+	}
+
+	// Keep our own copy so later changes by the caller don't leak into the snapshot
+	var snapshot game.GameState
+	if err := json.Unmarshal(stateJSON, &snapshot); err != nil {
+		return err
+	}
+	g.state = &snapshot
+	return nil
+}
+
+// cloneState deep-copies a game state using its saved representation.
+func cloneState(state *game.GameState) (*game.GameState, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	var clone game.GameState
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
+
+// SyncTimeout is how long to wait for client acknowledgments before proceeding anyway.
+const SyncTimeout = 30 * time.Second
+
+// CreatePendingEvent creates a new event that requires client acknowledgment.
+// requiredPlayers are the human player IDs that must acknowledge.
+// onComplete is called on the actor when all acks are received or timeout occurs.
+func (g *GameActor) CreatePendingEvent(eventID, eventType string, requiredPlayers []string, onComplete func()) *PendingEvent {
+	acks := make(map[string]bool)
+	for _, pid := range requiredPlayers {
+		acks[pid] = false
+	}
+
+	event := &PendingEvent{
+		ID:           eventID,
+		Type:         eventType,
+		GameID:       g.id,
+		RequiredAcks: acks,
+		Timeout:      time.Now().Add(SyncTimeout),
+		OnComplete:   onComplete,
+	}
+
+	// If no human players need to ack, complete immediately
+	if len(requiredPlayers) == 0 {
+		g.completeEvent(event)
+		return event
+	}
+
+	g.pendingEvents[eventID] = event
+	event.timer = g.After(SyncTimeout, func() {
+		if !event.completed {
+			log.Printf("Event %s timed out, proceeding anyway", eventID)
+			g.completeEvent(event)
+		}
+	})
+
+	return event
+}
+
+// AcknowledgeEvent processes a client's acknowledgment of an event.
+func (g *GameActor) AcknowledgeEvent(eventID, playerID string) {
+	event, exists := g.pendingEvents[eventID]
+	if !exists {
+		log.Printf("Ack received for unknown event %s from player %s", eventID, playerID)
+		return
+	}
+
+	if _, required := event.RequiredAcks[playerID]; required {
+		event.RequiredAcks[playerID] = true
+		log.Printf("Event %s: received ack from %s", eventID, playerID)
+	}
+
+	// Check if all acks received
+	for _, acked := range event.RequiredAcks {
+		if !acked {
+			return
+		}
+	}
+	g.completeEvent(event)
+}
+
+// completeEvent marks an event as complete and calls its callback.
+func (g *GameActor) completeEvent(event *PendingEvent) {
+	if event.completed {
+		return
+	}
+	event.completed = true
+	delete(g.pendingEvents, event.ID)
+	g.Cancel(event.timer)
+
+	log.Printf("Event %s (%s) completed, proceeding", event.ID, event.Type)
+
+	// Call the completion callback
+	if event.OnComplete != nil {
+		event.OnComplete()
+	}
+}
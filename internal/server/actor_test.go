@@ -0,0 +1,111 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+// wait fails the test if ch isn't closed within a few seconds.
+func wait(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal(what)
+	}
+}
+
+// running reports whether the hub has an actor for a game.
+func running(h *Hub, gameID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	_, ok := h.games[gameID]
+	return ok
+}
+
+func TestActorRunsCommandsInOrder(t *testing.T) {
+	g := newGameActor(nil, "game")
+	defer g.Stop()
+
+	var got []int
+	for i := 0; i < 100; i++ {
+		g.Post(func() { got = append(got, i) })
+	}
+	drain(t, g)
+
+	if len(got) != 100 {
+		t.Fatalf("ran %d commands, want 100", len(got))
+	}
+	for i, n := range got {
+		if n != i {
+			t.Fatalf("command %d ran in position %d", n, i)
+		}
+	}
+}
+
+func TestActorPostAfterStop(t *testing.T) {
+	g := newGameActor(nil, "game")
+	g.Stop()
+	if err := g.Post(func() { t.Error("command ran on a stopped actor") }); err != errActorStopped {
+		t.Errorf("Post after Stop: err = %v, want %v", err, errActorStopped)
+	}
+}
+
+func TestActorAfterAndCancel(t *testing.T) {
+	g := newGameActor(nil, "game")
+	defer g.Stop()
+
+	fired := make(chan struct{})
+	g.Post(func() {
+		g.After(10*time.Millisecond, func() { close(fired) })
+		cancelled := g.After(time.Hour, func() { t.Error("cancelled timer fired") })
+		g.Cancel(cancelled)
+		if g.timers != 1 || g.idle() {
+			t.Errorf("after scheduling: %d timers, idle %v; want 1 and busy", g.timers, g.idle())
+		}
+	})
+	wait(t, fired, "timer did not fire")
+
+	drain(t, g)
+	g.Post(func() {
+		if g.timers != 0 || !g.idle() {
+			t.Errorf("after firing: %d timers, idle %v; want 0 and idle", g.timers, g.idle())
+		}
+	})
+	drain(t, g)
+}
+
+func TestIdleReleaseKeepsPostInFlight(t *testing.T) {
+	s := newTestServer(t)
+	h := s.hub
+	gameID := "game"
+
+	// A command posted while the release is waiting keeps the actor alive
+	g := h.Game(gameID)
+	release := make(chan struct{})
+	g.Post(func() { <-release })
+	h.releaseGameIfIdle(gameID)
+	ran := make(chan struct{})
+	h.PostToGame(gameID, func() { close(ran) })
+	close(release)
+	wait(t, ran, "command posted during an idle release was dropped")
+	if h.Game(gameID) != g {
+		t.Error("actor was released with a command in its mailbox")
+	}
+
+	// Once released, the next command starts a fresh actor
+	h.releaseGameIfIdle(gameID)
+	deadline := time.Now().Add(5 * time.Second)
+	for running(h, gameID) {
+		if time.Now().After(deadline) {
+			t.Fatal("idle actor was not released")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	ran = make(chan struct{})
+	h.PostToGame(gameID, func() { close(ran) })
+	wait(t, ran, "command posted after an idle release was dropped")
+	if h.Game(gameID) == g {
+		t.Error("command went to the released actor")
+	}
+}
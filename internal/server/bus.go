@@ -0,0 +1,198 @@
+package server
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"lords-of-conquest/internal/protocol"
+)
+
+// Bus carries envelopes between server instances. Every instance subscribes
+// under its own node ID and receives the envelopes published to that ID.
+type Bus interface {
+	// Publish sends an envelope to the instance subscribed as node.
+	Publish(node string, env *Envelope) error
+
+	// Subscribe registers the handler for envelopes addressed to node.
+	Subscribe(node string, handler func(*Envelope)) error
+}
+
+// EnvelopeKind identifies what an envelope asks the receiving instance to do.
+type EnvelopeKind string
+
+const (
+	// EnvelopeCommand is a client message for a game owned by the receiver.
+	EnvelopeCommand EnvelopeKind = "command"
+	// EnvelopeDetach tells a game's owner that a forwarded client disconnected.
+	EnvelopeDetach EnvelopeKind = "detach"
+	// EnvelopeDeliver is a server message for a client connected to the receiver.
+	EnvelopeDeliver EnvelopeKind = "deliver"
+)
+
+// Envelope is a message travelling between server instances.
+type Envelope struct {
+	Kind     EnvelopeKind      `json:"kind"`
+	From     string            `json:"from"`             // Node that sent the envelope
+	PlayerID string            `json:"playerId"`         // Player the message is from or for
+	Name     string            `json:"name,omitempty"`   // Player display name (commands)
+	GameID   string            `json:"gameId,omitempty"` // Game the player is in, as seen by the sender
+	Message  *protocol.Message `json:"message,omitempty"`
+}
+
+// ErrUnknownNode is returned when publishing to a node nobody subscribed as.
+var ErrUnknownNode = errors.New("unknown node")
+
+// MemoryBus is an in-process Bus, for tests and for running several hubs in
+// one process. Envelopes are copied through JSON, as they would be on the
+// wire, and delivered synchronously on the publisher's goroutine.
+type MemoryBus struct {
+	mu       sync.RWMutex
+	handlers map[string]func(*Envelope)
+}
+
+// NewMemoryBus creates an empty in-process bus.
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{handlers: make(map[string]func(*Envelope))}
+}
+
+// Publish delivers env to the handler subscribed as node.
+func (b *MemoryBus) Publish(node string, env *Envelope) error {
+	b.mu.RLock()
+	handler := b.handlers[node]
+	b.mu.RUnlock()
+	if handler == nil {
+		return ErrUnknownNode
+	}
+
+	data, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	var copied Envelope
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return err
+	}
+
+	handler(&copied)
+	return nil
+}
+
+// Subscribe registers the handler for node, replacing any previous one.
+func (b *MemoryBus) Subscribe(node string, handler func(*Envelope)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[node] = handler
+	return nil
+}
+
+// HTTPBus is a Bus between instances that can reach each other over HTTP.
+// Each instance serves the bus at /bus on its internal bus address, never the
+// public game port, and publishes by POSTing to its peers.
+type HTTPBus struct {
+	// Peers maps node IDs to bus base URLs, e.g. "node-b" -> "http://10.0.0.2:30001".
+	Peers map[string]string
+
+	// Secret must be presented by peers in the X-Bus-Secret header. Envelopes
+	// act as any player in any game, so a bus without one accepts nothing.
+	Secret string
+
+	client  *http.Client
+	mu      sync.RWMutex
+	handler func(*Envelope)
+}
+
+// ErrNoBusSecret is returned when creating an HTTP bus without a secret.
+var ErrNoBusSecret = errors.New("bus secret is required")
+
+// NewHTTPBus creates a bus that publishes to the given peers.
+func NewHTTPBus(peers map[string]string, secret string) (*HTTPBus, error) {
+	if secret == "" {
+		return nil, ErrNoBusSecret
+	}
+	return &HTTPBus{
+		Peers:  peers,
+		Secret: secret,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}, nil
+}
+
+// Publish POSTs env to the peer's /bus endpoint.
+func (b *HTTPBus) Publish(node string, env *Envelope) error {
+	base, ok := b.Peers[node]
+	if !ok {
+		return ErrUnknownNode
+	}
+
+	data, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, base+"/bus", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Bus-Secret", b.Secret)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("bus publish to %s: %s", node, resp.Status)
+	}
+	return nil
+}
+
+// Subscribe registers the handler for envelopes POSTed to this instance.
+func (b *HTTPBus) Subscribe(node string, handler func(*Envelope)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handler = handler
+	return nil
+}
+
+// ServeHTTP receives envelopes from peers.
+func (b *HTTPBus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if !b.authorized(r) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	var env Envelope
+	if err := json.NewDecoder(r.Body).Decode(&env); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	b.mu.RLock()
+	handler := b.handler
+	b.mu.RUnlock()
+	if handler == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	handler(&env)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorized reports whether a request carries the bus secret.
+func (b *HTTPBus) authorized(r *http.Request) bool {
+	if b.Secret == "" {
+		return false
+	}
+	got := r.Header.Get("X-Bus-Secret")
+	return subtle.ConstantTimeCompare([]byte(got), []byte(b.Secret)) == 1
+}
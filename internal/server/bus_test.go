@@ -0,0 +1,63 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPBusRequiresSecret(t *testing.T) {
+	if _, err := NewHTTPBus(map[string]string{"b": "http://b"}, ""); err != ErrNoBusSecret {
+		t.Fatalf("NewHTTPBus without a secret: err = %v, want %v", err, ErrNoBusSecret)
+	}
+
+	bus, err := NewHTTPBus(nil, "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := 0
+	bus.Subscribe("a", func(*Envelope) { received++ })
+
+	for _, tc := range []struct {
+		secret string
+		want   int
+	}{
+		{"", http.StatusForbidden},
+		{"wrong", http.StatusForbidden},
+		{"s3cret", http.StatusNoContent},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/bus", strings.NewReader(`{"kind":"detach","from":"b","playerId":"p"}`))
+		if tc.secret != "" {
+			req.Header.Set("X-Bus-Secret", tc.secret)
+		}
+		rec := httptest.NewRecorder()
+		bus.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("secret %q: status %d, want %d", tc.secret, rec.Code, tc.want)
+		}
+	}
+	if received != 1 {
+		t.Errorf("handler ran %d times, want once", received)
+	}
+
+	// A bus built by hand without a secret accepts nothing
+	open := &HTTPBus{}
+	open.Subscribe("a", func(*Envelope) { received++ })
+	rec := httptest.NewRecorder()
+	open.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bus", strings.NewReader(`{}`)))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("bus without a secret: status %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestBusNeedsItsOwnAddress(t *testing.T) {
+	bus, err := NewHTTPBus(nil, "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{addr: ":30000", busAddr: ":30000"}
+	if err := s.startBus(bus); err == nil {
+		t.Error("bus started on the public game port")
+	}
+}
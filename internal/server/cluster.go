@@ -0,0 +1,244 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"lords-of-conquest/internal/protocol"
+)
+
+// When several instances share a database, every game is owned by exactly one
+// of them: the one running its actor. Ownership is a lease in the database that
+// the owner keeps renewing. A client may be connected to any instance; messages
+// for a game owned elsewhere are forwarded over the Bus to the owner, which acts
+// for the player through a remote client and sends its replies back the same way.
+
+// ownerLeaseTTL is how long a game stays with an instance that stops renewing it.
+const ownerLeaseTTL = 30 * time.Second
+
+// errGameUnavailable is reported when the instance running a game can't be reached.
+var errGameUnavailable = errors.New("game server unavailable, please try again")
+
+// ownerLease is a cached answer to "who owns this game".
+type ownerLease struct {
+	node    string
+	expires time.Time
+}
+
+// clustered reports whether this hub shares its games with other instances.
+func (h *Hub) clustered() bool {
+	return h.bus != nil
+}
+
+// startCluster subscribes to the bus and keeps this instance's leases alive.
+func (h *Hub) startCluster() error {
+	if err := h.bus.Subscribe(h.node, h.handleEnvelope); err != nil {
+		return err
+	}
+	go h.renewLeases()
+	log.Printf("Cluster: node %s joined", h.node)
+	return nil
+}
+
+// ownerOf returns the node that owns a game, claiming it for this node if
+// nobody holds a live lease. Without a cluster every game is local.
+func (h *Hub) ownerOf(gameID string) string {
+	if !h.clustered() {
+		return h.node
+	}
+
+	h.mu.RLock()
+	lease, ok := h.owners[gameID]
+	h.mu.RUnlock()
+	if ok && time.Now().Before(lease.expires) {
+		return lease.node
+	}
+
+	owner, expires, err := h.server.db.ClaimGameOwner(gameID, h.node, ownerLeaseTTL)
+	if err != nil {
+		// Most likely the game doesn't exist; handle locally and let the handler report it
+		log.Printf("Cluster: failed to claim game %s: %v", gameID, err)
+		return h.node
+	}
+
+	h.mu.Lock()
+	h.owners[gameID] = ownerLease{node: owner, expires: expires}
+	h.mu.Unlock()
+	return owner
+}
+
+// ownsGame reports whether this instance runs the game.
+func (h *Hub) ownsGame(gameID string) bool {
+	return h.ownerOf(gameID) == h.node
+}
+
+// releaseOwnership gives up this node's lease on a game it no longer runs.
+func (h *Hub) releaseOwnership(gameID string) {
+	if !h.clustered() {
+		return
+	}
+
+	h.mu.Lock()
+	delete(h.owners, gameID)
+	h.mu.Unlock()
+
+	if err := h.server.db.ReleaseGameOwner(gameID, h.node); err != nil {
+		log.Printf("Cluster: failed to release game %s: %v", gameID, err)
+	}
+}
+
+// renewLeases periodically renews the lease of every game this node is running
+// or has clients in, and drops games another node has taken over.
+func (h *Hub) renewLeases() {
+	ticker := time.NewTicker(ownerLeaseTTL / 3)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		h.mu.RLock()
+		gameIDs := make([]string, 0, len(h.games)+len(h.gameClients))
+		for gameID := range h.games {
+			gameIDs = append(gameIDs, gameID)
+		}
+		for gameID, clients := range h.gameClients {
+			if _, running := h.games[gameID]; !running && len(clients) > 0 {
+				gameIDs = append(gameIDs, gameID)
+			}
+		}
+		h.mu.RUnlock()
+
+		for _, gameID := range gameIDs {
+			owner, expires, err := h.server.db.ClaimGameOwner(gameID, h.node, ownerLeaseTTL)
+			if err != nil {
+				log.Printf("Cluster: failed to renew game %s: %v", gameID, err)
+				continue
+			}
+
+			h.mu.Lock()
+			h.owners[gameID] = ownerLease{node: owner, expires: expires}
+			h.mu.Unlock()
+
+			if owner != h.node {
+				log.Printf("Cluster: game %s moved to node %s, stopping local actor", gameID, owner)
+				h.RemoveGame(gameID)
+			}
+		}
+	}
+}
+
+// forward sends a client's message to the instance that owns the game.
+func (h *Hub) forward(client *Client, gameID string, msg *protocol.Message) {
+	owner := h.ownerOf(gameID)
+	err := h.bus.Publish(owner, &Envelope{
+		Kind:     EnvelopeCommand,
+		From:     h.node,
+		PlayerID: client.PlayerID,
+		Name:     client.Name,
+		GameID:   client.GameID,
+		Message:  msg,
+	})
+	if err != nil {
+		log.Printf("Cluster: failed to forward %s to node %s: %v", msg.Type, owner, err)
+		NewHandlers(h).sendError(client, msg.ID, errGameUnavailable)
+	}
+}
+
+// detach tells a game's owner that a forwarded client has gone away.
+func (h *Hub) detach(client *Client, gameID string) {
+	owner := h.ownerOf(gameID)
+	if owner == h.node {
+		return
+	}
+	err := h.bus.Publish(owner, &Envelope{
+		Kind:     EnvelopeDetach,
+		From:     h.node,
+		PlayerID: client.PlayerID,
+		GameID:   gameID,
+	})
+	if err != nil {
+		log.Printf("Cluster: failed to detach player %s from node %s: %v", client.PlayerID, owner, err)
+	}
+}
+
+// handleEnvelope processes an envelope published to this node.
+func (h *Hub) handleEnvelope(env *Envelope) {
+	switch env.Kind {
+	case EnvelopeCommand:
+		if env.Message == nil {
+			return
+		}
+		client := h.remoteClient(env)
+		NewHandlers(h).Handle(client, env.Message)
+
+	case EnvelopeDetach:
+		h.mu.RLock()
+		client := h.playerClients[env.PlayerID]
+		h.mu.RUnlock()
+		if client != nil && client.node == env.From {
+			h.handleDisconnect(client)
+		}
+
+	case EnvelopeDeliver:
+		h.mu.Lock()
+		client := h.playerClients[env.PlayerID]
+		if client != nil && client.node == "" {
+			// The owner decides which game the player is in
+			client.GameID = env.GameID
+		}
+		h.mu.Unlock()
+		if client != nil && client.node == "" && env.Message != nil {
+			client.Send(env.Message)
+		}
+
+	default:
+		log.Printf("Cluster: unknown envelope kind %q from node %s", env.Kind, env.From)
+	}
+}
+
+// remoteClient returns the client acting for a player connected to another
+// node, creating it on first use.
+func (h *Hub) remoteClient(env *Envelope) *Client {
+	h.mu.Lock()
+	client := h.playerClients[env.PlayerID]
+	if client != nil && client.node != "" {
+		// The player may have reconnected through a different node
+		client.node = env.From
+		client.Name = env.Name
+		h.mu.Unlock()
+		return client
+	}
+
+	client = &Client{
+		hub:      h,
+		node:     env.From,
+		PlayerID: env.PlayerID,
+		Name:     env.Name,
+	}
+	h.clients[client] = true
+	h.playerClients[env.PlayerID] = client
+	h.mu.Unlock()
+
+	// After a failover the player is already in a game; pick them up in it
+	if env.GameID != "" && h.ownsGame(env.GameID) {
+		h.AddClientToGame(client, env.GameID)
+	}
+	return client
+}
+
+// sendRemote delivers a message to a remote client through its node.
+func (h *Hub) sendRemote(client *Client, msg *protocol.Message) {
+	h.mu.RLock()
+	node, gameID := client.node, client.GameID
+	h.mu.RUnlock()
+
+	err := h.bus.Publish(node, &Envelope{
+		Kind:     EnvelopeDeliver,
+		From:     h.node,
+		PlayerID: client.PlayerID,
+		GameID:   gameID,
+		Message:  msg,
+	})
+	if err != nil {
+		log.Printf("Cluster: failed to deliver %s to player %s on node %s: %v", msg.Type, client.PlayerID, node, err)
+	}
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"path/filepath"
+	"testing"
+
+	"lords-of-conquest/internal/database"
+	"lords-of-conquest/internal/game"
+	"lords-of-conquest/internal/protocol"
+)
+
+// newClusterNode creates a server sharing dbPath and bus with other nodes.
+func newClusterNode(t *testing.T, dbPath, node string, bus Bus) *Server {
+	t.Helper()
+	s, err := New(Config{DBPath: dbPath, NodeID: node, Bus: bus})
+	if err != nil {
+		t.Fatalf("New(%s): %v", node, err)
+	}
+	t.Cleanup(func() { s.db.Close() })
+	if err := s.hub.startCluster(); err != nil {
+		t.Fatalf("startCluster(%s): %v", node, err)
+	}
+	return s
+}
+
+// receive returns the next queued message for a local client.
+func receive(t *testing.T, c *Client) *protocol.Message {
+	t.Helper()
+	select {
+	case msg := <-c.send:
+		return msg
+	default:
+		t.Fatal("expected a message for client")
+		return nil
+	}
+}
+
+func TestJoinGameOwnedByAnotherNode(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "lords.db")
+	bus := NewMemoryBus()
+	a := newClusterNode(t, dbPath, "a", bus)
+	b := newClusterNode(t, dbPath, "b", bus)
+
+	host, err := a.db.CreatePlayer("Host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	guest, err := a.db.CreatePlayer("Guest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := a.db.CreateGame("Test", host.ID, database.GameSettings{MaxPlayers: 4}, true, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Node b runs the game
+	if owner := b.hub.ownerOf(g.ID); owner != "b" {
+		t.Fatalf("b claimed game, owner = %q", owner)
+	}
+	if owner := a.hub.ownerOf(g.ID); owner != "b" {
+		t.Fatalf("a sees owner %q, want b", owner)
+	}
+
+	// The guest is connected to node a
+	client := &Client{hub: a.hub, send: make(chan *protocol.Message, 16), Name: guest.Name}
+	a.hub.clients[client] = true
+	a.hub.SetClientPlayer(client, guest.ID)
+
+	join, _ := protocol.NewMessage(protocol.TypeJoinGame, protocol.JoinGamePayload{GameID: g.ID})
+	join.ID = "join-1"
+	NewHandlers(a.hub).Handle(client, join)
+
+	reply := receive(t, client)
+	if reply.Type != protocol.TypeJoinedGame || reply.ID != "join-1" {
+		t.Fatalf("got %s (id %q), want joined_game reply", reply.Type, reply.ID)
+	}
+	if client.GameID != g.ID {
+		t.Errorf("client.GameID = %q, want %q", client.GameID, g.ID)
+	}
+	if lobby := receive(t, client); lobby.Type != protocol.TypeLobbyState {
+		t.Errorf("got %s, want lobby_state", lobby.Type)
+	}
+
+	// Node b tracks the guest; node a does not run the game
+	if !b.hub.IsPlayerOnline(guest.ID) {
+		t.Error("owner should see the guest as online")
+	}
+	if len(b.hub.clientsInGame(g.ID)) != 1 {
+		t.Errorf("owner has %d clients in game, want 1", len(b.hub.clientsInGame(g.ID)))
+	}
+	if len(a.hub.clientsInGame(g.ID)) != 0 {
+		t.Errorf("edge has %d clients in game, want 0", len(a.hub.clientsInGame(g.ID)))
+	}
+
+	// Disconnecting from node a removes the guest on node b
+	a.hub.handleDisconnect(client)
+	if b.hub.IsPlayerOnline(guest.ID) {
+		t.Error("owner still sees the guest after disconnect")
+	}
+}
+
+func TestOwnerLeaseIsExclusive(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "lords.db")
+	bus := NewMemoryBus()
+	a := newClusterNode(t, dbPath, "a", bus)
+	b := newClusterNode(t, dbPath, "b", bus)
+
+	host, err := a.db.CreatePlayer("Host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := a.db.CreateGame("Test", host.ID, database.GameSettings{MaxPlayers: 2}, true, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !a.hub.ownsGame(g.ID) {
+		t.Fatal("first claim should succeed")
+	}
+	if b.hub.ownsGame(g.ID) {
+		t.Fatal("second node must not take a live lease")
+	}
+
+	// Once released, the other node can take over
+	a.hub.releaseOwnership(g.ID)
+	b.hub.mu.Lock()
+	delete(b.hub.owners, g.ID)
+	b.hub.mu.Unlock()
+	if !b.hub.ownsGame(g.ID) {
+		t.Fatal("released game should be claimable")
+	}
+}
+
+func TestOnlyOwnerRecoversGame(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "lords.db")
+	bus := NewMemoryBus()
+	a := newClusterNode(t, dbPath, "a", bus)
+	b := newClusterNode(t, dbPath, "b", bus)
+
+	host, err := a.db.CreatePlayer("Host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := a.db.CreateGame("Test", host.ID, database.GameSettings{MaxPlayers: 2}, true, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.db.StartGame(g.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// A card battle waiting on its defender keeps the recovered actor running
+	saveTestState(t, a, g.ID, &game.GameState{
+		Settings:        game.Settings{CombatMode: game.CombatModeCards},
+		Phase:           game.PhaseConquest,
+		CurrentPlayerID: "x",
+		PlayerOrder:     []string{"x", "y"},
+		Players: map[string]*game.Player{
+			"x": {ID: "x", Name: "X", Stockpile: game.NewStockpile(), AttacksRemaining: 1},
+			"y": {ID: "y", Name: "Y", Stockpile: game.NewStockpile()},
+		},
+		Territories:       map[string]*game.Territory{"t": {ID: "t", Name: "T", Owner: "y"}},
+		PendingCardBattle: &game.PendingCardBattle{ID: "battle", AttackerID: "x", DefenderID: "y", TargetTerritory: "t"},
+	})
+
+	if !b.hub.ownsGame(g.ID) {
+		t.Fatal("b should claim the game")
+	}
+	NewHandlers(a.hub).RecoverGames()
+	NewHandlers(b.hub).RecoverGames()
+
+	if running(a.hub, g.ID) {
+		t.Error("a recovered a game b holds the lease on")
+	}
+	if owner := a.hub.ownerOf(g.ID); owner != "b" {
+		t.Errorf("owner after recovery = %q, want b", owner)
+	}
+
+	actor := b.hub.Game(g.ID)
+	drain(t, actor)
+	restored := make(chan bool, 1)
+	actor.Post(func() { restored <- actor.pendingCardBattles["battle"] != nil })
+	if !<-restored {
+		t.Error("b did not recover the game")
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"strconv"
+	"strings"
 	"time"
 
 	"lords-of-conquest/internal/database"
@@ -24,8 +26,92 @@ func NewHandlers(hub *Hub) *Handlers {
 	return &Handlers{hub: hub}
 }
 
-// Handle routes a message to the appropriate handler.
+// gameCommands are the message types that read or change a running game.
+// They are run on the game's actor so they apply one at a time, in order.
+var gameCommands = map[protocol.MessageType]bool{
+	protocol.TypeChangeColor:        true,
+	protocol.TypeStartGame:          true,
+	protocol.TypeSelectTerritory:    true,
+	protocol.TypePlaceStockpile:     true,
+	protocol.TypeMoveStockpile:      true,
+	protocol.TypeMoveUnit:           true,
+	protocol.TypeShipResources:      true,
+	protocol.TypeEndPhase:           true,
+	protocol.TypePlanAttack:         true,
+	protocol.TypeRequestAttackPlan:  true,
+	protocol.TypeExecuteAttack:      true,
+	protocol.TypeMarketTrade:        true,
+	protocol.TypeRaid:               true,
+	protocol.TypeNavalAttack:        true,
+	protocol.TypeBuild:              true,
+	protocol.TypeSetAlliance:        true,
+	protocol.TypeAllianceVote:       true,
+	protocol.TypeProposeTrade:       true,
+	protocol.TypeRespondTrade:       true,
+	protocol.TypeClientReady:        true,
+	protocol.TypeSurrender:          true,
+	protocol.TypeOfferVassalage:     true,
+	protocol.TypeAnswerVassalage:    true,
+	protocol.TypeRenameTerritory:    true,
+	protocol.TypeDrawTerritory:      true,
+	protocol.TypeBuyCard:            true,
+	protocol.TypeDiscardCard:        true,
+	protocol.TypeCombineCards:       true,
+	protocol.TypeSelectDefenseCards: true,
+}
+
+// Handle routes a message to the appropriate handler. Game commands are
+// queued on the owning game's actor; everything else runs immediately.
 func (h *Handlers) Handle(client *Client, msg *protocol.Message) {
+	// Messages for a game run by another instance go to that instance
+	if gameID := h.targetGame(client, msg); gameID != "" && !h.hub.ownsGame(gameID) {
+		h.hub.forward(client, gameID, msg)
+		return
+	}
+
+	if gameID := client.GameID; gameID != "" && gameCommands[msg.Type] {
+		h.hub.PostToGame(gameID, func() {
+			h.dispatch(client, msg)
+		})
+		return
+	}
+	h.dispatch(client, msg)
+}
+
+// targetGame returns the game a message is about when this instance shares its
+// games with others, or "" if the message can be handled on any instance.
+func (h *Handlers) targetGame(client *Client, msg *protocol.Message) string {
+	if !h.hub.clustered() || client.PlayerID == "" {
+		return ""
+	}
+
+	switch msg.Type {
+	case protocol.TypeAuthenticate, protocol.TypeCreateGame, protocol.TypeListGames, protocol.TypeYourGames,
+		protocol.TypeListMaps, protocol.TypeGetMap, protocol.TypePublishMap, protocol.TypeRateMap:
+		return ""
+	case protocol.TypeJoinGame:
+		var payload protocol.JoinGamePayload
+		if msg.ParsePayload(&payload) == nil {
+			return payload.GameID
+		}
+	case protocol.TypeJoinByCode:
+		var payload protocol.JoinByCodePayload
+		if msg.ParsePayload(&payload) == nil {
+			if game, err := h.hub.server.db.GetGameByJoinCode(payload.JoinCode); err == nil {
+				return game.ID
+			}
+		}
+	case protocol.TypeDeleteGame:
+		var payload protocol.DeleteGamePayload
+		if msg.ParsePayload(&payload) == nil {
+			return payload.GameID
+		}
+	}
+	return client.GameID
+}
+
+// dispatch calls the handler for a message and reports any error to the client.
+func (h *Handlers) dispatch(client *Client, msg *protocol.Message) {
 	var err error
 
 	switch msg.Type {
@@ -53,6 +139,16 @@ func (h *Handlers) Handle(client *Client, msg *protocol.Message) {
 		err = h.handleChangeColor(client, msg)
 	case protocol.TypeStartGame:
 		err = h.handleStartGame(client, msg)
+	case protocol.TypeListMaps:
+		err = h.handleListMaps(client, msg)
+	case protocol.TypeGetMap:
+		err = h.handleGetMap(client, msg)
+	case protocol.TypePublishMap:
+		err = h.handlePublishMap(client, msg)
+	case protocol.TypeRateMap:
+		err = h.handleRateMap(client, msg)
+	case protocol.TypeSelectMap:
+		err = h.handleSelectMap(client, msg)
 	case protocol.TypeSelectTerritory:
 		err = h.handleSelectTerritory(client, msg)
 	case protocol.TypePlaceStockpile:
@@ -61,6 +157,8 @@ func (h *Handlers) Handle(client *Client, msg *protocol.Message) {
 		err = h.handleMoveStockpile(client, msg)
 	case protocol.TypeMoveUnit:
 		err = h.handleMoveUnit(client, msg)
+	case protocol.TypeShipResources:
+		err = h.handleShipResources(client, msg)
 	case protocol.TypeEndPhase:
 		err = h.handleEndPhase(client, msg)
 	case protocol.TypePlanAttack:
@@ -69,6 +167,10 @@ func (h *Handlers) Handle(client *Client, msg *protocol.Message) {
 		err = h.handleRequestAttackPlan(client, msg)
 	case protocol.TypeExecuteAttack:
 		err = h.handleExecuteAttack(client, msg)
+	case protocol.TypeRaid:
+		err = h.handleRaid(client, msg)
+	case protocol.TypeNavalAttack:
+		err = h.handleNavalAttack(client, msg)
 	case protocol.TypeBuild:
 		err = h.handleBuild(client, msg)
 	case protocol.TypeSetAlliance:
@@ -79,6 +181,8 @@ func (h *Handlers) Handle(client *Client, msg *protocol.Message) {
 		err = h.handleProposeTrade(client, msg)
 	case protocol.TypeRespondTrade:
 		err = h.handleRespondTrade(client, msg)
+	case protocol.TypeMarketTrade:
+		err = h.handleMarketTrade(client, msg)
 	case protocol.TypeListGames:
 		err = h.handleListGames(client, msg)
 	case protocol.TypeYourGames:
@@ -87,12 +191,20 @@ func (h *Handlers) Handle(client *Client, msg *protocol.Message) {
 		err = h.handleClientReady(client, msg)
 	case protocol.TypeSurrender:
 		err = h.handleSurrender(client, msg)
+	case protocol.TypeOfferVassalage:
+		err = h.handleOfferVassalage(client, msg)
+	case protocol.TypeAnswerVassalage:
+		err = h.handleAnswerVassalage(client, msg)
 	case protocol.TypeRenameTerritory:
 		err = h.handleRenameTerritory(client, msg)
 	case protocol.TypeDrawTerritory:
 		err = h.handleDrawTerritory(client, msg)
 	case protocol.TypeBuyCard:
 		err = h.handleBuyCard(client, msg)
+	case protocol.TypeDiscardCard:
+		err = h.handleDiscardCard(client, msg)
+	case protocol.TypeCombineCards:
+		err = h.handleCombineCards(client, msg)
 	case protocol.TypeSelectDefenseCards:
 		err = h.handleSelectDefenseCards(client, msg)
 	default:
@@ -203,11 +315,18 @@ func (h *Handlers) handleCreateGame(client *Client, msg *protocol.Message) error
 			Height:      payload.MapData.Height,
 			Grid:        payload.MapData.Grid,
 			Territories: make(map[string]maps.RawTerritory),
+			Topology:    maps.Topology(payload.MapData.Topology),
+		}
+		for _, sea := range payload.MapData.Seas {
+			rawMap.Seas = append(rawMap.Seas, maps.RawSea{Name: sea.Name, Cell: sea.Cell})
 		}
+		rawMap.Symmetry = maps.Symmetry(payload.MapData.Symmetry)
+		rawMap.Starts = payload.MapData.Starts
 		for id, t := range payload.MapData.Territories {
 			rawMap.Territories[id] = maps.RawTerritory{
 				Name:     t.Name,
 				Resource: t.Resource,
+				Terrain:  t.Terrain,
 			}
 		}
 
@@ -219,7 +338,10 @@ func (h *Handlers) handleCreateGame(client *Client, msg *protocol.Message) error
 		mapJSON = string(mapBytes)
 
 		// Process and register the map in memory
-		processedMap := maps.Process(rawMap)
+		processedMap, err := maps.LoadRaw(rawMap)
+		if err != nil {
+			return err
+		}
 		maps.Register(processedMap)
 		log.Printf("Registered generated map: %s (%dx%d, %d territories)",
 			rawMap.ID, rawMap.Width, rawMap.Height, len(rawMap.Territories))
@@ -232,6 +354,18 @@ func (h *Handlers) handleCreateGame(client *Client, msg *protocol.Message) error
 		VictoryCities: payload.Settings.VictoryCities,
 		MapID:         payload.Settings.MapID,
 		CombatMode:    payload.Settings.CombatMode,
+		CardSet:       payload.Settings.CardSet,
+		Terrain:       payload.Settings.Terrain,
+		Straits:       payload.Settings.Straits,
+		PresetStarts:  payload.Settings.PresetStarts,
+		ExtendedUnits: payload.Settings.ExtendedUnits,
+		Economy:       payload.Settings.Economy,
+		Market:        payload.Settings.Market,
+		WorldEvents:   payload.Settings.WorldEvents,
+		Neutrals:      payload.Settings.Neutrals,
+		Barbarians:    payload.Settings.Barbarians,
+		Depots:        payload.Settings.Depots,
+		NavalCombat:   payload.Settings.NavalCombat,
 	}
 	if settings.MaxPlayers == 0 {
 		settings.MaxPlayers = protocol.DefaultMaxPlayers
@@ -245,12 +379,18 @@ func (h *Handlers) handleCreateGame(client *Client, msg *protocol.Message) error
 	if settings.CombatMode == "" {
 		settings.CombatMode = "classic"
 	}
+	if settings.CardSet != "" && !validCardSet(settings.CardSet) {
+		return errors.New("unknown card set: " + settings.CardSet)
+	}
 
 	game, err := h.hub.server.db.CreateGame(payload.Name, client.PlayerID, settings, payload.IsPublic, mapJSON)
 	if err != nil {
 		return err
 	}
 
+	// Claim the new game for this instance before anyone else can join it
+	h.hub.ownerOf(game.ID)
+
 	// Add creator to the game
 	if err := h.hub.server.db.JoinGame(game.ID, client.PlayerID, "orange"); err != nil {
 		return err
@@ -362,11 +502,12 @@ func (h *Handlers) joinGame(client *Client, msgID string, gameID string, preferr
 		})
 		client.Send(startedMsg)
 
-		// Then send the current game state
-		h.broadcastGameState(gameID)
-
-		// Send game history
-		h.sendGameHistory(client, gameID)
+		// Then send the current game state and history from the game's actor
+		h.hub.PostToGame(gameID, func() {
+			h.broadcastGameState(gameID)
+			h.sendGameHistory(client, gameID)
+			h.resendDefenseCardRequest(gameID, client.PlayerID)
+		})
 	} else {
 		// Game is in lobby - broadcast lobby state to all players (including the one who just joined)
 		h.broadcastLobbyState(gameID)
@@ -394,6 +535,7 @@ func (h *Handlers) handleLeaveGame(client *Client, msg *protocol.Message) error
 		// Can't leave a started game, just disconnect
 		h.hub.RemoveClientFromGame(client, gameID)
 		db.SetPlayerConnected(gameID, client.PlayerID, false)
+		h.hub.releaseGameIfIdle(gameID)
 	} else {
 		// Remove from game
 		db.LeaveGame(gameID, client.PlayerID)
@@ -479,6 +621,62 @@ func (h *Handlers) handleUpdateSettings(client *Client, msg *protocol.Message) e
 		if err := h.hub.server.db.UpdateGameSetting(client.GameID, "combat_mode", payload.Value); err != nil {
 			return err
 		}
+	case "cardSet":
+		if !validCardSet(payload.Value) {
+			return errors.New("unknown card set: " + payload.Value)
+		}
+		if err := h.hub.server.db.UpdateGameSetting(client.GameID, "card_set", payload.Value); err != nil {
+			return err
+		}
+	case "terrain":
+		if err := h.hub.server.db.UpdateGameSetting(client.GameID, "terrain", payload.Value); err != nil {
+			return err
+		}
+	case "straits":
+		if err := h.hub.server.db.UpdateGameSetting(client.GameID, "straits", payload.Value); err != nil {
+			return err
+		}
+	case "presetStarts":
+		if on, _ := strconv.ParseBool(payload.Value); on {
+			if m := h.gameMap(client.GameID, game.Settings.MapID); m == nil || len(m.Starts) == 0 {
+				return errNoStarts
+			}
+		}
+		if err := h.hub.server.db.UpdateGameSetting(client.GameID, "preset_starts", payload.Value); err != nil {
+			return err
+		}
+	case "extendedUnits":
+		if err := h.hub.server.db.UpdateGameSetting(client.GameID, "extended_units", payload.Value); err != nil {
+			return err
+		}
+	case "economy":
+		if err := h.hub.server.db.UpdateGameSetting(client.GameID, "economy", payload.Value); err != nil {
+			return err
+		}
+	case "market":
+		if err := h.hub.server.db.UpdateGameSetting(client.GameID, "market", payload.Value); err != nil {
+			return err
+		}
+	case "worldEvents":
+		if err := h.hub.server.db.UpdateGameSetting(client.GameID, "world_events", payload.Value); err != nil {
+			return err
+		}
+	case "neutrals":
+		if err := h.hub.server.db.UpdateGameSetting(client.GameID, "neutrals", payload.Value); err != nil {
+			return err
+		}
+	case "barbarians":
+		if err := h.hub.server.db.UpdateGameSetting(client.GameID, "barbarians", payload.Value); err != nil {
+			return err
+		}
+	case "depots":
+		if err := h.hub.server.db.UpdateGameSetting(client.GameID, "depots", payload.Value); err != nil {
+			return err
+		}
+	case "navalCombat":
+		if err := h.hub.server.db.UpdateGameSetting(client.GameID, "naval_combat", payload.Value); err != nil {
+			return err
+		}
 	default:
 		return errors.New("unknown setting: " + payload.Key)
 	}
@@ -520,11 +718,17 @@ func (h *Handlers) handleUpdateMap(client *Client, msg *protocol.Message) error
 	if err != nil {
 		return err
 	}
+	if _, err := maps.LoadFromJSON(mapJSON); err != nil {
+		return err
+	}
 
 	// Update map in database
 	if err := h.hub.server.db.UpdateGameMap(client.GameID, string(mapJSON)); err != nil {
 		return err
 	}
+	if err := h.hub.server.db.UpdateGameSetting(client.GameID, "map_id", payload.MapData.ID); err != nil {
+		return err
+	}
 
 	log.Printf("Host %s updated map for game %s", client.Name, client.GameID)
 
@@ -604,28 +808,18 @@ func (h *Handlers) handleChangeColor(client *Client, msg *protocol.Message) erro
 
 	if dbGame.Status == database.GameStatusStarted {
 		// Update game state with new color
-		stateJSON, err := h.hub.server.db.GetGameState(client.GameID)
+		state, err := h.loadState(client.GameID)
 		if err != nil {
 			return err
 		}
 
-		var state game.GameState
-		if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-			return err
-		}
-
 		// Update player color in state
 		if player := state.Players[client.PlayerID]; player != nil {
 			player.Color = game.PlayerColor(payload.Color)
 		}
 
 		// Save updated state
-		newStateJSON, err := json.Marshal(state)
-		if err != nil {
-			return err
-		}
-		if err := h.hub.server.db.SaveGameState(client.GameID, string(newStateJSON),
-			state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
+		if err := h.saveState(client.GameID, state); err != nil {
 			return err
 		}
 
@@ -672,17 +866,27 @@ func (h *Handlers) handleStartGame(client *Client, msg *protocol.Message) error
 		}
 	}
 
+	// Set up the game state first, so a game that can't start (e.g. a map with
+	// starting territories for a different number of players) stays in the lobby
+	state, err := h.newGameState(client.GameID, game, players)
+	if err != nil {
+		log.Printf("Failed to initialize game state: %v", err)
+		return err
+	}
+
 	// Start the game
 	if err := db.StartGame(client.GameID); err != nil {
 		return err
 	}
+	if err := h.saveState(client.GameID, state); err != nil {
+		return err
+	}
 
 	log.Printf("Game started: %s", client.GameID)
 
-	// Initialize game state
-	if err := h.initializeGameState(client.GameID, game, players); err != nil {
-		log.Printf("Failed to initialize game state: %v", err)
-		return err
+	// Count the play if the map came from the library
+	if err := db.IncrementMapPlays(game.Settings.MapID); err != nil {
+		log.Printf("Warning: Failed to count map play: %v", err)
 	}
 
 	// Log game start in history (Round 0 = Territory Selection phase)
@@ -702,7 +906,7 @@ func (h *Handlers) handleStartGame(client *Client, msg *protocol.Message) error
 	h.broadcastGameHistory(client.GameID)
 
 	// Trigger AI if first player is AI
-	go h.checkAndTriggerAI(client.GameID)
+	h.scheduleAI(client.GameID)
 
 	return nil
 }
@@ -778,6 +982,18 @@ func (h *Handlers) sendLobbyState(client *Client, gameID string) {
 			VictoryCities: game.Settings.VictoryCities,
 			MapID:         game.Settings.MapID,
 			CombatMode:    game.Settings.CombatMode,
+			CardSet:       game.Settings.CardSet,
+			Terrain:       game.Settings.Terrain,
+			Straits:       game.Settings.Straits,
+			PresetStarts:  game.Settings.PresetStarts,
+			ExtendedUnits: game.Settings.ExtendedUnits,
+			Economy:       game.Settings.Economy,
+			Market:        game.Settings.Market,
+			WorldEvents:   game.Settings.WorldEvents,
+			Neutrals:      game.Settings.Neutrals,
+			Barbarians:    game.Settings.Barbarians,
+			Depots:        game.Settings.Depots,
+			NavalCombat:   game.Settings.NavalCombat,
 		},
 		Players: lobbyPlayers,
 	}
@@ -796,11 +1012,7 @@ func (h *Handlers) sendLobbyState(client *Client, gameID string) {
 
 // broadcastLobbyState sends lobby state to all clients in a game.
 func (h *Handlers) broadcastLobbyState(gameID string) {
-	h.hub.mu.RLock()
-	clients := h.hub.gameClients[gameID]
-	h.hub.mu.RUnlock()
-
-	for client := range clients {
+	for _, client := range h.hub.clientsInGame(gameID) {
 		h.sendLobbyState(client, gameID)
 	}
 }
@@ -837,17 +1049,35 @@ func (h *Handlers) pickColor(players []*database.GamePlayer, preferred string) s
 	return "orange" // Fallback
 }
 
-// initializeGameState creates the game state from the map and players.
-func (h *Handlers) initializeGameState(gameID string, dbGame *database.Game, dbPlayers []*database.GamePlayer) error {
-	// Get the map - prioritize stored map_json (from host's map changes) over Settings.MapID
-	// First try to load from database (where host's map changes are stored)
-	mapData := h.loadMapFromDatabase(gameID, dbGame.Settings.MapID)
+// aiTurnDelay paces AI moves so clients can render the previous action.
+const aiTurnDelay = 100 * time.Millisecond
+
+// loadState returns a working copy of a game's state from its actor.
+// Must be called from the game's actor goroutine.
+func (h *Handlers) loadState(gameID string) (*game.GameState, error) {
+	return h.hub.Game(gameID).loadState()
+}
+
+// saveState persists a game's state through its actor.
+// Must be called from the game's actor goroutine.
+func (h *Handlers) saveState(gameID string, state *game.GameState) error {
+	return h.hub.Game(gameID).saveState(state)
+}
+
+// scheduleAI queues an AI turn check on the game's actor.
+// Must be called from the game's actor goroutine.
+func (h *Handlers) scheduleAI(gameID string) {
+	h.hub.Game(gameID).After(aiTurnDelay, func() {
+		h.checkAndTriggerAI(gameID)
+	})
+}
+
+// newGameState creates the game state from the map and players. Nothing is
+// saved; the caller saves the state once the game has started.
+func (h *Handlers) newGameState(gameID string, dbGame *database.Game, dbPlayers []*database.GamePlayer) (*game.GameState, error) {
+	mapData := h.gameMap(gameID, dbGame.Settings.MapID)
 	if mapData == nil {
-		// No stored map in database, fall back to in-memory registry
-		mapData = maps.Get(dbGame.Settings.MapID)
-		if mapData == nil {
-			return errors.New("map not found: " + dbGame.Settings.MapID)
-		}
+		return nil, errors.New("map not found: " + dbGame.Settings.MapID)
 	}
 
 	// Convert database players to game players
@@ -877,27 +1107,44 @@ func (h *Handlers) initializeGameState(gameID string, dbGame *database.Game, dbP
 		MapID:         dbGame.Settings.MapID,
 		MaxPlayers:    dbGame.Settings.MaxPlayers,
 		CombatMode:    game.ParseCombatMode(dbGame.Settings.CombatMode),
+		CardSet:       dbGame.Settings.CardSet,
+		Terrain:       dbGame.Settings.Terrain,
+		Straits:       dbGame.Settings.Straits,
+		PresetStarts:  dbGame.Settings.PresetStarts,
+		ExtendedUnits: dbGame.Settings.ExtendedUnits,
+		Economy:       dbGame.Settings.Economy,
+		Market:        dbGame.Settings.Market,
+		WorldEvents:   dbGame.Settings.WorldEvents,
+		Neutrals:      dbGame.Settings.Neutrals,
+		Barbarians:    dbGame.Settings.Barbarians,
+		Depots:        dbGame.Settings.Depots,
+		NavalCombat:   dbGame.Settings.NavalCombat,
 	}
 
 	// Initialize game state
 	state, err := game.InitializeGame(gameMapData, gamePlayers, gameSettings)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Set the game ID to match the database game
 	state.ID = gameID
+	return state, nil
+}
 
-	// Serialize state to JSON
-	stateJSON, err := json.Marshal(state)
-	if err != nil {
-		return err
+// gameMap returns the map a game is played on: the map stored with the game
+// (from the host's map changes) if there is one, otherwise the registry's.
+func (h *Handlers) gameMap(gameID, mapID string) *maps.Map {
+	if mapData := h.loadMapFromDatabase(gameID, mapID); mapData != nil {
+		return mapData
 	}
-
-	// Save to database
-	return h.hub.server.db.SaveGameState(gameID, string(stateJSON), state.CurrentPlayerID, state.Round, state.Phase.String())
+	return maps.Get(mapID)
 }
 
+// errNoStarts is returned when preset starts are asked for on a map without
+// starting territories.
+var errNoStarts = errors.New("the map has no starting territories for preset starts")
+
 // convertMapToGameData converts a map to game initialization data.
 func convertMapToGameData(m *maps.Map) game.MapData {
 	territories := make(map[string]game.TerritoryData)
@@ -915,6 +1162,7 @@ func convertMapToGameData(m *maps.Map) game.MapData {
 		territories[maps.TerritoryIDToString(id)] = game.TerritoryData{
 			Name:         t.Name,
 			Resource:     t.Resource,
+			Terrain:      t.Terrain,
 			Adjacent:     adjacent,
 			CoastalTiles: t.CoastalCells,
 			WaterBodies:  waters,
@@ -929,45 +1177,67 @@ func convertMapToGameData(m *maps.Map) game.MapData {
 		}
 
 		waterBodies[maps.WaterIDToString(id)] = game.WaterBodyData{
+			Name:        wb.Name,
 			Territories: coastal,
 		}
 	}
 
+	starts := make([][]string, len(m.Starts))
+	for i, start := range m.Starts {
+		for _, tid := range start {
+			starts[i] = append(starts[i], maps.TerritoryIDToString(tid))
+		}
+	}
+
 	return game.MapData{
 		ID:          m.ID,
 		Name:        m.Name,
 		Territories: territories,
 		WaterBodies: waterBodies,
+		Starts:      starts,
 	}
 }
 
 // broadcastGameState sends the current game state to all players.
-// If there are skipped phases, it broadcasts those first with acknowledgment,
-// then broadcasts the game state. Returns true if there were skipped phases
-// (meaning caller should NOT trigger AI immediately - it will be triggered after acks).
+// If there are world events or skipped phases, it broadcasts those first with
+// acknowledgment, then broadcasts the game state. Returns true if there were
+// any (meaning caller should NOT trigger AI immediately - it will be triggered after acks).
 func (h *Handlers) broadcastGameState(gameID string) bool {
-	// Load game state from database
-	stateJSON, err := h.hub.server.db.GetGameState(gameID)
+	state, err := h.loadState(gameID)
 	if err != nil {
-		log.Printf("Failed to load game state: %v", err)
+		log.Printf("Failed to load game state for game %s: %v", gameID, err)
 		return false
 	}
 
-	if stateJSON == "" {
-		log.Printf("No game state found for game %s", gameID)
-		return false
-	}
+	// Announce world events first: they happen at the very start of the round
+	if len(state.WorldEvents) > 0 {
+		events := state.WorldEvents
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		log.Printf("Failed to unmarshal game state: %v", err)
-		return false
+		// Clear world events and save immediately
+		state.WorldEvents = nil
+		if err := h.saveState(gameID, state); err != nil {
+			log.Printf("Failed to save game state: %v", err)
+		}
+
+		for _, event := range events {
+			h.logHistory(gameID, state.Round, state.Phase.String(), "", "",
+				database.EventWorldEvent, fmt.Sprintf("%s! %s", event.Name, event.Description))
+		}
+
+		// After the events are acknowledged, carry on with production or skips
+		h.broadcastWorldEventsWithAck(gameID, events, 0, func() {
+			if !h.broadcastGameState(gameID) {
+				h.scheduleAI(gameID)
+			}
+		})
+
+		return true
 	}
 
 	// Check for production pending - need to trigger production animation
 	if state.ProductionPending {
 		log.Printf("Production pending - triggering animation for game %s", gameID)
-		h.triggerProductionAnimation(gameID, &state)
+		h.triggerProductionAnimation(gameID, state)
 		return true // Caller should NOT trigger AI - we'll do it after production completes
 	}
 
@@ -977,15 +1247,15 @@ func (h *Handlers) broadcastGameState(gameID string) bool {
 
 		// Clear skipped phases and save immediately
 		state.SkippedPhases = nil
-		updatedJSON, _ := json.Marshal(state)
-		h.hub.server.db.SaveGameState(gameID, string(updatedJSON),
-			state.CurrentPlayerID, state.Round, state.Phase.String())
+		if err := h.saveState(gameID, state); err != nil {
+			log.Printf("Failed to save game state: %v", err)
+		}
 
 		// Chain the phase skip broadcasts with acknowledgment
 		h.broadcastPhaseSkipsWithAck(gameID, skips, 0, func() {
 			// After all phase skips are acknowledged, broadcast game state and trigger AI
 			h.broadcastGameStateImmediate(gameID)
-			go h.checkAndTriggerAI(gameID)
+			h.scheduleAI(gameID)
 		})
 
 		return true // Caller should NOT trigger AI - we'll do it after acks
@@ -1013,7 +1283,7 @@ func (h *Handlers) broadcastGameState(gameID string) bool {
 
 	// Create payload with state and map rendering data
 	payload := protocol.GameStatePayload{
-		State: createStatePayload(&state, mapData),
+		State: createStatePayload(state, mapData),
 	}
 
 	log.Printf("Broadcasting game state for game %s", gameID)
@@ -1029,19 +1299,9 @@ func (h *Handlers) broadcastGameState(gameID string) bool {
 // broadcastGameStateImmediate broadcasts game state without checking for skipped phases.
 // Used as a callback after phase skips are acknowledged.
 func (h *Handlers) broadcastGameStateImmediate(gameID string) {
-	stateJSON, err := h.hub.server.db.GetGameState(gameID)
+	state, err := h.loadState(gameID)
 	if err != nil {
-		log.Printf("Failed to load game state: %v", err)
-		return
-	}
-
-	if stateJSON == "" {
-		return
-	}
-
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		log.Printf("Failed to unmarshal game state: %v", err)
+		log.Printf("Failed to load game state for game %s: %v", gameID, err)
 		return
 	}
 
@@ -1063,7 +1323,7 @@ func (h *Handlers) broadcastGameStateImmediate(gameID string) {
 	}
 
 	payload := protocol.GameStatePayload{
-		State: createStatePayload(&state, mapData),
+		State: createStatePayload(state, mapData),
 	}
 
 	h.hub.notifyGamePlayers(gameID, protocol.TypeGameState, payload)
@@ -1096,6 +1356,32 @@ func (h *Handlers) broadcastPhaseSkipsWithAck(gameID string, skips []game.PhaseS
 	})
 }
 
+// broadcastWorldEventsWithAck broadcasts world events one at a time,
+// waiting for acknowledgment before proceeding to the next.
+func (h *Handlers) broadcastWorldEventsWithAck(gameID string, events []game.WorldEvent, index int, onComplete func()) {
+	if index >= len(events) {
+		onComplete()
+		return
+	}
+
+	event := events[index]
+	eventID := fmt.Sprintf("event-%s-%d-%d", gameID, time.Now().UnixNano(), index)
+	payload := protocol.WorldEventPayload{
+		EventID:     eventID,
+		Type:        string(event.Type),
+		Name:        event.Name,
+		Description: event.Description,
+		TerritoryID: event.TerritoryID,
+		WaterBodyID: event.WaterBodyID,
+	}
+
+	log.Printf("Broadcasting world event %d/%d: %s - %s", index+1, len(events), event.Name, event.Description)
+
+	h.broadcastWithAck(gameID, eventID, protocol.EventWorldEvent, protocol.TypeWorldEvent, payload, func() {
+		h.broadcastWorldEventsWithAck(gameID, events, index+1, onComplete)
+	})
+}
+
 // loadMapFromDatabase loads a map from the database and registers it.
 func (h *Handlers) loadMapFromDatabase(gameID, mapID string) *maps.Map {
 	mapJSON, err := h.hub.server.db.GetGameMapJSON(gameID)
@@ -1132,6 +1418,7 @@ func createStatePayload(state *game.GameState, mapData *maps.Map) map[string]int
 			"name":         t.Name,
 			"owner":        t.Owner,
 			"resource":     t.Resource.String(),
+			"terrain":      string(t.Terrain),
 			"hasCity":      t.HasCity,
 			"hasWeapon":    t.HasWeapon,
 			"hasHorse":     t.HasHorse,
@@ -1141,6 +1428,17 @@ func createStatePayload(state *game.GameState, mapData *maps.Map) map[string]int
 			"waterBodies":  t.WaterBodies,
 			"adjacent":     t.Adjacent, // Adjacent territory IDs for city influence check
 		}
+		if state.Settings.ExtendedUnits {
+			terrData["fortresses"] = t.Fortresses
+			terrData["siegeEngines"] = t.SiegeEngines
+			terrData["transports"] = t.Transports // Map of water body ID -> how many boats are transports
+		}
+		if t.Garrison > 0 {
+			terrData["garrison"] = t.Garrison
+		}
+		if state.Settings.Economy && t.Specialization != "" {
+			terrData["specialization"] = string(t.Specialization)
+		}
 		if len(t.Drawing) > 0 {
 			terrData["drawing"] = t.Drawing
 		}
@@ -1169,11 +1467,33 @@ func createStatePayload(state *game.GameState, mapData *maps.Map) map[string]int
 				"timber": p.Stockpile.Timber,
 			}
 		}
+		if state.Settings.Economy {
+			playerData["storageCap"] = state.StorageCap(id)
+		}
+		if p.Overlord != "" {
+			playerData["overlord"] = p.Overlord
+		}
+		if p.VassalOffer != "" {
+			playerData["vassalOffer"] = p.VassalOffer
+		}
+		if len(p.Depots) > 0 {
+			depots := make(map[string]interface{})
+			for tid, d := range p.Depots {
+				depots[tid] = map[string]interface{}{
+					"coal":   d.Coal,
+					"gold":   d.Gold,
+					"iron":   d.Iron,
+					"timber": d.Timber,
+				}
+			}
+			playerData["depots"] = depots
+		}
 
 		// Include combat cards if card mode is active
 		if state.Settings.CombatMode == game.CombatModeCards {
 			playerData["attackCards"] = p.AttackCards
 			playerData["defenseCards"] = p.DefenseCards
+			playerData["deckSize"] = p.DeckSize()
 		}
 
 		players[id] = playerData
@@ -1184,6 +1504,7 @@ func createStatePayload(state *game.GameState, mapData *maps.Map) map[string]int
 	for id, wb := range mapData.WaterBodies {
 		waterBodies[maps.WaterIDToString(id)] = map[string]interface{}{
 			"id":          maps.WaterIDToString(id),
+			"name":        wb.Name,
 			"cells":       wb.Cells,
 			"territories": wb.CoastalTerritories,
 		}
@@ -1198,9 +1519,10 @@ func createStatePayload(state *game.GameState, mapData *maps.Map) map[string]int
 		"grid":        mapData.Grid,
 		"waterGrid":   mapData.WaterGrid,
 		"waterBodies": waterBodies,
+		"topology":    string(mapData.Topology),
 	}
 
-	return map[string]interface{}{
+	payload := map[string]interface{}{
 		"gameId":                    state.ID,
 		"round":                     state.Round,
 		"phase":                     state.Phase.String(),
@@ -1212,10 +1534,31 @@ func createStatePayload(state *game.GameState, mapData *maps.Map) map[string]int
 		"stockpilePlacementPending": state.StockpilePlacementPending,
 		"settings": map[string]interface{}{
 			"combatMode":    int(state.Settings.CombatMode),
+			"cardSet":       state.CardSet().ID,
 			"chanceLevel":   int(state.Settings.ChanceLevel),
 			"victoryCities": state.Settings.VictoryCities,
+			"terrain":       state.Settings.Terrain,
+			"straits":       state.Settings.Straits,
+			"extendedUnits": state.Settings.ExtendedUnits,
+			"economy":       state.Settings.Economy,
+			"market":        state.Settings.Market,
+			"worldEvents":   state.Settings.WorldEvents,
+			"neutrals":      state.Settings.Neutrals,
+			"barbarians":    state.Settings.Barbarians,
+			"depots":        state.Settings.Depots,
+			"navalCombat":   state.Settings.NavalCombat,
 		},
 	}
+	if state.Harvest {
+		payload["harvest"] = true
+	}
+	if state.StormWater != "" {
+		payload["stormWater"] = state.StormWater
+	}
+	if state.Market != nil {
+		payload["market"] = state.Market // Prices, this round's trading and price history
+	}
+	return payload
 }
 
 // handleSelectTerritory handles territory selection during the initial phase.
@@ -1230,16 +1573,11 @@ func (h *Handlers) handleSelectTerritory(client *Client, msg *protocol.Message)
 	}
 
 	// Load game state
-	stateJSON, err := h.hub.server.db.GetGameState(client.GameID)
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		return err
-	}
-
 	// Get territory name before selection
 	terrName := payload.TerritoryID
 	if terr, ok := state.Territories[payload.TerritoryID]; ok {
@@ -1260,13 +1598,7 @@ func (h *Handlers) handleSelectTerritory(client *Client, msg *protocol.Message)
 		database.EventTerritorySelected, fmt.Sprintf("Selected %s", terrName))
 
 	// Save updated state
-	stateJSON2, err := json.Marshal(state)
-	if err != nil {
-		return err
-	}
-
-	if err := h.hub.server.db.SaveGameState(client.GameID, string(stateJSON2),
-		state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
+	if err := h.saveState(client.GameID, state); err != nil {
 		return err
 	}
 
@@ -1276,7 +1608,7 @@ func (h *Handlers) handleSelectTerritory(client *Client, msg *protocol.Message)
 	// and AI will be triggered after acknowledgment
 	if !h.broadcastGameState(client.GameID) {
 		// No phase skips, trigger AI immediately
-		go h.checkAndTriggerAI(client.GameID)
+		h.scheduleAI(client.GameID)
 	}
 
 	return nil
@@ -1294,16 +1626,11 @@ func (h *Handlers) handlePlaceStockpile(client *Client, msg *protocol.Message) e
 	}
 
 	// Load game state
-	stateJSON, err := h.hub.server.db.GetGameState(client.GameID)
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		return err
-	}
-
 	// Get territory name before placement
 	terrName := payload.TerritoryID
 	if terr, ok := state.Territories[payload.TerritoryID]; ok {
@@ -1320,13 +1647,7 @@ func (h *Handlers) handlePlaceStockpile(client *Client, msg *protocol.Message) e
 		database.EventStockpilePlaced, fmt.Sprintf("Placed stockpile on %s", terrName))
 
 	// Save updated state
-	stateJSON2, err := json.Marshal(state)
-	if err != nil {
-		return err
-	}
-
-	if err := h.hub.server.db.SaveGameState(client.GameID, string(stateJSON2),
-		state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
+	if err := h.saveState(client.GameID, state); err != nil {
 		return err
 	}
 
@@ -1340,14 +1661,14 @@ func (h *Handlers) handlePlaceStockpile(client *Client, msg *protocol.Message) e
 		log.Printf("All stockpiles placed - triggering production animation")
 		// Set production pending flag and save
 		state.ProductionPending = true
-		stateJSON3, _ := json.Marshal(state)
-		h.hub.server.db.SaveGameState(client.GameID, string(stateJSON3),
-			state.CurrentPlayerID, state.Round, state.Phase.String())
+		if err := h.saveState(client.GameID, state); err != nil {
+			return err
+		}
 
-		h.triggerProductionAnimation(client.GameID, &state)
+		h.triggerProductionAnimation(client.GameID, state)
 	} else {
 		// Still waiting for stockpiles, trigger AI placement
-		go h.checkAndTriggerAI(client.GameID)
+		h.scheduleAI(client.GameID)
 	}
 
 	return nil
@@ -1367,6 +1688,11 @@ func parseChanceLevel(s string) game.ChanceLevel {
 	}
 }
 
+// validCardSet reports whether there's a card set with the given ID.
+func validCardSet(id string) bool {
+	return game.CardSets[id] != nil
+}
+
 func parseAIPersonality(s string) game.AIPersonality {
 	switch s {
 	case "aggressive":
@@ -1403,11 +1729,10 @@ func (h *Handlers) handleYourGames(client *Client, msg *protocol.Message) error
 		if g.Status == database.GameStatusStarted {
 			stateJSON, err := h.hub.server.db.GetGameState(g.ID)
 			if err == nil && stateJSON != "" {
-				var state game.GameState
-				if err := json.Unmarshal([]byte(stateJSON), &state); err == nil {
+				if state, err := game.UnmarshalState([]byte(stateJSON)); err == nil {
 					isYourTurn = state.CurrentPlayerID == client.PlayerID
 					round = state.Round
-					phase = string(state.Phase)
+					phase = state.Phase.String()
 
 					// Get player names from state
 					for _, p := range state.Players {
@@ -1517,6 +1842,7 @@ func (h *Handlers) handleDeleteGame(client *Client, msg *protocol.Message) error
 		delete(h.hub.gameClients, payload.GameID)
 	}
 	h.hub.mu.Unlock()
+	h.hub.RemoveGame(payload.GameID)
 
 	// Send confirmation to requester
 	respMsg, _ := protocol.NewMessage(protocol.TypeGameDeleted, protocol.GameDeletedPayload{
@@ -1549,8 +1875,7 @@ func (h *Handlers) sendUpdatedGameList(client *Client) {
 		if g.Status == database.GameStatusStarted {
 			stateJSON, err := h.hub.server.db.GetGameState(g.ID)
 			if err == nil && stateJSON != "" {
-				var state game.GameState
-				if err := json.Unmarshal([]byte(stateJSON), &state); err == nil {
+				if state, err := game.UnmarshalState([]byte(stateJSON)); err == nil {
 					isYourTurn = state.CurrentPlayerID == client.PlayerID
 				}
 			}
@@ -1574,23 +1899,15 @@ func (h *Handlers) sendUpdatedGameList(client *Client) {
 // ==================== AI Logic ====================
 
 // checkAndTriggerAI checks if the current player is an AI and triggers their move.
+// Runs on the game's actor, normally via scheduleAI.
 func (h *Handlers) checkAndTriggerAI(gameID string) {
-	// Small delay to ensure state is fully saved and broadcast
-	time.Sleep(100 * time.Millisecond)
-
 	// Load current game state
-	stateJSON, err := h.hub.server.db.GetGameState(gameID)
+	state, err := h.loadState(gameID)
 	if err != nil {
 		log.Printf("AI: Failed to load game state: %v", err)
 		return
 	}
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		log.Printf("AI: Failed to unmarshal game state: %v", err)
-		return
-	}
-
 	// Note: We don't check IsGameOver() here because city victory is only
 	// evaluated at end of round (after Conquest phase). The proper game over
 	// handling is done in NextPhase for Conquest.
@@ -1605,7 +1922,7 @@ func (h *Handlers) checkAndTriggerAI(gameID string) {
 	// This needs to happen regardless of whose turn it is
 	if state.Phase == game.PhaseProduction && state.StockpilePlacementPending {
 		log.Printf("AI: Production phase with stockpile placement pending - checking for AI placements")
-		h.aiPlaceStockpile(gameID, &state)
+		h.aiPlaceStockpile(gameID, state)
 		return
 	}
 
@@ -1632,19 +1949,19 @@ func (h *Handlers) checkAndTriggerAI(gameID string) {
 	// Trigger AI action based on phase
 	switch state.Phase {
 	case game.PhaseTerritorySelection:
-		h.aiSelectTerritory(gameID, &state)
+		h.aiSelectTerritory(gameID, state)
 	case game.PhaseProduction:
 		// After round 1, production is automatic - just advance
 		log.Printf("AI: Production is automatic, skipping")
 	case game.PhaseTrade:
 		// AI doesn't trade for now, just skip
-		h.aiSkipTrade(gameID, &state)
+		h.aiSkipTrade(gameID, state)
 	case game.PhaseShipment:
-		h.aiShipment(gameID, &state)
+		h.aiShipment(gameID, state)
 	case game.PhaseConquest:
-		h.aiConquest(gameID, &state)
+		h.aiConquest(gameID, state)
 	case game.PhaseDevelopment:
-		h.aiDevelopment(gameID, &state)
+		h.aiDevelopment(gameID, state)
 	default:
 		log.Printf("AI: No handler for phase: %s", state.Phase)
 	}
@@ -1663,7 +1980,7 @@ func (h *Handlers) aiSelectTerritory(gameID string, state *game.GameState) {
 	if len(availableTerritories) == 0 {
 		log.Printf("AI: No territories available to select")
 		// Still trigger next AI check in case phase changed
-		go h.checkAndTriggerAI(gameID)
+		h.scheduleAI(gameID)
 		return
 	}
 
@@ -1686,7 +2003,7 @@ func (h *Handlers) aiSelectTerritory(gameID string, state *game.GameState) {
 	if err := state.SelectTerritory(state.CurrentPlayerID, selectedID); err != nil {
 		log.Printf("AI: Failed to select territory: %v", err)
 		// Try again after a delay
-		go h.checkAndTriggerAI(gameID)
+		h.scheduleAI(gameID)
 		return
 	}
 
@@ -1700,7 +2017,7 @@ func (h *Handlers) aiSelectTerritory(gameID string, state *game.GameState) {
 	log.Printf("AI: Successfully selected territory %s", selectedID)
 
 	// Check if next player is also AI
-	go h.checkAndTriggerAI(gameID)
+	h.scheduleAI(gameID)
 }
 
 // aiPlaceStockpile places all AI players' stockpiles during stockpile placement phase.
@@ -1763,9 +2080,10 @@ func (h *Handlers) aiPlaceStockpile(gameID string, state *game.GameState) {
 
 	if anyPlaced {
 		// Save state
-		stateJSON, _ := json.Marshal(state)
-		h.hub.server.db.SaveGameState(gameID, string(stateJSON),
-			state.CurrentPlayerID, state.Round, state.Phase.String())
+		if err := h.saveState(gameID, state); err != nil {
+			log.Printf("AI: Failed to save state: %v", err)
+			return
+		}
 
 		// Broadcast updated state
 		h.broadcastGameStateImmediate(gameID)
@@ -1774,9 +2092,10 @@ func (h *Handlers) aiPlaceStockpile(gameID string, state *game.GameState) {
 		if state.AllStockpilesPlaced() {
 			log.Printf("AI: All stockpiles placed - triggering production animation")
 			state.ProductionPending = true
-			stateJSON2, _ := json.Marshal(state)
-			h.hub.server.db.SaveGameState(gameID, string(stateJSON2),
-				state.CurrentPlayerID, state.Round, state.Phase.String())
+			if err := h.saveState(gameID, state); err != nil {
+				log.Printf("AI: Failed to save state: %v", err)
+				return
+			}
 
 			h.triggerProductionAnimation(gameID, state)
 		} else {
@@ -1786,8 +2105,13 @@ func (h *Handlers) aiPlaceStockpile(gameID string, state *game.GameState) {
 	}
 }
 
-// aiSkipTrade skips the trade phase (AI doesn't trade for now).
+// aiSkipTrade skips the trade phase. The AI doesn't trade with other players
+// for now, but trades with the market when there is one.
 func (h *Handlers) aiSkipTrade(gameID string, state *game.GameState) {
+	if state.Market != nil {
+		h.aiMarketTrade(gameID, state)
+	}
+
 	log.Printf("AI: Skipping trade phase for player %s", state.CurrentPlayerID)
 
 	if err := state.SkipTrade(state.CurrentPlayerID); err != nil {
@@ -1797,10 +2121,51 @@ func (h *Handlers) aiSkipTrade(gameID string, state *game.GameState) {
 
 	// Save state
 	if !h.saveAndBroadcastAIState(gameID, state) {
-		go h.checkAndTriggerAI(gameID)
+		h.scheduleAI(gameID)
+	}
+}
+
+// aiMarketTrade sells the resource the AI holds most of for the one it holds
+// least of, but only while the first sells at or above its recent average
+// price and the second doesn't cost more than its average.
+func (h *Handlers) aiMarketTrade(gameID string, state *game.GameState) {
+	player := state.Players[state.CurrentPlayerID]
+	if player == nil {
+		return
+	}
+	m := state.Market
+
+	resources := []game.ResourceType{game.ResourceCoal, game.ResourceGold, game.ResourceIron, game.ResourceTimber}
+	most, least := resources[0], resources[0]
+	for _, r := range resources {
+		if player.Stockpile.Get(r) > player.Stockpile.Get(most) {
+			most = r
+		}
+		if player.Stockpile.Get(r) < player.Stockpile.Get(least) {
+			least = r
+		}
+	}
+
+	surplus := (player.Stockpile.Get(most) - player.Stockpile.Get(least)) / 2
+	if most == least || surplus < 2 {
+		return
+	}
+	if float64(m.SellPrice(most)) < m.AveragePrice(most, aiMarketMemory) ||
+		float64(m.Prices.Get(least)) > m.AveragePrice(least, aiMarketMemory) {
+		return
+	}
+
+	if got, err := state.MarketTrade(player.ID, most, surplus, least); err == nil {
+		log.Printf("AI: Sold %d %s to the market for %d %s", surplus, most, got, least)
+		h.logHistory(gameID, state.Round, state.Phase.String(), player.ID, player.Name,
+			database.EventMarketTrade, fmt.Sprintf("Sold %d %s to the market for %d %s",
+				surplus, strings.ToLower(most.String()), got, strings.ToLower(least.String())))
 	}
 }
 
+// aiMarketMemory is how many rounds of price history the AI averages over.
+const aiMarketMemory = 5
+
 // aiShipment handles the AI's shipment phase.
 func (h *Handlers) aiShipment(gameID string, state *game.GameState) {
 	player := state.Players[state.CurrentPlayerID]
@@ -1834,7 +2199,7 @@ func (h *Handlers) aiShipment(gameID string, state *game.GameState) {
 
 	// Save state
 	if !h.saveAndBroadcastAIState(gameID, state) {
-		go h.checkAndTriggerAI(gameID)
+		h.scheduleAI(gameID)
 	}
 }
 
@@ -1845,6 +2210,11 @@ func (h *Handlers) aiConquest(gameID string, state *game.GameState) {
 		return
 	}
 
+	// Wait for the defender; the card battle schedules the AI once it resolves
+	if state.PendingCardBattle != nil {
+		return
+	}
+
 	// If no attacks remaining, end conquest phase
 	if player.AttacksRemaining <= 0 {
 		log.Printf("AI: No attacks remaining, ending conquest")
@@ -1857,7 +2227,7 @@ func (h *Handlers) aiConquest(gameID string, state *game.GameState) {
 			return
 		}
 		if !h.saveAndBroadcastAIState(gameID, state) {
-			go h.checkAndTriggerAI(gameID)
+			h.scheduleAI(gameID)
 		}
 		return
 	}
@@ -1876,12 +2246,12 @@ func (h *Handlers) aiConquest(gameID string, state *game.GameState) {
 			return
 		}
 		if !h.saveAndBroadcastAIState(gameID, state) {
-			go h.checkAndTriggerAI(gameID)
+			h.scheduleAI(gameID)
 		}
 		return
 	}
 
-	// Simple AI: Try to attack if we have a strength advantage
+	// Simple AI: attack the target we're most likely to take
 	var bestTarget string
 	var bestOdds float64 = 0
 
@@ -1891,21 +2261,18 @@ func (h *Handlers) aiConquest(gameID string, state *game.GameState) {
 			continue
 		}
 
-		// Calculate odds
-		if plan.DefenseStrength == 0 {
-			bestTarget = targetID
-			bestOdds = 999
-			break
-		}
-		odds := float64(plan.AttackStrength) / float64(plan.DefenseStrength)
+		odds := state.AttackOdds(plan.AttackStrength, plan.DefenseStrength, state.Territories[targetID])
 		if odds > bestOdds {
 			bestOdds = odds
 			bestTarget = targetID
 		}
+		if odds >= 1 {
+			break
+		}
 	}
 
-	// Only attack if we have at least 1:1 odds (simple AI)
-	if bestTarget != "" && bestOdds >= 1.0 {
+	// Only attack if we're at least as likely to win as to lose
+	if bestTarget != "" && bestOdds >= 0.5 {
 		// Capture attacker ID BEFORE attack (Attack() may advance turn)
 		attackerID := state.CurrentPlayerID
 
@@ -1924,9 +2291,7 @@ func (h *Handlers) aiConquest(gameID string, state *game.GameState) {
 		aiDefenderName := ""
 		if terr, ok := state.Territories[bestTarget]; ok {
 			aiDefenderID = terr.Owner
-			if dp, ok := state.Players[terr.Owner]; ok {
-				aiDefenderName = dp.Name
-			}
+			aiDefenderName = state.OwnerName(terr.Owner)
 		}
 
 		log.Printf("AI: Attacking %s (odds: %.2f)", bestTarget, bestOdds)
@@ -1949,7 +2314,7 @@ func (h *Handlers) aiConquest(gameID string, state *game.GameState) {
 				return
 			}
 			if !h.saveAndBroadcastAIState(gameID, state) {
-				go h.checkAndTriggerAI(gameID)
+				h.scheduleAI(gameID)
 			}
 		} else {
 			// Log history
@@ -1962,6 +2327,7 @@ func (h *Handlers) aiConquest(gameID string, state *game.GameState) {
 				h.logHistory(gameID, state.Round, state.Phase.String(), attackerID, playerName,
 					database.EventAttackFailed, fmt.Sprintf("Attack on %s failed", terrName))
 			}
+			h.logFreedVassal(gameID, state, result.FreedVassal)
 
 			// Broadcast combat result and wait for client acknowledgment
 			unitsDestroyed := make([]string, 0)
@@ -1998,6 +2364,7 @@ func (h *Handlers) aiConquest(gameID string, state *game.GameState) {
 				combatResult.CapturedTimber = result.StockpileCaptured.Timber
 				combatResult.CapturedFromTerritory = bestTarget
 			}
+			combatResult.FreedVassal = result.FreedVassal
 
 			// Check for elimination victory (city victory waits for end of round)
 			if state.IsEliminationVictory() {
@@ -2007,13 +2374,16 @@ func (h *Handlers) aiConquest(gameID string, state *game.GameState) {
 				return
 			}
 
-			// Use broadcastWithAck to wait for clients before proceeding
-			// Capture state for the closure
-			stateCopy := state
+			// Save now so commands handled while clients watch the battle
+			// see its outcome, then wait for clients before proceeding
+			if err := h.saveState(gameID, state); err != nil {
+				log.Printf("AI: Failed to save state: %v", err)
+				return
+			}
 			h.broadcastWithAck(gameID, eventID, protocol.EventCombat, protocol.TypeActionResult, combatResult, func() {
 				// Called after all clients acknowledge (or timeout)
-				if !h.saveAndBroadcastAIState(gameID, stateCopy) {
-					go h.checkAndTriggerAI(gameID)
+				if !h.broadcastGameState(gameID) {
+					h.scheduleAI(gameID)
 				}
 			})
 		}
@@ -2036,7 +2406,7 @@ func (h *Handlers) aiConquest(gameID string, state *game.GameState) {
 
 	// Save state
 	if !h.saveAndBroadcastAIState(gameID, state) {
-		go h.checkAndTriggerAI(gameID)
+		h.scheduleAI(gameID)
 	}
 }
 
@@ -2049,14 +2419,12 @@ func (h *Handlers) aiCardAttack(gameID string, state *game.GameState, attackerID
 	}
 
 	// AI selects attack cards: play all available attack cards
-	var attackCards []game.CombatCard
-	if len(attacker.AttackCards) > 0 {
-		attackCards = make([]game.CombatCard, len(attacker.AttackCards))
-		copy(attackCards, attacker.AttackCards)
-		attacker.AttackCards = []game.CombatCard{} // Remove from hand
+	attackCardIDs := make([]string, len(attacker.AttackCards))
+	for i, c := range attacker.AttackCards {
+		attackCardIDs[i] = c.ID
 	}
 
-	log.Printf("AI Card Combat: %s attacking %s with %d cards", playerName, terrName, len(attackCards))
+	log.Printf("AI Card Combat: %s attacking %s with %d cards", playerName, terrName, len(attackCardIDs))
 
 	// Find the defender
 	target := state.Territories[targetID]
@@ -2065,14 +2433,7 @@ func (h *Handlers) aiCardAttack(gameID string, state *game.GameState, attackerID
 		return
 	}
 	defenderID := target.Owner
-	defenderName := defenderID
-	if dp := state.Players[defenderID]; dp != nil {
-		defenderName = dp.Name
-	}
-
-	// Calculate base strengths
-	baseAttack := state.CalculateAttackWithAllies(attackerID, target, nil, nil)
-	baseDefense := state.CalculateDefenseWithAllies(target, nil)
+	defenderName := state.OwnerName(defenderID)
 
 	// Create a synthetic client for the AI attacker (used by resolveCardCombat/finishAttack)
 	aiClient := &Client{
@@ -2098,105 +2459,41 @@ func (h *Handlers) aiCardAttack(gameID string, state *game.GameState, attackerID
 		}
 
 		// Resolve immediately
+		attackCards := attacker.TakeCards(game.CardTypeAttack, attackCardIDs)
 		err := h.resolveCardCombat(aiClient, state, payload, nil, nil, nil, attackCards, defenseCards, terrName, defenderID, defenderName)
 		if err != nil {
 			log.Printf("AI Card Combat: resolve error: %v", err)
 			state.EndConquest(attackerID)
 			if !h.saveAndBroadcastAIState(gameID, state) {
-				go h.checkAndTriggerAI(gameID)
+				h.scheduleAI(gameID)
 			}
 		}
 		return
 	}
 
-	// Human defender: save state, send defense card request, wait for response
-	stateJSON, err := json.Marshal(state)
-	if err != nil {
-		log.Printf("AI Card Combat: marshal error: %v", err)
-		return
-	}
-	if err := h.hub.server.db.SaveGameState(gameID, string(stateJSON),
-		state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
-		log.Printf("AI Card Combat: save error: %v", err)
-		return
-	}
-
-	// Create pending card battle
-	battleID := fmt.Sprintf("card-battle-%s-%d", gameID, time.Now().UnixNano())
-	pendingBattle := &PendingCardBattle{
-		ID:               battleID,
-		GameID:           gameID,
-		AttackerID:       attackerID,
-		DefenderID:       defenderID,
-		TargetTerritory:  targetID,
-		BroughtUnit:      nil,
-		AttackerAllies:   nil,
-		DefenderAllies:   nil,
-		AttackCards:      attackCards,
-		DefenseCardsChan: make(chan []string, 1),
-	}
-
-	h.hub.mu.Lock()
-	h.hub.pendingCardBattles[battleID] = pendingBattle
-	h.hub.mu.Unlock()
-
-	// Send defense card request to human defender
-	request := protocol.DefenseCardRequestPayload{
-		BattleID:          battleID,
-		AttackerID:        attackerID,
-		AttackerName:      playerName,
-		TargetTerritory:   targetID,
-		TerritoryName:     terrName,
-		BaseAttackStr:     baseAttack,
-		BaseDefenseStr:    baseDefense,
-		AttackerCardCount: len(attackCards),
+	// Human defender: the cards leave the AI's hand with the battle, which is
+	// saved and waits for the defender's answer
+	battle := &game.PendingCardBattle{
+		ID:              fmt.Sprintf("card-battle-%s-%d", gameID, time.Now().UnixNano()),
+		AttackerID:      attackerID,
+		DefenderID:      defenderID,
+		TargetTerritory: targetID,
 	}
-	h.hub.sendToPlayer(defenderID, protocol.TypeDefenseCardRequest, request)
-
-	log.Printf("AI Card Combat: Waiting for defender %s to select defense cards (battle %s)", defenderID, battleID)
-
-	// Wait for defender's card selection (no timeout)
-	defenseCardIDs := <-pendingBattle.DefenseCardsChan
-
-	// Clean up pending battle
-	h.hub.mu.Lock()
-	delete(h.hub.pendingCardBattles, battleID)
-	h.hub.mu.Unlock()
-
-	// Re-load state (may have changed while waiting)
-	stateJSON2, err := h.hub.server.db.GetGameState(gameID)
-	if err != nil {
-		log.Printf("AI Card Combat: reload state error: %v", err)
+	if err := state.BeginCardBattle(battle, attackCardIDs); err != nil {
+		log.Printf("AI Card Combat: %v", err)
+		state.EndConquest(attackerID)
+		if !h.saveAndBroadcastAIState(gameID, state) {
+			h.scheduleAI(gameID)
+		}
 		return
 	}
-	var freshState game.GameState
-	if err := json.Unmarshal([]byte(stateJSON2), &freshState); err != nil {
-		log.Printf("AI Card Combat: unmarshal error: %v", err)
+	if err := h.saveState(gameID, state); err != nil {
+		log.Printf("AI: Failed to save state: %v", err)
 		return
 	}
 
-	// Remove defender's selected defense cards from hand
-	defenderPlayer := freshState.Players[defenderID]
-	if defenderPlayer != nil {
-		removed := defenderPlayer.RemoveCardsFromHand(defenseCardIDs)
-		for _, c := range removed {
-			if c.CardType == game.CardTypeDefense {
-				defenseCards = append(defenseCards, c)
-			}
-		}
-	}
-
-	log.Printf("AI Card Combat: Defender %s selected %d defense cards", defenderID, len(defenseCards))
-
-	// Resolve using freshState
-	err = h.resolveCardCombat(aiClient, &freshState, payload, nil, nil, nil, attackCards, defenseCards, terrName, defenderID, defenderName)
-	if err != nil {
-		log.Printf("AI Card Combat: resolve error: %v", err)
-		freshState.EndConquest(attackerID)
-		if !h.saveAndBroadcastAIState(gameID, &freshState) {
-			go h.checkAndTriggerAI(gameID)
-		}
-	}
+	h.requestDefenseCards(gameID, state)
+	h.broadcastGameState(gameID)
 }
 
 // aiDevelopment handles the AI's development phase.
@@ -2274,6 +2571,7 @@ func (h *Handlers) aiDevelopment(gameID string, state *game.GameState) {
 
 	// Card combat: AI tries to buy cards with remaining resources
 	if state.Settings.CombatMode == game.CombatModeCards {
+		h.aiCombineCards(state, player)
 		h.aiBuyCards(state, player)
 	}
 
@@ -2282,7 +2580,38 @@ func (h *Handlers) aiDevelopment(gameID string, state *game.GameState) {
 
 	// Save state
 	if !h.saveAndBroadcastAIState(gameID, state) {
-		go h.checkAndTriggerAI(gameID)
+		h.scheduleAI(gameID)
+	}
+}
+
+// aiCombineCards has AI trade two common cards for an uncommon one when a
+// hand is full, making room to buy more.
+func (h *Handlers) aiCombineCards(state *game.GameState, player *game.Player) {
+	hands := map[game.CardType][]game.CombatCard{
+		game.CardTypeAttack:  player.AttackCards,
+		game.CardTypeDefense: player.DefenseCards,
+	}
+	for _, cardType := range []game.CardType{game.CardTypeAttack, game.CardTypeDefense} {
+		hand := hands[cardType]
+		if (cardType == game.CardTypeAttack && len(hand) < game.MaxAttackCards) ||
+			(cardType == game.CardTypeDefense && len(hand) < game.MaxDefenseCards) {
+			continue
+		}
+
+		var commons []string
+		for _, c := range hand {
+			if c.Rarity == game.RarityCommon {
+				commons = append(commons, c.ID)
+			}
+		}
+		if len(commons) < 2 {
+			continue
+		}
+		card, err := state.CombineCards(state.CurrentPlayerID, commons[0], commons[1])
+		if err != nil {
+			continue
+		}
+		log.Printf("AI: Combined two %s cards into %s", cardType, card.Name)
 	}
 }
 
@@ -2353,14 +2682,7 @@ func (h *Handlers) aiBuyCards(state *game.GameState, player *game.Player) {
 
 // saveAndBroadcastAIState saves the AI's state changes and broadcasts to clients.
 func (h *Handlers) saveAndBroadcastAIState(gameID string, state *game.GameState) bool {
-	stateJSON, err := json.Marshal(state)
-	if err != nil {
-		log.Printf("AI: Failed to marshal state: %v", err)
-		return false
-	}
-
-	if err := h.hub.server.db.SaveGameState(gameID, string(stateJSON),
-		state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
+	if err := h.saveState(gameID, state); err != nil {
 		log.Printf("AI: Failed to save state: %v", err)
 		return false
 	}
@@ -2380,16 +2702,11 @@ func (h *Handlers) handleMoveStockpile(client *Client, msg *protocol.Message) er
 	}
 
 	// Load game state
-	stateJSON, err := h.hub.server.db.GetGameState(client.GameID)
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		return err
-	}
-
 	// Get destination territory name
 	destName := payload.Destination
 	if terr, ok := state.Territories[payload.Destination]; ok {
@@ -2406,13 +2723,7 @@ func (h *Handlers) handleMoveStockpile(client *Client, msg *protocol.Message) er
 		database.EventStockpileMoved, fmt.Sprintf("Moved stockpile to %s", destName))
 
 	// Save updated state
-	stateJSON2, err := json.Marshal(state)
-	if err != nil {
-		return err
-	}
-
-	if err := h.hub.server.db.SaveGameState(client.GameID, string(stateJSON2),
-		state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
+	if err := h.saveState(client.GameID, state); err != nil {
 		return err
 	}
 
@@ -2420,47 +2731,81 @@ func (h *Handlers) handleMoveStockpile(client *Client, msg *protocol.Message) er
 
 	// Broadcast updated state
 	if !h.broadcastGameState(client.GameID) {
-		go h.checkAndTriggerAI(client.GameID)
+		h.scheduleAI(client.GameID)
 	}
 
 	return nil
 }
 
-// handleMoveUnit handles moving a unit during shipment phase (Expert level only).
-func (h *Handlers) handleMoveUnit(client *Client, msg *protocol.Message) error {
+// handleShipResources handles moving resources between a player's stockpile
+// and depots during the shipment phase.
+func (h *Handlers) handleShipResources(client *Client, msg *protocol.Message) error {
 	if client.GameID == "" {
 		return errors.New("not in a game")
 	}
 
-	var payload protocol.MoveUnitPayload
+	var payload protocol.ShipResourcesPayload
 	if err := msg.ParsePayload(&payload); err != nil {
 		return err
 	}
 
-	// Load game state
-	stateJSON, err := h.hub.server.db.GetGameState(client.GameID)
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
+	load := game.Stockpile{Coal: payload.Coal, Gold: payload.Gold, Iron: payload.Iron, Timber: payload.Timber}
+	if err := state.ShipResources(client.PlayerID, payload.From, payload.To, load); err != nil {
 		return err
 	}
 
-	// Execute move
-	if err := state.MoveUnit(client.PlayerID, payload.UnitType, payload.From, payload.To, payload.WaterBodyID, payload.CarryHorse, payload.CarryWeapon); err != nil {
+	fromName, toName := payload.From, payload.To
+	if terr, ok := state.Territories[payload.From]; ok {
+		fromName = terr.Name
+	}
+	if terr, ok := state.Territories[payload.To]; ok {
+		toName = terr.Name
+	}
+	h.logHistory(client.GameID, state.Round, state.Phase.String(), client.PlayerID, client.Name,
+		database.EventResourcesShipped, fmt.Sprintf("Shipped %d resources from %s to %s", load.Total(), fromName, toName))
+
+	if err := h.saveState(client.GameID, state); err != nil {
 		return err
 	}
 
-	// Save updated state
-	stateJSON2, err := json.Marshal(state)
+	log.Printf("Player %s shipped %+v from %s to %s", client.Name, load, payload.From, payload.To)
+
+	if !h.broadcastGameState(client.GameID) {
+		h.scheduleAI(client.GameID)
+	}
+
+	return nil
+}
+
+// handleMoveUnit handles moving a unit during shipment phase (Expert level only).
+func (h *Handlers) handleMoveUnit(client *Client, msg *protocol.Message) error {
+	if client.GameID == "" {
+		return errors.New("not in a game")
+	}
+
+	var payload protocol.MoveUnitPayload
+	if err := msg.ParsePayload(&payload); err != nil {
+		return err
+	}
+
+	// Load game state
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
 
-	if err := h.hub.server.db.SaveGameState(client.GameID, string(stateJSON2),
-		state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
+	// Execute move
+	if err := state.MoveUnit(client.PlayerID, payload.UnitType, payload.From, payload.To, payload.WaterBodyID, payload.CarryHorse, payload.CarryWeapon, payload.CarrySiege); err != nil {
+		return err
+	}
+
+	// Save updated state
+	if err := h.saveState(client.GameID, state); err != nil {
 		return err
 	}
 
@@ -2468,7 +2813,7 @@ func (h *Handlers) handleMoveUnit(client *Client, msg *protocol.Message) error {
 
 	// Broadcast updated state
 	if !h.broadcastGameState(client.GameID) {
-		go h.checkAndTriggerAI(client.GameID)
+		h.scheduleAI(client.GameID)
 	}
 
 	return nil
@@ -2492,9 +2837,12 @@ type PendingTrade struct {
 	RequestTimber         int
 	RequestHorses         int
 	RequestHorseDestTerrs []string // Where proposer wants received horses placed
-	ResponseChan          chan bool
+	timer                 *time.Timer
 }
 
+// tradeTimeout is how long a trade proposal waits for the target's response.
+const tradeTimeout = 60 * time.Second
+
 // handleProposeTrade handles a player proposing a trade to another player.
 func (h *Handlers) handleProposeTrade(client *Client, msg *protocol.Message) error {
 	if client.GameID == "" {
@@ -2507,16 +2855,11 @@ func (h *Handlers) handleProposeTrade(client *Client, msg *protocol.Message) err
 	}
 
 	// Load game state
-	stateJSON, err := h.hub.server.db.GetGameState(client.GameID)
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		return err
-	}
-
 	// Check it's trade phase and player's turn
 	if state.Phase != game.PhaseTrade {
 		return game.ErrInvalidAction
@@ -2591,16 +2934,26 @@ func (h *Handlers) handleProposeTrade(client *Client, msg *protocol.Message) err
 		RequestTimber:         payload.RequestTimber,
 		RequestHorses:         payload.RequestHorses,
 		RequestHorseDestTerrs: payload.RequestHorseDestTerrs,
-		ResponseChan:          make(chan bool, 1),
 	}
 
-	// Store pending trade
-	h.hub.mu.Lock()
-	if h.hub.pendingTrades == nil {
-		h.hub.pendingTrades = make(map[string]*PendingTrade)
-	}
-	h.hub.pendingTrades[tradeID] = trade
-	h.hub.mu.Unlock()
+	// Store pending trade; it is rejected if the target doesn't answer in time
+	g := h.hub.Game(client.GameID)
+	g.pendingTrades[tradeID] = trade
+	trade.timer = g.After(tradeTimeout, func() {
+		if g.pendingTrades[tradeID] != trade {
+			return
+		}
+		delete(g.pendingTrades, tradeID)
+
+		result := protocol.TradeResultPayload{
+			TradeID:  tradeID,
+			Accepted: false,
+			Message:  "Trade timed out",
+		}
+		respMsg, _ := protocol.NewMessage(protocol.TypeTradeResult, result)
+		client.Send(respMsg)
+		log.Printf("Trade %s timed out", tradeID)
+	})
 
 	// Send proposal to target player
 	proposerName := client.Name
@@ -2623,27 +2976,6 @@ func (h *Handlers) handleProposeTrade(client *Client, msg *protocol.Message) err
 	h.hub.sendToPlayer(payload.TargetPlayer, protocol.TypeTradeProposal, proposal)
 	log.Printf("Trade proposal %s sent from %s to %s", tradeID, client.Name, payload.TargetPlayer)
 
-	// Wait for response (synchronous - blocks until response or timeout)
-	// Timeout after 60 seconds
-	select {
-	case <-trade.ResponseChan:
-		// Response received, handled in handleRespondTrade
-	case <-time.After(60 * time.Second):
-		// Timeout - reject trade
-		h.hub.mu.Lock()
-		delete(h.hub.pendingTrades, tradeID)
-		h.hub.mu.Unlock()
-
-		result := protocol.TradeResultPayload{
-			TradeID:  tradeID,
-			Accepted: false,
-			Message:  "Trade timed out",
-		}
-		respMsg, _ := protocol.NewMessage(protocol.TypeTradeResult, result)
-		client.Send(respMsg)
-		log.Printf("Trade %s timed out", tradeID)
-	}
-
 	return nil
 }
 
@@ -2655,13 +2987,8 @@ func (h *Handlers) handleRespondTrade(client *Client, msg *protocol.Message) err
 	}
 
 	// Get the pending trade
-	h.hub.mu.Lock()
-	trade, exists := h.hub.pendingTrades[payload.TradeID]
-	if exists {
-		delete(h.hub.pendingTrades, payload.TradeID)
-	}
-	h.hub.mu.Unlock()
-
+	g := h.hub.Game(client.GameID)
+	trade, exists := g.pendingTrades[payload.TradeID]
 	if !exists {
 		return errors.New("trade not found or expired")
 	}
@@ -2671,17 +2998,14 @@ func (h *Handlers) handleRespondTrade(client *Client, msg *protocol.Message) err
 		return errors.New("not the trade target")
 	}
 
+	// The trade is answered either way - stop its timeout
+	delete(g.pendingTrades, payload.TradeID)
+	g.Cancel(trade.timer)
+
 	if payload.Accepted {
 		// Load game state
-		stateJSON, err := h.hub.server.db.GetGameState(trade.GameID)
+		state, err := h.loadState(trade.GameID)
 		if err != nil {
-			trade.ResponseChan <- false
-			return err
-		}
-
-		var state game.GameState
-		if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-			trade.ResponseChan <- false
 			return err
 		}
 
@@ -2707,20 +3031,11 @@ func (h *Handlers) handleRespondTrade(client *Client, msg *protocol.Message) err
 		// payload.HorseDestinations: where accepter wants offered horses placed (for OfferHorses)
 		// trade.RequestHorseDestTerrs: where proposer wants received horses placed (for RequestHorses)
 		if err := state.ExecuteTrade(offer, payload.HorseSources, payload.HorseDestinations, trade.RequestHorseDestTerrs); err != nil {
-			trade.ResponseChan <- false
 			return err
 		}
 
 		// Save state
-		stateJSON2, err := json.Marshal(state)
-		if err != nil {
-			trade.ResponseChan <- false
-			return err
-		}
-
-		if err := h.hub.server.db.SaveGameState(trade.GameID, string(stateJSON2),
-			state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
-			trade.ResponseChan <- false
+		if err := h.saveState(trade.GameID, state); err != nil {
 			return err
 		}
 
@@ -2748,12 +3063,68 @@ func (h *Handlers) handleRespondTrade(client *Client, msg *protocol.Message) err
 		log.Printf("Trade %s rejected by %s", payload.TradeID, client.Name)
 	}
 
-	// Signal that we got a response
-	trade.ResponseChan <- payload.Accepted
+	return nil
+}
+
+// handleMarketTrade handles selling resources to the bank for another resource.
+func (h *Handlers) handleMarketTrade(client *Client, msg *protocol.Message) error {
+	if client.GameID == "" {
+		return errors.New("not in a game")
+	}
+
+	var payload protocol.MarketTradePayload
+	if err := msg.ParsePayload(&payload); err != nil {
+		return err
+	}
+
+	sell, err := parseResource(payload.Sell)
+	if err != nil {
+		return err
+	}
+	buy, err := parseResource(payload.Buy)
+	if err != nil {
+		return err
+	}
+
+	state, err := h.loadState(client.GameID)
+	if err != nil {
+		return err
+	}
+
+	got, err := state.MarketTrade(client.PlayerID, sell, payload.Amount, buy)
+	if err != nil {
+		return err
+	}
+
+	h.logHistory(client.GameID, state.Round, state.Phase.String(), client.PlayerID, client.Name,
+		database.EventMarketTrade, fmt.Sprintf("Sold %d %s to the market for %d %s", payload.Amount, payload.Sell, got, payload.Buy))
+
+	if err := h.saveState(client.GameID, state); err != nil {
+		return err
+	}
+
+	log.Printf("Player %s sold %d %s to the market for %d %s", client.Name, payload.Amount, payload.Sell, got, payload.Buy)
 
+	h.broadcastGameState(client.GameID)
 	return nil
 }
 
+// parseResource converts a stockpile resource name to its ResourceType.
+func parseResource(name string) (game.ResourceType, error) {
+	switch name {
+	case "coal":
+		return game.ResourceCoal, nil
+	case "gold":
+		return game.ResourceGold, nil
+	case "iron":
+		return game.ResourceIron, nil
+	case "timber":
+		return game.ResourceTimber, nil
+	default:
+		return game.ResourceNone, errors.New("invalid resource: " + name)
+	}
+}
+
 // handleEndPhase handles a player ending their turn in the current phase.
 func (h *Handlers) handleEndPhase(client *Client, msg *protocol.Message) error {
 	if client.GameID == "" {
@@ -2761,16 +3132,11 @@ func (h *Handlers) handleEndPhase(client *Client, msg *protocol.Message) error {
 	}
 
 	// Load game state
-	stateJSON, err := h.hub.server.db.GetGameState(client.GameID)
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		return err
-	}
-
 	// Check it's the player's turn
 	if state.CurrentPlayerID != client.PlayerID {
 		return game.ErrNotYourTurn
@@ -2791,21 +3157,18 @@ func (h *Handlers) handleEndPhase(client *Client, msg *protocol.Message) error {
 		}
 	case game.PhaseConquest:
 		// End conquest phase for this player
-		state.EndConquest(client.PlayerID)
+		if err := state.EndConquest(client.PlayerID); err != nil {
+			return err
+		}
 		// Check for game over - if still in Conquest phase after EndConquest, game is over
 		// (NextPhase doesn't advance past Conquest if game is over)
 		if state.Phase == game.PhaseConquest && state.IsGameOver() {
 			// Save state before handling game over
-			stateJSON2, err := json.Marshal(state)
-			if err != nil {
-				return err
-			}
-			if err := h.hub.server.db.SaveGameState(client.GameID, string(stateJSON2),
-				state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
+			if err := h.saveState(client.GameID, state); err != nil {
 				return err
 			}
 			log.Printf("Game over at end of Conquest phase")
-			h.handleGameOver(client.GameID, &state)
+			h.handleGameOver(client.GameID, state)
 			return nil
 		}
 	case game.PhaseDevelopment:
@@ -2816,13 +3179,7 @@ func (h *Handlers) handleEndPhase(client *Client, msg *protocol.Message) error {
 	}
 
 	// Save updated state
-	stateJSON2, err := json.Marshal(state)
-	if err != nil {
-		return err
-	}
-
-	if err := h.hub.server.db.SaveGameState(client.GameID, string(stateJSON2),
-		state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
+	if err := h.saveState(client.GameID, state); err != nil {
 		return err
 	}
 
@@ -2830,7 +3187,7 @@ func (h *Handlers) handleEndPhase(client *Client, msg *protocol.Message) error {
 
 	// Broadcast updated state
 	if !h.broadcastGameState(client.GameID) {
-		go h.checkAndTriggerAI(client.GameID)
+		h.scheduleAI(client.GameID)
 	}
 
 	return nil
@@ -2848,16 +3205,11 @@ func (h *Handlers) handlePlanAttack(client *Client, msg *protocol.Message) error
 	}
 
 	// Load game state
-	stateJSON, err := h.hub.server.db.GetGameState(client.GameID)
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		return err
-	}
-
 	// Get attack plan/preview
 	plan := state.GetAttackPlan(client.PlayerID, payload.TargetTerritory)
 	if plan == nil {
@@ -2950,6 +3302,11 @@ func (h *Handlers) handlePlanAttack(client *Client, msg *protocol.Message) error
 		reinforcements = append(reinforcements, opt)
 	}
 
+	winChance := 0.0
+	if target != nil {
+		winChance = state.AttackOdds(attackStrength, defenseStrength, target)
+	}
+
 	// Send preview
 	preview := protocol.AttackPreviewPayload{
 		TargetTerritory:         plan.TargetID,
@@ -2957,6 +3314,7 @@ func (h *Handlers) handlePlanAttack(client *Client, msg *protocol.Message) error
 		DefenseStrength:         defenseStrength,
 		AttackerAllyStrength:    attackerAllyStrength,
 		DefenderAllyStrength:    defenderAllyStrength,
+		WinChance:               winChance,
 		CanAttack:               plan.CanAttack,
 		AvailableReinforcements: reinforcements,
 	}
@@ -2981,16 +3339,11 @@ func (h *Handlers) handleRequestAttackPlan(client *Client, msg *protocol.Message
 	}
 
 	// Load game state
-	stateJSON, err := h.hub.server.db.GetGameState(client.GameID)
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		return err
-	}
-
 	// Get target territory info
 	target := state.Territories[payload.TargetTerritory]
 	if target == nil {
@@ -3080,101 +3433,34 @@ func (h *Handlers) handleRequestAttackPlan(client *Client, msg *protocol.Message
 		}
 	}
 
-	// If there are "ask" players, we need to wait for their votes
-	if len(askPlayers) > 0 {
-		log.Printf("RequestAttackPlan: Waiting for %d alliance votes from: %v", len(askPlayers), askPlayers)
-
-		battleID := fmt.Sprintf("plan-%s-%d", client.GameID, time.Now().UnixNano())
-		battle := &PendingBattle{
-			ID:           battleID,
-			GameID:       client.GameID,
-			AttackerID:   client.PlayerID,
-			DefenderID:   defenderID,
-			TerritoryID:  payload.TargetTerritory,
-			ThirdParties: askPlayers,
-			Votes:        make(map[string]string),
-			VoteChan:     make(chan string, len(askPlayers)),
-			ExpiresAt:    time.Now().Add(60 * time.Second),
-		}
-
-		h.hub.mu.Lock()
-		h.hub.pendingBattles[battleID] = battle
-		h.hub.mu.Unlock()
-
-		// Send alliance requests to all "ask" players
-		for _, askID := range askPlayers {
-			askPlayer := state.Players[askID]
-			askStrength := state.CalculatePlayerStrengthAtTerritory(askID, target)
-
-			attackerName := client.Name
-			defenderName := "Unclaimed"
-			if defender := state.Players[defenderID]; defender != nil {
-				defenderName = defender.Name
-			}
-
-			request := protocol.AllianceRequestPayload{
-				BattleID:      battleID,
-				AttackerID:    client.PlayerID,
-				AttackerName:  attackerName,
-				DefenderID:    defenderID,
-				DefenderName:  defenderName,
-				TerritoryID:   payload.TargetTerritory,
-				TerritoryName: terrName,
-				YourStrength:  askStrength,
-				TimeLimit:     60,
-				ExpiresAt:     battle.ExpiresAt.Unix(),
-			}
-
-			log.Printf("RequestAttackPlan: Sending alliance request to %s (%s)", askID, askPlayer.Name)
-			h.hub.sendToPlayer(askID, protocol.TypeAllianceRequest, request)
+	// Ask the "ask" players to pick a side, then resolve the plan with their votes
+	h.requestAllianceVotes(client, state, target, askPlayers, "plan", func(attackerVotes, defenderVotes []string) {
+		attackerAllies = append(attackerAllies, attackerVotes...)
+		defenderAllies = append(defenderAllies, defenderVotes...)
+		if err := h.sendResolvedAttackPlan(client, msg.ID, &payload, attackerAllies, defenderAllies, baseAttackStrength, baseDefenseStrength); err != nil {
+			h.sendError(client, msg.ID, err)
 		}
+	})
 
-		// Wait for votes or timeout
-		votesReceived := 0
-		timeout := time.After(60 * time.Second)
-
-	voteLoop:
-		for votesReceived < len(askPlayers) {
-			select {
-			case <-battle.VoteChan:
-				votesReceived++
-				log.Printf("RequestAttackPlan: Received vote %d/%d", votesReceived, len(askPlayers))
-			case <-timeout:
-				log.Printf("RequestAttackPlan: Alliance vote timeout, proceeding with %d/%d votes", votesReceived, len(askPlayers))
-				break voteLoop
-			}
-		}
+	return nil
+}
 
-		// Collect the votes
-		h.hub.mu.Lock()
-		for playerID, side := range battle.Votes {
-			switch side {
-			case "attacker":
-				attackerAllies = append(attackerAllies, playerID)
-				log.Printf("  %s voted for attacker", playerID)
-			case "defender":
-				defenderAllies = append(defenderAllies, playerID)
-				log.Printf("  %s voted for defender", playerID)
-			default:
-				log.Printf("  %s voted neutral", playerID)
-			}
-		}
-		delete(h.hub.pendingBattles, battleID)
-		h.hub.mu.Unlock()
-	}
+// attackPlanTTL is how long a resolved attack plan can be executed.
+const attackPlanTTL = 60 * time.Second
 
+// sendResolvedAttackPlan caches an attack plan with its final allies and sends
+// the resulting strengths back to the attacker.
+func (h *Handlers) sendResolvedAttackPlan(client *Client, msgID string, payload *protocol.RequestAttackPlanPayload, attackerAllies, defenderAllies []string, baseAttackStrength, baseDefenseStrength int) error {
 	// Re-load game state in case it changed during voting
-	stateJSON, err = h.hub.server.db.GetGameState(client.GameID)
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		return err
-	}
-	target = state.Territories[payload.TargetTerritory]
+	target := state.Territories[payload.TargetTerritory]
 	if target == nil {
 		return errors.New("target territory not found")
 	}
+	terrName := target.Name
 
 	// Calculate final ally strengths
 	attackerAllyStrength := 0
@@ -3202,7 +3488,7 @@ func (h *Handlers) handleRequestAttackPlan(client *Client, msg *protocol.Message
 
 	// Create pending attack plan
 	planID := fmt.Sprintf("attack-%s-%d", client.GameID, time.Now().UnixNano())
-	expiresAt := time.Now().Add(60 * time.Second)
+	expiresAt := time.Now().Add(attackPlanTTL)
 
 	pendingPlan := &PendingAttackPlan{
 		ID:              planID,
@@ -3221,20 +3507,16 @@ func (h *Handlers) handleRequestAttackPlan(client *Client, msg *protocol.Message
 		ExpiresAt:       expiresAt,
 	}
 
-	h.hub.mu.Lock()
-	h.hub.pendingAttackPlans[planID] = pendingPlan
-	h.hub.mu.Unlock()
+	g := h.hub.Game(client.GameID)
+	g.pendingAttackPlans[planID] = pendingPlan
 
-	// Start cleanup goroutine for expired plans
-	go func() {
-		time.Sleep(65 * time.Second) // 5 seconds after expiry
-		h.hub.mu.Lock()
-		if p, exists := h.hub.pendingAttackPlans[planID]; exists && time.Now().After(p.ExpiresAt) {
-			delete(h.hub.pendingAttackPlans, planID)
+	// Clean up the plan shortly after it expires
+	g.After(attackPlanTTL+5*time.Second, func() {
+		if _, exists := g.pendingAttackPlans[planID]; exists {
+			delete(g.pendingAttackPlans, planID)
 			log.Printf("Cleaned up expired attack plan %s", planID)
 		}
-		h.hub.mu.Unlock()
-	}()
+	})
 
 	// Send resolved plan back to attacker
 	response := protocol.AttackPlanResolvedPayload{
@@ -3250,7 +3532,7 @@ func (h *Handlers) handleRequestAttackPlan(client *Client, msg *protocol.Message
 	}
 
 	respMsg, _ := protocol.NewMessage(protocol.TypeAttackPlanResolved, response)
-	respMsg.ID = msg.ID
+	respMsg.ID = msgID
 	client.Send(respMsg)
 
 	return nil
@@ -3270,33 +3552,27 @@ func (h *Handlers) handleExecuteAttack(client *Client, msg *protocol.Message) er
 	// Check if we have a cached attack plan from RequestAttackPlan
 	var cachedPlan *PendingAttackPlan
 	if payload.PlanID != "" {
-		h.hub.mu.Lock()
-		if plan, exists := h.hub.pendingAttackPlans[payload.PlanID]; exists {
+		g := h.hub.Game(client.GameID)
+		if plan, exists := g.pendingAttackPlans[payload.PlanID]; exists {
 			if plan.AttackerID == client.PlayerID &&
 				plan.GameID == client.GameID &&
 				plan.TargetTerritory == payload.TargetTerritory &&
 				time.Now().Before(plan.ExpiresAt) {
 				cachedPlan = plan
-				delete(h.hub.pendingAttackPlans, payload.PlanID) // Use it once
+				delete(g.pendingAttackPlans, payload.PlanID) // Use it once
 				log.Printf("Using cached attack plan %s", payload.PlanID)
 			} else {
 				log.Printf("Cached attack plan %s invalid or expired", payload.PlanID)
 			}
 		}
-		h.hub.mu.Unlock()
 	}
 
 	// Load game state
-	stateJSON, err := h.hub.server.db.GetGameState(client.GameID)
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		return err
-	}
-
 	// Build brought unit if specified
 	var brought *game.BroughtUnit
 	if payload.BringUnit != "" && payload.BringFrom != "" {
@@ -3342,9 +3618,12 @@ func (h *Handlers) handleExecuteAttack(client *Client, msg *protocol.Message) er
 	}
 	terrName := target.Name
 	defenderID := target.Owner
-	defenderName := defenderID
-	if dp := state.Players[defenderID]; dp != nil {
-		defenderName = dp.Name
+	defenderName := state.OwnerName(defenderID)
+	if state.IsVassalOf(client.PlayerID, defenderID) {
+		return errors.New("cannot attack your overlord")
+	}
+	if brought != nil && brought.UnitType == game.UnitBoat && state.Blockaded(brought.FromTerritory, brought.WaterBodyID) {
+		return game.ErrBlockaded
 	}
 
 	// Collect allies based on alliance settings
@@ -3415,107 +3694,40 @@ func (h *Handlers) handleExecuteAttack(client *Client, msg *protocol.Message) er
 			}
 		}
 
-		// If there are "ask" players, we need to wait for their votes
-		if len(askPlayers) > 0 {
-			log.Printf("Waiting for %d alliance votes from: %v", len(askPlayers), askPlayers)
-
-			// Create a pending battle
-			battleID := fmt.Sprintf("battle-%s-%d", client.GameID, time.Now().UnixNano())
-			battle := &PendingBattle{
-				ID:           battleID,
-				GameID:       client.GameID,
-				AttackerID:   client.PlayerID,
-				DefenderID:   defenderID,
-				TerritoryID:  payload.TargetTerritory,
-				ThirdParties: askPlayers,
-				Votes:        make(map[string]string),
-				VoteChan:     make(chan string, len(askPlayers)),
-				ExpiresAt:    time.Now().Add(60 * time.Second),
-			}
-
-			h.hub.mu.Lock()
-			h.hub.pendingBattles[battleID] = battle
-			h.hub.mu.Unlock()
-
-			// Send alliance requests to all "ask" players
-			for _, askID := range askPlayers {
-				askPlayer := state.Players[askID]
-				askStrength := state.CalculatePlayerStrengthAtTerritory(askID, target)
-
-				attackerName := client.Name
-				defenderName := "Unclaimed"
-				if defender := state.Players[defenderID]; defender != nil {
-					defenderName = defender.Name
-				}
-
-				request := protocol.AllianceRequestPayload{
-					BattleID:      battleID,
-					AttackerID:    client.PlayerID,
-					AttackerName:  attackerName,
-					DefenderID:    defenderID,
-					DefenderName:  defenderName,
-					TerritoryID:   payload.TargetTerritory,
-					TerritoryName: terrName,
-					YourStrength:  askStrength,
-					TimeLimit:     60,
-					ExpiresAt:     battle.ExpiresAt.Unix(),
-				}
-
-				log.Printf("Sending alliance request to %s (%s)", askID, askPlayer.Name)
-				h.hub.sendToPlayer(askID, protocol.TypeAllianceRequest, request)
-			}
-
-			// Wait for votes or timeout
-			votesReceived := 0
-			timeout := time.After(60 * time.Second)
-
-		voteLoop:
-			for votesReceived < len(askPlayers) {
-				select {
-				case <-battle.VoteChan:
-					votesReceived++
-					log.Printf("Received vote %d/%d", votesReceived, len(askPlayers))
-				case <-timeout:
-					log.Printf("Alliance vote timeout, proceeding with %d/%d votes", votesReceived, len(askPlayers))
-					break voteLoop
-				}
-			}
+		// Ask the "ask" players to pick a side, then attack with their votes
+		h.requestAllianceVotes(client, state, target, askPlayers, "battle", func(attackerVotes, defenderVotes []string) {
+			attackerAllies = append(attackerAllies, attackerVotes...)
+			defenderAllies = append(defenderAllies, defenderVotes...)
+			log.Printf("Battle at %s: Final allies - Attacker: %v, Defender: %v", terrName, attackerAllies, defenderAllies)
 
-			// Collect the votes
-			h.hub.mu.Lock()
-			for playerID, side := range battle.Votes {
-				switch side {
-				case "attacker":
-					attackerAllies = append(attackerAllies, playerID)
-					log.Printf("  %s voted for attacker", playerID)
-				case "defender":
-					defenderAllies = append(defenderAllies, playerID)
-					log.Printf("  %s voted for defender", playerID)
-				default:
-					log.Printf("  %s voted neutral", playerID)
-				}
+			if err := h.executeAttack(client, &payload, brought, attackerAllies, defenderAllies, terrName, defenderID, defenderName); err != nil {
+				h.sendError(client, msg.ID, err)
 			}
-			// Clean up the pending battle
-			delete(h.hub.pendingBattles, battleID)
-			h.hub.mu.Unlock()
-		}
+		})
+		return nil
+	}
 
-		log.Printf("Battle at %s: Final allies - Attacker: %v, Defender: %v", terrName, attackerAllies, defenderAllies)
-	} // End of cachedPlan == nil block
+	return h.executeAttack(client, &payload, brought, attackerAllies, defenderAllies, terrName, defenderID, defenderName)
+}
 
+// executeAttack resolves an attack once its allies are known.
+func (h *Handlers) executeAttack(
+	client *Client,
+	payload *protocol.ExecuteAttackPayload,
+	brought *game.BroughtUnit,
+	attackerAllies, defenderAllies []string,
+	terrName, defenderID, defenderName string,
+) error {
 	// Re-load game state in case it changed during voting
-	stateJSON, err = h.hub.server.db.GetGameState(client.GameID)
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		return err
-	}
 
 	// Branch on combat mode: card combat or classic
-	if state.Settings.CombatMode == game.CombatModeCards && len(payload.AttackCardIDs) >= 0 {
+	if state.Settings.CombatMode == game.CombatModeCards {
 		// === CARD COMBAT MODE ===
-		return h.executeCardAttack(client, &state, &payload, brought, attackerAllies, defenderAllies, terrName, defenderID, defenderName)
+		return h.executeCardAttack(client, state, payload, brought, attackerAllies, defenderAllies, terrName, defenderID, defenderName)
 	}
 
 	// === CLASSIC COMBAT MODE ===
@@ -3524,55 +3736,234 @@ func (h *Handlers) handleExecuteAttack(client *Client, msg *protocol.Message) er
 		return err
 	}
 
-	h.finishAttack(client, &state, result, &payload, terrName, defenderID, defenderName)
+	h.finishAttack(client, state, result, payload, terrName, defenderID, defenderName)
 	return nil
 }
 
-// finishAttack handles the common post-combat logic for both classic and card combat.
-func (h *Handlers) finishAttack(client *Client, state *game.GameState, result *game.CombatResult, payload *protocol.ExecuteAttackPayload, terrName, defenderID, defenderName string) {
-	// Log history event
-	if result.AttackerWins {
-		h.logHistory(client.GameID, state.Round, state.Phase.String(), client.PlayerID, client.Name,
-			database.EventAttackSuccess, fmt.Sprintf("Captured %s", terrName))
-	} else {
-		h.logHistory(client.GameID, state.Round, state.Phase.String(), client.PlayerID, client.Name,
-			database.EventAttackFailed, fmt.Sprintf("Attack on %s failed", terrName))
+// handleRaid handles a raid on an enemy stockpile or depot. Raids are quick
+// strikes: no units are brought along and third parties don't join in.
+func (h *Handlers) handleRaid(client *Client, msg *protocol.Message) error {
+	if client.GameID == "" {
+		return errors.New("not in a game")
 	}
 
-	// Save updated state
-	stateJSON2, err := json.Marshal(state)
+	var payload protocol.RaidPayload
+	if err := msg.ParsePayload(&payload); err != nil {
+		return err
+	}
+
+	state, err := h.loadState(client.GameID)
 	if err != nil {
-		log.Printf("Error marshaling state after attack: %v", err)
-		return
+		return err
 	}
 
-	if err := h.hub.server.db.SaveGameState(client.GameID, string(stateJSON2),
-		state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
-		log.Printf("Error saving state after attack: %v", err)
-		return
+	target := state.Territories[payload.TargetTerritory]
+	if target == nil {
+		return errors.New("target territory not found")
 	}
+	terrName := target.Name
+	defenderID := target.Owner
+	defenderName := state.OwnerName(defenderID)
 
-	// Convert result to protocol format
-	unitsDestroyed := make([]string, 0)
-	for _, u := range result.UnitsDestroyed {
-		unitsDestroyed = append(unitsDestroyed, u.TerritoryID)
+	result, err := state.Raid(client.PlayerID, payload.TargetTerritory)
+	if err != nil {
+		return err
 	}
-	unitsCaptured := make([]string, 0)
-	for _, u := range result.UnitsCaptured {
-		unitsCaptured = append(unitsCaptured, u.TerritoryID)
+
+	h.finishAttack(client, state, result, &protocol.ExecuteAttackPayload{TargetTerritory: payload.TargetTerritory}, terrName, defenderID, defenderName)
+	return nil
+}
+
+// handleNavalAttack handles a sea battle against the boats a territory keeps
+// in a water body. Like raids, sea battles bring no units along and third
+// parties don't join in.
+func (h *Handlers) handleNavalAttack(client *Client, msg *protocol.Message) error {
+	if client.GameID == "" {
+		return errors.New("not in a game")
 	}
 
-	attackerName := client.Name
+	var payload protocol.NavalAttackPayload
+	if err := msg.ParsePayload(&payload); err != nil {
+		return err
+	}
 
-	if result.AttackerWins {
-		log.Printf("Player %s conquered %s", client.Name, payload.TargetTerritory)
-	} else {
-		log.Printf("Player %s failed to conquer %s", client.Name, payload.TargetTerritory)
+	state, err := h.loadState(client.GameID)
+	if err != nil {
+		return err
 	}
 
-	// Build shared combat result payload
-	buildCombatResult := func(eventID string) protocol.CombatResultPayload {
-		cr := protocol.CombatResultPayload{
+	target := state.Territories[payload.TargetTerritory]
+	if target == nil {
+		return errors.New("target territory not found")
+	}
+	terrName := target.Name
+	defenderID := target.Owner
+	defenderName := state.OwnerName(defenderID)
+
+	result, err := state.NavalAttack(client.PlayerID, payload.TargetTerritory, payload.WaterBodyID)
+	if err != nil {
+		return err
+	}
+
+	h.finishAttack(client, state, result, &protocol.ExecuteAttackPayload{TargetTerritory: payload.TargetTerritory}, terrName, defenderID, defenderName)
+	return nil
+}
+
+// allianceVoteTimeout is how long third parties have to pick a side in a battle.
+const allianceVoteTimeout = 60 * time.Second
+
+// requestAllianceVotes asks third-party players to pick a side in a battle.
+// onResolved receives the players who sided with the attacker and with the
+// defender once every vote is in or the vote times out. With nobody to ask,
+// onResolved runs straight away.
+func (h *Handlers) requestAllianceVotes(client *Client, state *game.GameState, target *game.Territory, askPlayers []string, idPrefix string, onResolved func(attackerVotes, defenderVotes []string)) {
+	if len(askPlayers) == 0 {
+		onResolved(nil, nil)
+		return
+	}
+
+	log.Printf("Waiting for %d alliance votes from: %v", len(askPlayers), askPlayers)
+
+	g := h.hub.Game(client.GameID)
+	battleID := fmt.Sprintf("%s-%s-%d", idPrefix, client.GameID, time.Now().UnixNano())
+	battle := &PendingBattle{
+		ID:           battleID,
+		GameID:       client.GameID,
+		AttackerID:   client.PlayerID,
+		DefenderID:   target.Owner,
+		TerritoryID:  target.ID,
+		ThirdParties: askPlayers,
+		Votes:        make(map[string]string),
+		ExpiresAt:    time.Now().Add(allianceVoteTimeout),
+	}
+	battle.OnResolved = func() {
+		var attackerVotes, defenderVotes []string
+		for playerID, side := range battle.Votes {
+			switch side {
+			case "attacker":
+				attackerVotes = append(attackerVotes, playerID)
+				log.Printf("  %s voted for attacker", playerID)
+			case "defender":
+				defenderVotes = append(defenderVotes, playerID)
+				log.Printf("  %s voted for defender", playerID)
+			default:
+				log.Printf("  %s voted neutral", playerID)
+			}
+		}
+		onResolved(attackerVotes, defenderVotes)
+	}
+
+	g.pendingBattles[battleID] = battle
+	battle.timer = g.After(allianceVoteTimeout, func() {
+		if g.pendingBattles[battleID] != battle {
+			return
+		}
+		log.Printf("Alliance vote timeout, proceeding with %d/%d votes", len(battle.Votes), len(askPlayers))
+		h.resolveAllianceVote(g, battle)
+	})
+
+	// Send alliance requests to all "ask" players
+	defenderName := state.OwnerName(target.Owner)
+	if defenderName == "" {
+		defenderName = "Unclaimed"
+	}
+	for _, askID := range askPlayers {
+		request := protocol.AllianceRequestPayload{
+			BattleID:      battleID,
+			AttackerID:    client.PlayerID,
+			AttackerName:  client.Name,
+			DefenderID:    target.Owner,
+			DefenderName:  defenderName,
+			TerritoryID:   target.ID,
+			TerritoryName: target.Name,
+			YourStrength:  state.CalculatePlayerStrengthAtTerritory(askID, target),
+			TimeLimit:     int(allianceVoteTimeout / time.Second),
+			ExpiresAt:     battle.ExpiresAt.Unix(),
+		}
+
+		if askPlayer := state.Players[askID]; askPlayer != nil {
+			log.Printf("Sending alliance request to %s (%s)", askID, askPlayer.Name)
+		}
+		h.hub.sendToPlayer(askID, protocol.TypeAllianceRequest, request)
+	}
+}
+
+// resolveAllianceVote closes a battle's vote and resumes the waiting attack.
+func (h *Handlers) resolveAllianceVote(g *GameActor, battle *PendingBattle) {
+	delete(g.pendingBattles, battle.ID)
+	g.Cancel(battle.timer)
+	battle.OnResolved()
+}
+
+// finishAttack handles the common post-combat logic for both classic and card combat.
+func (h *Handlers) finishAttack(client *Client, state *game.GameState, result *game.CombatResult, payload *protocol.ExecuteAttackPayload, terrName, defenderID, defenderName string) {
+	if err := h.recordAttack(client, state, result, terrName); err != nil {
+		log.Printf("Error saving state after attack: %v", err)
+		return
+	}
+	h.announceAttack(client, state, result, payload, defenderID, defenderName)
+}
+
+// recordAttack logs an attack in the game history and saves the state after it.
+func (h *Handlers) recordAttack(client *Client, state *game.GameState, result *game.CombatResult, terrName string) error {
+	// Log history event
+	if result.Raid {
+		outcome := "was driven off"
+		if result.AttackerWins {
+			outcome = fmt.Sprintf("carried off %d resources", result.StockpileCaptured.Total())
+		}
+		h.logHistory(client.GameID, state.Round, state.Phase.String(), client.PlayerID, client.Name,
+			database.EventRaid, fmt.Sprintf("Raided %s and %s", terrName, outcome))
+	} else if result.Naval {
+		outcome := "lost a boat"
+		if result.AttackerWins && len(result.UnitsCaptured) > 0 {
+			outcome = "captured a boat"
+		} else if result.AttackerWins {
+			outcome = "sank a boat"
+		}
+		h.logHistory(client.GameID, state.Round, state.Phase.String(), client.PlayerID, client.Name,
+			database.EventNavalBattle, fmt.Sprintf("Fought the fleet off %s and %s", terrName, outcome))
+	} else if result.AttackerWins {
+		h.logHistory(client.GameID, state.Round, state.Phase.String(), client.PlayerID, client.Name,
+			database.EventAttackSuccess, fmt.Sprintf("Captured %s", terrName))
+	} else {
+		h.logHistory(client.GameID, state.Round, state.Phase.String(), client.PlayerID, client.Name,
+			database.EventAttackFailed, fmt.Sprintf("Attack on %s failed", terrName))
+	}
+	h.logFreedVassal(client.GameID, state, result.FreedVassal)
+
+	// Save updated state
+	return h.saveState(client.GameID, state)
+}
+
+// announceAttack sends a recorded attack's result to the players and ends the
+// game if the attack won it; otherwise play resumes once they've seen it.
+func (h *Handlers) announceAttack(client *Client, state *game.GameState, result *game.CombatResult, payload *protocol.ExecuteAttackPayload, defenderID, defenderName string) {
+	// Convert result to protocol format
+	unitsDestroyed := make([]string, 0)
+	for _, u := range result.UnitsDestroyed {
+		unitsDestroyed = append(unitsDestroyed, u.TerritoryID)
+	}
+	unitsCaptured := make([]string, 0)
+	for _, u := range result.UnitsCaptured {
+		unitsCaptured = append(unitsCaptured, u.TerritoryID)
+	}
+
+	attackerName := client.Name
+
+	if result.Raid {
+		log.Printf("Player %s raided %s (success: %v)", client.Name, payload.TargetTerritory, result.AttackerWins)
+	} else if result.Naval {
+		log.Printf("Player %s fought the fleet off %s (success: %v)", client.Name, payload.TargetTerritory, result.AttackerWins)
+	} else if result.AttackerWins {
+		log.Printf("Player %s conquered %s", client.Name, payload.TargetTerritory)
+	} else {
+		log.Printf("Player %s failed to conquer %s", client.Name, payload.TargetTerritory)
+	}
+
+	// Build shared combat result payload
+	buildCombatResult := func(eventID string) protocol.CombatResultPayload {
+		cr := protocol.CombatResultPayload{
 			EventID:         eventID,
 			Success:         true,
 			AttackerID:      client.PlayerID,
@@ -3594,6 +3985,9 @@ func (h *Handlers) finishAttack(client *Client, state *game.GameState, result *g
 			cr.CapturedTimber = result.StockpileCaptured.Timber
 			cr.CapturedFromTerritory = payload.TargetTerritory
 		}
+		cr.FreedVassal = result.FreedVassal
+		cr.Raid = result.Raid
+		cr.Naval = result.Naval
 		return cr
 	}
 
@@ -3613,7 +4007,7 @@ func (h *Handlers) finishAttack(client *Client, state *game.GameState, result *g
 	gameID := client.GameID
 	h.broadcastWithAck(gameID, eventID, protocol.EventCombat, protocol.TypeActionResult, combatResult, func() {
 		if !h.broadcastGameState(gameID) {
-			go h.checkAndTriggerAI(gameID)
+			h.scheduleAI(gameID)
 		}
 	})
 }
@@ -3632,27 +4026,17 @@ func (h *Handlers) executeCardAttack(
 	if attacker == nil {
 		return errors.New("attacker not found")
 	}
-
-	// Validate and remove attacker's selected attack cards from hand
-	attackCards := attacker.RemoveCardsFromHand(payload.AttackCardIDs)
-	// Verify all requested cards were found and are attack cards
-	validAttackCards := make([]game.CombatCard, 0, len(attackCards))
-	for _, c := range attackCards {
-		if c.CardType == game.CardTypeAttack {
-			validAttackCards = append(validAttackCards, c)
-		}
+	if state.PendingCardBattle != nil {
+		return game.ErrCardBattlePending
 	}
 
-	log.Printf("Card combat: %s attacking %s with %d cards", client.Name, terrName, len(validAttackCards))
-
-	// Calculate base strengths for preview
-	target := state.Territories[payload.TargetTerritory]
-	baseAttack := state.CalculateAttackWithAllies(client.PlayerID, target, brought, attackerAllies)
-	baseDefense := state.CalculateDefenseWithAllies(target, defenderAllies)
-
 	// If defender is unclaimed or AI, resolve immediately with no defense cards
 	defender := state.Players[defenderID]
 	if defenderID == "" || defender == nil || defender.IsAI {
+		// Validate and remove attacker's selected attack cards from hand
+		attackCards := attacker.TakeCards(game.CardTypeAttack, payload.AttackCardIDs)
+		log.Printf("Card combat: %s attacking %s with %d cards", client.Name, terrName, len(attackCards))
+
 		// AI card defense: simple heuristic - play all defense cards if under threat
 		var defenseCards []game.CombatCard
 		if defender != nil && defender.IsAI && len(defender.DefenseCards) > 0 {
@@ -3662,86 +4046,156 @@ func (h *Handlers) executeCardAttack(
 			defender.DefenseCards = []game.CombatCard{} // Remove all from hand
 		}
 
-		return h.resolveCardCombat(client, state, payload, brought, attackerAllies, defenderAllies, validAttackCards, defenseCards, terrName, defenderID, defenderName)
+		return h.resolveCardCombat(client, state, payload, brought, attackerAllies, defenderAllies, attackCards, defenseCards, terrName, defenderID, defenderName)
 	}
 
-	// Save state (attacker's cards have been removed from hand)
-	stateJSON, err := json.Marshal(state)
-	if err != nil {
+	// Human defender: the attacker's cards leave their hand now and the battle
+	// is saved with the state, so it resumes after a restart
+	battle := &game.PendingCardBattle{
+		ID:              fmt.Sprintf("card-battle-%s-%d", client.GameID, time.Now().UnixNano()),
+		AttackerID:      client.PlayerID,
+		DefenderID:      defenderID,
+		TargetTerritory: payload.TargetTerritory,
+		BroughtUnit:     brought,
+		AttackerAllies:  attackerAllies,
+		DefenderAllies:  defenderAllies,
+	}
+	if err := state.BeginCardBattle(battle, payload.AttackCardIDs); err != nil {
 		return err
 	}
-	if err := h.hub.server.db.SaveGameState(client.GameID, string(stateJSON),
-		state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
+	if err := h.saveState(client.GameID, state); err != nil {
 		return err
 	}
 
-	// Create pending card battle and wait for defender's cards
-	battleID := fmt.Sprintf("card-battle-%s-%d", client.GameID, time.Now().UnixNano())
-	pendingBattle := &PendingCardBattle{
-		ID:               battleID,
-		GameID:           client.GameID,
-		AttackerID:       client.PlayerID,
-		DefenderID:       defenderID,
-		TargetTerritory:  payload.TargetTerritory,
-		BroughtUnit:      brought,
-		AttackerAllies:   attackerAllies,
-		DefenderAllies:   defenderAllies,
-		AttackCards:      validAttackCards,
-		DefenseCardsChan: make(chan []string, 1),
-	}
+	log.Printf("Card combat: %s attacking %s with %d cards", client.Name, terrName, len(battle.AttackCards))
 
-	h.hub.mu.Lock()
-	h.hub.pendingCardBattles[battleID] = pendingBattle
-	h.hub.mu.Unlock()
+	h.requestDefenseCards(client.GameID, state)
+	h.broadcastGameState(client.GameID)
 
-	// Notify defender to select defense cards
-	request := protocol.DefenseCardRequestPayload{
-		BattleID:          battleID,
-		AttackerID:        client.PlayerID,
-		AttackerName:      client.Name,
-		TargetTerritory:   payload.TargetTerritory,
-		TerritoryName:     terrName,
-		BaseAttackStr:     baseAttack,
-		BaseDefenseStr:    baseDefense,
-		AttackerCardCount: len(validAttackCards),
-	}
-	h.hub.sendToPlayer(defenderID, protocol.TypeDefenseCardRequest, request)
+	return nil
+}
 
-	log.Printf("Card combat: Waiting for defender %s to select defense cards (battle %s)", defenderID, battleID)
+// defenseCardTimeout is how long a defender has to choose defense cards.
+// A variable so tests can shorten it.
+var defenseCardTimeout = 60 * time.Second
+
+// requestDefenseCards starts the defender's clock on the card battle saved in
+// the state and asks them to choose defense cards. If they don't answer in
+// time, the battle resolves without defense cards.
+func (h *Handlers) requestDefenseCards(gameID string, state *game.GameState) {
+	battle := state.PendingCardBattle
+	g := h.hub.Game(gameID)
+	pending := &PendingCardBattle{
+		ID:         battle.ID,
+		GameID:     gameID,
+		DefenderID: battle.DefenderID,
+		ExpiresAt:  time.Now().Add(defenseCardTimeout),
+	}
+	g.pendingCardBattles[battle.ID] = pending
+	pending.timer = g.After(defenseCardTimeout, func() {
+		if g.pendingCardBattles[battle.ID] != pending {
+			return
+		}
+		log.Printf("Card combat: defender %s ran out of time in battle %s", battle.DefenderID, battle.ID)
+		h.resolveDefenseCards(gameID, battle.ID, nil)
+	})
 
-	// Wait for defender's card selection (no timeout per plan)
-	defenseCardIDs := <-pendingBattle.DefenseCardsChan
+	h.sendDefenseCardRequest(state, pending)
+	log.Printf("Card combat: Waiting for defender %s to select defense cards (battle %s)", battle.DefenderID, battle.ID)
+}
 
-	// Clean up pending battle
-	h.hub.mu.Lock()
-	delete(h.hub.pendingCardBattles, battleID)
-	h.hub.mu.Unlock()
+// sendDefenseCardRequest asks a card battle's defender to choose defense cards.
+func (h *Handlers) sendDefenseCardRequest(state *game.GameState, pending *PendingCardBattle) {
+	battle := state.PendingCardBattle
+	target := state.Territories[battle.TargetTerritory]
+	if target == nil {
+		return
+	}
+	h.hub.sendToPlayer(battle.DefenderID, protocol.TypeDefenseCardRequest, protocol.DefenseCardRequestPayload{
+		BattleID:          battle.ID,
+		AttackerID:        battle.AttackerID,
+		AttackerName:      state.OwnerName(battle.AttackerID),
+		TargetTerritory:   battle.TargetTerritory,
+		TerritoryName:     target.Name,
+		BaseAttackStr:     state.CalculateAttackWithAllies(battle.AttackerID, target, battle.BroughtUnit, battle.AttackerAllies),
+		BaseDefenseStr:    state.CalculateDefenseWithAllies(target, battle.DefenderAllies),
+		AttackerCardCount: len(battle.AttackCards),
+		TimeLimit:         int(defenseCardTimeout / time.Second),
+		ExpiresAt:         pending.ExpiresAt.Unix(),
+	})
+}
 
-	// Re-load state (may have changed)
-	stateJSON2, err := h.hub.server.db.GetGameState(client.GameID)
+// resendDefenseCardRequest asks a reconnecting defender again for the defense
+// cards of the battle waiting on them. Runs on the game's actor.
+func (h *Handlers) resendDefenseCardRequest(gameID, playerID string) {
+	state, err := h.loadState(gameID)
+	if err != nil || state.PendingCardBattle == nil || state.PendingCardBattle.DefenderID != playerID {
+		return
+	}
+	if pending := h.hub.Game(gameID).pendingCardBattles[state.PendingCardBattle.ID]; pending != nil {
+		h.sendDefenseCardRequest(state, pending)
+	}
+}
+
+// resolveDefenseCards resolves the card battle waiting on a game's defender
+// with the defense cards they chose, or none if they ran out of time. Runs on
+// the game's actor.
+func (h *Handlers) resolveDefenseCards(gameID, battleID string, defenseCardIDs []string) {
+	g := h.hub.Game(gameID)
+	if pending := g.pendingCardBattles[battleID]; pending != nil {
+		g.Cancel(pending.timer)
+		delete(g.pendingCardBattles, battleID)
+	}
+
+	state, err := h.loadState(gameID)
 	if err != nil {
-		return err
+		log.Printf("Card combat: reload state error: %v", err)
+		return
 	}
-	var freshState game.GameState
-	if err := json.Unmarshal([]byte(stateJSON2), &freshState); err != nil {
-		return err
+	if state.PendingCardBattle == nil || state.PendingCardBattle.ID != battleID {
+		return
 	}
+	battle := state.EndCardBattle()
 
 	// Remove defender's selected defense cards from hand
-	defenderPlayer := freshState.Players[defenderID]
 	var defenseCards []game.CombatCard
-	if defenderPlayer != nil {
-		removed := defenderPlayer.RemoveCardsFromHand(defenseCardIDs)
-		for _, c := range removed {
-			if c.CardType == game.CardTypeDefense {
-				defenseCards = append(defenseCards, c)
-			}
-		}
+	if defender := state.Players[battle.DefenderID]; defender != nil {
+		defenseCards = defender.TakeCards(game.CardTypeDefense, defenseCardIDs)
 	}
+	log.Printf("Card combat: Defender %s selected %d defense cards", battle.DefenderID, len(defenseCards))
 
-	log.Printf("Card combat: Defender %s selected %d defense cards", defenderID, len(defenseCards))
+	terrName := battle.TargetTerritory
+	if target := state.Territories[battle.TargetTerritory]; target != nil {
+		terrName = target.Name
+	}
+	attacker := &Client{
+		hub:      h.hub,
+		PlayerID: battle.AttackerID,
+		GameID:   gameID,
+		Name:     state.OwnerName(battle.AttackerID),
+	}
+	payload := &protocol.ExecuteAttackPayload{TargetTerritory: battle.TargetTerritory}
+	err = h.resolveCardCombat(attacker, state, payload, battle.BroughtUnit, battle.AttackerAllies, battle.DefenderAllies,
+		battle.AttackCards, defenseCards, terrName, battle.DefenderID, state.OwnerName(battle.DefenderID))
+	if err == nil {
+		return
+	}
 
-	return h.resolveCardCombat(client, &freshState, payload, brought, attackerAllies, defenderAllies, validAttackCards, defenseCards, terrName, defenderID, defenderName)
+	// The battle can't go ahead after all; hand the cards back
+	log.Printf("Card combat: resolve error: %v", err)
+	if p := state.Players[battle.AttackerID]; p != nil {
+		p.ReturnCardsToHand(battle.AttackCards)
+	}
+	if p := state.Players[battle.DefenderID]; p != nil {
+		p.ReturnCardsToHand(defenseCards)
+	}
+	if err := h.saveState(gameID, state); err != nil {
+		log.Printf("Card combat: save state error: %v", err)
+		return
+	}
+	if !h.broadcastGameState(gameID) {
+		h.scheduleAI(gameID)
+	}
 }
 
 // resolveCardCombat resolves a card combat and broadcasts the result.
@@ -3799,17 +4253,34 @@ func (h *Handlers) resolveCardCombat(
 		BlitzReturnCount: len(cardResult.BlitzReturn),
 	}
 
-	// Send card reveal synchronously - wait for all human players to ack before proceeding
-	// Use a channel to block until the card reveal is acknowledged
+	// Show the card reveal first; once every human player has dismissed it,
+	// announce the result with the standard flow
 	if len(cardReveal.AttackerCards) > 0 || len(cardReveal.DefenderCards) > 0 {
-		revealDone := make(chan struct{})
-		h.broadcastWithAck(client.GameID, cardRevealEventID, protocol.EventCardReveal, protocol.TypeCardReveal, cardReveal, func() {
-			close(revealDone)
+		// Record the battle now, so commands handled while the reveal is on
+		// screen see the outcome and a restart during it loses only the reveal
+		if err := h.recordAttack(client, state, result, terrName); err != nil {
+			return err
+		}
+		gameID := client.GameID
+		if state.IsEliminationVictory() {
+			// The game is over; nothing waits on the reveal
+			h.hub.notifyGamePlayers(gameID, protocol.TypeCardReveal, cardReveal)
+			h.announceAttack(client, state, result, payload, defenderID, defenderName)
+			return nil
+		}
+		h.broadcastWithAck(gameID, cardRevealEventID, protocol.EventCardReveal, protocol.TypeCardReveal, cardReveal, func() {
+			// Commands may have run during the reveal; announce from the state as
+			// it stands now rather than the one from before the wait
+			fresh, err := h.loadState(gameID)
+			if err != nil {
+				log.Printf("Card combat: reload state after reveal: %v", err)
+				return
+			}
+			h.announceAttack(client, fresh, result, payload, defenderID, defenderName)
 		})
-		<-revealDone // Block until all players have dismissed the card reveal
+		return nil
 	}
 
-	// Now finish the attack with the standard flow (save state, broadcast combat result, etc.)
 	h.finishAttack(client, state, result, payload, terrName, defenderID, defenderName)
 
 	return nil
@@ -3844,16 +4315,11 @@ func (h *Handlers) handleBuyCard(client *Client, msg *protocol.Message) error {
 	}
 
 	// Load game state
-	stateJSON, err := h.hub.server.db.GetGameState(client.GameID)
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		return err
-	}
-
 	// Parse card type
 	var cardType game.CardType
 	switch payload.CardType {
@@ -3865,19 +4331,9 @@ func (h *Handlers) handleBuyCard(client *Client, msg *protocol.Message) error {
 		return errors.New("invalid card type: " + payload.CardType)
 	}
 
-	// Parse resource
-	var resource game.ResourceType
-	switch payload.Resource {
-	case "coal":
-		resource = game.ResourceCoal
-	case "gold":
-		resource = game.ResourceGold
-	case "iron":
-		resource = game.ResourceIron
-	case "timber":
-		resource = game.ResourceTimber
-	default:
-		return errors.New("invalid resource: " + payload.Resource)
+	resource, err := parseResource(payload.Resource)
+	if err != nil {
+		return err
 	}
 
 	// Buy the card
@@ -3887,16 +4343,22 @@ func (h *Handlers) handleBuyCard(client *Client, msg *protocol.Message) error {
 	}
 
 	// Save state
-	stateJSON2, err := json.Marshal(state)
-	if err != nil {
-		return err
-	}
-	if err := h.hub.server.db.SaveGameState(client.GameID, string(stateJSON2),
-		state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
+	if err := h.saveState(client.GameID, state); err != nil {
 		return err
 	}
 
-	// Send the drawn card back to the player
+	sendCardDrawn(client, msg.ID, card)
+
+	log.Printf("Player %s bought %s card: %s (%s)", client.Name, cardType, card.Name, card.Rarity)
+
+	// Broadcast updated game state
+	h.broadcastGameState(client.GameID)
+
+	return nil
+}
+
+// sendCardDrawn sends a player the card they just got.
+func sendCardDrawn(client *Client, msgID string, card *game.CombatCard) {
 	cardDrawn := protocol.CardDrawnPayload{
 		Card: protocol.CardInfo{
 			ID:          card.ID,
@@ -3909,12 +4371,81 @@ func (h *Handlers) handleBuyCard(client *Client, msg *protocol.Message) error {
 		},
 	}
 	respMsg, _ := protocol.NewMessage(protocol.TypeCardDrawn, cardDrawn)
-	respMsg.ID = msg.ID
+	respMsg.ID = msgID
 	client.Send(respMsg)
+}
 
-	log.Printf("Player %s bought %s card: %s (%s)", client.Name, cardType, card.Name, card.Rarity)
+// handleDiscardCard handles discarding a combat card for resources during
+// Development.
+func (h *Handlers) handleDiscardCard(client *Client, msg *protocol.Message) error {
+	if client.GameID == "" {
+		return errors.New("not in a game")
+	}
+
+	var payload protocol.DiscardCardPayload
+	if err := msg.ParsePayload(&payload); err != nil {
+		return err
+	}
+
+	state, err := h.loadState(client.GameID)
+	if err != nil {
+		return err
+	}
+
+	resource, err := parseResource(payload.Resource)
+	if err != nil {
+		return err
+	}
+
+	amount, err := state.DiscardCard(client.PlayerID, payload.CardID, resource)
+	if err != nil {
+		return err
+	}
+
+	if err := h.saveState(client.GameID, state); err != nil {
+		return err
+	}
+
+	log.Printf("Player %s discarded card %s for %d %s", client.Name, payload.CardID, amount, resource)
+
+	h.broadcastGameState(client.GameID)
+
+	return nil
+}
+
+// handleCombineCards handles trading two common combat cards for an uncommon
+// one during Development.
+func (h *Handlers) handleCombineCards(client *Client, msg *protocol.Message) error {
+	if client.GameID == "" {
+		return errors.New("not in a game")
+	}
+
+	var payload protocol.CombineCardsPayload
+	if err := msg.ParsePayload(&payload); err != nil {
+		return err
+	}
+	if len(payload.CardIDs) != 2 {
+		return errors.New("combine exactly two cards")
+	}
+
+	state, err := h.loadState(client.GameID)
+	if err != nil {
+		return err
+	}
+
+	card, err := state.CombineCards(client.PlayerID, payload.CardIDs[0], payload.CardIDs[1])
+	if err != nil {
+		return err
+	}
+
+	if err := h.saveState(client.GameID, state); err != nil {
+		return err
+	}
+
+	sendCardDrawn(client, msg.ID, card)
+
+	log.Printf("Player %s combined two cards into %s (%s)", client.Name, card.Name, card.Rarity)
 
-	// Broadcast updated game state
 	h.broadcastGameState(client.GameID)
 
 	return nil
@@ -3928,15 +4459,14 @@ func (h *Handlers) handleSelectDefenseCards(client *Client, msg *protocol.Messag
 	}
 
 	// Find the pending card battle for this defender
-	h.hub.mu.Lock()
+	g := h.hub.Game(client.GameID)
 	var battle *PendingCardBattle
-	for _, b := range h.hub.pendingCardBattles {
-		if b.DefenderID == client.PlayerID && b.GameID == client.GameID {
+	for _, b := range g.pendingCardBattles {
+		if b.DefenderID == client.PlayerID {
 			battle = b
 			break
 		}
 	}
-	h.hub.mu.Unlock()
 
 	if battle == nil {
 		return errors.New("no pending card battle found")
@@ -3944,8 +4474,8 @@ func (h *Handlers) handleSelectDefenseCards(client *Client, msg *protocol.Messag
 
 	log.Printf("Defender %s selected %d defense cards for battle %s", client.Name, len(payload.CardIDs), battle.ID)
 
-	// Send the card IDs through the channel to unblock the attacker's goroutine
-	battle.DefenseCardsChan <- payload.CardIDs
+	// Resume the attack that was waiting on the defender
+	h.resolveDefenseCards(client.GameID, battle.ID, payload.CardIDs)
 
 	return nil
 }
@@ -3962,16 +4492,11 @@ func (h *Handlers) handleBuild(client *Client, msg *protocol.Message) error {
 	}
 
 	// Load game state
-	stateJSON, err := h.hub.server.db.GetGameState(client.GameID)
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		return err
-	}
-
 	// Convert type string to BuildType
 	var buildType game.BuildType
 	switch payload.Type {
@@ -3981,6 +4506,20 @@ func (h *Handlers) handleBuild(client *Client, msg *protocol.Message) error {
 		buildType = game.BuildWeapon
 	case "boat":
 		buildType = game.BuildBoat
+	case "fortress":
+		buildType = game.BuildFortress
+	case "siege":
+		buildType = game.BuildSiege
+	case "transport":
+		buildType = game.BuildTransport
+	case "mine":
+		buildType = game.BuildMine
+	case "lumber_mill":
+		buildType = game.BuildLumberMill
+	case "stable":
+		buildType = game.BuildStable
+	case "depot":
+		buildType = game.BuildDepot
 	default:
 		return game.ErrInvalidTarget
 	}
@@ -3996,6 +4535,10 @@ func (h *Handlers) handleBuild(client *Client, msg *protocol.Message) error {
 		if err := state.BuildBoatInWater(client.PlayerID, payload.Territory, payload.WaterBodyID, payload.UseGold); err != nil {
 			return err
 		}
+	} else if buildType == game.BuildTransport && payload.WaterBodyID != "" {
+		if err := state.BuildTransportInWater(client.PlayerID, payload.Territory, payload.WaterBodyID, payload.UseGold); err != nil {
+			return err
+		}
 	} else {
 		if err := state.Build(client.PlayerID, buildType, payload.Territory, payload.UseGold); err != nil {
 			return err
@@ -4007,13 +4550,7 @@ func (h *Handlers) handleBuild(client *Client, msg *protocol.Message) error {
 		database.EventBuild, fmt.Sprintf("Built %s on %s", payload.Type, terrName))
 
 	// Save updated state
-	stateJSON2, err := json.Marshal(state)
-	if err != nil {
-		return err
-	}
-
-	if err := h.hub.server.db.SaveGameState(client.GameID, string(stateJSON2),
-		state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
+	if err := h.saveState(client.GameID, state); err != nil {
 		return err
 	}
 
@@ -4066,11 +4603,7 @@ func (h *Handlers) sendGameHistory(client *Client, gameID string) {
 
 // broadcastGameHistory sends the game history to all players in a game.
 func (h *Handlers) broadcastGameHistory(gameID string) {
-	h.hub.mu.RLock()
-	clients := h.hub.gameClients[gameID]
-	h.hub.mu.RUnlock()
-
-	for client := range clients {
+	for _, client := range h.hub.clientsInGame(gameID) {
 		h.sendGameHistory(client, gameID)
 	}
 }
@@ -4117,16 +4650,11 @@ func (h *Handlers) handleSetAlliance(client *Client, msg *protocol.Message) erro
 	}
 
 	// Update game state
-	stateJSON, err := h.hub.server.db.GetGameState(client.GameID)
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		return err
-	}
-
 	if player := state.Players[client.PlayerID]; player != nil {
 		log.Printf("Updating player %s alliance from '%s' to '%s'", client.PlayerID, player.Alliance, setting)
 		player.Alliance = game.AllianceSetting(setting)
@@ -4135,20 +4663,16 @@ func (h *Handlers) handleSetAlliance(client *Client, msg *protocol.Message) erro
 	}
 
 	// Save updated state
-	newStateJSON, err := json.Marshal(state)
-	if err != nil {
-		return err
-	}
-	if err := h.hub.server.db.SaveGameState(client.GameID, string(newStateJSON), state.CurrentPlayerID, state.Round, string(state.Phase)); err != nil {
+	if err := h.saveState(client.GameID, state); err != nil {
 		log.Printf("Failed to save game state: %v", err)
 		return err
 	}
 
 	// Verify the save by re-reading
 	verifyJSON, _ := h.hub.server.db.GetGameState(client.GameID)
-	var verifyState game.GameState
-	json.Unmarshal([]byte(verifyJSON), &verifyState)
-	if verifyPlayer := verifyState.Players[client.PlayerID]; verifyPlayer != nil {
+	if verifyState, err := game.UnmarshalState([]byte(verifyJSON)); err != nil {
+		log.Printf("Failed to re-read game state: %v", err)
+	} else if verifyPlayer := verifyState.Players[client.PlayerID]; verifyPlayer != nil {
 		log.Printf("Verified: Player %s alliance is now '%s' in saved state", client.PlayerID, verifyPlayer.Alliance)
 	}
 
@@ -4182,16 +4706,11 @@ func (h *Handlers) handleSurrender(client *Client, msg *protocol.Message) error
 	}
 
 	// Load game state
-	stateJSON, err := h.hub.server.db.GetGameState(client.GameID)
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		return err
-	}
-
 	// Verify surrendering player is not already eliminated
 	surrenderPlayer := state.Players[client.PlayerID]
 	if surrenderPlayer == nil {
@@ -4222,11 +4741,7 @@ func (h *Handlers) handleSurrender(client *Client, msg *protocol.Message) error
 			surrenderPlayer.Name, targetPlayer.Name, territoriesTransferred))
 
 	// Save updated state
-	newStateJSON, err := json.Marshal(state)
-	if err != nil {
-		return err
-	}
-	if err := h.hub.server.db.SaveGameState(client.GameID, string(newStateJSON), state.CurrentPlayerID, state.Round, string(state.Phase)); err != nil {
+	if err := h.saveState(client.GameID, state); err != nil {
 		log.Printf("Failed to save game state: %v", err)
 		return err
 	}
@@ -4243,7 +4758,7 @@ func (h *Handlers) handleSurrender(client *Client, msg *protocol.Message) error
 
 	// Check for victory (only one non-eliminated player remaining)
 	if state.IsEliminationVictory() {
-		h.handleGameOver(client.GameID, &state)
+		h.handleGameOver(client.GameID, state)
 		return nil
 	}
 
@@ -4253,6 +4768,112 @@ func (h *Handlers) handleSurrender(client *Client, msg *protocol.Message) error
 	return nil
 }
 
+// handleOfferVassalage handles a player offering to become another player's vassal.
+func (h *Handlers) handleOfferVassalage(client *Client, msg *protocol.Message) error {
+	if client.GameID == "" {
+		return errors.New("not in a game")
+	}
+
+	var payload protocol.OfferVassalagePayload
+	if err := msg.ParsePayload(&payload); err != nil {
+		return err
+	}
+
+	state, err := h.loadState(client.GameID)
+	if err != nil {
+		return err
+	}
+	if err := state.OfferVassalage(client.PlayerID, payload.TargetPlayerID); err != nil {
+		return err
+	}
+	vassal := state.Players[client.PlayerID]
+	overlord := state.Players[payload.TargetPlayerID]
+
+	log.Printf("Player %s offered to become the vassal of %s", vassal.Name, overlord.Name)
+	h.logHistory(client.GameID, state.Round, state.Phase.String(), vassal.ID, vassal.Name,
+		database.EventVassalage, fmt.Sprintf("%s offered to serve %s", vassal.Name, overlord.Name))
+
+	// The AI never turns down a vassal
+	if overlord.IsAI {
+		return h.answerVassalage(client.GameID, state, overlord.ID, vassal.ID, true)
+	}
+
+	if err := h.saveState(client.GameID, state); err != nil {
+		return err
+	}
+	h.hub.sendToPlayer(overlord.ID, protocol.TypeVassalageOffer, protocol.VassalageOfferPayload{
+		VassalID:   vassal.ID,
+		VassalName: vassal.Name,
+	})
+	h.broadcastGameState(client.GameID)
+	return nil
+}
+
+// handleAnswerVassalage handles a player accepting or declining an offer of vassalage.
+func (h *Handlers) handleAnswerVassalage(client *Client, msg *protocol.Message) error {
+	if client.GameID == "" {
+		return errors.New("not in a game")
+	}
+
+	var payload protocol.AnswerVassalagePayload
+	if err := msg.ParsePayload(&payload); err != nil {
+		return err
+	}
+
+	state, err := h.loadState(client.GameID)
+	if err != nil {
+		return err
+	}
+	return h.answerVassalage(client.GameID, state, client.PlayerID, payload.VassalID, payload.Accept)
+}
+
+// answerVassalage applies an overlord's answer to an offer of vassalage and
+// tells everyone how it went.
+func (h *Handlers) answerVassalage(gameID string, state *game.GameState, overlordID, vassalID string, accept bool) error {
+	if err := state.AnswerVassalage(overlordID, vassalID, accept); err != nil {
+		return err
+	}
+	overlord, vassal := state.Players[overlordID], state.Players[vassalID]
+
+	result := protocol.VassalageResultPayload{
+		Result:       protocol.VassalageDeclined,
+		VassalID:     vassalID,
+		VassalName:   vassal.Name,
+		OverlordID:   overlordID,
+		OverlordName: overlord.Name,
+	}
+	message := fmt.Sprintf("%s turned down %s's offer of service", overlord.Name, vassal.Name)
+	if accept {
+		result.Result = protocol.VassalageSworn
+		message = fmt.Sprintf("%s became the vassal of %s", vassal.Name, overlord.Name)
+	}
+	h.logHistory(gameID, state.Round, state.Phase.String(), overlordID, overlord.Name, database.EventVassalage, message)
+
+	if err := h.saveState(gameID, state); err != nil {
+		return err
+	}
+	h.hub.notifyGamePlayers(gameID, protocol.TypeVassalageResult, result)
+
+	// A vassal no longer counts as a rival, which may leave a single player standing
+	if state.IsEliminationVictory() {
+		h.handleGameOver(gameID, state)
+		return nil
+	}
+
+	h.broadcastGameState(gameID)
+	return nil
+}
+
+// logFreedVassal records a vassal winning their freedom in battle.
+func (h *Handlers) logFreedVassal(gameID string, state *game.GameState, vassalID string) {
+	vassal := state.Players[vassalID]
+	if vassal == nil {
+		return
+	}
+	h.logHistory(gameID, state.Round, state.Phase.String(), vassal.ID, vassal.Name,
+		database.EventVassalage, fmt.Sprintf("%s won their freedom in battle", vassal.Name))
+}
+
 // handleAllianceVote handles a player's vote during a battle.
 func (h *Handlers) handleAllianceVote(client *Client, msg *protocol.Message) error {
 	if client.GameID == "" {
@@ -4270,21 +4891,12 @@ func (h *Handlers) handleAllianceVote(client *Client, msg *protocol.Message) err
 	}
 
 	// Record the vote in the pending battle
-	h.hub.mu.Lock()
-	battle := h.hub.pendingBattles[payload.BattleID]
-	if battle != nil {
-		battle.Votes[client.PlayerID] = payload.Side
-		// Signal that we received a vote
-		select {
-		case battle.VoteChan <- client.PlayerID:
-		default:
-		}
-	}
-	h.hub.mu.Unlock()
-
+	g := h.hub.Game(client.GameID)
+	battle := g.pendingBattles[payload.BattleID]
 	if battle == nil {
 		return errors.New("battle not found or already resolved")
 	}
+	battle.Votes[client.PlayerID] = payload.Side
 
 	// Send confirmation
 	result := protocol.AllianceResultPayload{
@@ -4295,6 +4907,11 @@ func (h *Handlers) handleAllianceVote(client *Client, msg *protocol.Message) err
 	client.Send(respMsg)
 
 	log.Printf("Player %s voted %s for battle %s", client.PlayerID, payload.Side, payload.BattleID)
+
+	// Resume the attack once everyone asked has voted
+	if len(battle.Votes) >= len(battle.ThirdParties) {
+		h.resolveAllianceVote(g, battle)
+	}
 	return nil
 }
 
@@ -4346,7 +4963,7 @@ func (h *Handlers) handleClientReady(client *Client, msg *protocol.Message) erro
 	}
 
 	log.Printf("Client %s ready for event %s (%s)", client.PlayerID, payload.EventID, payload.EventType)
-	h.hub.AcknowledgeEvent(payload.EventID, client.PlayerID)
+	h.hub.Game(client.GameID).AcknowledgeEvent(payload.EventID, client.PlayerID)
 	return nil
 }
 
@@ -4363,7 +4980,7 @@ func (h *Handlers) broadcastWithAck(gameID, eventID, eventType string, msgType p
 	h.hub.notifyGamePlayers(gameID, msgType, payload)
 
 	// Create pending event and wait for acks
-	h.hub.CreatePendingEvent(eventID, eventType, gameID, humanPlayers, onComplete)
+	h.hub.Game(gameID).CreatePendingEvent(eventID, eventType, humanPlayers, onComplete)
 }
 
 // triggerProductionAnimation sends production results to all players for animation.
@@ -4379,7 +4996,9 @@ func (h *Handlers) triggerProductionAnimation(gameID string, state *game.GameSta
 	eventID := fmt.Sprintf("production-%s-%d", gameID, time.Now().UnixNano())
 	playersToWaitFor := make([]string, 0)
 
-	// Send production results to each player
+	// Apply each player's production and tribute, then settle economies once
+	// it's all in
+	payloads := make(map[string]*protocol.ProductionResultsPayload)
 	for _, player := range state.Players {
 		if player.Eliminated {
 			continue
@@ -4407,7 +5026,7 @@ func (h *Handlers) triggerProductionAnimation(gameID string, state *game.GameSta
 			}
 		}
 
-		payload := protocol.ProductionResultsPayload{
+		payload := &protocol.ProductionResultsPayload{
 			EventID:                eventID,
 			PlayerID:               player.ID,
 			Productions:            protoProductions,
@@ -4417,6 +5036,28 @@ func (h *Handlers) triggerProductionAnimation(gameID string, state *game.GameSta
 
 		// Apply production to state immediately (animation is just visual)
 		pm.ApplyProductionResults(player.ID, productions)
+		if tribute := state.PayTribute(player.ID, productions); tribute != nil {
+			payload.Tribute = resourceAmounts(tribute)
+		}
+		payloads[player.ID] = payload
+		log.Printf("Applied production for player %s: %d productions", player.Name, len(productions))
+	}
+	reports := state.SettleEconomies()
+
+	// Send production results to each player
+	for playerID, payload := range payloads {
+		player := state.Players[playerID]
+		if report := reports[playerID]; report != nil {
+			payload.Upkeep = resourceAmounts(report.Upkeep)
+			payload.Spoiled = resourceAmounts(report.Spoiled)
+			for _, d := range report.Disbanded {
+				payload.Disbanded = append(payload.Disbanded, protocol.DisbandedUnit{
+					TerritoryID:   d.TerritoryID,
+					TerritoryName: d.TerritoryName,
+					UnitType:      d.Unit,
+				})
+			}
+		}
 
 		// Send to this player only (if online)
 		if h.hub.IsPlayerOnline(player.ID) {
@@ -4427,18 +5068,21 @@ func (h *Handlers) triggerProductionAnimation(gameID string, state *game.GameSta
 				playersToWaitFor = append(playersToWaitFor, player.ID)
 			}
 		}
-
-		log.Printf("Sent production results to player %s: %d productions", player.Name, len(productions))
 	}
 
+	// Production is now in the stockpiles. Recording that in the saved state means
+	// a restart finishes the phase instead of producing a second time.
+	state.ProductionPending = false
+	state.ProductionApplied = true
+
 	// Save state with production applied
-	stateJSON, _ := json.Marshal(state)
-	h.hub.server.db.SaveGameState(gameID, string(stateJSON),
-		state.CurrentPlayerID, state.Round, state.Phase.String())
+	if err := h.saveState(gameID, state); err != nil {
+		log.Printf("Failed to save game state: %v", err)
+	}
 
 	// Wait for all human players to acknowledge
 	if len(playersToWaitFor) > 0 {
-		h.hub.CreatePendingEvent(eventID, protocol.EventProduction, gameID, playersToWaitFor, func() {
+		h.hub.Game(gameID).CreatePendingEvent(eventID, protocol.EventProduction, playersToWaitFor, func() {
 			// All players acknowledged - complete production phase
 			h.completeProductionPhase(gameID)
 		})
@@ -4448,34 +5092,44 @@ func (h *Handlers) triggerProductionAnimation(gameID string, state *game.GameSta
 	}
 }
 
+// resourceAmounts lists the non-zero resources in a stockpile by name, or
+// nil if there are none.
+func resourceAmounts(s *game.Stockpile) map[string]int {
+	var amounts map[string]int
+	for _, r := range []game.ResourceType{game.ResourceCoal, game.ResourceGold, game.ResourceIron, game.ResourceTimber} {
+		if n := s.Get(r); n > 0 {
+			if amounts == nil {
+				amounts = make(map[string]int)
+			}
+			amounts[r.String()] = n
+		}
+	}
+	return amounts
+}
+
 // completeProductionPhase finishes production and advances to the next phase.
 func (h *Handlers) completeProductionPhase(gameID string) {
 	log.Printf("Completing production phase for game %s", gameID)
 
 	// Load current state
-	stateJSON, err := h.hub.server.db.GetGameState(gameID)
+	state, err := h.loadState(gameID)
 	if err != nil {
 		log.Printf("Failed to load game state: %v", err)
 		return
 	}
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		log.Printf("Failed to unmarshal game state: %v", err)
-		return
-	}
-
 	// Advance phase
 	state.CompleteProduction()
 
 	// Save updated state
-	stateJSON2, _ := json.Marshal(state)
-	h.hub.server.db.SaveGameState(gameID, string(stateJSON2),
-		state.CurrentPlayerID, state.Round, state.Phase.String())
+	if err := h.saveState(gameID, state); err != nil {
+		log.Printf("Failed to save game state: %v", err)
+		return
+	}
 
 	// Broadcast new state (may have phase skips)
 	if !h.broadcastGameState(gameID) {
-		go h.checkAndTriggerAI(gameID)
+		h.scheduleAI(gameID)
 	}
 }
 
@@ -4491,16 +5145,11 @@ func (h *Handlers) handleDrawTerritory(client *Client, msg *protocol.Message) er
 	}
 
 	// Load game state
-	stateJSON, err := h.hub.server.db.GetGameState(client.GameID)
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		return err
-	}
-
 	// Validate territory exists and player owns it
 	terr, ok := state.Territories[payload.TerritoryID]
 	if !ok {
@@ -4540,13 +5189,7 @@ func (h *Handlers) handleDrawTerritory(client *Client, msg *protocol.Message) er
 	}
 
 	// Save updated state
-	stateJSON2, err := json.Marshal(state)
-	if err != nil {
-		return err
-	}
-
-	if err := h.hub.server.db.SaveGameState(client.GameID, string(stateJSON2),
-		state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
+	if err := h.saveState(client.GameID, state); err != nil {
 		return err
 	}
 
@@ -4570,16 +5213,11 @@ func (h *Handlers) handleRenameTerritory(client *Client, msg *protocol.Message)
 	}
 
 	// Load game state
-	stateJSON, err := h.hub.server.db.GetGameState(client.GameID)
+	state, err := h.loadState(client.GameID)
 	if err != nil {
 		return err
 	}
 
-	var state game.GameState
-	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
-		return err
-	}
-
 	// Get old name for history
 	oldName := payload.TerritoryID
 	if terr, ok := state.Territories[payload.TerritoryID]; ok {
@@ -4596,13 +5234,7 @@ func (h *Handlers) handleRenameTerritory(client *Client, msg *protocol.Message)
 		database.EventTerritoryRenamed, fmt.Sprintf("Renamed %s to %s", oldName, payload.Name))
 
 	// Save updated state
-	stateJSON2, err := json.Marshal(state)
-	if err != nil {
-		return err
-	}
-
-	if err := h.hub.server.db.SaveGameState(client.GameID, string(stateJSON2),
-		state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
+	if err := h.saveState(client.GameID, state); err != nil {
 		return err
 	}
 
@@ -4613,3 +5245,58 @@ func (h *Handlers) handleRenameTerritory(client *Client, msg *protocol.Message)
 
 	return nil
 }
+
+// ==================== Recovery ====================
+
+// RecoverGames resumes every game that was in progress when the server stopped.
+// Trades, alliance votes and attack plans only reach the saved state once they
+// resolve, so after a restart they simply never happened and the players can
+// start them again. Two interactions leave a mark: a production that was
+// applied but not yet acknowledged, which recovery finishes, and a card battle
+// waiting for the defender, whose clock recovery starts again. In a cluster,
+// each node only recovers the games it holds (or can claim) the lease on.
+func (h *Handlers) RecoverGames() {
+	gameIDs, err := h.hub.server.db.GetStartedGameIDs()
+	if err != nil {
+		log.Printf("Recovery: failed to list games in progress: %v", err)
+		return
+	}
+
+	recovered := 0
+	for _, gameID := range gameIDs {
+		if !h.hub.ownsGame(gameID) {
+			continue
+		}
+		recovered++
+		h.hub.PostToGame(gameID, func() {
+			h.recoverGame(gameID)
+		})
+	}
+	log.Printf("Recovery: resuming %d of %d games in progress", recovered, len(gameIDs))
+}
+
+// recoverGame brings a single game back after a restart. Runs on the game's actor.
+func (h *Handlers) recoverGame(gameID string) {
+	state, err := h.loadState(gameID)
+	if err != nil {
+		log.Printf("Recovery: failed to load game %s: %v", gameID, err)
+		h.hub.releaseGameIfIdle(gameID)
+		return
+	}
+
+	if state.PendingCardBattle != nil {
+		log.Printf("Recovery: resuming card battle %s for game %s", state.PendingCardBattle.ID, gameID)
+		h.requestDefenseCards(gameID, state)
+	}
+
+	if state.ProductionApplied {
+		log.Printf("Recovery: finishing interrupted production for game %s", gameID)
+		h.completeProductionPhase(gameID)
+	} else if !h.broadcastGameState(gameID) {
+		// Pending production or phase skips run from broadcastGameState; otherwise
+		// wake up the AI in case it was mid-turn
+		h.scheduleAI(gameID)
+	}
+
+	h.hub.releaseGameIfIdle(gameID)
+}
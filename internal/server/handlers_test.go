@@ -0,0 +1,350 @@
+package server
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"lords-of-conquest/internal/database"
+	"lords-of-conquest/internal/game"
+	"lords-of-conquest/internal/protocol"
+	"lords-of-conquest/pkg/maps"
+)
+
+// newTestServer creates a single-instance server with its own database.
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	return openTestServer(t, filepath.Join(t.TempDir(), "lords.db"))
+}
+
+// openTestServer creates a single-instance server on an existing database
+// file, as a server restarting would.
+func openTestServer(t *testing.T, dbPath string) *Server {
+	t.Helper()
+	s, err := New(Config{DBPath: dbPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.db.Close() })
+	return s
+}
+
+// startTestGame creates a game in the database with the given state saved as
+// its game in progress, and a client for each named player in the state. The
+// state's player IDs are replaced by the database's.
+func startTestGame(t *testing.T, s *Server, state *game.GameState) (string, map[string]*Client) {
+	t.Helper()
+	clients := make(map[string]*Client)
+	players := make(map[string]*game.Player)
+	var host string
+	for _, name := range state.PlayerOrder {
+		dbPlayer, err := s.db.CreatePlayer(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if host == "" {
+			host = dbPlayer.ID
+		}
+		p := state.Players[name]
+		p.ID = dbPlayer.ID
+		players[dbPlayer.ID] = p
+		clients[name] = &Client{hub: s.hub, send: make(chan *protocol.Message, 64), Name: name, PlayerID: dbPlayer.ID}
+		if state.CurrentPlayerID == name {
+			state.CurrentPlayerID = dbPlayer.ID
+		}
+	}
+	for i, name := range state.PlayerOrder {
+		state.PlayerOrder[i] = clients[name].PlayerID
+	}
+	state.Players = players
+
+	dbGame, err := s.db.CreateGame("Test", host, database.GameSettings{MaxPlayers: 4}, true, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.ID = dbGame.ID
+	if err := s.db.StartGame(dbGame.ID); err != nil {
+		t.Fatal(err)
+	}
+	saveTestState(t, s, dbGame.ID, state)
+	for _, c := range clients {
+		c.GameID = dbGame.ID
+	}
+	return dbGame.ID, clients
+}
+
+// saveTestState writes a game state straight to the database.
+func saveTestState(t *testing.T, s *Server, gameID string, state *game.GameState) {
+	t.Helper()
+	data, err := game.MarshalState(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.db.SaveGameState(gameID, string(data), state.CurrentPlayerID, state.Round, state.Phase.String()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// savedState reads a game's state back from the database.
+func savedState(t *testing.T, s *Server, gameID string) *game.GameState {
+	t.Helper()
+	data, err := s.db.GetGameState(gameID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := game.UnmarshalState([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+// drain waits until everything queued on a game's actor so far has run.
+func drain(t *testing.T, g *GameActor) {
+	t.Helper()
+	done := make(chan struct{})
+	if err := g.Post(func() { close(done) }); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("actor did not run queued commands")
+	}
+}
+
+func TestMarketTradeRunsOnActor(t *testing.T) {
+	s := newTestServer(t)
+	gameID, clients := startTestGame(t, s, &game.GameState{
+		Settings:        game.Settings{Market: true},
+		Phase:           game.PhaseTrade,
+		Round:           1,
+		CurrentPlayerID: "Trader",
+		PlayerOrder:     []string{"Trader"},
+		Players:         map[string]*game.Player{"Trader": {Name: "Trader", Stockpile: &game.Stockpile{Coal: 6}}},
+		Territories:     map[string]*game.Territory{},
+		Market:          game.NewMarket(1),
+	})
+	trader := clients["Trader"]
+
+	// Hold the actor so the trade has to wait its turn
+	actor := s.hub.Game(gameID)
+	release := make(chan struct{})
+	actor.Post(func() { <-release })
+
+	msg, _ := protocol.NewMessage(protocol.TypeMarketTrade, protocol.MarketTradePayload{Sell: "coal", Amount: 5, Buy: "iron"})
+	NewHandlers(s.hub).Handle(trader, msg)
+	if coal := savedState(t, s, gameID).Players[trader.PlayerID].Stockpile.Coal; coal != 6 {
+		t.Errorf("trade ran outside the actor: coal = %d while the actor was busy", coal)
+	}
+
+	close(release)
+	drain(t, actor)
+	if stockpile := savedState(t, s, gameID).Players[trader.PlayerID].Stockpile; stockpile.Coal != 1 || stockpile.Iron != 4 {
+		t.Errorf("after trade: stockpile %+v, want 1 coal and 4 iron", *stockpile)
+	}
+}
+
+func TestCardBattleSurvivesRestart(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "lords.db")
+	s := openTestServer(t, dbPath)
+	skirmish := game.CombatCard{ID: "skirmish", CardType: game.CardTypeAttack, Effect: game.EffectSkirmish, Value: 1}
+	fortify := game.CombatCard{ID: "fortify", CardType: game.CardTypeDefense, Effect: game.EffectFortify, Value: 1}
+	state := &game.GameState{
+		Settings:        game.Settings{CombatMode: game.CombatModeCards},
+		Phase:           game.PhaseConquest,
+		Round:           2,
+		CurrentPlayerID: "Attacker",
+		PlayerOrder:     []string{"Attacker", "Defender"},
+		Players: map[string]*game.Player{
+			"Attacker": {Name: "Attacker", Stockpile: game.NewStockpile(), AttacksRemaining: 2, AttackCards: []game.CombatCard{skirmish}},
+			"Defender": {Name: "Defender", Stockpile: game.NewStockpile(), DefenseCards: []game.CombatCard{fortify}},
+		},
+		Territories: map[string]*game.Territory{
+			"a": {ID: "a", Name: "Alpha", Adjacent: []string{"b"}},
+			"b": {ID: "b", Name: "Beta", Adjacent: []string{"a"}},
+		},
+	}
+	gameID, clients := startTestGame(t, s, state)
+	attacker, defender := clients["Attacker"], clients["Defender"]
+	state.Territories["a"].Owner = attacker.PlayerID
+	state.Territories["b"].Owner = defender.PlayerID
+	saveTestState(t, s, gameID, state)
+
+	// Committing the cards takes them out of hand and saves the battle
+	h := NewHandlers(s.hub)
+	attack, _ := protocol.NewMessage(protocol.TypeExecuteAttack, protocol.ExecuteAttackPayload{TargetTerritory: "b", AttackCardIDs: []string{skirmish.ID}})
+	h.Handle(attacker, attack)
+	drain(t, s.hub.Game(gameID))
+	saved := savedState(t, s, gameID)
+	if saved.PendingCardBattle == nil || len(saved.PendingCardBattle.AttackCards) != 1 {
+		t.Fatalf("pending card battle not saved: %+v", saved.PendingCardBattle)
+	}
+	if cards := saved.Players[attacker.PlayerID].AttackCards; len(cards) != 0 {
+		t.Errorf("attacker still holds %v while the battle waits", cards)
+	}
+
+	// A second attack is refused while the first waits
+	h.Handle(attacker, attack)
+	drain(t, s.hub.Game(gameID))
+	select {
+	case msg := <-attacker.send:
+		var payload protocol.ErrorPayload
+		if msg.Type != protocol.TypeError || msg.ParsePayload(&payload) != nil || payload.Message != game.ErrCardBattlePending.Error() {
+			t.Errorf("second attack answered with %s %s", msg.Type, msg.Payload)
+		}
+	default:
+		t.Error("second attack was not refused")
+	}
+
+	// Restart: the new server restores the battle, and the defender's clock
+	// runs out without an answer
+	s.hub.RemoveGame(gameID)
+	s.db.Close()
+	defer func(timeout time.Duration) { defenseCardTimeout = timeout }(defenseCardTimeout)
+	defenseCardTimeout = 20 * time.Millisecond
+
+	s = openTestServer(t, dbPath)
+	NewHandlers(s.hub).RecoverGames()
+	deadline := time.Now().Add(5 * time.Second)
+	for saved = savedState(t, s, gameID); saved.PendingCardBattle != nil; saved = savedState(t, s, gameID) {
+		if time.Now().After(deadline) {
+			t.Fatal("recovered card battle never resolved")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	drain(t, s.hub.Game(gameID))
+	saved = savedState(t, s, gameID)
+
+	if remaining := saved.Players[attacker.PlayerID].AttacksRemaining; remaining == 2 {
+		t.Error("the recovered battle did not use up an attack")
+	}
+	if cards := saved.Players[defender.PlayerID].DefenseCards; len(cards) != 1 {
+		t.Errorf("defender's cards after timing out: %v, want fortify still in hand", cards)
+	}
+	if cards := saved.Players[attacker.PlayerID].AttackCards; len(cards) != 0 {
+		t.Errorf("attacker got %v back after the battle", cards)
+	}
+}
+
+func TestCardRevealRecordsAttackFirst(t *testing.T) {
+	s := newTestServer(t)
+	skirmish := game.CombatCard{ID: "skirmish", CardType: game.CardTypeAttack, Effect: game.EffectSkirmish, Value: 1}
+	state := &game.GameState{
+		Settings:        game.Settings{CombatMode: game.CombatModeCards},
+		Phase:           game.PhaseConquest,
+		Round:           2,
+		CurrentPlayerID: "Attacker",
+		PlayerOrder:     []string{"Attacker", "Defender", "Bystander"},
+		Players: map[string]*game.Player{
+			"Attacker":  {Name: "Attacker", Stockpile: game.NewStockpile(), AttacksRemaining: 2, AttackCards: []game.CombatCard{skirmish}},
+			"Defender":  {Name: "Defender", Stockpile: game.NewStockpile(), IsAI: true},
+			"Bystander": {Name: "Bystander", Stockpile: game.NewStockpile(), IsAI: true},
+		},
+		Territories: map[string]*game.Territory{
+			"a": {ID: "a", Name: "Alpha", Adjacent: []string{"b"}},
+			"b": {ID: "b", Name: "Beta", Adjacent: []string{"a"}},
+			"c": {ID: "c", Name: "Gamma"},
+		},
+	}
+	gameID, clients := startTestGame(t, s, state)
+	attacker := clients["Attacker"]
+	state.Territories["a"].Owner = attacker.PlayerID
+	state.Territories["b"].Owner = clients["Defender"].PlayerID
+	state.Territories["c"].Owner = clients["Bystander"].PlayerID
+	saveTestState(t, s, gameID, state)
+	s.hub.AddClientToGame(attacker, gameID)
+
+	// The attacker is connected, so the reveal waits for their ack; the
+	// battle is already recorded while it does
+	h := NewHandlers(s.hub)
+	attack, _ := protocol.NewMessage(protocol.TypeExecuteAttack, protocol.ExecuteAttackPayload{TargetTerritory: "b", AttackCardIDs: []string{skirmish.ID}})
+	h.Handle(attacker, attack)
+	g := s.hub.Game(gameID)
+	drain(t, g)
+	g.Post(func() {
+		if len(g.pendingEvents) != 1 {
+			t.Errorf("%d events waiting on acks, want the card reveal", len(g.pendingEvents))
+		}
+	})
+	drain(t, g)
+
+	saved := savedState(t, s, gameID)
+	if saved.PendingCardBattle != nil {
+		t.Errorf("card battle still pending during the reveal: %+v", saved.PendingCardBattle)
+	}
+	if remaining := saved.Players[attacker.PlayerID].AttacksRemaining; remaining != 1 {
+		t.Errorf("attacks remaining during the reveal = %d, want 1", remaining)
+	}
+	events, err := s.db.GetGameHistory(gameID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logged := false
+	for _, e := range events {
+		logged = logged || e.EventType == database.EventAttackSuccess || e.EventType == database.EventAttackFailed
+	}
+	if !logged {
+		t.Error("attack not in the history until the reveal is acknowledged")
+	}
+}
+
+func TestStartGameChecksPresetSeats(t *testing.T) {
+	s := newTestServer(t)
+	h := NewHandlers(s.hub)
+	db := s.db
+
+	host, err := db.CreatePlayer("Host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbGame, err := db.CreateGame("Test", host.ID, database.GameSettings{MaxPlayers: 4, PresetStarts: true}, true, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.JoinGame(dbGame.ID, host.ID, "orange"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SetPlayerReady(dbGame.ID, host.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	for _, color := range []string{"cyan", "green"} {
+		if err := db.AddAIPlayer(dbGame.ID, color, "balanced"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A mirrored map has starts for two seats, not three
+	opts := maps.DefaultOptions()
+	opts.Symmetry = maps.SymmetryMirror
+	mirrored, _ := maps.NewGenerator(opts).Generate()
+	setMap := func(raw *maps.RawMap) {
+		data, err := json.Marshal(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.UpdateGameMap(dbGame.ID, string(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setMap(mirrored.Raw())
+
+	client := &Client{hub: s.hub, send: make(chan *protocol.Message, 64), Name: "Host", PlayerID: host.ID, GameID: dbGame.ID}
+	start, _ := protocol.NewMessage(protocol.TypeStartGame, nil)
+	if err := h.handleStartGame(client, start); err == nil {
+		t.Fatal("started a three-player game on a two-seat map")
+	}
+	if got, _ := db.GetGame(dbGame.ID); got.Status != database.GameStatusWaiting {
+		t.Errorf("game status = %s, want it still in the lobby", got.Status)
+	}
+
+	// Preset starts can't be turned on for a map without starts
+	noStarts := mirrored.Raw()
+	noStarts.Starts = nil
+	setMap(noStarts)
+	update, _ := protocol.NewMessage(protocol.TypeUpdateSettings, protocol.UpdateSettingPayload{Key: "presetStarts", Value: "true"})
+	if err := h.handleUpdateSettings(client, update); err != errNoStarts {
+		t.Errorf("turning on preset starts: err = %v, want %v", err, errNoStarts)
+	}
+}
@@ -0,0 +1,257 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+
+	"lords-of-conquest/internal/database"
+	"lords-of-conquest/internal/protocol"
+	"lords-of-conquest/pkg/maps"
+)
+
+// maxMapNameLength is the longest name a published map can have.
+const maxMapNameLength = 40
+
+// handleListMaps sends a page of the map library.
+func (h *Handlers) handleListMaps(client *Client, msg *protocol.Message) error {
+	var payload protocol.ListMapsPayload
+	if err := msg.ParsePayload(&payload); err != nil {
+		return err
+	}
+
+	limit := payload.Limit
+	if limit <= 0 || limit > protocol.MapListLimit {
+		limit = protocol.MapListLimit
+	}
+
+	libraryMaps, err := h.hub.server.db.ListLibraryMaps(database.MapOrder(payload.Sort), limit)
+	if err != nil {
+		return err
+	}
+
+	response := protocol.MapListPayload{Maps: make([]protocol.MapListItem, len(libraryMaps))}
+	for i, m := range libraryMaps {
+		response.Maps[i] = mapListItem(m)
+	}
+	respMsg, _ := protocol.NewMessage(protocol.TypeMapList, response)
+	respMsg.ID = msg.ID
+	client.Send(respMsg)
+
+	return nil
+}
+
+// handleGetMap sends a library map with its full map data for previewing.
+func (h *Handlers) handleGetMap(client *Client, msg *protocol.Message) error {
+	var payload protocol.GetMapPayload
+	if err := msg.ParsePayload(&payload); err != nil {
+		return err
+	}
+
+	libraryMap, err := h.hub.server.db.GetLibraryMap(payload.MapID)
+	if err != nil {
+		return err
+	}
+	raw, err := libraryRawMap(libraryMap)
+	if err != nil {
+		return err
+	}
+
+	// RawMap and MapData share a JSON shape
+	var mapData protocol.MapData
+	mapJSON, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(mapJSON, &mapData); err != nil {
+		return err
+	}
+
+	response := protocol.MapPreviewPayload{
+		Map:     mapListItem(libraryMap),
+		MapData: &mapData,
+	}
+	respMsg, _ := protocol.NewMessage(protocol.TypeMapPreview, response)
+	respMsg.ID = msg.ID
+	client.Send(respMsg)
+
+	return nil
+}
+
+// handlePublishMap validates a map and adds it to the library.
+func (h *Handlers) handlePublishMap(client *Client, msg *protocol.Message) error {
+	if client.PlayerID == "" {
+		return errors.New("not authenticated")
+	}
+
+	var payload protocol.PublishMapPayload
+	if err := msg.ParsePayload(&payload); err != nil {
+		return err
+	}
+	if payload.MapData == nil {
+		return errors.New("no map data provided")
+	}
+
+	name := strings.TrimSpace(payload.Name)
+	if name == "" {
+		name = strings.TrimSpace(payload.MapData.Name)
+	}
+	if name == "" {
+		return errors.New("map name is required")
+	}
+	if len(name) > maxMapNameLength {
+		return errors.New("map name is too long")
+	}
+
+	// RawMap and MapData share a JSON shape
+	var raw maps.RawMap
+	mapJSON, err := json.Marshal(payload.MapData)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(mapJSON, &raw); err != nil {
+		return err
+	}
+	raw.Name = name
+	m, err := maps.LoadRaw(&raw)
+	if err != nil {
+		return err
+	}
+	mapJSON, err = json.Marshal(&raw)
+	if err != nil {
+		return err
+	}
+
+	libraryMap, err := h.hub.server.db.PublishMap(client.PlayerID, name, raw.Width, raw.Height, len(m.Territories), string(mapJSON))
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Player %s published map %q (%s)", client.Name, name, libraryMap.ID)
+
+	response := protocol.MapPublishedPayload{Map: mapListItem(libraryMap)}
+	respMsg, _ := protocol.NewMessage(protocol.TypeMapPublished, response)
+	respMsg.ID = msg.ID
+	client.Send(respMsg)
+
+	return nil
+}
+
+// handleRateMap records the player's rating of a library map.
+func (h *Handlers) handleRateMap(client *Client, msg *protocol.Message) error {
+	if client.PlayerID == "" {
+		return errors.New("not authenticated")
+	}
+
+	var payload protocol.RateMapPayload
+	if err := msg.ParsePayload(&payload); err != nil {
+		return err
+	}
+	if payload.Rating < protocol.MinMapRating || payload.Rating > protocol.MaxMapRating {
+		return errors.New("rating must be between 1 and 5")
+	}
+
+	db := h.hub.server.db
+	if _, err := db.GetLibraryMap(payload.MapID); err != nil {
+		return err
+	}
+	if err := db.RateMap(payload.MapID, client.PlayerID, payload.Rating); err != nil {
+		return err
+	}
+
+	// Reply with the new average
+	libraryMap, err := db.GetLibraryMap(payload.MapID)
+	if err != nil {
+		return err
+	}
+	response := protocol.MapRatedPayload{Map: mapListItem(libraryMap)}
+	respMsg, _ := protocol.NewMessage(protocol.TypeMapRated, response)
+	respMsg.ID = msg.ID
+	client.Send(respMsg)
+
+	return nil
+}
+
+// handleSelectMap handles the host picking a library map for their lobby.
+func (h *Handlers) handleSelectMap(client *Client, msg *protocol.Message) error {
+	if client.GameID == "" {
+		return errors.New("not in a game")
+	}
+
+	db := h.hub.server.db
+
+	// Verify client is host and the game hasn't started
+	game, err := db.GetGame(client.GameID)
+	if err != nil {
+		return err
+	}
+	if game.HostPlayerID != client.PlayerID {
+		return errors.New("only host can change the map")
+	}
+	if game.Status != database.GameStatusWaiting {
+		return errors.New("game has already started")
+	}
+
+	var payload protocol.SelectMapPayload
+	if err := msg.ParsePayload(&payload); err != nil {
+		return err
+	}
+
+	libraryMap, err := db.GetLibraryMap(payload.MapID)
+	if err != nil {
+		return err
+	}
+	raw, err := libraryRawMap(libraryMap)
+	if err != nil {
+		return err
+	}
+	if game.Settings.PresetStarts && len(raw.Starts) == 0 {
+		return errNoStarts
+	}
+	mapJSON, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	if err := db.UpdateGameMap(client.GameID, string(mapJSON)); err != nil {
+		return err
+	}
+	if err := db.UpdateGameSetting(client.GameID, "map_id", libraryMap.ID); err != nil {
+		return err
+	}
+
+	log.Printf("Host %s selected library map %q for game %s", client.Name, libraryMap.Name, client.GameID)
+
+	// Broadcast updated lobby state
+	h.broadcastLobbyState(client.GameID)
+
+	return nil
+}
+
+// libraryRawMap decodes a library map, using its library ID as the map ID so
+// games on it can be traced back to the library.
+func libraryRawMap(m *database.LibraryMap) (*maps.RawMap, error) {
+	var raw maps.RawMap
+	if err := json.Unmarshal([]byte(m.MapJSON), &raw); err != nil {
+		return nil, err
+	}
+	raw.ID = m.ID
+	raw.Name = m.Name
+	return &raw, nil
+}
+
+// mapListItem converts a library map to its protocol summary.
+func mapListItem(m *database.LibraryMap) protocol.MapListItem {
+	return protocol.MapListItem{
+		ID:             m.ID,
+		Name:           m.Name,
+		AuthorName:     m.AuthorName,
+		Width:          m.Width,
+		Height:         m.Height,
+		TerritoryCount: m.TerritoryCount,
+		PlayCount:      m.PlayCount,
+		Rating:         m.Rating,
+		RatingCount:    m.RatingCount,
+	}
+}
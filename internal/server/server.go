@@ -6,12 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"sync"
 	"time"
 
 	"lords-of-conquest/internal/database"
-	game "lords-of-conquest/internal/game"
 	"lords-of-conquest/internal/protocol"
 
 	"github.com/coder/websocket"
@@ -19,31 +19,54 @@ import (
 
 // Server is the main game server.
 type Server struct {
-	db     *database.DB
-	hub    *Hub
-	addr   string
-	server *http.Server
+	db        database.Store
+	hub       *Hub
+	addr      string
+	busAddr   string
+	server    *http.Server
+	busServer *http.Server
 }
 
 // Config holds server configuration.
 type Config struct {
 	Addr   string
 	DBPath string
+
+	// DatabaseURL selects PostgreSQL instead of the SQLite file at DBPath
+	DatabaseURL string
+
+	// NodeID and Bus are set when several instances share the database.
+	// Leave Bus nil to run a single instance that owns every game.
+	NodeID string
+	Bus    Bus
+
+	// BusAddr is where a bus that serves HTTP listens for its peers. It must
+	// differ from Addr so the bus is never exposed on the public game port.
+	BusAddr string
 }
 
 // New creates a new server.
 func New(cfg Config) (*Server, error) {
-	db, err := database.New(cfg.DBPath)
+	var db database.Store
+	var err error
+	if cfg.DatabaseURL != "" {
+		db, err = database.NewPostgres(cfg.DatabaseURL)
+	} else {
+		db, err = database.New(cfg.DBPath)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
 	s := &Server{
-		db:   db,
-		addr: cfg.Addr,
+		db:      db,
+		addr:    cfg.Addr,
+		busAddr: cfg.BusAddr,
 	}
 
 	s.hub = NewHub(s)
+	s.hub.node = cfg.NodeID
+	s.hub.bus = cfg.Bus
 
 	return s, nil
 }
@@ -64,6 +87,19 @@ func (s *Server) Start() error {
 	// API endpoints for listing games (can be used by web clients too)
 	mux.HandleFunc("/api/games", s.handleListGames)
 
+	// Instances talk to each other over the bus when clustered, on their own
+	// internal listener
+	if s.hub.clustered() {
+		if handler, ok := s.hub.bus.(http.Handler); ok {
+			if err := s.startBus(handler); err != nil {
+				return err
+			}
+		}
+		if err := s.hub.startCluster(); err != nil {
+			return fmt.Errorf("failed to join cluster: %w", err)
+		}
+	}
+
 	s.server = &http.Server{
 		Addr:    s.addr,
 		Handler: mux,
@@ -76,14 +112,47 @@ func (s *Server) Start() error {
 	log.Printf("")
 	log.Printf("Press Ctrl+C to stop")
 
+	// Resume games that were in progress before the last shutdown
+	NewHandlers(s.hub).RecoverGames()
+
 	// Start the hub
 	go s.hub.Run()
 
 	return s.server.ListenAndServe()
 }
 
+// startBus serves the bus at /bus on the internal bus address.
+func (s *Server) startBus(handler http.Handler) error {
+	if s.busAddr == "" || s.busAddr == s.addr {
+		return fmt.Errorf("bus needs its own address, not the game port %s", s.addr)
+	}
+
+	busMux := http.NewServeMux()
+	busMux.Handle("/bus", handler)
+	s.busServer = &http.Server{
+		Addr:    s.busAddr,
+		Handler: busMux,
+	}
+	ln, err := net.Listen("tcp", s.busAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for the bus: %w", err)
+	}
+	go func() {
+		if err := s.busServer.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.Printf("Bus server error: %v", err)
+		}
+	}()
+	log.Printf("  Bus: http://localhost%s/bus (internal)", s.busAddr)
+	return nil
+}
+
 // Stop gracefully shuts down the server.
 func (s *Server) Stop(ctx context.Context) error {
+	if s.busServer != nil {
+		if err := s.busServer.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
 	if s.server != nil {
 		if err := s.server.Shutdown(ctx); err != nil {
 			return err
@@ -150,8 +219,9 @@ type PendingBattle struct {
 	TerritoryID  string
 	ThirdParties []string          // Player IDs who can vote
 	Votes        map[string]string // PlayerID -> "attacker", "defender", or "neutral"
-	VoteChan     chan string       // Signals when votes arrive
 	ExpiresAt    time.Time
+	OnResolved   func()            // Called once every vote is in or the vote times out
+	timer        *time.Timer
 }
 
 // PendingEvent tracks an event that requires client acknowledgment before proceeding.
@@ -163,20 +233,17 @@ type PendingEvent struct {
 	Timeout      time.Time
 	OnComplete   func()                  // Called when all acks received or timeout
 	completed    bool                    // Prevent double-completion
+	timer        *time.Timer
 }
 
-// PendingCardBattle tracks a card combat waiting for the defender's card selection.
+// PendingCardBattle tracks the defender's clock in a card combat waiting for
+// their card selection. The battle itself is saved in the game state.
 type PendingCardBattle struct {
-	ID               string
-	GameID           string
-	AttackerID       string
-	DefenderID       string
-	TargetTerritory  string
-	BroughtUnit      *game.BroughtUnit
-	AttackerAllies   []string
-	DefenderAllies   []string
-	AttackCards      []game.CombatCard  // Cards the attacker committed
-	DefenseCardsChan chan []string       // Channel to receive defender's card IDs
+	ID         string
+	GameID     string
+	DefenderID string
+	ExpiresAt  time.Time
+	timer      *time.Timer
 }
 
 // PendingAttackPlan stores a resolved attack plan waiting for confirmation.
@@ -210,20 +277,14 @@ type Hub struct {
 	// Clients in each game
 	gameClients map[string]map[*Client]bool
 
-	// Pending battles waiting for alliance votes
-	pendingBattles map[string]*PendingBattle
+	// Actors for games with in-flight activity, by game ID
+	games map[string]*GameActor
 
-	// Pending trades waiting for responses
-	pendingTrades map[string]*PendingTrade
-
-	// Pending events waiting for client acknowledgment
-	pendingEvents map[string]*PendingEvent
-
-	// Pending attack plans waiting for confirmation
-	pendingAttackPlans map[string]*PendingAttackPlan
-
-	// Pending card battles waiting for defender's card selection
-	pendingCardBattles map[string]*PendingCardBattle
+	// Cluster membership: this instance's node ID, the bus to the other
+	// instances and cached game ownership (see cluster.go)
+	node   string
+	bus    Bus
+	owners map[string]ownerLease
 
 	// Register requests
 	register chan *Client
@@ -246,17 +307,15 @@ type ClientMessage struct {
 // NewHub creates a new Hub.
 func NewHub(server *Server) *Hub {
 	return &Hub{
-		server:             server,
-		clients:            make(map[*Client]bool),
-		playerClients:      make(map[string]*Client),
-		gameClients:        make(map[string]map[*Client]bool),
-		pendingBattles:     make(map[string]*PendingBattle),
-		pendingEvents:      make(map[string]*PendingEvent),
-		pendingAttackPlans: make(map[string]*PendingAttackPlan),
-		pendingCardBattles: make(map[string]*PendingCardBattle),
-		register:           make(chan *Client, 100),
-		unregister:         make(chan *Client, 100),
-		broadcast:          make(chan *ClientMessage, 256),
+		server:        server,
+		clients:       make(map[*Client]bool),
+		playerClients: make(map[string]*Client),
+		gameClients:   make(map[string]map[*Client]bool),
+		games:         make(map[string]*GameActor),
+		owners:        make(map[string]ownerLease),
+		register:      make(chan *Client, 100),
+		unregister:    make(chan *Client, 100),
+		broadcast:     make(chan *ClientMessage, 256),
 	}
 }
 
@@ -344,9 +403,17 @@ func (h *Hub) handleDisconnect(client *Client) {
 		}
 	}
 
-	close(client.send)
+	if client.send != nil {
+		close(client.send)
+	}
 	h.mu.Unlock()
 
+	// A player in a game run by another instance: let the owner clean up
+	if h.clustered() && client.node == "" && client.GameID != "" && !shouldNotify {
+		h.detach(client, client.GameID)
+		return
+	}
+
 	// Do database and notification AFTER releasing the lock
 	if gameID != "" {
 		h.server.db.SetPlayerConnected(gameID, playerID, false)
@@ -357,6 +424,7 @@ func (h *Hub) handleDisconnect(client *Client) {
 			PlayerID: playerID,
 			Reason:   "disconnected",
 		})
+		h.releaseGameIfIdle(gameID)
 	}
 }
 
@@ -368,14 +436,7 @@ func (h *Hub) handleMessage(cm *ClientMessage) {
 
 // notifyGamePlayers sends a message to all players in a game.
 func (h *Hub) notifyGamePlayers(gameID string, msgType protocol.MessageType, payload interface{}) {
-	h.mu.RLock()
-	gameClients := h.gameClients[gameID]
-	// Make a slice copy of clients to avoid race conditions during iteration
-	clients := make([]*Client, 0, len(gameClients))
-	for client := range gameClients {
-		clients = append(clients, client)
-	}
-	h.mu.RUnlock()
+	clients := h.clientsInGame(gameID)
 
 	log.Printf("Notifying %d clients in game %s with message type %s", len(clients), gameID, msgType)
 
@@ -449,113 +510,91 @@ func (h *Hub) IsPlayerOnline(playerID string) bool {
 	return ok
 }
 
-// SyncTimeout is how long to wait for client acknowledgments before proceeding anyway.
-const SyncTimeout = 30 * time.Second
+// Game returns the actor that owns a game, starting one if needed. Commands
+// from outside the actor should go through PostToGame instead, since the
+// actor may be released between this call and a Post.
+func (h *Hub) Game(gameID string) *GameActor {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.gameLocked(gameID)
+}
 
-// CreatePendingEvent creates a new event that requires client acknowledgment.
-// requiredPlayers are the human player IDs that must acknowledge.
-// onComplete is called when all acks are received or timeout occurs.
-func (h *Hub) CreatePendingEvent(eventID, eventType, gameID string, requiredPlayers []string, onComplete func()) *PendingEvent {
-	acks := make(map[string]bool)
-	for _, pid := range requiredPlayers {
-		acks[pid] = false
+// gameLocked is Game for callers holding h.mu.
+func (h *Hub) gameLocked(gameID string) *GameActor {
+	g, ok := h.games[gameID]
+	if !ok {
+		g = newGameActor(h, gameID)
+		h.games[gameID] = g
 	}
+	return g
+}
 
-	event := &PendingEvent{
-		ID:           eventID,
-		Type:         eventType,
-		GameID:       gameID,
-		RequiredAcks: acks,
-		Timeout:      time.Now().Add(SyncTimeout),
-		OnComplete:   onComplete,
+// PostToGame queues fn on the actor that runs a game, starting one if
+// needed. The lookup and the post happen under the hub lock, which an idle
+// release also holds while it stops an actor, so fn can't land on an actor
+// that is being discarded.
+func (h *Hub) PostToGame(gameID string, fn func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Actors are only stopped once out of h.games, so this one is running
+	if err := h.gameLocked(gameID).Post(fn); err != nil {
+		log.Printf("Game %s: dropped command: %v", gameID, err)
 	}
+}
 
+// RemoveGame stops and forgets a game's actor (e.g. when the game is deleted).
+func (h *Hub) RemoveGame(gameID string) {
 	h.mu.Lock()
-	h.pendingEvents[eventID] = event
+	g, ok := h.games[gameID]
+	delete(h.games, gameID)
 	h.mu.Unlock()
 
-	// Start timeout goroutine
-	go h.eventTimeoutWatcher(eventID)
-
-	// If no human players need to ack, complete immediately
-	if len(requiredPlayers) == 0 {
-		h.completeEvent(event)
+	if ok {
+		g.Stop()
 	}
-
-	return event
+	h.releaseOwnership(gameID)
 }
 
-// eventTimeoutWatcher waits for timeout and completes the event if not already done.
-func (h *Hub) eventTimeoutWatcher(eventID string) {
+// releaseGameIfIdle discards a game's actor once nobody is watching the game
+// and nothing is in flight. The next command reloads it from the database.
+// The actor is only stopped while its mailbox is empty, under the hub lock
+// that PostToGame holds, so no command posted to it is lost.
+func (h *Hub) releaseGameIfIdle(gameID string) {
 	h.mu.RLock()
-	event, exists := h.pendingEvents[eventID]
+	g, ok := h.games[gameID]
 	h.mu.RUnlock()
-
-	if !exists {
-		return
-	}
-
-	// Wait until timeout
-	time.Sleep(time.Until(event.Timeout))
-
-	h.mu.Lock()
-	event, exists = h.pendingEvents[eventID]
-	if exists && !event.completed {
-		log.Printf("Event %s timed out, proceeding anyway", eventID)
-		h.mu.Unlock()
-		h.completeEvent(event)
-	} else {
-		h.mu.Unlock()
-	}
-}
-
-// AcknowledgeEvent processes a client's acknowledgment of an event.
-func (h *Hub) AcknowledgeEvent(eventID, playerID string) {
-	h.mu.Lock()
-	event, exists := h.pendingEvents[eventID]
-	if !exists {
-		h.mu.Unlock()
-		log.Printf("Ack received for unknown event %s from player %s", eventID, playerID)
+	if !ok {
 		return
 	}
 
-	if _, required := event.RequiredAcks[playerID]; required {
-		event.RequiredAcks[playerID] = true
-		log.Printf("Event %s: received ack from %s", eventID, playerID)
-	}
-
-	// Check if all acks received
-	allAcked := true
-	for _, acked := range event.RequiredAcks {
-		if !acked {
-			allAcked = false
-			break
+	g.Post(func() {
+		if !g.idle() {
+			return
 		}
-	}
-	h.mu.Unlock()
+		h.mu.Lock()
+		if len(h.gameClients[gameID]) > 0 || h.games[gameID] != g || !g.stopIfEmpty() {
+			h.mu.Unlock()
+			return
+		}
+		delete(h.games, gameID)
+		h.mu.Unlock()
 
-	if allAcked {
-		h.completeEvent(event)
-	}
+		log.Printf("Game %s: released idle actor", gameID)
+		h.releaseOwnership(gameID)
+	})
 }
 
-// completeEvent marks an event as complete and calls its callback.
-func (h *Hub) completeEvent(event *PendingEvent) {
-	h.mu.Lock()
-	if event.completed {
-		h.mu.Unlock()
-		return
-	}
-	event.completed = true
-	delete(h.pendingEvents, event.ID)
-	h.mu.Unlock()
-
-	log.Printf("Event %s (%s) completed, proceeding", event.ID, event.Type)
+// clientsInGame returns a snapshot of the clients currently watching a game.
+func (h *Hub) clientsInGame(gameID string) []*Client {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
 
-	// Call the completion callback
-	if event.OnComplete != nil {
-		event.OnComplete()
+	clients := make([]*Client, 0, len(h.gameClients[gameID]))
+	for client := range h.gameClients[gameID] {
+		clients = append(clients, client)
 	}
+	return clients
 }
 
 // GetHumanPlayersInGame returns the player IDs of connected human players in a game.
@@ -580,6 +619,10 @@ type Client struct {
 	conn *websocket.Conn
 	send chan *protocol.Message
 
+	// node is set for clients connected to another instance; their messages
+	// are delivered over the bus instead of a local connection
+	node string
+
 	PlayerID string
 	GameID   string
 	Name     string
@@ -604,6 +647,11 @@ func NewClient(hub *Hub, conn *websocket.Conn) *Client {
 
 // Send queues a message to be sent to the client.
 func (c *Client) Send(msg *protocol.Message) {
+	if c.node != "" {
+		c.hub.sendRemote(c, msg)
+		return
+	}
+
 	select {
 	case c.send <- msg:
 		// Message queued successfully
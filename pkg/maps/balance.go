@@ -0,0 +1,349 @@
+package maps
+
+import (
+	"sort"
+
+	"lords-of-conquest/internal/game"
+)
+
+// seaCost is the travel cost of crossing water between two coastal
+// territories, relative to crossing a land border.
+const seaCost = 2
+
+// BalanceReport describes how evenly a map treats the players on it.
+type BalanceReport struct {
+	Regions     []RegionBalance // Land masses, largest first
+	Chokepoints []int           // Territories whose loss splits their land mass
+	SeaOnly     []int           // Territories off the main land mass, reachable only by boat
+	Seats       []SeatBalance   // Estimated home area of each player
+	Fairness    float64         // Weakest seat value / strongest, 0-1 (1 = perfectly fair)
+}
+
+// RegionBalance describes one land mass.
+type RegionBalance struct {
+	Territories []int
+	Resources   map[game.ResourceType]int
+}
+
+// SeatBalance describes the territories one player would likely hold.
+type SeatBalance struct {
+	Start       int // Territory the seat is centred on
+	Territories []int
+	Resources   map[game.ResourceType]int
+	Value       float64
+}
+
+// Analyze reports the resource distribution, chokepoints and per-seat
+// fairness of a map for the given number of players.
+//
+// Seats are estimated, not taken from a game: starts are spread as far apart
+// as possible, with a sea crossing costing seaCost land moves, and the seats
+// then share out the map the way players do in territory selection.
+func Analyze(m *Map, seats int) *BalanceReport {
+	ids := sortedTerritoryIDs(m)
+	report := &BalanceReport{Fairness: 1}
+	if len(ids) == 0 {
+		return report
+	}
+
+	// Land masses, largest first; everything off the largest needs boats to reach
+	for _, region := range landMasses(m, ids) {
+		report.Regions = append(report.Regions, RegionBalance{
+			Territories: region,
+			Resources:   countResources(m, region),
+		})
+	}
+	for _, region := range report.Regions[1:] {
+		report.SeaOnly = append(report.SeaOnly, region.Territories...)
+	}
+	sort.Ints(report.SeaOnly)
+
+	report.Chokepoints = chokepoints(m, ids)
+
+	report.Seats = estimateSeats(m, ids, report.Regions, seats)
+	if len(report.Seats) > 1 {
+		lowest, highest := report.Seats[0].Value, report.Seats[0].Value
+		for _, seat := range report.Seats[1:] {
+			lowest = min(lowest, seat.Value)
+			highest = max(highest, seat.Value)
+		}
+		if highest > 0 {
+			report.Fairness = lowest / highest
+		}
+	}
+
+	return report
+}
+
+// seatValue scores a seat's home area. Land counts a little, resources count
+// more, and covering more resource types counts most, since cities, weapons
+// and boats each need a mix of resources.
+func seatValue(territories int, resources map[game.ResourceType]int) float64 {
+	value := float64(territories)
+	for _, n := range resources {
+		value += 2*float64(n) + 1
+	}
+	return value
+}
+
+// estimateSeats places seats starts as far apart as possible and then plays
+// out territory selection: seats take turns claiming the unclaimed territory
+// nearest their holdings, preferring ones with resources. Starts are only
+// placed on land masses big enough to hold half a player's share of the map.
+// Maps with starting territories for every seat are taken as they are.
+func estimateSeats(m *Map, ids []int, regions []RegionBalance, seats int) []SeatBalance {
+	seats = clamp(seats, 1, len(ids))
+	if len(m.Starts) == seats {
+		return presetSeats(m)
+	}
+
+	canStart := make(map[int]bool)
+	for _, region := range regions {
+		if len(region.Territories)*seats*2 >= len(ids) || len(canStart) == 0 {
+			for _, tid := range region.Territories {
+				canStart[tid] = true
+			}
+		}
+	}
+
+	// The first start is the territory farthest from an arbitrary one, so it
+	// sits at an edge of the map rather than in the middle
+	starts := []int{farthest(distancesFrom(m, ids, []int{ids[0]}), ids, canStart)}
+	for len(starts) < seats {
+		starts = append(starts, farthest(distancesFrom(m, ids, starts), ids, canStart))
+	}
+
+	owner := make(map[int]int, len(ids))
+	areas := make([][]int, seats)
+	for i, start := range starts {
+		owner[start] = i
+		areas[i] = []int{start}
+	}
+	for len(owner) < len(ids) {
+		for i := range areas {
+			if len(owner) == len(ids) {
+				break
+			}
+			tid := nextClaim(m, ids, areas[i], owner)
+			owner[tid] = i
+			areas[i] = append(areas[i], tid)
+		}
+	}
+
+	result := make([]SeatBalance, seats)
+	for i, start := range starts {
+		sort.Ints(areas[i])
+		resources := countResources(m, areas[i])
+		result[i] = SeatBalance{
+			Start:       start,
+			Territories: areas[i],
+			Resources:   resources,
+			Value:       seatValue(len(areas[i]), resources),
+		}
+	}
+	return result
+}
+
+// presetSeats is the balance of a map whose seats start with the map's own
+// starting territories rather than drafting.
+func presetSeats(m *Map) []SeatBalance {
+	result := make([]SeatBalance, len(m.Starts))
+	for i, start := range m.Starts {
+		area := append([]int(nil), start...)
+		sort.Ints(area)
+		resources := countResources(m, area)
+		result[i] = SeatBalance{
+			Territories: area,
+			Resources:   resources,
+			Value:       seatValue(len(area), resources),
+		}
+		if len(area) > 0 {
+			result[i].Start = area[0]
+		}
+	}
+	return result
+}
+
+// nextClaim picks the unclaimed territory a seat holding area takes next:
+// one bordering it by land if possible, then one across water, then any.
+func nextClaim(m *Map, ids []int, area []int, owner map[int]int) int {
+	byLand := make(map[int]bool)
+	bySea := make(map[int]bool)
+	for _, tid := range area {
+		t := m.Territories[tid]
+		for _, adj := range t.AdjacentTerritories {
+			byLand[adj] = true
+		}
+		for _, wid := range t.AdjacentWaters {
+			if wb := m.WaterBodies[wid]; wb != nil {
+				for _, coastal := range wb.CoastalTerritories {
+					bySea[coastal] = true
+				}
+			}
+		}
+	}
+
+	for _, reachable := range []map[int]bool{byLand, bySea, nil} {
+		best := 0
+		for _, tid := range ids {
+			if _, claimed := owner[tid]; claimed || (reachable != nil && !reachable[tid]) {
+				continue
+			}
+			if best == 0 || (m.Territories[best].Resource == game.ResourceNone &&
+				m.Territories[tid].Resource != game.ResourceNone) {
+				best = tid
+			}
+		}
+		if best != 0 {
+			return best
+		}
+	}
+	return 0
+}
+
+// farthest returns the reachable start candidate with the greatest distance,
+// preferring the lowest ID on ties.
+func farthest(dists map[int]int, ids []int, canStart map[int]bool) int {
+	best, bestDist := ids[0], -1
+	for _, tid := range ids {
+		if d, ok := dists[tid]; ok && canStart[tid] && d > bestDist {
+			best, bestDist = tid, d
+		}
+	}
+	return best
+}
+
+// distancesFrom returns the travel cost from the nearest of sources to every
+// reachable territory. Maps are small, so a plain O(n²) Dijkstra will do.
+func distancesFrom(m *Map, ids []int, sources []int) map[int]int {
+	dist := make(map[int]int, len(ids))
+	done := make(map[int]bool, len(ids))
+	for _, s := range sources {
+		dist[s] = 0
+	}
+
+	for {
+		cur, curDist := 0, -1
+		for _, tid := range ids {
+			if d, ok := dist[tid]; ok && !done[tid] && (curDist < 0 || d < curDist) {
+				cur, curDist = tid, d
+			}
+		}
+		if curDist < 0 {
+			return dist
+		}
+		done[cur] = true
+
+		relax := func(next, cost int) {
+			if d, ok := dist[next]; !ok || curDist+cost < d {
+				dist[next] = curDist + cost
+			}
+		}
+		t := m.Territories[cur]
+		for _, adj := range t.AdjacentTerritories {
+			relax(adj, 1)
+		}
+		for _, wid := range t.AdjacentWaters {
+			if wb := m.WaterBodies[wid]; wb != nil {
+				for _, coastal := range wb.CoastalTerritories {
+					if coastal != cur {
+						relax(coastal, seaCost)
+					}
+				}
+			}
+		}
+	}
+}
+
+// landMasses groups territories connected by land borders, largest first.
+func landMasses(m *Map, ids []int) [][]int {
+	seen := make(map[int]bool, len(ids))
+	var masses [][]int
+	for _, tid := range ids {
+		if seen[tid] {
+			continue
+		}
+		seen[tid] = true
+		mass := []int{tid}
+		for i := 0; i < len(mass); i++ {
+			for _, adj := range m.Territories[mass[i]].AdjacentTerritories {
+				if !seen[adj] {
+					seen[adj] = true
+					mass = append(mass, adj)
+				}
+			}
+		}
+		sort.Ints(mass)
+		masses = append(masses, mass)
+	}
+
+	sort.SliceStable(masses, func(i, j int) bool { return len(masses[i]) > len(masses[j]) })
+	return masses
+}
+
+// chokepoints returns the articulation points of the land border graph:
+// territories that are the only land link between two parts of a land mass.
+func chokepoints(m *Map, ids []int) []int {
+	order := make(map[int]int, len(ids))
+	low := make(map[int]int, len(ids))
+	cut := make(map[int]bool)
+	counter := 0
+
+	var visit func(tid, parent int)
+	visit = func(tid, parent int) {
+		counter++
+		order[tid], low[tid] = counter, counter
+		children := 0
+		for _, adj := range m.Territories[tid].AdjacentTerritories {
+			if adj == parent {
+				continue
+			}
+			if _, seen := order[adj]; seen {
+				low[tid] = min(low[tid], order[adj])
+				continue
+			}
+			children++
+			visit(adj, tid)
+			low[tid] = min(low[tid], low[adj])
+			if parent != 0 && low[adj] >= order[tid] {
+				cut[tid] = true
+			}
+		}
+		if parent == 0 && children > 1 {
+			cut[tid] = true
+		}
+	}
+	for _, tid := range ids {
+		if _, seen := order[tid]; !seen {
+			visit(tid, 0)
+		}
+	}
+
+	result := make([]int, 0, len(cut))
+	for tid := range cut {
+		result = append(result, tid)
+	}
+	sort.Ints(result)
+	return result
+}
+
+// countResources counts the resource territories among ids.
+func countResources(m *Map, ids []int) map[game.ResourceType]int {
+	counts := make(map[game.ResourceType]int)
+	for _, tid := range ids {
+		if r := m.Territories[tid].Resource; r != game.ResourceNone {
+			counts[r]++
+		}
+	}
+	return counts
+}
+
+// sortedTerritoryIDs returns the map's territory IDs in ascending order.
+func sortedTerritoryIDs(m *Map) []int {
+	ids := make([]int, 0, len(m.Territories))
+	for tid := range m.Territories {
+		ids = append(ids, tid)
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,80 @@
+package maps
+
+import (
+	"reflect"
+	"testing"
+
+	"lords-of-conquest/internal/game"
+)
+
+// TestAnalyzeSmallMap checks the analysis of a hand-drawn map: a chain of
+// three territories on one continent and an island across the water.
+func TestAnalyzeSmallMap(t *testing.T) {
+	m := Process(&RawMap{
+		Width:  7,
+		Height: 3,
+		Grid: [][]int{
+			{1, 1, 2, 3, 3, 0, 4},
+			{1, 1, 2, 3, 3, 0, 4},
+			{0, 0, 0, 0, 0, 0, 0},
+		},
+		Territories: map[string]RawTerritory{
+			"1": {Name: "West", Resource: "gold"},
+			"2": {Name: "Middle"},
+			"3": {Name: "East", Resource: "iron"},
+			"4": {Name: "Island", Resource: "timber"},
+		},
+	})
+
+	ids := make(map[string]int)
+	for tid, terr := range m.Territories {
+		ids[terr.Name] = tid
+	}
+
+	report := Analyze(m, 2)
+
+	if len(report.Regions) != 2 || len(report.Regions[0].Territories) != 3 {
+		t.Fatalf("regions = %+v, want the continent then the island", report.Regions)
+	}
+	if got := report.Regions[0].Resources[game.ResourceGold]; got != 1 {
+		t.Errorf("continent has %d gold, want 1", got)
+	}
+	if want := []int{ids["Middle"]}; !reflect.DeepEqual(report.Chokepoints, want) {
+		t.Errorf("chokepoints = %v, want %v", report.Chokepoints, want)
+	}
+	if want := []int{ids["Island"]}; !reflect.DeepEqual(report.SeaOnly, want) {
+		t.Errorf("sea-only = %v, want %v", report.SeaOnly, want)
+	}
+
+	if len(report.Seats) != 2 {
+		t.Fatalf("got %d seats, want 2", len(report.Seats))
+	}
+	assigned := 0
+	for _, seat := range report.Seats {
+		assigned += len(seat.Territories)
+	}
+	if assigned != 4 {
+		t.Errorf("seats cover %d territories, want 4", assigned)
+	}
+	if report.Fairness <= 0 || report.Fairness > 1 {
+		t.Errorf("fairness = %v, want (0, 1]", report.Fairness)
+	}
+}
+
+// TestGenerateReachesFairness checks that the generator keeps trying until
+// the map is fair enough, for a target that is easy to reach.
+func TestGenerateReachesFairness(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Seats = 3
+	opts.Fairness = 60
+
+	for i := 0; i < 3; i++ {
+		m, steps := NewGenerator(opts).Generate()
+		if len(steps) == 0 || !steps[len(steps)-1].IsComplete {
+			t.Fatal("generation steps should end with the completion step")
+		}
+		if f := Analyze(m, 3).Fairness; f < 0.6 {
+			t.Errorf("map %d fairness = %.2f, want at least 0.60", i, f)
+		}
+	}
+}
@@ -3,7 +3,10 @@ package maps
 import (
 	"fmt"
 	"math/rand"
+	"sort"
 	"time"
+
+	"lords-of-conquest/internal/game"
 )
 
 // GeneratorOptions contains settings for map generation.
@@ -14,45 +17,31 @@ type GeneratorOptions struct {
 	WaterBorder bool // Whether to surround map with water
 	Islands     int  // Island spread: 1-5 (1=one landmass, 5=many islands)
 	Resources   int  // Resource coverage percentage: 10-100
-}
-
-// Legacy enum types kept for backwards compatibility during transition
-// TODO: Remove these once all code is updated
-
-// MapSize represents map dimensions (legacy).
-type MapSize int
-
-const (
-	MapSizeSmall MapSize = iota
-	MapSizeMedium
-	MapSizeLarge
-)
+	Seats       int  // Players to balance the map for (default 4)
+	Fairness    int  // Minimum fairness percentage: 0 (don't check) - 100
 
-// TerritoryCount represents number of territories (legacy).
-type TerritoryCount int
+	// Topology is the grid the map is grown on; empty means square.
+	Topology Topology
 
-const (
-	TerritoryCountLow TerritoryCount = iota
-	TerritoryCountMedium
-	TerritoryCountHigh
-)
+	// Terrain gives every territory a terrain, chosen to suit its resource.
+	Terrain bool
 
-// IslandAmount represents how spread out/clustered land is (legacy).
-type IslandAmount int
+	// Template lays out the land masses; empty scatters land by Islands.
+	Template Template
 
-const (
-	IslandAmountLow    IslandAmount = iota // One big landmass
-	IslandAmountMedium                     // A few landmasses
-	IslandAmountHigh                       // Many small islands
-)
+	// Symmetry makes a map of identical copies, one per seat, each starting
+	// with its own copy. Hex maps are always turned round (SymmetryRotate).
+	Symmetry Symmetry
 
-// ResourceAmount represents resource density (legacy).
-type ResourceAmount int
+	// Seed makes generation repeatable; 0 picks a random seed.
+	Seed int64
+}
 
+// Attempts made to reach GeneratorOptions.Fairness: resources are reassigned
+// a few times on each layout before growing a new layout.
 const (
-	ResourceAmountLow ResourceAmount = iota
-	ResourceAmountMedium
-	ResourceAmountHigh
+	rebalanceAttempts = 8
+	layoutAttempts    = 10
 )
 
 // GeneratorStep represents one territory being placed.
@@ -72,6 +61,10 @@ type Generator struct {
 	grid        [][]int // 0 = water, 1+ = territory ID
 	territories map[int]*terrData
 	steps       []GeneratorStep
+
+	land       [][]bool // Cells a template allows land on; nil allows any
+	sym        Symmetry // Symmetry the map is generated with
+	copyOffset int      // ID gap between copies of a territory; 0 if none
 }
 
 type terrData struct {
@@ -169,9 +162,14 @@ var resourceNamePools = map[string][]string{
 
 // NewGenerator creates a new map generator.
 func NewGenerator(opts GeneratorOptions) *Generator {
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
 	g := &Generator{
 		options:     opts,
-		rng:         rand.New(rand.NewSource(time.Now().UnixNano())),
+		rng:         rand.New(rand.NewSource(seed)),
 		territories: make(map[int]*terrData),
 		steps:       make([]GeneratorStep, 0),
 	}
@@ -183,6 +181,20 @@ func NewGenerator(opts GeneratorOptions) *Generator {
 		g.height = 15
 	}
 
+	// The mirror template is two halves, turned round unless asked otherwise
+	g.sym = opts.Symmetry
+	if g.sym == "" && opts.Template == TemplateMirror {
+		g.sym = SymmetryRotate
+	}
+	if !g.sym.Known() || !g.sym.Supports(opts.Topology) {
+		g.sym = SymmetryRotate
+	}
+	// Even sides leave no cell that is its own copy
+	if g.sym != "" {
+		g.width -= g.width % 2
+		g.height += g.height % 2
+	}
+
 	return g
 }
 
@@ -198,7 +210,55 @@ func clamp(val, min, max int) int {
 }
 
 // Generate creates the map. Water is whatever is left after territories are placed.
+//
+// If Fairness is set, resources are reassigned and, failing that, new layouts
+// grown until Analyze rates the map at least that fair for Seats players. The
+// fairest map found is returned if the target is never reached.
 func (g *Generator) Generate() (*Map, []GeneratorStep) {
+	if g.options.Fairness <= 0 && g.sym == "" {
+		g.generateLayout()
+		return g.buildMap(), g.steps
+	}
+
+	target := float64(clamp(g.options.Fairness, 0, 100)) / 100
+	seats := g.options.Seats
+	if seats <= 0 {
+		seats = 4
+	}
+
+	var best, last *Map
+	var bestSteps []GeneratorStep
+	bestFairness := -1.0
+	for layout := 0; layout < layoutAttempts; layout++ {
+		g.generateLayout()
+		for attempt := 0; attempt < rebalanceAttempts; attempt++ {
+			m := g.buildMap()
+			last = m
+			if CheckSymmetry(m) != nil {
+				break // Resources won't fix the layout
+			}
+			fairness := Analyze(m, seats).Fairness
+			if fairness > bestFairness {
+				best, bestSteps, bestFairness = m, g.steps, fairness
+			}
+			if fairness >= target {
+				return best, bestSteps
+			}
+		}
+	}
+	if best == nil {
+		return last, g.steps
+	}
+	return best, bestSteps
+}
+
+// generateLayout grows a fresh set of territories on an empty grid.
+func (g *Generator) generateLayout() {
+	g.territories = make(map[int]*terrData)
+	g.steps = make([]GeneratorStep, 0)
+	g.land = nil
+	g.copyOffset = 0
+
 	// Initialize grid as all water
 	g.grid = make([][]int, g.height)
 	for y := range g.grid {
@@ -211,7 +271,22 @@ func (g *Generator) Generate() (*Map, []GeneratorStep) {
 
 	// Place territory seeds based on islands setting
 	// More islands = more spread out seeds = more water between them
-	seeds := g.placeSeeds(numTerritories)
+	var seeds [][2]int
+	if g.options.Template != "" {
+		seeds = g.templateSeeds(numTerritories)
+	} else {
+		seeds = g.placeSeeds(numTerritories)
+	}
+	if g.sym != "" {
+		// Only part 0 is grown; the rest of the map is copied from it
+		grown := seeds[:0]
+		for _, seed := range seeds {
+			if g.sym.part(seed[0], seed[1], g.width, g.height) == 0 {
+				grown = append(grown, seed)
+			}
+		}
+		seeds = grown
+	}
 
 	// Grow each territory one at a time
 	for i, seed := range seeds {
@@ -231,23 +306,33 @@ func (g *Generator) Generate() (*Map, []GeneratorStep) {
 		}
 	}
 
+	// Territories stop growing at their target size; make template land
+	// masses whole
+	if g.land != nil {
+		g.fillLand()
+	}
+
 	// Fix any diagonal-only connections (split disconnected parts)
 	g.fixDiagonalConnections()
 
 	// Merge tiny territories (< 5 cells) into neighbors
 	g.mergeTinyTerritories(5)
 
+	if g.sym != "" {
+		g.symmetrize()
+	}
+
 	// Note: Single-pixel lakes are filled by fillLakes() in process.go
 	// when Process(raw) is called in buildMap()
 
 	g.steps = append(g.steps, GeneratorStep{IsComplete: true})
-	return g.buildMap(), g.steps
 }
 
 // fixDiagonalConnections ensures all cells in a territory are orthogonally connected.
 // Any disconnected parts are reassigned to neighbors or converted to water.
 func (g *Generator) fixDiagonalConnections() {
-	for terrID, terr := range g.territories {
+	for _, terrID := range g.territoryIDs() {
+		terr := g.territories[terrID]
 		if len(terr.cells) == 0 {
 			continue
 		}
@@ -294,6 +379,17 @@ func (g *Generator) fixDiagonalConnections() {
 	}
 }
 
+// territoryIDs returns the IDs of the territories grown so far in ascending
+// order, so that a seeded generator always visits them the same way.
+func (g *Generator) territoryIDs() []int {
+	ids := make([]int, 0, len(g.territories))
+	for tid := range g.territories {
+		ids = append(ids, tid)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
 // findConnectedComponents finds all orthogonally connected groups of cells.
 func (g *Generator) findConnectedComponents(cells [][2]int, terrID int) [][][2]int {
 	// Create a set of cells for quick lookup
@@ -304,7 +400,6 @@ func (g *Generator) findConnectedComponents(cells [][2]int, terrID int) [][][2]i
 
 	visited := make(map[[2]int]bool)
 	var components [][][2]int
-	dirs := [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
 
 	for _, startCell := range cells {
 		if visited[startCell] {
@@ -321,9 +416,7 @@ func (g *Generator) findConnectedComponents(cells [][2]int, terrID int) [][][2]i
 			queue = queue[1:]
 			component = append(component, cell)
 
-			x, y := cell[0], cell[1]
-			for _, d := range dirs {
-				neighbor := [2]int{x + d[0], y + d[1]}
+			for _, neighbor := range g.neighbors(cell[0], cell[1]) {
 				if cellSet[neighbor] && !visited[neighbor] {
 					visited[neighbor] = true
 					queue = append(queue, neighbor)
@@ -339,23 +432,19 @@ func (g *Generator) findConnectedComponents(cells [][2]int, terrID int) [][][2]i
 
 // findOrthogonalNeighborTerritory finds a different territory orthogonally adjacent to this cell.
 func (g *Generator) findOrthogonalNeighborTerritory(x, y, excludeID int) int {
-	dirs := [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
 	counts := make(map[int]int)
 
-	for _, d := range dirs {
-		nx, ny := x+d[0], y+d[1]
-		if nx >= 0 && nx < g.width && ny >= 0 && ny < g.height {
-			tid := g.grid[ny][nx]
-			if tid > 0 && tid != excludeID {
-				counts[tid]++
-			}
+	for _, n := range g.neighbors(x, y) {
+		tid := g.grid[n[1]][n[0]]
+		if tid > 0 && tid != excludeID {
+			counts[tid]++
 		}
 	}
 
 	bestID := 0
 	bestCount := 0
 	for tid, count := range counts {
-		if count > bestCount {
+		if count > bestCount || (count == bestCount && tid < bestID) {
 			bestCount = count
 			bestID = tid
 		}
@@ -370,24 +459,20 @@ func (g *Generator) mergeTinyTerritories(minSize int) {
 		changed = false
 
 		// Find territories that are too small
-		for terrID, terr := range g.territories {
+		for _, terrID := range g.territoryIDs() {
+			terr := g.territories[terrID]
 			if len(terr.cells) >= minSize {
 				continue
 			}
 
 			// Find the best neighbor to merge into
 			neighborCounts := make(map[int]int)
-			dirs := [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
 
 			for _, cell := range terr.cells {
-				x, y := cell[0], cell[1]
-				for _, d := range dirs {
-					nx, ny := x+d[0], y+d[1]
-					if nx >= 0 && nx < g.width && ny >= 0 && ny < g.height {
-						neighborID := g.grid[ny][nx]
-						if neighborID != 0 && neighborID != terrID {
-							neighborCounts[neighborID]++
-						}
+				for _, n := range g.neighbors(cell[0], cell[1]) {
+					neighborID := g.grid[n[1]][n[0]]
+					if neighborID != 0 && neighborID != terrID {
+						neighborCounts[neighborID]++
 					}
 				}
 			}
@@ -396,7 +481,7 @@ func (g *Generator) mergeTinyTerritories(minSize int) {
 			bestNeighbor := 0
 			bestCount := 0
 			for nid, count := range neighborCounts {
-				if count > bestCount {
+				if count > bestCount || (count == bestCount && nid < bestNeighbor) {
 					bestCount = count
 					bestNeighbor = nid
 				}
@@ -716,6 +801,11 @@ func (g *Generator) growTerritory(terrID, startX, startY, targetSize int) [][2]i
 	return cells
 }
 
+// neighbors returns the cells next to x,y on the generator's grid.
+func (g *Generator) neighbors(x, y int) [][2]int {
+	return g.options.Topology.Neighbors(x, y, g.width, g.height)
+}
+
 func (g *Generator) isValidLandCell(x, y int) bool {
 	if x < 0 || x >= g.width || y < 0 || y >= g.height {
 		return false
@@ -726,16 +816,19 @@ func (g *Generator) isValidLandCell(x, y int) bool {
 			return false
 		}
 	}
+	if g.land != nil && !g.land[y][x] {
+		return false
+	}
+	if g.sym != "" && g.sym.part(x, y, g.width, g.height) != 0 {
+		return false
+	}
 	return true
 }
 
 func (g *Generator) addValidNeighbors(x, y int, frontier *[][2]int, inFrontier map[[2]int]bool) {
-	// Only cardinal directions - diagonals don't count as neighbors
-	dirs := [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
-
-	for _, d := range dirs {
-		nx, ny := x+d[0], y+d[1]
-		cell := [2]int{nx, ny}
+	// On a square grid diagonals don't count as neighbors
+	for _, cell := range g.neighbors(x, y) {
+		nx, ny := cell[0], cell[1]
 		if g.isValidLandCell(nx, ny) && g.grid[ny][nx] == 0 && !inFrontier[cell] {
 			*frontier = append(*frontier, cell)
 			inFrontier[cell] = true
@@ -763,19 +856,13 @@ func (g *Generator) pickGrowthCell(frontier [][2]int, terrID int) int {
 }
 
 func (g *Generator) pickLowConnectivity(frontier [][2]int, terrID int) int {
-	dirs := [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
-
 	// Find cells with exactly 1 neighbor (creates branches)
 	lowCells := make([]int, 0)
 	for i, cell := range frontier {
 		count := 0
-		x, y := cell[0], cell[1]
-		for _, d := range dirs {
-			nx, ny := x+d[0], y+d[1]
-			if nx >= 0 && nx < g.width && ny >= 0 && ny < g.height {
-				if g.grid[ny][nx] == terrID {
-					count++
-				}
+		for _, n := range g.neighbors(cell[0], cell[1]) {
+			if g.grid[n[1]][n[0]] == terrID {
+				count++
 			}
 		}
 		if count == 1 {
@@ -790,28 +877,23 @@ func (g *Generator) pickLowConnectivity(frontier [][2]int, terrID int) int {
 }
 
 func (g *Generator) pickModerateCell(frontier [][2]int, terrID int) int {
-	dirs := [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
-
 	// Group cells by score
 	byScore := make(map[int][]int)
 	for i, cell := range frontier {
 		score := 0
-		x, y := cell[0], cell[1]
-		for _, d := range dirs {
-			nx, ny := x+d[0], y+d[1]
-			if nx >= 0 && nx < g.width && ny >= 0 && ny < g.height {
-				if g.grid[ny][nx] == terrID {
-					score++
-				}
+		for _, n := range g.neighbors(cell[0], cell[1]) {
+			if g.grid[n[1]][n[0]] == terrID {
+				score++
 			}
 		}
 		byScore[score] = append(byScore[score], i)
 	}
 
-	// Weighted random: prefer score 1-2 over 3-4 for more organic shapes
+	// Weighted random: prefer score 1-2 over 3+ for more organic shapes
 	weights := map[int]int{1: 5, 2: 4, 3: 2, 4: 1}
 	choices := make([]int, 0)
-	for score, indices := range byScore {
+	for score := 0; score <= 6; score++ {
+		indices := byScore[score]
 		w := weights[score]
 		if w == 0 {
 			w = 1
@@ -831,16 +913,33 @@ func (g *Generator) pickModerateCell(frontier [][2]int, terrID int) int {
 
 func (g *Generator) buildMap() *Map {
 	raw := &RawMap{
-		ID:          fmt.Sprintf("gen_%d", time.Now().Unix()),
+		ID:          g.mapID(),
 		Name:        "Generated Map",
 		Width:       g.width,
 		Height:      g.height,
 		Grid:        g.grid,
 		Territories: make(map[string]RawTerritory),
+		Topology:    g.options.Topology,
+		Symmetry:    g.sym,
+		Starts:      g.starts(),
 	}
 
 	g.assignResources(raw)
-	return Process(raw)
+	if g.options.Terrain {
+		g.assignTerrain(raw)
+	}
+	m := Process(raw)
+	g.nameSeas(m)
+	return m
+}
+
+// mapID names a generated map after its seed, if it has one, so the same
+// options and seed always give the same map.
+func (g *Generator) mapID() string {
+	if g.options.Seed != 0 {
+		return fmt.Sprintf("gen_%d", g.options.Seed)
+	}
+	return fmt.Sprintf("gen_%d", time.Now().Unix())
 }
 
 func (g *Generator) assignResources(raw *RawMap) {
@@ -848,10 +947,8 @@ func (g *Generator) assignResources(raw *RawMap) {
 	resourcePct := clamp(g.options.Resources, 10, 100)
 	ratio := float64(resourcePct) / 100.0
 
-	ids := make([]int, 0, len(g.territories))
-	for tid := range g.territories {
-		ids = append(ids, tid)
-	}
+	// Copies of territories on symmetric maps copy their resource below
+	ids := g.originalIDs()
 	g.rng.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
 
 	numWithRes := int(float64(len(ids)) * ratio)
@@ -898,6 +995,74 @@ func (g *Generator) assignResources(raw *RawMap) {
 			Resource: res,
 		}
 	}
+	for _, tid := range g.territoryIDs() {
+		if orig, k := g.original(tid); k > 0 {
+			res := raw.Territories[fmt.Sprintf("%d", orig)].Resource
+			raw.Territories[fmt.Sprintf("%d", tid)] = RawTerritory{
+				Name:     g.genName(tid, res, usedNames),
+				Resource: res,
+			}
+		}
+	}
+}
+
+// originalIDs returns the territory IDs, in order, leaving out the copies
+// on symmetric maps.
+func (g *Generator) originalIDs() []int {
+	ids := g.territoryIDs()
+	originals := ids[:0]
+	for _, tid := range ids {
+		if _, k := g.original(tid); k == 0 {
+			originals = append(originals, tid)
+		}
+	}
+	return originals
+}
+
+// terrainWeights are the odds of each terrain (plains, forest, mountains,
+// marsh) for a territory with a given resource: timber grows in forests,
+// metals and coal come out of mountains and horses graze on plains.
+var terrainWeights = map[string][4]int{
+	"":          {5, 3, 2, 2},
+	"timber":    {1, 8, 1, 0},
+	"iron":      {1, 1, 7, 0},
+	"coal":      {1, 1, 6, 2},
+	"gold":      {3, 1, 5, 1},
+	"grassland": {8, 1, 0, 1},
+}
+
+// assignTerrain picks a terrain for every territory from its resource.
+// Marshes only form on the coast.
+func (g *Generator) assignTerrain(raw *RawMap) {
+	for _, tid := range g.originalIDs() {
+		key := fmt.Sprintf("%d", tid)
+		rt := raw.Territories[key]
+		weights := terrainWeights[rt.Resource]
+		if !g.isCoastalTerritory(tid) {
+			weights[3] = 0
+		}
+
+		total := 0
+		for _, w := range weights {
+			total += w
+		}
+		roll := g.rng.Intn(total)
+		for i, w := range weights {
+			if roll < w {
+				rt.Terrain = string(game.Terrains[i])
+				break
+			}
+			roll -= w
+		}
+		raw.Territories[key] = rt
+	}
+	for _, tid := range g.territoryIDs() {
+		if orig, k := g.original(tid); k > 0 {
+			rt := raw.Territories[fmt.Sprintf("%d", tid)]
+			rt.Terrain = raw.Territories[fmt.Sprintf("%d", orig)].Terrain
+			raw.Territories[fmt.Sprintf("%d", tid)] = rt
+		}
+	}
 }
 
 // countLandMasses counts distinct connected groups of land cells on the grid
@@ -909,7 +1074,6 @@ func (g *Generator) countLandMasses() int {
 		visited[y] = make([]bool, g.width)
 	}
 
-	dirs := [4][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
 	count := 0
 
 	for y := 0; y < g.height; y++ {
@@ -924,12 +1088,11 @@ func (g *Generator) countLandMasses() int {
 			for len(queue) > 0 {
 				cell := queue[0]
 				queue = queue[1:]
-				for _, d := range dirs {
-					nx, ny := cell[0]+d[0], cell[1]+d[1]
-					if nx >= 0 && nx < g.width && ny >= 0 && ny < g.height &&
-						g.grid[ny][nx] != 0 && !visited[ny][nx] {
+				for _, n := range g.neighbors(cell[0], cell[1]) {
+					nx, ny := n[0], n[1]
+					if g.grid[ny][nx] != 0 && !visited[ny][nx] {
 						visited[ny][nx] = true
-						queue = append(queue, [2]int{nx, ny})
+						queue = append(queue, n)
 					}
 				}
 			}
@@ -945,14 +1108,9 @@ func (g *Generator) isCoastalTerritory(tid int) bool {
 	if !ok {
 		return false
 	}
-	dirs := [4][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
 	for _, cell := range terr.cells {
-		x, y := cell[0], cell[1]
-		for _, d := range dirs {
-			nx, ny := x+d[0], y+d[1]
-			if nx < 0 || nx >= g.width || ny < 0 || ny >= g.height {
-				continue
-			}
+		for _, n := range g.neighbors(cell[0], cell[1]) {
+			nx, ny := n[0], n[1]
 			if g.grid[ny][nx] == 0 && g.waterBodySize(nx, ny) > 10 {
 				return true
 			}
@@ -976,18 +1134,14 @@ func (g *Generator) waterBodySize(sx, sy int) int {
 	queue := [][2]int{{sx, sy}}
 	visited[[2]int{sx, sy}] = true
 	count := 0
-	dirs := [4][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
 
 	for len(queue) > 0 && count < 11 {
 		cell := queue[0]
 		queue = queue[1:]
 		count++
 
-		for _, d := range dirs {
-			nx, ny := cell[0]+d[0], cell[1]+d[1]
-			nb := [2]int{nx, ny}
-			if nx >= 0 && nx < g.width && ny >= 0 && ny < g.height &&
-				g.grid[ny][nx] == 0 && !visited[nb] {
+		for _, nb := range g.neighbors(cell[0], cell[1]) {
+			if g.grid[nb[1]][nb[0]] == 0 && !visited[nb] {
 				visited[nb] = true
 				queue = append(queue, nb)
 			}
@@ -0,0 +1,198 @@
+package maps
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"lords-of-conquest/internal/game"
+)
+
+// TestSeedIsRepeatable checks that the same options and seed always give the
+// same map, so curated maps can be regenerated.
+func TestSeedIsRepeatable(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Islands = 4
+	opts.Seed = 1234
+
+	first, _ := NewGenerator(opts).Generate()
+	for i := 0; i < 3; i++ {
+		again, _ := NewGenerator(opts).Generate()
+		if !reflect.DeepEqual(first.Raw(), again.Raw()) {
+			t.Fatalf("run %d produced a different map from the same seed", i+2)
+		}
+	}
+	if first.ID != "gen_1234" {
+		t.Errorf("ID = %q, want gen_1234", first.ID)
+	}
+}
+
+// TestGeneratedTerrain checks that terrain is only generated when asked for,
+// and that marshes stay on the coast.
+func TestGeneratedTerrain(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Seed = 99
+
+	plain, _ := NewGenerator(opts).Generate()
+	for id, terr := range plain.Territories {
+		if terr.Terrain != game.TerrainNone {
+			t.Fatalf("territory %d has terrain %q without the terrain option", id, terr.Terrain)
+		}
+	}
+
+	opts.Terrain = true
+	m, _ := NewGenerator(opts).Generate()
+	for id, terr := range m.Territories {
+		if terr.Terrain == game.TerrainNone {
+			t.Errorf("territory %d has no terrain", id)
+		}
+		if terr.Terrain == game.TerrainMarsh && len(terr.AdjacentWaters) == 0 {
+			t.Errorf("territory %d is an inland marsh", id)
+		}
+	}
+	if !reflect.DeepEqual(m.Raw(), mustLoad(t, m.Raw()).Raw()) {
+		t.Error("terrain did not survive a save and reload")
+	}
+}
+
+func mustLoad(t *testing.T, raw *RawMap) *Map {
+	t.Helper()
+	m, err := LoadRaw(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// TestTemplates checks that each template gives the land masses it promises,
+// repeatably from a seed.
+func TestTemplates(t *testing.T) {
+	for _, tpl := range Templates {
+		for _, seed := range []int64{1, 2, 3} {
+			opts := DefaultOptions()
+			opts.Template = tpl
+			opts.Seed = seed
+			if tpl == TemplateMirror {
+				opts.Seats = 2
+				opts.Topology = Topology([]string{"", "hex"}[seed%2])
+			}
+			m, _ := NewGenerator(opts).Generate()
+			again, _ := NewGenerator(opts).Generate()
+			if !reflect.DeepEqual(m.Raw(), again.Raw()) {
+				t.Errorf("%s seed %d: not repeatable", tpl, seed)
+			}
+			for _, d := range Validate(m.Raw()) {
+				if d.Severity == SeverityError {
+					t.Errorf("%s seed %d: %v", tpl, seed, d)
+				}
+			}
+
+			regions := Analyze(m, 4).Regions
+			switch tpl {
+			case TemplateContinents:
+				if len(regions) != 4 {
+					t.Errorf("continents seed %d: %d land masses, want 4", seed, len(regions))
+				}
+			case TemplateArchipelago:
+				if len(regions) < 6 {
+					t.Errorf("archipelago seed %d: %d land masses, want at least 6", seed, len(regions))
+				}
+			case TemplatePangaea, TemplateRing:
+				if len(regions) != 1 {
+					t.Errorf("%s seed %d: %d land masses, want 1", tpl, seed, len(regions))
+				}
+			case TemplateMirror:
+				for y := range m.Grid {
+					for x, tid := range m.Grid[y] {
+						twin := m.Grid[m.Height-1-y][m.Width-1-x]
+						if (tid == 0) != (twin == 0) {
+							t.Fatalf("mirror seed %d: land at %d,%d isn't mirrored", seed, x, y)
+						}
+						if tid != 0 && m.Territories[tid].Resource != m.Territories[twin].Resource {
+							t.Fatalf("mirror seed %d: territories %d and %d have different resources", seed, tid, twin)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// The ring's inland sea doesn't reach the map edge
+	opts := DefaultOptions()
+	opts.Template = TemplateRing
+	opts.Seed = 5
+	m, _ := NewGenerator(opts).Generate()
+	inland := false
+	for _, wb := range m.WaterBodies {
+		edge := false
+		for _, c := range wb.Cells {
+			edge = edge || c[0] == 0 || c[1] == 0 || c[0] == m.Width-1 || c[1] == m.Height-1
+		}
+		inland = inland || !edge
+	}
+	if !inland {
+		t.Error("ring has no inland sea")
+	}
+}
+
+// TestSymmetricMaps checks that symmetric maps are made of identical copies,
+// one per seat, and that breaking the symmetry is caught.
+func TestSymmetricMaps(t *testing.T) {
+	for _, sym := range Symmetries {
+		opts := DefaultOptions()
+		opts.Symmetry = sym
+		opts.Terrain = true
+		opts.Seed = 7
+		m, _ := NewGenerator(opts).Generate()
+		if err := CheckSymmetry(m); err != nil {
+			t.Errorf("%s: %v", sym, err)
+		}
+		if len(m.Starts) != sym.Seats() {
+			t.Fatalf("%s: %d seats of starts, want %d", sym, len(m.Starts), sym.Seats())
+		}
+		started := 0
+		for _, start := range m.Starts {
+			started += len(start)
+		}
+		if started != len(m.Territories) {
+			t.Errorf("%s: starts cover %d of %d territories", sym, started, len(m.Territories))
+		}
+		if fairness := Analyze(m, sym.Seats()).Fairness; fairness != 1 {
+			t.Errorf("%s: fairness %.2f, want 1", sym, fairness)
+		}
+
+		raw := m.Raw()
+		if err := Validate(raw).Err(); err != nil {
+			t.Errorf("%s: %v", sym, err)
+		}
+		if !reflect.DeepEqual(raw, mustLoad(t, raw).Raw()) {
+			t.Errorf("%s: symmetry did not survive a save and reload", sym)
+		}
+
+		// A resource on one side only breaks the symmetry
+		tid := m.Starts[0][0]
+		rt := raw.Territories[strconv.Itoa(tid)]
+		if rt.Resource == "gold" {
+			rt.Resource = "coal"
+		} else {
+			rt.Resource = "gold"
+		}
+		raw.Territories[strconv.Itoa(tid)] = rt
+		found := false
+		for _, d := range Validate(raw) {
+			found = found || d.Code == DiagAsymmetric
+		}
+		if !found {
+			t.Errorf("%s: one-sided resource not reported", sym)
+		}
+	}
+
+	// Hex maps can only be turned round
+	opts := DefaultOptions()
+	opts.Symmetry = SymmetryQuad
+	opts.Topology = TopologyHex
+	opts.Seed = 7
+	if m, _ := NewGenerator(opts).Generate(); m.Symmetry != SymmetryRotate || CheckSymmetry(m) != nil {
+		t.Errorf("hex map has %q symmetry, want a checked %q", m.Symmetry, SymmetryRotate)
+	}
+}
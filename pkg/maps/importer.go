@@ -0,0 +1,279 @@
+package maps
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/png" // Register the PNG decoder
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	_ "golang.org/x/image/bmp" // Register the BMP decoder
+)
+
+// maxImportSize is the largest grid, in cells per side, an image may produce.
+// Bigger pictures need a larger CellSize.
+const maxImportSize = 200
+
+// ImageOptions controls how an image is turned into a map.
+type ImageOptions struct {
+	ID   string
+	Name string
+
+	// CellSize is how many pixels square each grid cell covers. Each cell
+	// takes the most common colour among its pixels. Defaults to 1.
+	CellSize int
+
+	// MinCells is the smallest region kept as a territory. Smaller specks,
+	// such as anti-aliasing or stray labels, are absorbed by their
+	// neighbours. Defaults to 1 (keep everything).
+	MinCells int
+
+	// IsWater and IsBorder classify cell colours. Border cells, such as the
+	// outlines drawn between territories, are shared out among the
+	// territories next to them. They default to IsBlue and IsBlack.
+	IsWater  func(color.Color) bool
+	IsBorder func(color.Color) bool
+}
+
+// IsBlue reports whether a colour is blue enough to be water. Transparent
+// pixels also count as water.
+func IsBlue(c color.Color) bool {
+	r, g, b, a := c.RGBA()
+	if a < 0x8000 {
+		return true
+	}
+	r, g, b = r>>8, g>>8, b>>8
+	return b >= 96 && b > r+40 && b > g+20
+}
+
+// IsBlack reports whether a colour is dark enough to be a border line.
+func IsBlack(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return max(r, g, b)>>8 < 40
+}
+
+// ImportImageFile imports a PNG or BMP map picture. The map ID and name
+// default to the file name.
+func ImportImageFile(path string, opts ImageOptions) (*RawMap, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if opts.ID == "" {
+		opts.ID = base
+	}
+	if opts.Name == "" {
+		opts.Name = base
+	}
+	return ImportImage(f, opts)
+}
+
+// ImportImage turns a colour-indexed PNG or BMP picture into a raw map.
+// Every connected area of one colour becomes a territory and blue becomes
+// water, so classic maps can be traced or screenshotted and loaded through
+// Process. Territories are numbered in reading order and have no resources;
+// those are left for the map author to fill in.
+//
+// The original C64 and Apple II map files are not read directly; import a
+// screenshot of the map instead, with CellSize set to the pixel size of one
+// map square.
+func ImportImage(r io.Reader, opts ImageOptions) (*RawMap, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode map image: %w", err)
+	}
+	return imageToRaw(img, opts)
+}
+
+// Cell labels used while importing, before territories are numbered.
+const (
+	cellWater      = 0
+	cellBorder     = -1
+	cellUnlabelled = -2
+)
+
+func imageToRaw(img image.Image, opts ImageOptions) (*RawMap, error) {
+	opts.CellSize = max(opts.CellSize, 1)
+	opts.MinCells = max(opts.MinCells, 1)
+	if opts.IsWater == nil {
+		opts.IsWater = IsBlue
+	}
+	if opts.IsBorder == nil {
+		opts.IsBorder = IsBlack
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx()/opts.CellSize, bounds.Dy()/opts.CellSize
+	if width == 0 || height == 0 {
+		return nil, errors.New("image is smaller than one map cell")
+	}
+	if width > maxImportSize || height > maxImportSize {
+		return nil, fmt.Errorf("image is %dx%d cells, more than %d per side; use a larger cell size",
+			width, height, maxImportSize)
+	}
+
+	// Colour of each cell
+	colors := make([][]color.RGBA, height)
+	for y := range colors {
+		colors[y] = make([]color.RGBA, width)
+		for x := range colors[y] {
+			colors[y][x] = cellColor(img, bounds.Min.X+x*opts.CellSize, bounds.Min.Y+y*opts.CellSize, opts.CellSize)
+		}
+	}
+
+	// Label connected areas of one colour, in reading order
+	labels := make([][]int, height)
+	for y := range labels {
+		labels[y] = make([]int, width)
+		for x := range labels[y] {
+			switch c := colors[y][x]; {
+			case opts.IsWater(c):
+				labels[y][x] = cellWater
+			case opts.IsBorder(c):
+				labels[y][x] = cellBorder
+			default:
+				labels[y][x] = cellUnlabelled
+			}
+		}
+	}
+	regions := 0
+	for y := range labels {
+		for x := range labels[y] {
+			if labels[y][x] != cellUnlabelled {
+				continue
+			}
+			regions++
+			area := fillRegion(labels, colors, x, y, regions)
+			if len(area) < opts.MinCells {
+				for _, c := range area {
+					labels[c[1]][c[0]] = cellBorder
+				}
+			}
+		}
+	}
+
+	fillBorders(labels)
+
+	// Number the surviving territories 1..n in reading order
+	raw := &RawMap{
+		ID:          opts.ID,
+		Name:        opts.Name,
+		Width:       width,
+		Height:      height,
+		Grid:        make([][]int, height),
+		Territories: make(map[string]RawTerritory),
+	}
+	ids := make(map[int]int)
+	for y, row := range labels {
+		raw.Grid[y] = make([]int, width)
+		for x, label := range row {
+			if label <= 0 {
+				continue
+			}
+			tid, ok := ids[label]
+			if !ok {
+				tid = len(ids) + 1
+				ids[label] = tid
+				raw.Territories[strconv.Itoa(tid)] = RawTerritory{Name: "Territory " + strconv.Itoa(tid)}
+			}
+			raw.Grid[y][x] = tid
+		}
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("image has no land")
+	}
+
+	return raw, nil
+}
+
+// cellColor returns the most common colour in a size x size block of pixels,
+// preferring the first seen on ties.
+func cellColor(img image.Image, x0, y0, size int) color.RGBA {
+	if size == 1 {
+		return color.RGBAModel.Convert(img.At(x0, y0)).(color.RGBA)
+	}
+	counts := make(map[color.RGBA]int)
+	var best color.RGBA
+	for y := y0; y < y0+size; y++ {
+		for x := x0; x < x0+size; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			counts[c]++
+			if counts[c] > counts[best] {
+				best = c
+			}
+		}
+	}
+	return best
+}
+
+// fillRegion gives label to the unlabelled cells connected to x,y that have
+// the same colour, and returns them.
+func fillRegion(labels [][]int, colors [][]color.RGBA, x, y, label int) [][2]int {
+	want := colors[y][x]
+	labels[y][x] = label
+	area := [][2]int{{x, y}}
+	for i := 0; i < len(area); i++ {
+		for _, d := range [4][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}} {
+			nx, ny := area[i][0]+d[0], area[i][1]+d[1]
+			if ny < 0 || ny >= len(labels) || nx < 0 || nx >= len(labels[ny]) {
+				continue
+			}
+			if labels[ny][nx] == cellUnlabelled && colors[ny][nx] == want {
+				labels[ny][nx] = label
+				area = append(area, [2]int{nx, ny})
+			}
+		}
+	}
+	return area
+}
+
+// fillBorders grows territories into border cells one step at a time, each
+// cell joining the territory most of its labelled neighbours belong to.
+// Border cells that no territory reaches become water.
+func fillBorders(labels [][]int) {
+	for {
+		changed := false
+		next := make([][]int, len(labels))
+		for y, row := range labels {
+			next[y] = append([]int(nil), row...)
+			for x, label := range row {
+				if label != cellBorder {
+					continue
+				}
+				counts := make(map[int]int)
+				for _, d := range [4][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}} {
+					nx, ny := x+d[0], y+d[1]
+					if ny >= 0 && ny < len(labels) && nx >= 0 && nx < len(row) && labels[ny][nx] > 0 {
+						counts[labels[ny][nx]]++
+					}
+				}
+				if len(counts) > 0 {
+					next[y][x] = findMajority(counts)
+					changed = true
+				}
+			}
+		}
+		for y := range labels {
+			copy(labels[y], next[y])
+		}
+		if !changed {
+			break
+		}
+	}
+
+	for _, row := range labels {
+		for x, label := range row {
+			if label == cellBorder {
+				row[x] = cellWater
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package maps
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"golang.org/x/image/bmp"
+)
+
+// testPicture draws a 2x-scaled map: water at the edges, a red and a yellow
+// territory split by a black border line, and a one-pixel green speck.
+func testPicture() image.Image {
+	water := color.RGBA{20, 40, 200, 255}
+	red := color.RGBA{200, 30, 30, 255}
+	yellow := color.RGBA{230, 220, 40, 255}
+	black := color.RGBA{0, 0, 0, 255}
+	green := color.RGBA{30, 200, 30, 255}
+
+	rows := []string{
+		"~~~~~~~~",
+		"~RRRkYY~",
+		"~RRRkYY~",
+		"~RgRkYY~",
+		"~~~~~~~~",
+	}
+	palette := map[byte]color.RGBA{'~': water, 'R': red, 'Y': yellow, 'k': black, 'g': green}
+
+	img := image.NewRGBA(image.Rect(0, 0, 16, 10))
+	for y, row := range rows {
+		for x := range row {
+			c := palette[row[x]]
+			for dy := 0; dy < 2; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					img.Set(x*2+dx, y*2+dy, c)
+				}
+			}
+		}
+	}
+	return img
+}
+
+func TestImportImage(t *testing.T) {
+	encoders := map[string]func(*bytes.Buffer, image.Image) error{
+		"png": func(b *bytes.Buffer, img image.Image) error { return png.Encode(b, img) },
+		"bmp": func(b *bytes.Buffer, img image.Image) error { return bmp.Encode(b, img) },
+	}
+	for format, encode := range encoders {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := encode(&buf, testPicture()); err != nil {
+				t.Fatal(err)
+			}
+			raw, err := ImportImage(&buf, ImageOptions{ID: "imported", Name: "Imported", CellSize: 2, MinCells: 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if raw.Width != 8 || raw.Height != 5 {
+				t.Fatalf("size = %dx%d, want 8x5", raw.Width, raw.Height)
+			}
+			if len(raw.Territories) != 2 {
+				t.Fatalf("territories = %v, want 2", raw.Territories)
+			}
+			want := [][]int{
+				{0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 1, 1, 1, 1, 2, 2, 0},
+				{0, 1, 1, 1, 1, 2, 2, 0},
+				{0, 1, 1, 1, 1, 2, 2, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0},
+			}
+			for y := range want {
+				for x := range want[y] {
+					if raw.Grid[y][x] != want[y][x] {
+						t.Fatalf("grid = %v, want %v", raw.Grid, want)
+					}
+				}
+			}
+
+			m, err := LoadRaw(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(m.Territories[1].AdjacentTerritories) != 1 {
+				t.Errorf("territory 1 adjacent to %v, want territory 2", m.Territories[1].AdjacentTerritories)
+			}
+		})
+	}
+}
+
+func TestImportImageRejectsEmptyAndHugeImages(t *testing.T) {
+	sea := image.NewUniform(color.RGBA{0, 0, 255, 255})
+	if _, err := imageToRaw(&boundedImage{sea, image.Rect(0, 0, 10, 10)}, ImageOptions{}); err == nil {
+		t.Error("all-water image should be rejected")
+	}
+	land := image.NewUniform(color.RGBA{200, 30, 30, 255})
+	if _, err := imageToRaw(&boundedImage{land, image.Rect(0, 0, 1000, 10)}, ImageOptions{}); err == nil {
+		t.Error("image wider than the import limit should be rejected")
+	}
+}
+
+// boundedImage gives an infinite image fixed bounds.
+type boundedImage struct {
+	image.Image
+	bounds image.Rectangle
+}
+
+func (b *boundedImage) Bounds() image.Rectangle { return b.bounds }
@@ -5,6 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"path"
+	"strconv"
+	"strings"
+
+	"lords-of-conquest/internal/game"
 )
 
 var mapFiles embed.FS
@@ -47,13 +51,7 @@ func Load(filename string) (*Map, error) {
 		return nil, fmt.Errorf("failed to parse map JSON: %w", err)
 	}
 
-	// Validate
-	if err := validate(&raw); err != nil {
-		return nil, fmt.Errorf("invalid map: %w", err)
-	}
-
-	// Process
-	return Process(&raw), nil
+	return LoadRaw(&raw)
 }
 
 // Get retrieves a map from the registry by ID.
@@ -85,28 +83,6 @@ type MapInfo struct {
 	TerritoryCount int    `json:"territory_count"`
 }
 
-// validate checks a raw map for errors.
-func validate(raw *RawMap) error {
-	if raw.ID == "" {
-		return fmt.Errorf("map ID is required")
-	}
-	if raw.Name == "" {
-		return fmt.Errorf("map name is required")
-	}
-	if raw.Width <= 0 || raw.Height <= 0 {
-		return fmt.Errorf("invalid dimensions: %dx%d", raw.Width, raw.Height)
-	}
-	if len(raw.Grid) != raw.Height {
-		return fmt.Errorf("grid height mismatch: expected %d, got %d", raw.Height, len(raw.Grid))
-	}
-	for y, row := range raw.Grid {
-		if len(row) != raw.Width {
-			return fmt.Errorf("row %d width mismatch: expected %d, got %d", y, raw.Width, len(row))
-		}
-	}
-	return nil
-}
-
 // LoadFromJSON loads a map from JSON bytes (for custom/uploaded maps).
 func LoadFromJSON(data []byte) (*Map, error) {
 	var raw RawMap
@@ -114,11 +90,47 @@ func LoadFromJSON(data []byte) (*Map, error) {
 		return nil, fmt.Errorf("failed to parse map JSON: %w", err)
 	}
 
-	if err := validate(&raw); err != nil {
+	return LoadRaw(&raw)
+}
+
+// LoadRaw validates and processes a raw map. Maps with errors are rejected;
+// warnings are left for tools like mapcheck to report.
+func LoadRaw(raw *RawMap) (*Map, error) {
+	if err := Validate(raw).Err(); err != nil {
 		return nil, fmt.Errorf("invalid map: %w", err)
 	}
+	return Process(raw), nil
+}
 
-	return Process(&raw), nil
+// Raw converts a processed map back to the format maps are saved in.
+func (m *Map) Raw() *RawMap {
+	raw := &RawMap{
+		ID:          m.ID,
+		Name:        m.Name,
+		Width:       m.Width,
+		Height:      m.Height,
+		Grid:        make([][]int, len(m.Grid)),
+		Territories: make(map[string]RawTerritory, len(m.Territories)),
+	}
+	if m.Topology != TopologySquare {
+		raw.Topology = m.Topology
+	}
+	raw.Seas = m.Seas()
+	raw.Symmetry = m.Symmetry
+	for _, start := range m.Starts {
+		raw.Starts = append(raw.Starts, append([]int(nil), start...))
+	}
+	for y, row := range m.Grid {
+		raw.Grid[y] = append([]int(nil), row...)
+	}
+	for id, t := range m.Territories {
+		resource := ""
+		if t.Resource != game.ResourceNone {
+			resource = strings.ToLower(t.Resource.String())
+		}
+		raw.Territories[strconv.Itoa(id)] = RawTerritory{Name: t.Name, Resource: resource, Terrain: string(t.Terrain)}
+	}
+	return raw
 }
 
 // Register adds a map to the registry.
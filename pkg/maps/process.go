@@ -2,20 +2,31 @@ package maps
 
 import (
 	"lords-of-conquest/internal/game"
+	"sort"
 	"strconv"
 	"strings"
 )
 
 // Process takes a raw map and computes all derived data.
 func Process(raw *RawMap) *Map {
+	raw = rasterized(raw)
 	m := &Map{
 		ID:          raw.ID,
 		Name:        raw.Name,
 		Width:       raw.Width,
 		Height:      raw.Height,
+		Topology:    raw.Topology,
+		Symmetry:    raw.Symmetry,
 		Territories: make(map[int]*Territory),
 		WaterBodies: make(map[int]*WaterBody),
 	}
+	if m.Topology == "" {
+		m.Topology = TopologySquare
+	}
+
+	for _, start := range raw.Starts {
+		m.Starts = append(m.Starts, append([]int(nil), start...))
+	}
 
 	// Copy grid
 	m.Grid = make([][]int, raw.Height)
@@ -34,8 +45,9 @@ func Process(raw *RawMap) *Map {
 		// 0 = unprocessed water or land
 	}
 
-	// Step 3: Flood fill water bodies
+	// Step 3: Flood fill water bodies and name them
 	floodFillWaterBodies(m)
+	nameWaterBodies(m, raw)
 
 	// Step 4: Create territory objects and collect cells
 	createTerritories(m, raw)
@@ -49,6 +61,31 @@ func Process(raw *RawMap) *Map {
 // fillLakes converts isolated water cells (1-2 cell lakes) to land.
 // Any small water body completely surrounded by land becomes the majority neighbor.
 func fillLakes(m *Map) {
+	for _, cells := range smallLakes(m) {
+		landCounts := make(map[int]int)
+		for _, cell := range cells {
+			for _, n := range m.Neighbors(cell[0], cell[1]) {
+				if neighborVal := m.Grid[n[1]][n[0]]; neighborVal != 0 {
+					landCounts[neighborVal]++
+				}
+			}
+		}
+
+		// Fill with the majority neighbor
+		if len(landCounts) > 0 {
+			majority := findMajority(landCounts)
+			for _, cell := range cells {
+				m.Grid[cell[1]][cell[0]] = majority
+			}
+		}
+	}
+}
+
+// smallLakes returns the water regions of 1-2 cells that are completely
+// surrounded by land.
+func smallLakes(m *Map) [][][2]int {
+	var lakes [][][2]int
+
 	// Find all water regions using flood fill
 	visited := make([][]bool, m.Height)
 	for y := range visited {
@@ -70,25 +107,23 @@ func fillLakes(m *Map) {
 			}
 
 			// Check if this water region is completely surrounded by land
-			// (no water neighbors outside the region, and all cells have 4 neighbors)
+			// (no water neighbors outside the region, and no cell on the map edge)
 			allSurroundedByLand := true
-			landCounts := make(map[int]int)
 
 			for _, cell := range cells {
 				cx, cy := cell[0], cell[1]
-				dirs := [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+				neighbors := m.Neighbors(cx, cy)
 
-				for _, d := range dirs {
-					nx, ny := cx+d[0], cy+d[1]
+				// Edge of map = not surrounded
+				if len(neighbors) < len(m.Topology.offsets(cy)) {
+					allSurroundedByLand = false
+					break
+				}
 
-					// Check if out of bounds (edge of map = not surrounded)
-					if nx < 0 || nx >= m.Width || ny < 0 || ny >= m.Height {
-						allSurroundedByLand = false
-						break
-					}
+				for _, n := range neighbors {
+					nx, ny := n[0], n[1]
 
-					neighborVal := m.Grid[ny][nx]
-					if neighborVal == 0 {
+					if m.Grid[ny][nx] == 0 {
 						// Water neighbor - check if it's part of our region
 						isPartOfRegion := false
 						for _, c := range cells {
@@ -101,8 +136,6 @@ func fillLakes(m *Map) {
 							allSurroundedByLand = false
 							break
 						}
-					} else {
-						landCounts[neighborVal]++
 					}
 				}
 
@@ -111,15 +144,12 @@ func fillLakes(m *Map) {
 				}
 			}
 
-			// If completely surrounded by land, fill with majority
-			if allSurroundedByLand && len(landCounts) > 0 {
-				majority := findMajority(landCounts)
-				for _, cell := range cells {
-					m.Grid[cell[1]][cell[0]] = majority
-				}
+			if allSurroundedByLand {
+				lakes = append(lakes, cells)
 			}
 		}
 	}
+	return lakes
 }
 
 // floodFillWater finds all connected water cells from a starting point.
@@ -135,14 +165,11 @@ func floodFillWater(m *Map, startX, startY int, visited [][]bool) [][2]int {
 
 		cells = append(cells, [2]int{x, y})
 
-		dirs := [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
-		for _, d := range dirs {
-			nx, ny := x+d[0], y+d[1]
-			if nx >= 0 && nx < m.Width && ny >= 0 && ny < m.Height {
-				if m.Grid[ny][nx] == 0 && !visited[ny][nx] {
-					visited[ny][nx] = true
-					queue = append(queue, [2]int{nx, ny})
-				}
+		for _, n := range m.Neighbors(x, y) {
+			nx, ny := n[0], n[1]
+			if m.Grid[ny][nx] == 0 && !visited[ny][nx] {
+				visited[ny][nx] = true
+				queue = append(queue, [2]int{nx, ny})
 			}
 		}
 	}
@@ -150,26 +177,23 @@ func floodFillWater(m *Map, startX, startY int, visited [][]bool) [][2]int {
 	return cells
 }
 
-// getOrthogonalNeighbors returns the 4 orthogonal neighbors (up, down, left, right).
+// getOrthogonalNeighbors returns the territory IDs of the cells next to x,y.
 func getOrthogonalNeighbors(m *Map, x, y int) []int {
-	neighbors := make([]int, 0, 4)
-	dirs := [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
-
-	for _, d := range dirs {
-		nx, ny := x+d[0], y+d[1]
-		if nx >= 0 && nx < m.Width && ny >= 0 && ny < m.Height {
-			neighbors = append(neighbors, m.Grid[ny][nx])
-		}
+	cells := m.Neighbors(x, y)
+	neighbors := make([]int, 0, len(cells))
+	for _, n := range cells {
+		neighbors = append(neighbors, m.Grid[n[1]][n[0]])
 	}
 	return neighbors
 }
 
-// findMajority returns the most common value in the counts map.
+// findMajority returns the most common value in the counts map, the lowest
+// on ties so that processing a map always gives the same result.
 func findMajority(counts map[int]int) int {
 	maxCount := 0
 	majority := 0
 	for val, count := range counts {
-		if count > maxCount {
+		if count > maxCount || (count == maxCount && val < majority) {
 			maxCount = count
 			majority = val
 		}
@@ -224,15 +248,12 @@ func floodFill(m *Map, startX, startY int, visited [][]bool) [][2]int {
 
 		cells = append(cells, [2]int{x, y})
 
-		// Check 4 orthogonal neighbors
-		dirs := [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
-		for _, d := range dirs {
-			nx, ny := x+d[0], y+d[1]
-			if nx >= 0 && nx < m.Width && ny >= 0 && ny < m.Height {
-				if m.Grid[ny][nx] == 0 && !visited[ny][nx] {
-					visited[ny][nx] = true
-					queue = append(queue, [2]int{nx, ny})
-				}
+		// Check neighbors
+		for _, n := range m.Neighbors(x, y) {
+			nx, ny := n[0], n[1]
+			if m.Grid[ny][nx] == 0 && !visited[ny][nx] {
+				visited[ny][nx] = true
+				queue = append(queue, [2]int{nx, ny})
 			}
 		}
 	}
@@ -259,9 +280,11 @@ func createTerritories(m *Map, raw *RawMap) {
 		rawT, ok := raw.Territories[strconv.Itoa(tid)]
 		name := ""
 		resource := game.ResourceNone
+		terrain := game.TerrainNone
 		if ok {
 			name = rawT.Name
 			resource = parseResource(rawT.Resource)
+			terrain, _ = game.ParseTerrain(strings.ToLower(rawT.Terrain))
 		}
 		if name == "" {
 			name = "Territory " + strconv.Itoa(tid)
@@ -271,6 +294,7 @@ func createTerritories(m *Map, raw *RawMap) {
 			ID:       tid,
 			Name:     name,
 			Resource: resource,
+			Terrain:  terrain,
 			Cells:    cells,
 		}
 	}
@@ -319,16 +343,12 @@ func renumberTerritories(m *Map) {
 				}
 
 				// Find an adjacent valid territory to merge into
-				dirs := [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
 				newID := 0
-				for _, d := range dirs {
-					nx, ny := x+d[0], y+d[1]
-					if nx >= 0 && nx < m.Width && ny >= 0 && ny < m.Height {
-						neighborID := m.Grid[ny][nx]
-						if neighborID > 0 && !orphanedIDs[neighborID] {
-							newID = neighborID
-							break
-						}
+				for _, n := range m.Neighbors(x, y) {
+					neighborID := m.Grid[n[1]][n[0]]
+					if neighborID > 0 && !orphanedIDs[neighborID] {
+						newID = neighborID
+						break
 					}
 				}
 
@@ -388,6 +408,13 @@ func renumberTerritories(m *Map) {
 		}
 	}
 
+	// Starting territories follow their territories
+	for _, start := range m.Starts {
+		for i, oldID := range start {
+			start[i] = oldToNew[oldID]
+		}
+	}
+
 	// Rebuild territories map with new IDs
 	newTerritories := make(map[int]*Territory)
 	for oldID, terr := range m.Territories {
@@ -425,17 +452,8 @@ func computeAdjacencies(m *Map) {
 		coastalCount := 0
 
 		for _, cell := range t.Cells {
-			x, y := cell[0], cell[1]
-
-			// Check 4 orthogonal neighbors
-			dirs := [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
-			for _, d := range dirs {
-				nx, ny := x+d[0], y+d[1]
-
-				// Check bounds
-				if nx < 0 || nx >= m.Width || ny < 0 || ny >= m.Height {
-					continue
-				}
+			for _, n := range m.Neighbors(cell[0], cell[1]) {
+				nx, ny := n[0], n[1]
 
 				neighborTerr := m.Grid[ny][nx]
 				neighborWater := m.WaterGrid[ny][nx]
@@ -461,6 +479,8 @@ func computeAdjacencies(m *Map) {
 		for id := range adjWaters {
 			t.AdjacentWaters = append(t.AdjacentWaters, id)
 		}
+		sort.Ints(t.AdjacentTerritories)
+		sort.Sort(sort.Reverse(sort.IntSlice(t.AdjacentWaters)))
 
 		t.CoastalCells = coastalCount
 	}
@@ -470,18 +490,8 @@ func computeAdjacencies(m *Map) {
 		coastalTerr := make(map[int]bool)
 
 		for _, cell := range wb.Cells {
-			x, y := cell[0], cell[1]
-
-			// Check 4 orthogonal neighbors
-			dirs := [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
-			for _, d := range dirs {
-				nx, ny := x+d[0], y+d[1]
-
-				if nx < 0 || nx >= m.Width || ny < 0 || ny >= m.Height {
-					continue
-				}
-
-				neighborTerr := m.Grid[ny][nx]
+			for _, n := range m.Neighbors(cell[0], cell[1]) {
+				neighborTerr := m.Grid[n[1]][n[0]]
 				if neighborTerr > 0 {
 					coastalTerr[neighborTerr] = true
 				}
@@ -492,5 +502,6 @@ func computeAdjacencies(m *Map) {
 		for id := range coastalTerr {
 			wb.CoastalTerritories = append(wb.CoastalTerritories, id)
 		}
+		sort.Ints(wb.CoastalTerritories)
 	}
 }
@@ -0,0 +1,109 @@
+package maps
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// RawSea names the water body containing a cell. Seas are tied to a cell
+// rather than a water body ID because IDs are only assigned by Process.
+type RawSea struct {
+	Name string `json:"name"`
+	Cell [2]int `json:"cell"` // [x,y] of any water cell in the sea
+}
+
+// nameWaterBodies gives the water bodies named in raw their names.
+func nameWaterBodies(m *Map, raw *RawMap) {
+	for _, sea := range raw.Seas {
+		if wid := m.WaterBodyAt(sea.Cell[0], sea.Cell[1]); wid != 0 && sea.Name != "" {
+			m.WaterBodies[wid].Name = sea.Name
+		}
+	}
+}
+
+// Seas lists the map's named water bodies, each marked by its first cell.
+func (m *Map) Seas() []RawSea {
+	var seas []RawSea
+	for _, id := range m.waterBodyIDs() {
+		wb := m.WaterBodies[id]
+		if wb.Name != "" && len(wb.Cells) > 0 {
+			seas = append(seas, RawSea{Name: wb.Name, Cell: wb.Cells[0]})
+		}
+	}
+	return seas
+}
+
+// waterBodyIDs returns the water body IDs in order: -1, -2, ...
+func (m *Map) waterBodyIDs() []int {
+	ids := make([]int, 0, len(m.WaterBodies))
+	for id := range m.WaterBodies {
+		ids = append(ids, id)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(ids)))
+	return ids
+}
+
+// Sea name parts. Seas get an adjective and a kind chosen by size.
+var (
+	seaAdjectives = []string{
+		"Azure", "Amber", "Grey", "Stormy", "Silver", "Emerald", "Sapphire",
+		"Misty", "Shallow", "Frozen", "Sunset", "Crimson", "Whispering",
+		"Endless", "Coral", "Iron", "Golden", "Sleeping", "Western", "Eastern",
+		"Northern", "Southern", "Dragon", "Serpent", "Pearl", "Windward",
+	}
+	seaKinds = []struct {
+		minCells int
+		lake     string // Used instead when the water doesn't reach the map edge
+		open     string
+	}{
+		{200, "Inland Sea", "Ocean"},
+		{60, "Inland Sea", "Sea"},
+		{20, "Lake", "Gulf"},
+		{0, "Mere", "Bay"},
+	}
+)
+
+// nameSeas gives every water body on a generated map a unique name. Names
+// come from their own random source, seeded from the map layout, so they
+// don't change the rest of the generated map.
+func (g *Generator) nameSeas(m *Map) {
+	used := make(map[string]bool)
+	for _, id := range m.waterBodyIDs() {
+		wb := m.WaterBodies[id]
+		first := wb.Cells[0]
+		rng := rand.New(rand.NewSource(int64(first[1]*m.Width+first[0])*7919 + int64(len(wb.Cells))))
+
+		open := false
+		for _, c := range wb.Cells {
+			if c[0] == 0 || c[1] == 0 || c[0] == m.Width-1 || c[1] == m.Height-1 {
+				open = true
+				break
+			}
+		}
+		kind := seaKinds[len(seaKinds)-1]
+		for _, k := range seaKinds {
+			if len(wb.Cells) >= k.minCells {
+				kind = k
+				break
+			}
+		}
+		suffix := kind.lake
+		if open {
+			suffix = kind.open
+		}
+
+		name := ""
+		for attempt := 0; attempt < 20 && name == ""; attempt++ {
+			candidate := seaAdjectives[rng.Intn(len(seaAdjectives))] + " " + suffix
+			if !used[candidate] {
+				name = candidate
+			}
+		}
+		if name == "" {
+			name = fmt.Sprintf("%s %d", suffix, -id)
+		}
+		used[name] = true
+		wb.Name = name
+	}
+}
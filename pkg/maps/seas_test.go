@@ -0,0 +1,49 @@
+package maps
+
+import "testing"
+
+func TestSeaNames(t *testing.T) {
+	raw := &RawMap{
+		ID:     "seas",
+		Name:   "Seas",
+		Width:  5,
+		Height: 3,
+		Grid: [][]int{
+			{0, 0, 1, 0, 0},
+			{0, 0, 1, 0, 0},
+			{0, 0, 1, 0, 0},
+		},
+		Territories: map[string]RawTerritory{"1": {Name: "Isthmus"}},
+		Seas:        []RawSea{{Name: "West Sea", Cell: [2]int{0, 2}}, {Name: "East Sea", Cell: [2]int{4, 0}}},
+	}
+	m, err := LoadRaw(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.WaterBodies[m.WaterBodyAt(1, 1)].Name; got != "West Sea" {
+		t.Errorf("west water = %q, want West Sea", got)
+	}
+	if got := m.WaterBodies[m.WaterBodyAt(3, 1)].Name; got != "East Sea" {
+		t.Errorf("east water = %q, want East Sea", got)
+	}
+	if seas := m.Raw().Seas; len(seas) != 2 {
+		t.Errorf("saved seas = %v, want both", seas)
+	}
+
+	raw.Seas = append(raw.Seas, RawSea{Name: "Dry Sea", Cell: [2]int{2, 1}})
+	found := false
+	for _, d := range Validate(raw) {
+		found = found || d.Code == DiagBadSea
+	}
+	if !found {
+		t.Error("sea marked on land should be reported")
+	}
+
+	// Generated maps name all their water
+	gen, _ := NewGenerator(DefaultOptions()).Generate()
+	for id, wb := range gen.WaterBodies {
+		if wb.Name == "" {
+			t.Errorf("generated water body %d has no name", id)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package maps
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Symmetry says how the copies of a symmetric map fit together. Each seat
+// gets one copy, so every seat starts from an equivalent position.
+type Symmetry string
+
+const (
+	// SymmetryMirror is two halves, the right half the left flipped over.
+	// Square maps only.
+	SymmetryMirror Symmetry = "mirror"
+
+	// SymmetryRotate is two halves, the right half the left turned round
+	// the centre of the map. It is the only symmetry hex maps can have.
+	SymmetryRotate Symmetry = "rotate"
+
+	// SymmetryQuad is four quarters, each a flip of its neighbours. Square
+	// maps only.
+	SymmetryQuad Symmetry = "quad"
+)
+
+// Symmetries lists the symmetries, for menus and flags.
+var Symmetries = []Symmetry{SymmetryMirror, SymmetryRotate, SymmetryQuad}
+
+// Known reports whether the symmetry is one the generator understands.
+// Empty means none.
+func (s Symmetry) Known() bool {
+	return s == "" || s == SymmetryMirror || s == SymmetryRotate || s == SymmetryQuad
+}
+
+// Supports reports whether a map with topology t can have the symmetry.
+// Flipping a hex map puts odd rows out of line; turning it round doesn't,
+// as long as it has an even number of rows.
+func (s Symmetry) Supports(t Topology) bool {
+	return t != TopologyHex || s == "" || s == SymmetryRotate
+}
+
+// Seats returns how many copies the map is made of.
+func (s Symmetry) Seats() int {
+	switch s {
+	case SymmetryMirror, SymmetryRotate:
+		return 2
+	case SymmetryQuad:
+		return 4
+	}
+	return 1
+}
+
+// transform returns where copy k of cell x,y lies on a width x height map.
+// Copy 0 is the cell itself. Copies compose by XOR: copy j of copy k is
+// copy j^k.
+func (s Symmetry) transform(k, x, y, width, height int) (int, int) {
+	switch {
+	case k == 0:
+		return x, y
+	case s == SymmetryRotate:
+		return width - 1 - x, height - 1 - y
+	case s == SymmetryMirror || k == 1:
+		return width - 1 - x, y
+	case k == 2:
+		return x, height - 1 - y
+	}
+	return width - 1 - x, height - 1 - y
+}
+
+// part returns which copy cell x,y belongs to. Part 0 is the one the
+// generator grows; part k is its copy k.
+func (s Symmetry) part(x, y, width, height int) int {
+	k := 0
+	if s != "" && x >= width/2 {
+		k |= 1
+	}
+	if s == SymmetryQuad && y >= height/2 {
+		k |= 2
+	}
+	return k
+}
+
+// CheckSymmetry checks that a map has the symmetry it claims: every copy of
+// a territory has the same shape, resource, terrain and coastline, touches
+// the copies of its neighbours, and belongs to a seat whose starting
+// territories are a copy of every other seat's.
+func CheckSymmetry(m *Map) error {
+	if m.Symmetry == "" {
+		return nil
+	}
+	if !m.Symmetry.Known() || !m.Symmetry.Supports(m.Topology) {
+		return fmt.Errorf("%s maps can't have %q symmetry", m.Topology, m.Symmetry)
+	}
+
+	for k := 1; k < m.Symmetry.Seats(); k++ {
+		image := make(map[int]int, len(m.Territories))
+		for y, row := range m.Grid {
+			for x, tid := range row {
+				tx, ty := m.Symmetry.transform(k, x, y, m.Width, m.Height)
+				other := m.Grid[ty][tx]
+				if (tid == 0) != (other == 0) {
+					return fmt.Errorf("cell %d,%d and its copy %d,%d are not both land or both water", x, y, tx, ty)
+				}
+				if tid == 0 {
+					continue
+				}
+				if prev, ok := image[tid]; ok && prev != other {
+					return fmt.Errorf("territory %d is copied onto both %d and %d", tid, prev, other)
+				}
+				image[tid] = other
+			}
+		}
+
+		for _, tid := range sortedTerritoryIDs(m) {
+			t, c := m.Territories[tid], m.Territories[image[tid]]
+			switch {
+			case c == nil:
+				return fmt.Errorf("territory %d has no copy", tid)
+			case len(t.Cells) != len(c.Cells):
+				return fmt.Errorf("territory %d has %d cells but its copy %d has %d", tid, len(t.Cells), c.ID, len(c.Cells))
+			case t.Resource != c.Resource || t.Terrain != c.Terrain:
+				return fmt.Errorf("territory %d and its copy %d differ in resource or terrain", tid, c.ID)
+			case t.CoastalCells != c.CoastalCells || len(t.AdjacentWaters) != len(c.AdjacentWaters):
+				return fmt.Errorf("territory %d and its copy %d have different coastlines", tid, c.ID)
+			}
+			for _, adj := range t.AdjacentTerritories {
+				if !containsInt(c.AdjacentTerritories, image[adj]) {
+					return fmt.Errorf("territory %d borders %d, but its copy %d doesn't border %d", tid, adj, c.ID, image[adj])
+				}
+			}
+		}
+
+		// The copy of each seat's starts must be another seat's starts
+		seats := make(map[string]bool, len(m.Starts))
+		for _, start := range m.Starts {
+			seats[startKey(start, nil)] = true
+		}
+		for i, start := range m.Starts {
+			if !seats[startKey(start, image)] {
+				return fmt.Errorf("the copy of seat %d's starting territories is not another seat's", i+1)
+			}
+		}
+	}
+	return nil
+}
+
+// startKey identifies a set of starting territories, after mapping them
+// through image if it is given.
+func startKey(start []int, image map[int]int) string {
+	ids := make([]int, len(start))
+	for i, tid := range start {
+		ids[i] = tid
+		if image != nil {
+			ids[i] = image[tid]
+		}
+	}
+	sort.Ints(ids)
+	return fmt.Sprint(ids)
+}
+
+func containsInt(list []int, v int) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// symmetrize copies every territory grown in part 0 into the other parts of
+// a symmetric map. Copy k of territory t gets the ID t + k*offset, where
+// offset is the highest ID grown.
+func (g *Generator) symmetrize() {
+	ids := g.territoryIDs()
+	if len(ids) == 0 {
+		return
+	}
+	g.copyOffset = ids[len(ids)-1]
+	for k := 1; k < g.sym.Seats(); k++ {
+		for _, tid := range ids {
+			twin := tid + k*g.copyOffset
+			cells := make([][2]int, len(g.territories[tid].cells))
+			for i, c := range g.territories[tid].cells {
+				x, y := g.sym.transform(k, c[0], c[1], g.width, g.height)
+				cells[i] = [2]int{x, y}
+				g.grid[y][x] = twin
+			}
+			g.territories[twin] = &terrData{id: twin, cells: cells}
+			g.steps = append(g.steps, GeneratorStep{TerritoryID: twin, Cells: cells})
+		}
+	}
+	g.fillSymmetricLakes()
+}
+
+// original returns the territory grown in part 0 that tid copies, and which
+// copy it is. Territories of maps without symmetry are their own copy 0.
+func (g *Generator) original(tid int) (int, int) {
+	if g.copyOffset == 0 {
+		return tid, 0
+	}
+	k := (tid - 1) / g.copyOffset
+	return tid - k*g.copyOffset, k
+}
+
+// copyOf returns copy k of territory tid.
+func (g *Generator) copyOf(tid, k int) int {
+	orig, j := g.original(tid)
+	return orig + (j^k)*g.copyOffset
+}
+
+// fillSymmetricLakes fills the small lakes Process would fill, the same way
+// in every copy. Left to Process, a lake can go to a territory whose copy
+// doesn't border the lake's copy.
+func (g *Generator) fillSymmetricLakes() {
+	m := &Map{Width: g.width, Height: g.height, Grid: g.grid, Topology: g.options.Topology}
+	for _, lake := range smallLakes(m) {
+		for _, c := range lake {
+			if g.sym.part(c[0], c[1], g.width, g.height) != 0 {
+				continue
+			}
+			// Each cell of the lake goes to the neighbour it touches most
+			counts := make(map[int]int)
+			for _, n := range m.Neighbors(c[0], c[1]) {
+				if tid := g.grid[n[1]][n[0]]; tid > 0 {
+					counts[tid]++
+				}
+			}
+			if len(counts) == 0 {
+				continue
+			}
+			tid := findMajority(counts)
+			for k := 0; k < g.sym.Seats(); k++ {
+				x, y := g.sym.transform(k, c[0], c[1], g.width, g.height)
+				twin := g.copyOf(tid, k)
+				g.grid[y][x] = twin
+				g.territories[twin].cells = append(g.territories[twin].cells, [2]int{x, y})
+			}
+		}
+	}
+}
+
+// starts splits the territories of a symmetric map between its seats: each
+// seat starts with one whole copy.
+func (g *Generator) starts() [][]int {
+	if g.copyOffset == 0 {
+		return nil
+	}
+	starts := make([][]int, g.sym.Seats())
+	for _, tid := range g.territoryIDs() {
+		_, k := g.original(tid)
+		starts[k] = append(starts[k], tid)
+	}
+	return starts
+}
@@ -0,0 +1,335 @@
+package maps
+
+import (
+	"math"
+	"sort"
+)
+
+// Template shapes the land masses of a generated map. Without one, land is
+// scattered according to GeneratorOptions.Islands; with one, Islands is
+// ignored and the land is laid out first, then cut into territories.
+type Template string
+
+const (
+	// TemplateContinents gives every seat a continent of its own, all of
+	// the same size and with the same number of territories.
+	TemplateContinents Template = "continents"
+
+	// TemplateArchipelago scatters many small islands.
+	TemplateArchipelago Template = "archipelago"
+
+	// TemplatePangaea is a single land mass.
+	TemplatePangaea Template = "pangaea"
+
+	// TemplateRing is a ring of land around an inland sea.
+	TemplateRing Template = "ring"
+
+	// TemplateMirror is for two players: a land mass grown on one half of
+	// the map and copied onto the other. It is generated with SymmetryRotate
+	// unless GeneratorOptions.Symmetry says otherwise.
+	TemplateMirror Template = "mirror"
+)
+
+// Templates lists the generation templates, for menus and flags.
+var Templates = []Template{TemplateContinents, TemplateArchipelago, TemplatePangaea, TemplateRing, TemplateMirror}
+
+// Known reports whether the generator understands the template. Empty means
+// no template.
+func (t Template) Known() bool {
+	if t == "" {
+		return true
+	}
+	for _, known := range Templates {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Average territory size in cells, for working out how much land a
+// template needs (territories grow to 6-12 cells).
+const templateCellsPerTerritory = 9
+
+// templateSeeds lays out the template's land masses, marks them in g.land
+// and returns territory seeds spread over them.
+func (g *Generator) templateSeeds(count int) [][2]int {
+	// Leave at least a third of the map to the sea
+	area := count * templateCellsPerTerritory
+	if limit := g.landCells() * 2 / 3; area > limit {
+		area = limit
+	}
+
+	cx, cy := float64(g.width-1)/2, float64(g.height-1)/2
+	var regions [][][2]int
+	balanced := false
+	switch g.options.Template {
+	case TemplateContinents:
+		seats := g.options.Seats
+		if seats <= 0 {
+			seats = 4
+		}
+		seats = clamp(seats, 2, 6)
+		centers := make([][2]float64, seats)
+		phase := g.rng.Float64() * 2 * math.Pi
+		for i := range centers {
+			angle := phase + 2*math.Pi*float64(i)/float64(seats)
+			centers[i] = [2]float64{cx + cx*0.55*math.Cos(angle), cy + cy*0.55*math.Sin(angle)}
+		}
+		regions = g.partition(centers, area/seats)
+		balanced = true
+
+	case TemplateArchipelago:
+		islands := count / 3
+		if islands < 6 {
+			islands = 6
+		}
+		regions = g.partition(g.spreadCenters(islands), area/islands)
+
+	case TemplatePangaea:
+		regions = [][][2]int{g.growLand(cx, cy, area, func(x, y int) (float64, bool) {
+			return math.Hypot(float64(x)-cx, float64(y)-cy), true
+		})}
+
+	case TemplateRing:
+		// Radii are measured as a fraction of the way to the map edge, so
+		// the ring follows the shape of the map
+		rx, ry := cx, cy
+		inner := 0.4
+		outer := math.Sqrt(float64(area)/(math.Pi*rx*ry) + inner*inner)
+		middle := (inner + outer) / 2
+		regions = [][][2]int{g.growLand(cx+rx*middle, cy, area, func(x, y int) (float64, bool) {
+			r := math.Hypot((float64(x)-cx)/rx, (float64(y)-cy)/ry)
+			return math.Abs(r-middle) * rx, r >= inner
+		})}
+
+	case TemplateMirror:
+		// Grow the left half; symmetrize fills in the right
+		hx := float64(g.width/2-1) / 2
+		regions = [][][2]int{g.growLand(hx, cy, area/2, func(x, y int) (float64, bool) {
+			return math.Hypot(float64(x)-hx, float64(y)-cy), true
+		})}
+		count /= 2
+		balanced = true
+	}
+
+	if balanced {
+		// Growth order keeps every prefix of a region connected, so
+		// trimming all regions to the smallest keeps them whole
+		size := len(regions[0])
+		for _, r := range regions[1:] {
+			if len(r) < size {
+				size = len(r)
+			}
+		}
+		for i := range regions {
+			regions[i] = regions[i][:size]
+		}
+	}
+
+	g.land = make([][]bool, g.height)
+	for y := range g.land {
+		g.land[y] = make([]bool, g.width)
+	}
+	for _, r := range regions {
+		for _, c := range r {
+			g.land[c[1]][c[0]] = true
+		}
+	}
+
+	seeds := make([][2]int, 0, count)
+	for i, r := range regions {
+		n := count / len(regions)
+		if !balanced && i < count%len(regions) {
+			n++
+		}
+		seeds = append(seeds, g.scatterSeeds(r, n)...)
+	}
+	return seeds
+}
+
+// landCells counts the cells land may be placed on.
+func (g *Generator) landCells() int {
+	if g.options.WaterBorder {
+		return (g.width - 2) * (g.height - 2)
+	}
+	return g.width * g.height
+}
+
+// growLand grows a connected patch of up to size land cells, starting at
+// the cell nearest x,y. score says whether a cell may be land and how
+// eagerly to take it: lowest scores first, with some noise for a ragged
+// coast. Cells are returned in the order they were taken.
+func (g *Generator) growLand(x, y float64, size int, score func(x, y int) (float64, bool)) [][2]int {
+	start, best := [2]int{-1, -1}, math.Inf(1)
+	for cy := 0; cy < g.height; cy++ {
+		for cx := 0; cx < g.width; cx++ {
+			if _, ok := score(cx, cy); !ok || !g.isValidLandCell(cx, cy) {
+				continue
+			}
+			if d := math.Hypot(float64(cx)-x, float64(cy)-y); d < best {
+				start, best = [2]int{cx, cy}, d
+			}
+		}
+	}
+	if start[0] < 0 {
+		return nil
+	}
+
+	type candidate struct {
+		cell  [2]int
+		score float64
+	}
+	cells := [][2]int{start}
+	seen := map[[2]int]bool{start: true}
+	var frontier []candidate
+	add := func(c [2]int) {
+		for _, n := range g.neighbors(c[0], c[1]) {
+			if seen[n] || !g.isValidLandCell(n[0], n[1]) {
+				continue
+			}
+			seen[n] = true
+			if s, ok := score(n[0], n[1]); ok {
+				frontier = append(frontier, candidate{n, s + g.rng.Float64()*2.5})
+			}
+		}
+	}
+	add(start)
+	for len(cells) < size && len(frontier) > 0 {
+		next := 0
+		for i, c := range frontier {
+			if c.score < frontier[next].score {
+				next = i
+			}
+		}
+		cell := frontier[next].cell
+		frontier = append(frontier[:next], frontier[next+1:]...)
+		cells = append(cells, cell)
+		add(cell)
+	}
+	return cells
+}
+
+// partition grows a patch of land of the given size around each center.
+// Each patch stays on its own side of the others, with a channel of water
+// between them.
+func (g *Generator) partition(centers [][2]float64, size int) [][][2]int {
+	const channel = 2.0
+	regions := make([][][2]int, 0, len(centers))
+	for i, c := range centers {
+		regions = append(regions, g.growLand(c[0], c[1], size, func(x, y int) (float64, bool) {
+			d := math.Hypot(float64(x)-c[0], float64(y)-c[1])
+			for j, o := range centers {
+				if j != i && math.Hypot(float64(x)-o[0], float64(y)-o[1]) < d+channel {
+					return d, false
+				}
+			}
+			return d, true
+		}))
+	}
+	return regions
+}
+
+// spreadCenters picks n points spread out over the map.
+func (g *Generator) spreadCenters(n int) [][2]float64 {
+	minX, minY := 1, 1
+	w, h := g.width-2, g.height-2
+	minDist := float64(w+h) / float64(n+1)
+	if minDist < 4 {
+		minDist = 4
+	}
+
+	centers := make([][2]float64, 0, n)
+	for len(centers) < n {
+		var c [2]float64
+		for attempt := 0; attempt < 100; attempt++ {
+			c = [2]float64{float64(minX + g.rng.Intn(w)), float64(minY + g.rng.Intn(h))}
+			tooClose := false
+			for _, o := range centers {
+				if math.Hypot(c[0]-o[0], c[1]-o[1]) < minDist {
+					tooClose = true
+					break
+				}
+			}
+			if !tooClose {
+				break
+			}
+		}
+		centers = append(centers, c)
+	}
+	return centers
+}
+
+// scatterSeeds picks n territory seeds from a land mass, at least two cells
+// apart where there is room.
+func (g *Generator) scatterSeeds(region [][2]int, n int) [][2]int {
+	if n > len(region) {
+		n = len(region)
+	}
+	order := g.rng.Perm(len(region))
+	seeds := make([][2]int, 0, n)
+	used := make(map[int]bool)
+	for _, i := range order {
+		if len(seeds) == n {
+			break
+		}
+		c := region[i]
+		tooClose := false
+		for _, s := range seeds {
+			dx, dy := c[0]-s[0], c[1]-s[1]
+			if dx*dx+dy*dy < 4 {
+				tooClose = true
+				break
+			}
+		}
+		if !tooClose {
+			seeds = append(seeds, c)
+			used[i] = true
+		}
+	}
+	// Crowded land mass: take whatever cells are left
+	for _, i := range order {
+		if len(seeds) == n {
+			break
+		}
+		if !used[i] {
+			seeds = append(seeds, region[i])
+		}
+	}
+	return seeds
+}
+
+// fillLand grows territories into the template land they didn't reach, so
+// the land masses come out whole.
+func (g *Generator) fillLand() {
+	added := make(map[int][][2]int)
+	for {
+		var open [][2]int
+		for y := 0; y < g.height; y++ {
+			for x := 0; x < g.width; x++ {
+				if g.isValidLandCell(x, y) && g.grid[y][x] == 0 && g.findOrthogonalNeighborTerritory(x, y, 0) > 0 {
+					open = append(open, [2]int{x, y})
+				}
+			}
+		}
+		if len(open) == 0 {
+			break
+		}
+		g.rng.Shuffle(len(open), func(i, j int) { open[i], open[j] = open[j], open[i] })
+		for _, c := range open {
+			tid := g.findOrthogonalNeighborTerritory(c[0], c[1], 0)
+			g.grid[c[1]][c[0]] = tid
+			g.territories[tid].cells = append(g.territories[tid].cells, c)
+			added[tid] = append(added[tid], c)
+		}
+	}
+
+	ids := make([]int, 0, len(added))
+	for tid := range added {
+		ids = append(ids, tid)
+	}
+	sort.Ints(ids)
+	for _, tid := range ids {
+		g.steps = append(g.steps, GeneratorStep{TerritoryID: tid, Cells: added[tid]})
+	}
+}
@@ -0,0 +1,130 @@
+package maps
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Topology says how the cells of a map grid connect. Adjacency, coastlines
+// and water bodies are all worked out through it, so the game itself never
+// sees the difference.
+type Topology string
+
+const (
+	// TopologySquare cells touch the four cells beside, above and below
+	// them. It is the default.
+	TopologySquare Topology = "square"
+
+	// TopologyHex cells are pointy-topped hexes in offset rows: odd rows sit
+	// half a cell to the right, and each cell touches six others.
+	TopologyHex Topology = "hex"
+)
+
+var (
+	squareOffsets = [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+
+	// Hex neighbours differ between even and odd rows because of the shift
+	hexEvenOffsets = [][2]int{{-1, 0}, {1, 0}, {-1, -1}, {0, -1}, {-1, 1}, {0, 1}}
+	hexOddOffsets  = [][2]int{{-1, 0}, {1, 0}, {0, -1}, {1, -1}, {0, 1}, {1, 1}}
+)
+
+// Known reports whether the topology is one Process understands. Empty
+// means square.
+func (t Topology) Known() bool {
+	return t == "" || t == TopologySquare || t == TopologyHex
+}
+
+// offsets returns the offsets from a cell in row y to its neighbours.
+func (t Topology) offsets(y int) [][2]int {
+	if t != TopologyHex {
+		return squareOffsets
+	}
+	if y%2 != 0 {
+		return hexOddOffsets
+	}
+	return hexEvenOffsets
+}
+
+// Neighbors returns the cells next to x,y on a width x height grid.
+func (t Topology) Neighbors(x, y, width, height int) [][2]int {
+	offsets := t.offsets(y)
+	cells := make([][2]int, 0, len(offsets))
+	for _, d := range offsets {
+		nx, ny := x+d[0], y+d[1]
+		if nx >= 0 && nx < width && ny >= 0 && ny < height {
+			cells = append(cells, [2]int{nx, ny})
+		}
+	}
+	return cells
+}
+
+// CellCenter returns the centre of cell x,y in cell units, for drawing.
+func (t Topology) CellCenter(x, y int) (float64, float64) {
+	if t == TopologyHex && y%2 != 0 {
+		return float64(x) + 1, float64(y) + 0.5
+	}
+	return float64(x) + 0.5, float64(y) + 0.5
+}
+
+// Neighbors returns the cells next to x,y on the map.
+func (m *Map) Neighbors(x, y int) [][2]int {
+	return m.Topology.Neighbors(x, y, m.Width, m.Height)
+}
+
+// rasterized returns raw with its grid filled in from its polygons, if it
+// has polygons and no grid. Other maps are returned unchanged.
+//
+// Each cell goes to the territory whose polygon contains the cell's centre,
+// the lowest ID if polygons overlap; cells outside every polygon are water.
+func rasterized(raw *RawMap) *RawMap {
+	if len(raw.Grid) > 0 || len(raw.Polygons) == 0 || raw.Width <= 0 || raw.Height <= 0 {
+		return raw
+	}
+
+	ids := make([]int, 0, len(raw.Polygons))
+	for key := range raw.Polygons {
+		if tid, err := strconv.Atoi(key); err == nil && tid > 0 {
+			ids = append(ids, tid)
+		}
+	}
+	sort.Ints(ids)
+
+	out := *raw
+	out.Grid = make([][]int, raw.Height)
+	for y := range out.Grid {
+		out.Grid[y] = make([]int, raw.Width)
+		for x := range out.Grid[y] {
+			cx, cy := raw.Topology.CellCenter(x, y)
+			for _, tid := range ids {
+				if pointInPolygon(cx, cy, raw.Polygons[strconv.Itoa(tid)]) {
+					out.Grid[y][x] = tid
+					break
+				}
+			}
+		}
+	}
+	return &out
+}
+
+// pointInPolygon reports whether x,y is inside the polygon, by the even-odd
+// rule.
+func pointInPolygon(x, y float64, polygon [][2]float64) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		xi, yi := polygon[i][0], polygon[i][1]
+		xj, yj := polygon[j][0], polygon[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// validPolygon checks a territory outline before it is rasterized.
+func validPolygon(polygon [][2]float64) error {
+	if len(polygon) < 3 {
+		return fmt.Errorf("polygon has %d points, needs at least 3", len(polygon))
+	}
+	return nil
+}
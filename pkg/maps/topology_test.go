@@ -0,0 +1,102 @@
+package maps
+
+import "testing"
+
+func TestHexNeighbors(t *testing.T) {
+	for _, tc := range []struct {
+		x, y int
+		want [][2]int
+	}{
+		{2, 2, [][2]int{{1, 2}, {3, 2}, {1, 1}, {2, 1}, {1, 3}, {2, 3}}},
+		{2, 1, [][2]int{{1, 1}, {3, 1}, {2, 0}, {3, 0}, {2, 2}, {3, 2}}},
+		{0, 0, [][2]int{{1, 0}, {0, 1}}},
+	} {
+		got := TopologyHex.Neighbors(tc.x, tc.y, 5, 5)
+		if len(got) != len(tc.want) {
+			t.Errorf("neighbours of %d,%d = %v, want %v", tc.x, tc.y, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("neighbours of %d,%d = %v, want %v", tc.x, tc.y, got, tc.want)
+				break
+			}
+		}
+	}
+}
+
+func TestHexAdjacency(t *testing.T) {
+	// Territories 1 and 2 only touch diagonally, which counts on a hex grid
+	raw := &RawMap{
+		ID:     "hex",
+		Name:   "Hex",
+		Width:  4,
+		Height: 2,
+		Grid: [][]int{
+			{0, 1, 0, 0},
+			{2, 0, 0, 0},
+		},
+		Territories: map[string]RawTerritory{"1": {Name: "A", Resource: "timber"}, "2": {Name: "B"}},
+	}
+	square, err := LoadRaw(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(square.Territories[1].AdjacentTerritories) != 0 {
+		t.Errorf("square adjacency = %v, want none", square.Territories[1].AdjacentTerritories)
+	}
+
+	raw.Topology = TopologyHex
+	hex, err := LoadRaw(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hex.Territories[1].AdjacentTerritories) != 1 {
+		t.Errorf("hex adjacency = %v, want territory 2", hex.Territories[1].AdjacentTerritories)
+	}
+}
+
+func TestPolygonMap(t *testing.T) {
+	raw := &RawMap{
+		ID:     "poly",
+		Name:   "Poly",
+		Width:  6,
+		Height: 4,
+		Polygons: map[string][][2]float64{
+			"1": {{1, 1}, {3, 1}, {3, 3}, {1, 3}},
+			"2": {{3, 1}, {5, 1}, {5, 3}, {3, 3}},
+		},
+		Territories: map[string]RawTerritory{"1": {Name: "West"}, "2": {Name: "East"}},
+	}
+	m, err := LoadRaw(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]int{
+		{0, 0, 0, 0, 0, 0},
+		{0, 1, 1, 2, 2, 0},
+		{0, 1, 1, 2, 2, 0},
+		{0, 0, 0, 0, 0, 0},
+	}
+	for y := range want {
+		for x := range want[y] {
+			if m.Grid[y][x] != want[y][x] {
+				t.Fatalf("grid = %v, want %v", m.Grid, want)
+			}
+		}
+	}
+	if len(m.Territories[1].AdjacentTerritories) != 1 {
+		t.Errorf("territory 1 adjacent to %v, want territory 2", m.Territories[1].AdjacentTerritories)
+	}
+
+	raw.Polygons["2"] = [][2]float64{{3, 1}, {5, 1}}
+	if !Validate(raw).HasErrors() {
+		t.Error("two-point polygon should fail validation")
+	}
+
+	raw.Polygons["2"] = [][2]float64{{3, 1}, {5, 1}, {5, 3}, {3, 3}}
+	raw.Topology = "triangle"
+	if !Validate(raw).HasErrors() {
+		t.Error("unknown topology should fail validation")
+	}
+}
@@ -11,12 +11,30 @@ type RawMap struct {
 	Height      int                 `json:"height"`
 	Grid        [][]int             `json:"grid"` // Territory IDs, 0 = water
 	Territories map[string]RawTerritory `json:"territories"`
+
+	// Topology is how grid cells connect; empty means square.
+	Topology Topology `json:"topology,omitempty"`
+
+	// Polygons are territory outlines in cell units, keyed like Territories.
+	// A map with polygons and no grid has its grid rasterized from them.
+	Polygons map[string][][2]float64 `json:"polygons,omitempty"`
+
+	// Seas names water bodies.
+	Seas []RawSea `json:"seas,omitempty"`
+
+	// Symmetry is the symmetry the map claims; Validate checks it.
+	Symmetry Symmetry `json:"symmetry,omitempty"`
+
+	// Starts lists each seat's starting territories, for games that skip
+	// the territory draft.
+	Starts [][]int `json:"starts,omitempty"`
 }
 
 // RawTerritory is territory data from the JSON file.
 type RawTerritory struct {
 	Name     string `json:"name"`
 	Resource string `json:"resource,omitempty"` // coal, gold, iron, timber, grassland, or empty
+	Terrain  string `json:"terrain,omitempty"`  // plains, forest, mountains, marsh, or empty
 }
 
 // Map is the processed, runtime map data.
@@ -29,6 +47,9 @@ type Map struct {
 	// Grid of territory IDs (1+ for territories, 0 for water)
 	Grid [][]int
 
+	// How grid cells connect (never empty once processed)
+	Topology Topology
+
 	// Water body grid (0 = land, negative values = water body IDs)
 	WaterGrid [][]int
 
@@ -37,6 +58,10 @@ type Map struct {
 
 	// Water bodies indexed by ID (negative numbers)
 	WaterBodies map[int]*WaterBody
+
+	// Symmetry and starting territories of each seat, if any
+	Symmetry Symmetry
+	Starts   [][]int
 }
 
 // Territory represents a territory on the map.
@@ -44,6 +69,7 @@ type Territory struct {
 	ID                  int
 	Name                string
 	Resource            game.ResourceType
+	Terrain             game.Terrain
 	Cells               [][2]int // List of [x,y] coordinates
 	AdjacentTerritories []int    // Neighboring territory IDs
 	AdjacentWaters      []int    // Water body IDs this touches (negative)
@@ -0,0 +1,331 @@
+package maps
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"lords-of-conquest/internal/game"
+)
+
+// Severity says whether a diagnostic makes a map unplayable.
+type Severity int
+
+const (
+	SeverityError   Severity = iota // The map can't be used
+	SeverityWarning                 // The map works but is probably not what the author meant
+)
+
+// String returns the severity name.
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Diagnostic codes reported by Validate.
+const (
+	DiagMissingField     = "missing_field"
+	DiagBadDimensions    = "bad_dimensions"
+	DiagBadCell          = "bad_cell"
+	DiagBadTopology      = "bad_topology"
+	DiagBadPolygon       = "bad_polygon"
+	DiagBadSea           = "bad_sea"
+	DiagNonContiguous    = "non_contiguous"
+	DiagIsolated         = "isolated"
+	DiagMissingTerritory = "missing_territory"
+	DiagUnusedTerritory  = "unused_territory"
+	DiagUnknownResource  = "unknown_resource"
+	DiagUnknownTerrain   = "unknown_terrain"
+	DiagDuplicateName    = "duplicate_name"
+	DiagNoTimber         = "no_timber"
+	DiagIslandNoTimber   = "island_no_timber"
+	DiagBadStart         = "bad_start"
+	DiagAsymmetric       = "asymmetric"
+)
+
+// Diagnostic is one problem found in a map.
+type Diagnostic struct {
+	Severity  Severity `json:"severity"`
+	Code      string   `json:"code"`
+	Territory int      `json:"territory,omitempty"` // Grid ID of the territory concerned, if any
+	Message   string   `json:"message"`
+}
+
+// String formats the diagnostic for display.
+func (d Diagnostic) String() string {
+	if d.Territory != 0 {
+		return fmt.Sprintf("%s: territory %d: %s", d.Severity, d.Territory, d.Message)
+	}
+	return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+}
+
+// Diagnostics is the result of validating a map.
+type Diagnostics []Diagnostic
+
+// HasErrors reports whether any diagnostic is an error.
+func (ds Diagnostics) HasErrors() bool {
+	for _, d := range ds {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns the errors among the diagnostics as a single error, or nil if
+// there are only warnings.
+func (ds Diagnostics) Err() error {
+	var errs []error
+	for _, d := range ds {
+		if d.Severity == SeverityError {
+			errs = append(errs, errors.New(d.String()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks a raw map before it is processed. Process quietly repairs
+// some problems, such as filling small lakes; Validate reports everything it
+// finds, so a map author can fix the source.
+func Validate(raw *RawMap) Diagnostics {
+	var ds Diagnostics
+	report := func(sev Severity, code string, tid int, format string, args ...any) {
+		ds = append(ds, Diagnostic{Severity: sev, Code: code, Territory: tid, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if raw.ID == "" {
+		report(SeverityError, DiagMissingField, 0, "map ID is required")
+	}
+	if raw.Name == "" {
+		report(SeverityError, DiagMissingField, 0, "map name is required")
+	}
+	if !raw.Topology.Known() {
+		report(SeverityError, DiagBadTopology, 0, "unknown topology %q", raw.Topology)
+		return ds
+	}
+	if !raw.Symmetry.Known() || !raw.Symmetry.Supports(raw.Topology) {
+		report(SeverityError, DiagAsymmetric, 0, "%q symmetry is not possible on this map", raw.Symmetry)
+	}
+	for _, key := range sortedKeys(raw.Polygons) {
+		if err := validPolygon(raw.Polygons[key]); err != nil {
+			tid, _ := strconv.Atoi(key)
+			report(SeverityError, DiagBadPolygon, tid, "%v", err)
+		}
+	}
+	raw = rasterized(raw)
+	if raw.Width <= 0 || raw.Height <= 0 {
+		report(SeverityError, DiagBadDimensions, 0, "invalid dimensions: %dx%d", raw.Width, raw.Height)
+		return ds
+	}
+	if len(raw.Grid) != raw.Height {
+		report(SeverityError, DiagBadDimensions, 0, "grid height mismatch: expected %d, got %d", raw.Height, len(raw.Grid))
+		return ds
+	}
+	for y, row := range raw.Grid {
+		if len(row) != raw.Width {
+			report(SeverityError, DiagBadDimensions, 0, "row %d width mismatch: expected %d, got %d", y, raw.Width, len(row))
+			return ds
+		}
+	}
+
+	// Seas must be marked by a water cell
+	for _, sea := range raw.Seas {
+		x, y := sea.Cell[0], sea.Cell[1]
+		if x < 0 || x >= raw.Width || y < 0 || y >= raw.Height || raw.Grid[y][x] != 0 {
+			report(SeverityWarning, DiagBadSea, 0, "sea %q is marked at %d,%d, which is not water", sea.Name, x, y)
+		}
+	}
+
+	// Collect the cells of each territory
+	cells := make(map[int][][2]int)
+	for y, row := range raw.Grid {
+		for x, tid := range row {
+			if tid < 0 {
+				report(SeverityError, DiagBadCell, 0, "cell %d,%d has negative ID %d", x, y, tid)
+			} else if tid > 0 {
+				cells[tid] = append(cells[tid], [2]int{x, y})
+			}
+		}
+	}
+	ids := make([]int, 0, len(cells))
+	for tid := range cells {
+		ids = append(ids, tid)
+	}
+	sort.Ints(ids)
+
+	// Shape and surroundings of each territory
+	neighbours := make(map[int]map[int]bool, len(ids))
+	for _, tid := range ids {
+		neighbours[tid] = make(map[int]bool)
+		coastal := false
+		for _, c := range cells[tid] {
+			for _, nc := range raw.Topology.Neighbors(c[0], c[1], raw.Width, raw.Height) {
+				switch n := raw.Grid[nc[1]][nc[0]]; {
+				case n == 0:
+					coastal = true
+				case n > 0 && n != tid:
+					neighbours[tid][n] = true
+				}
+			}
+		}
+
+		if parts := countParts(raw, tid, cells[tid]); parts > 1 {
+			report(SeverityError, DiagNonContiguous, tid, "split into %d separate parts", parts)
+		}
+		if !coastal && len(neighbours[tid]) == 0 {
+			report(SeverityError, DiagIsolated, tid, "has no neighbours and no water access")
+		}
+	}
+
+	// Territory entries
+	names := make(map[string]int)
+	for _, tid := range ids {
+		rt, ok := raw.Territories[strconv.Itoa(tid)]
+		if !ok {
+			report(SeverityWarning, DiagMissingTerritory, tid, "has no territory entry, will be named %q", "Territory "+strconv.Itoa(tid))
+			continue
+		}
+		if !knownResource(rt.Resource) {
+			report(SeverityError, DiagUnknownResource, tid, "unknown resource %q", rt.Resource)
+		}
+		if _, ok := game.ParseTerrain(strings.ToLower(rt.Terrain)); !ok {
+			report(SeverityError, DiagUnknownTerrain, tid, "unknown terrain %q", rt.Terrain)
+		}
+		if rt.Name == "" {
+			continue
+		}
+		if first, dup := names[rt.Name]; dup {
+			report(SeverityWarning, DiagDuplicateName, tid, "has the same name %q as territory %d", rt.Name, first)
+		} else {
+			names[rt.Name] = tid
+		}
+	}
+	for _, key := range sortedKeys(raw.Territories) {
+		tid, err := strconv.Atoi(key)
+		if err != nil || len(cells[tid]) == 0 {
+			report(SeverityWarning, DiagUnusedTerritory, 0, "territory entry %q has no cells on the grid", key)
+		}
+	}
+
+	// Each territory starts with at most one seat
+	startedBy := make(map[int]int)
+	for i, start := range raw.Starts {
+		for _, tid := range start {
+			if len(cells[tid]) == 0 {
+				report(SeverityError, DiagBadStart, tid, "is a starting territory of seat %d but is not on the grid", i+1)
+			} else if seat, dup := startedBy[tid]; dup {
+				report(SeverityError, DiagBadStart, tid, "is a starting territory of both seat %d and seat %d", seat, i+1)
+			} else {
+				startedBy[tid] = i + 1
+			}
+		}
+	}
+
+	// Islands can only be reached by boat, and boats need timber
+	masses := rawLandMasses(ids, neighbours)
+	if len(masses) > 1 {
+		timberOn := make([]bool, len(masses))
+		anyTimber := false
+		for i, mass := range masses {
+			for _, tid := range mass {
+				if parseResource(raw.Territories[strconv.Itoa(tid)].Resource) == game.ResourceTimber {
+					timberOn[i], anyTimber = true, true
+				}
+			}
+		}
+		if !anyTimber {
+			report(SeverityError, DiagNoTimber, 0, "map has %d land masses but no timber, so no boats can be built to cross between them", len(masses))
+		} else {
+			for i, mass := range masses[1:] {
+				if !timberOn[i+1] {
+					report(SeverityWarning, DiagIslandNoTimber, mass[0], "is on an island of %d territories without timber; it can only be reached by boat", len(mass))
+				}
+			}
+		}
+	}
+
+	// Symmetry is checked on the processed map, so only on maps that can be
+	// processed
+	if raw.Symmetry != "" && !ds.HasErrors() {
+		if err := CheckSymmetry(Process(raw)); err != nil {
+			report(SeverityError, DiagAsymmetric, 0, "not %s symmetric: %v", raw.Symmetry, err)
+		}
+	}
+
+	return ds
+}
+
+// knownResource reports whether Process understands a resource string.
+// Empty and "none" mean no resource.
+func knownResource(s string) bool {
+	switch strings.ToLower(s) {
+	case "", "none":
+		return true
+	}
+	return parseResource(s) != game.ResourceNone
+}
+
+// countParts returns how many orthogonally connected parts a territory has.
+func countParts(raw *RawMap, tid int, cells [][2]int) int {
+	seen := make(map[[2]int]bool, len(cells))
+	parts := 0
+	for _, start := range cells {
+		if seen[start] {
+			continue
+		}
+		parts++
+		seen[start] = true
+		queue := [][2]int{start}
+		for len(queue) > 0 {
+			c := queue[0]
+			queue = queue[1:]
+			for _, n := range raw.Topology.Neighbors(c[0], c[1], raw.Width, raw.Height) {
+				if raw.Grid[n[1]][n[0]] == tid && !seen[n] {
+					seen[n] = true
+					queue = append(queue, n)
+				}
+			}
+		}
+	}
+	return parts
+}
+
+// rawLandMasses groups territories connected by land, largest first.
+func rawLandMasses(ids []int, neighbours map[int]map[int]bool) [][]int {
+	seen := make(map[int]bool, len(ids))
+	var masses [][]int
+	for _, tid := range ids {
+		if seen[tid] {
+			continue
+		}
+		seen[tid] = true
+		mass := []int{tid}
+		for i := 0; i < len(mass); i++ {
+			for n := range neighbours[mass[i]] {
+				if !seen[n] {
+					seen[n] = true
+					mass = append(mass, n)
+				}
+			}
+		}
+		sort.Ints(mass)
+		masses = append(masses, mass)
+	}
+	sort.SliceStable(masses, func(i, j int) bool { return len(masses[i]) > len(masses[j]) })
+	return masses
+}
+
+// sortedKeys returns the keys of m in order, so diagnostics come out the same
+// way every time.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,76 @@
+package maps
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestValidateReportsProblems checks each kind of problem on a small map.
+func TestValidateReportsProblems(t *testing.T) {
+	raw := &RawMap{
+		ID:     "broken",
+		Name:   "Broken",
+		Width:  6,
+		Height: 3,
+		Grid: [][]int{
+			{1, 2, 1, 0, 4, 4},
+			{3, 3, 3, 0, 0, 0},
+			{3, 3, 3, 0, 5, 0},
+		},
+		Territories: map[string]RawTerritory{
+			"1": {Name: "Split", Resource: "gold"},
+			"2": {Name: "Pocket", Resource: "horses"},
+			"3": {Name: "Split", Resource: "timber"},
+			"4": {Name: "Isle", Terrain: "tundra"},
+			"9": {Name: "Nowhere"},
+		},
+	}
+
+	got := make(map[string]int)
+	for _, d := range Validate(raw) {
+		got[fmt.Sprintf("%s/%d", d.Code, d.Territory)]++
+	}
+
+	for _, want := range []string{
+		DiagNonContiguous + "/1",
+		DiagUnknownResource + "/2",
+		DiagDuplicateName + "/3",
+		DiagMissingTerritory + "/5",
+		DiagUnusedTerritory + "/0",
+		DiagIslandNoTimber + "/4",
+		DiagUnknownTerrain + "/4",
+	} {
+		if got[want] != 1 {
+			t.Errorf("expected one %s diagnostic, got %v", want, got)
+		}
+	}
+	if !Validate(raw).HasErrors() {
+		t.Error("map should have errors")
+	}
+}
+
+// TestGeneratedMapsValidate checks that maps made by the generator and sent
+// by the client, with resources written out by name, pass validation.
+func TestGeneratedMapsValidate(t *testing.T) {
+	for _, islands := range []int{1, 3, 5} {
+		opts := DefaultOptions()
+		opts.Islands = islands
+		m, _ := NewGenerator(opts).Generate()
+
+		raw := &RawMap{
+			ID:          m.ID,
+			Name:        m.Name,
+			Width:       m.Width,
+			Height:      m.Height,
+			Grid:        m.Grid,
+			Territories: make(map[string]RawTerritory),
+		}
+		for id, terr := range m.Territories {
+			raw.Territories[fmt.Sprintf("%d", id)] = RawTerritory{Name: terr.Name, Resource: terr.Resource.String()}
+		}
+
+		if err := Validate(raw).Err(); err != nil {
+			t.Errorf("islands=%d: generated map is invalid: %v", islands, err)
+		}
+	}
+}